@@ -0,0 +1,1213 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.14.0
+// source: api/camp/v1/commander.proto
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Commander_ListAliveInstance_FullMethodName       = "/camp.v1.Commander/ListAliveInstance"
+	Commander_SearchInstances_FullMethodName         = "/camp.v1.Commander/SearchInstances"
+	Commander_UpdateInstanceLabels_FullMethodName    = "/camp.v1.Commander/UpdateInstanceLabels"
+	Commander_GetInstance_FullMethodName             = "/camp.v1.Commander/GetInstance"
+	Commander_ListInstanceMetrics_FullMethodName     = "/camp.v1.Commander/ListInstanceMetrics"
+	Commander_ListInstanceEvents_FullMethodName      = "/camp.v1.Commander/ListInstanceEvents"
+	Commander_ListInstanceConnections_FullMethodName = "/camp.v1.Commander/ListInstanceConnections"
+	Commander_GetInstanceUptime_FullMethodName       = "/camp.v1.Commander/GetInstanceUptime"
+	Commander_DecommissionInstance_FullMethodName    = "/camp.v1.Commander/DecommissionInstance"
+	Commander_IssueInstruct_FullMethodName           = "/camp.v1.Commander/IssueInstruct"
+	Commander_ListInstruct_FullMethodName            = "/camp.v1.Commander/ListInstruct"
+	Commander_QueryInstructs_FullMethodName          = "/camp.v1.Commander/QueryInstructs"
+	Commander_IssueBatchInstruct_FullMethodName      = "/camp.v1.Commander/IssueBatchInstruct"
+	Commander_CompareBatch_FullMethodName            = "/camp.v1.Commander/CompareBatch"
+	Commander_QueryResults_FullMethodName            = "/camp.v1.Commander/QueryResults"
+	Commander_GetInstruct_FullMethodName             = "/camp.v1.Commander/GetInstruct"
+	Commander_CancelInstruct_FullMethodName          = "/camp.v1.Commander/CancelInstruct"
+	Commander_ListNodes_FullMethodName               = "/camp.v1.Commander/ListNodes"
+	Commander_CreateOrg_FullMethodName               = "/camp.v1.Commander/CreateOrg"
+	Commander_ListOrgs_FullMethodName                = "/camp.v1.Commander/ListOrgs"
+	Commander_GetOrg_FullMethodName                  = "/camp.v1.Commander/GetOrg"
+	Commander_UpdateOrg_FullMethodName               = "/camp.v1.Commander/UpdateOrg"
+	Commander_DeleteOrg_FullMethodName               = "/camp.v1.Commander/DeleteOrg"
+	Commander_CreateGroup_FullMethodName             = "/camp.v1.Commander/CreateGroup"
+	Commander_ListGroups_FullMethodName              = "/camp.v1.Commander/ListGroups"
+	Commander_GetGroup_FullMethodName                = "/camp.v1.Commander/GetGroup"
+	Commander_UpdateGroup_FullMethodName             = "/camp.v1.Commander/UpdateGroup"
+	Commander_DeleteGroup_FullMethodName             = "/camp.v1.Commander/DeleteGroup"
+)
+
+// CommanderClient is the client API for Commander service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// commander 对外提供的管理接口
+type CommanderClient interface {
+	// Deprecated: Do not use.
+	// 列出在线实例，已由 SearchInstances (status=online) 替代
+	ListAliveInstance(ctx context.Context, in *ListAliveInstanceRequest, opts ...grpc.CallOption) (*ListAliveInstanceReply, error)
+	// 按名称、在线状态、操作系统、版本及标签选择器分页搜索实例
+	SearchInstances(ctx context.Context, in *SearchInstancesRequest, opts ...grpc.CallOption) (*SearchInstancesReply, error)
+	// 设置及删除实例标签
+	UpdateInstanceLabels(ctx context.Context, in *UpdateInstanceLabelsRequest, opts ...grpc.CallOption) (*UpdateInstanceLabelsReply, error)
+	// 获取实例 (包含 soldier 上报的主机信息)
+	GetInstance(ctx context.Context, in *GetInstanceRequest, opts ...grpc.CallOption) (*GetInstanceReply, error)
+	// 查询实例最近的监控数据
+	ListInstanceMetrics(ctx context.Context, in *ListInstanceMetricsRequest, opts ...grpc.CallOption) (*ListInstanceMetricsReply, error)
+	// 按事件 id 增量读取实例上下线事件
+	ListInstanceEvents(ctx context.Context, in *ListInstanceEventsRequest, opts ...grpc.CallOption) (*ListInstanceEventsReply, error)
+	// 列出实例的连接记录
+	ListInstanceConnections(ctx context.Context, in *ListInstanceConnectionsRequest, opts ...grpc.CallOption) (*ListInstanceConnectionsReply, error)
+	// 查询实例的在线时间线及在线时间占比
+	GetInstanceUptime(ctx context.Context, in *GetInstanceUptimeRequest, opts ...grpc.CallOption) (*GetInstanceUptimeReply, error)
+	// 下线实例: 断开连接、使会话失效、清空指令队列，并归档实例及指令记录
+	DecommissionInstance(ctx context.Context, in *DecommissionInstanceRequest, opts ...grpc.CallOption) (*DecommissionInstanceReply, error)
+	// 下发指令，wait_seconds 大于 0 时等待指令结束后返回结果
+	IssueInstruct(ctx context.Context, in *IssueInstructRequest, opts ...grpc.CallOption) (*IssueInstructReply, error)
+	// Deprecated: Do not use.
+	// 列出实例最近 50 条指令，已由 QueryInstructs 替代
+	ListInstruct(ctx context.Context, in *ListInstructRequest, opts ...grpc.CallOption) (*ListInstructReply, error)
+	// 按组织、分组、实例、类型、状态、操作人及时间范围分页查询指令 (按创建时间倒序，不返回指令结果)
+	QueryInstructs(ctx context.Context, in *QueryInstructsRequest, opts ...grpc.CallOption) (*QueryInstructsReply, error)
+	// 将同一个探测指令下发到分组内的多个在线实例 (按实例名或标签选择器选择)
+	IssueBatchInstruct(ctx context.Context, in *IssueBatchInstructRequest, opts ...grpc.CallOption) (*IssueBatchInstructReply, error)
+	// 对比批次内各实例的探测结果，标记状态码、解析地址、耗时及丢包率偏离多数实例的实例
+	CompareBatch(ctx context.Context, in *CompareBatchRequest, opts ...grpc.CallOption) (*CompareBatchReply, error)
+	// 按目标、状态码、耗时及时间范围分页查询探测指令 (ChromeDp / Dns / Http / Icmp) 的结构化结果 (按收到结果的时间倒序)
+	QueryResults(ctx context.Context, in *QueryResultsRequest, opts ...grpc.CallOption) (*QueryResultsReply, error)
+	// 获取指令
+	GetInstruct(ctx context.Context, in *GetInstructRequest, opts ...grpc.CallOption) (*GetInstructReply, error)
+	// 取消尚未完成的指令，soldier 正在执行时终止执行
+	CancelInstruct(ctx context.Context, in *CancelInstructRequest, opts ...grpc.CallOption) (*CancelInstructReply, error)
+	// 列出存活的 commander 节点
+	ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesReply, error)
+	// 创建组织，uuid 为空时自动生成；已删除的组织重新启用
+	CreateOrg(ctx context.Context, in *CreateOrgRequest, opts ...grpc.CallOption) (*CreateOrgReply, error)
+	// 列出组织
+	ListOrgs(ctx context.Context, in *ListOrgsRequest, opts ...grpc.CallOption) (*ListOrgsReply, error)
+	// 获取组织
+	GetOrg(ctx context.Context, in *GetOrgRequest, opts ...grpc.CallOption) (*GetOrgReply, error)
+	// 修改组织名称及描述
+	UpdateOrg(ctx context.Context, in *UpdateOrgRequest, opts ...grpc.CallOption) (*UpdateOrgReply, error)
+	// 删除组织，断开其下的实例连接，后台清理组织下的分组、实例及指令记录
+	DeleteOrg(ctx context.Context, in *DeleteOrgRequest, opts ...grpc.CallOption) (*DeleteOrgReply, error)
+	// 创建分组，uuid 为空时自动生成；已删除的分组重新启用
+	CreateGroup(ctx context.Context, in *CreateGroupRequest, opts ...grpc.CallOption) (*CreateGroupReply, error)
+	// 列出组织下的分组
+	ListGroups(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (*ListGroupsReply, error)
+	// 获取分组
+	GetGroup(ctx context.Context, in *GetGroupRequest, opts ...grpc.CallOption) (*GetGroupReply, error)
+	// 修改分组名称、描述及默认配置
+	UpdateGroup(ctx context.Context, in *UpdateGroupRequest, opts ...grpc.CallOption) (*UpdateGroupReply, error)
+	// 删除分组，断开其下的实例连接，后台清理分组下的实例及指令记录
+	DeleteGroup(ctx context.Context, in *DeleteGroupRequest, opts ...grpc.CallOption) (*DeleteGroupReply, error)
+}
+
+type commanderClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCommanderClient(cc grpc.ClientConnInterface) CommanderClient {
+	return &commanderClient{cc}
+}
+
+// Deprecated: Do not use.
+func (c *commanderClient) ListAliveInstance(ctx context.Context, in *ListAliveInstanceRequest, opts ...grpc.CallOption) (*ListAliveInstanceReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAliveInstanceReply)
+	err := c.cc.Invoke(ctx, Commander_ListAliveInstance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) SearchInstances(ctx context.Context, in *SearchInstancesRequest, opts ...grpc.CallOption) (*SearchInstancesReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchInstancesReply)
+	err := c.cc.Invoke(ctx, Commander_SearchInstances_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) UpdateInstanceLabels(ctx context.Context, in *UpdateInstanceLabelsRequest, opts ...grpc.CallOption) (*UpdateInstanceLabelsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateInstanceLabelsReply)
+	err := c.cc.Invoke(ctx, Commander_UpdateInstanceLabels_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) GetInstance(ctx context.Context, in *GetInstanceRequest, opts ...grpc.CallOption) (*GetInstanceReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetInstanceReply)
+	err := c.cc.Invoke(ctx, Commander_GetInstance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) ListInstanceMetrics(ctx context.Context, in *ListInstanceMetricsRequest, opts ...grpc.CallOption) (*ListInstanceMetricsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListInstanceMetricsReply)
+	err := c.cc.Invoke(ctx, Commander_ListInstanceMetrics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) ListInstanceEvents(ctx context.Context, in *ListInstanceEventsRequest, opts ...grpc.CallOption) (*ListInstanceEventsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListInstanceEventsReply)
+	err := c.cc.Invoke(ctx, Commander_ListInstanceEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) ListInstanceConnections(ctx context.Context, in *ListInstanceConnectionsRequest, opts ...grpc.CallOption) (*ListInstanceConnectionsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListInstanceConnectionsReply)
+	err := c.cc.Invoke(ctx, Commander_ListInstanceConnections_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) GetInstanceUptime(ctx context.Context, in *GetInstanceUptimeRequest, opts ...grpc.CallOption) (*GetInstanceUptimeReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetInstanceUptimeReply)
+	err := c.cc.Invoke(ctx, Commander_GetInstanceUptime_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) DecommissionInstance(ctx context.Context, in *DecommissionInstanceRequest, opts ...grpc.CallOption) (*DecommissionInstanceReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecommissionInstanceReply)
+	err := c.cc.Invoke(ctx, Commander_DecommissionInstance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) IssueInstruct(ctx context.Context, in *IssueInstructRequest, opts ...grpc.CallOption) (*IssueInstructReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IssueInstructReply)
+	err := c.cc.Invoke(ctx, Commander_IssueInstruct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Deprecated: Do not use.
+func (c *commanderClient) ListInstruct(ctx context.Context, in *ListInstructRequest, opts ...grpc.CallOption) (*ListInstructReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListInstructReply)
+	err := c.cc.Invoke(ctx, Commander_ListInstruct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) QueryInstructs(ctx context.Context, in *QueryInstructsRequest, opts ...grpc.CallOption) (*QueryInstructsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryInstructsReply)
+	err := c.cc.Invoke(ctx, Commander_QueryInstructs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) IssueBatchInstruct(ctx context.Context, in *IssueBatchInstructRequest, opts ...grpc.CallOption) (*IssueBatchInstructReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IssueBatchInstructReply)
+	err := c.cc.Invoke(ctx, Commander_IssueBatchInstruct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) CompareBatch(ctx context.Context, in *CompareBatchRequest, opts ...grpc.CallOption) (*CompareBatchReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompareBatchReply)
+	err := c.cc.Invoke(ctx, Commander_CompareBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) QueryResults(ctx context.Context, in *QueryResultsRequest, opts ...grpc.CallOption) (*QueryResultsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryResultsReply)
+	err := c.cc.Invoke(ctx, Commander_QueryResults_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) GetInstruct(ctx context.Context, in *GetInstructRequest, opts ...grpc.CallOption) (*GetInstructReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetInstructReply)
+	err := c.cc.Invoke(ctx, Commander_GetInstruct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) CancelInstruct(ctx context.Context, in *CancelInstructRequest, opts ...grpc.CallOption) (*CancelInstructReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelInstructReply)
+	err := c.cc.Invoke(ctx, Commander_CancelInstruct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNodesReply)
+	err := c.cc.Invoke(ctx, Commander_ListNodes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) CreateOrg(ctx context.Context, in *CreateOrgRequest, opts ...grpc.CallOption) (*CreateOrgReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateOrgReply)
+	err := c.cc.Invoke(ctx, Commander_CreateOrg_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) ListOrgs(ctx context.Context, in *ListOrgsRequest, opts ...grpc.CallOption) (*ListOrgsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOrgsReply)
+	err := c.cc.Invoke(ctx, Commander_ListOrgs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) GetOrg(ctx context.Context, in *GetOrgRequest, opts ...grpc.CallOption) (*GetOrgReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOrgReply)
+	err := c.cc.Invoke(ctx, Commander_GetOrg_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) UpdateOrg(ctx context.Context, in *UpdateOrgRequest, opts ...grpc.CallOption) (*UpdateOrgReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateOrgReply)
+	err := c.cc.Invoke(ctx, Commander_UpdateOrg_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) DeleteOrg(ctx context.Context, in *DeleteOrgRequest, opts ...grpc.CallOption) (*DeleteOrgReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteOrgReply)
+	err := c.cc.Invoke(ctx, Commander_DeleteOrg_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) CreateGroup(ctx context.Context, in *CreateGroupRequest, opts ...grpc.CallOption) (*CreateGroupReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateGroupReply)
+	err := c.cc.Invoke(ctx, Commander_CreateGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) ListGroups(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (*ListGroupsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGroupsReply)
+	err := c.cc.Invoke(ctx, Commander_ListGroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) GetGroup(ctx context.Context, in *GetGroupRequest, opts ...grpc.CallOption) (*GetGroupReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetGroupReply)
+	err := c.cc.Invoke(ctx, Commander_GetGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) UpdateGroup(ctx context.Context, in *UpdateGroupRequest, opts ...grpc.CallOption) (*UpdateGroupReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateGroupReply)
+	err := c.cc.Invoke(ctx, Commander_UpdateGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commanderClient) DeleteGroup(ctx context.Context, in *DeleteGroupRequest, opts ...grpc.CallOption) (*DeleteGroupReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteGroupReply)
+	err := c.cc.Invoke(ctx, Commander_DeleteGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CommanderServer is the server API for Commander service.
+// All implementations must embed UnimplementedCommanderServer
+// for forward compatibility.
+//
+// commander 对外提供的管理接口
+type CommanderServer interface {
+	// Deprecated: Do not use.
+	// 列出在线实例，已由 SearchInstances (status=online) 替代
+	ListAliveInstance(context.Context, *ListAliveInstanceRequest) (*ListAliveInstanceReply, error)
+	// 按名称、在线状态、操作系统、版本及标签选择器分页搜索实例
+	SearchInstances(context.Context, *SearchInstancesRequest) (*SearchInstancesReply, error)
+	// 设置及删除实例标签
+	UpdateInstanceLabels(context.Context, *UpdateInstanceLabelsRequest) (*UpdateInstanceLabelsReply, error)
+	// 获取实例 (包含 soldier 上报的主机信息)
+	GetInstance(context.Context, *GetInstanceRequest) (*GetInstanceReply, error)
+	// 查询实例最近的监控数据
+	ListInstanceMetrics(context.Context, *ListInstanceMetricsRequest) (*ListInstanceMetricsReply, error)
+	// 按事件 id 增量读取实例上下线事件
+	ListInstanceEvents(context.Context, *ListInstanceEventsRequest) (*ListInstanceEventsReply, error)
+	// 列出实例的连接记录
+	ListInstanceConnections(context.Context, *ListInstanceConnectionsRequest) (*ListInstanceConnectionsReply, error)
+	// 查询实例的在线时间线及在线时间占比
+	GetInstanceUptime(context.Context, *GetInstanceUptimeRequest) (*GetInstanceUptimeReply, error)
+	// 下线实例: 断开连接、使会话失效、清空指令队列，并归档实例及指令记录
+	DecommissionInstance(context.Context, *DecommissionInstanceRequest) (*DecommissionInstanceReply, error)
+	// 下发指令，wait_seconds 大于 0 时等待指令结束后返回结果
+	IssueInstruct(context.Context, *IssueInstructRequest) (*IssueInstructReply, error)
+	// Deprecated: Do not use.
+	// 列出实例最近 50 条指令，已由 QueryInstructs 替代
+	ListInstruct(context.Context, *ListInstructRequest) (*ListInstructReply, error)
+	// 按组织、分组、实例、类型、状态、操作人及时间范围分页查询指令 (按创建时间倒序，不返回指令结果)
+	QueryInstructs(context.Context, *QueryInstructsRequest) (*QueryInstructsReply, error)
+	// 将同一个探测指令下发到分组内的多个在线实例 (按实例名或标签选择器选择)
+	IssueBatchInstruct(context.Context, *IssueBatchInstructRequest) (*IssueBatchInstructReply, error)
+	// 对比批次内各实例的探测结果，标记状态码、解析地址、耗时及丢包率偏离多数实例的实例
+	CompareBatch(context.Context, *CompareBatchRequest) (*CompareBatchReply, error)
+	// 按目标、状态码、耗时及时间范围分页查询探测指令 (ChromeDp / Dns / Http / Icmp) 的结构化结果 (按收到结果的时间倒序)
+	QueryResults(context.Context, *QueryResultsRequest) (*QueryResultsReply, error)
+	// 获取指令
+	GetInstruct(context.Context, *GetInstructRequest) (*GetInstructReply, error)
+	// 取消尚未完成的指令，soldier 正在执行时终止执行
+	CancelInstruct(context.Context, *CancelInstructRequest) (*CancelInstructReply, error)
+	// 列出存活的 commander 节点
+	ListNodes(context.Context, *ListNodesRequest) (*ListNodesReply, error)
+	// 创建组织，uuid 为空时自动生成；已删除的组织重新启用
+	CreateOrg(context.Context, *CreateOrgRequest) (*CreateOrgReply, error)
+	// 列出组织
+	ListOrgs(context.Context, *ListOrgsRequest) (*ListOrgsReply, error)
+	// 获取组织
+	GetOrg(context.Context, *GetOrgRequest) (*GetOrgReply, error)
+	// 修改组织名称及描述
+	UpdateOrg(context.Context, *UpdateOrgRequest) (*UpdateOrgReply, error)
+	// 删除组织，断开其下的实例连接，后台清理组织下的分组、实例及指令记录
+	DeleteOrg(context.Context, *DeleteOrgRequest) (*DeleteOrgReply, error)
+	// 创建分组，uuid 为空时自动生成；已删除的分组重新启用
+	CreateGroup(context.Context, *CreateGroupRequest) (*CreateGroupReply, error)
+	// 列出组织下的分组
+	ListGroups(context.Context, *ListGroupsRequest) (*ListGroupsReply, error)
+	// 获取分组
+	GetGroup(context.Context, *GetGroupRequest) (*GetGroupReply, error)
+	// 修改分组名称、描述及默认配置
+	UpdateGroup(context.Context, *UpdateGroupRequest) (*UpdateGroupReply, error)
+	// 删除分组，断开其下的实例连接，后台清理分组下的实例及指令记录
+	DeleteGroup(context.Context, *DeleteGroupRequest) (*DeleteGroupReply, error)
+	mustEmbedUnimplementedCommanderServer()
+}
+
+// UnimplementedCommanderServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCommanderServer struct{}
+
+func (UnimplementedCommanderServer) ListAliveInstance(context.Context, *ListAliveInstanceRequest) (*ListAliveInstanceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAliveInstance not implemented")
+}
+func (UnimplementedCommanderServer) SearchInstances(context.Context, *SearchInstancesRequest) (*SearchInstancesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchInstances not implemented")
+}
+func (UnimplementedCommanderServer) UpdateInstanceLabels(context.Context, *UpdateInstanceLabelsRequest) (*UpdateInstanceLabelsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateInstanceLabels not implemented")
+}
+func (UnimplementedCommanderServer) GetInstance(context.Context, *GetInstanceRequest) (*GetInstanceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInstance not implemented")
+}
+func (UnimplementedCommanderServer) ListInstanceMetrics(context.Context, *ListInstanceMetricsRequest) (*ListInstanceMetricsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInstanceMetrics not implemented")
+}
+func (UnimplementedCommanderServer) ListInstanceEvents(context.Context, *ListInstanceEventsRequest) (*ListInstanceEventsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInstanceEvents not implemented")
+}
+func (UnimplementedCommanderServer) ListInstanceConnections(context.Context, *ListInstanceConnectionsRequest) (*ListInstanceConnectionsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInstanceConnections not implemented")
+}
+func (UnimplementedCommanderServer) GetInstanceUptime(context.Context, *GetInstanceUptimeRequest) (*GetInstanceUptimeReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInstanceUptime not implemented")
+}
+func (UnimplementedCommanderServer) DecommissionInstance(context.Context, *DecommissionInstanceRequest) (*DecommissionInstanceReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecommissionInstance not implemented")
+}
+func (UnimplementedCommanderServer) IssueInstruct(context.Context, *IssueInstructRequest) (*IssueInstructReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IssueInstruct not implemented")
+}
+func (UnimplementedCommanderServer) ListInstruct(context.Context, *ListInstructRequest) (*ListInstructReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInstruct not implemented")
+}
+func (UnimplementedCommanderServer) QueryInstructs(context.Context, *QueryInstructsRequest) (*QueryInstructsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryInstructs not implemented")
+}
+func (UnimplementedCommanderServer) IssueBatchInstruct(context.Context, *IssueBatchInstructRequest) (*IssueBatchInstructReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IssueBatchInstruct not implemented")
+}
+func (UnimplementedCommanderServer) CompareBatch(context.Context, *CompareBatchRequest) (*CompareBatchReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompareBatch not implemented")
+}
+func (UnimplementedCommanderServer) QueryResults(context.Context, *QueryResultsRequest) (*QueryResultsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryResults not implemented")
+}
+func (UnimplementedCommanderServer) GetInstruct(context.Context, *GetInstructRequest) (*GetInstructReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInstruct not implemented")
+}
+func (UnimplementedCommanderServer) CancelInstruct(context.Context, *CancelInstructRequest) (*CancelInstructReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelInstruct not implemented")
+}
+func (UnimplementedCommanderServer) ListNodes(context.Context, *ListNodesRequest) (*ListNodesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNodes not implemented")
+}
+func (UnimplementedCommanderServer) CreateOrg(context.Context, *CreateOrgRequest) (*CreateOrgReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateOrg not implemented")
+}
+func (UnimplementedCommanderServer) ListOrgs(context.Context, *ListOrgsRequest) (*ListOrgsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOrgs not implemented")
+}
+func (UnimplementedCommanderServer) GetOrg(context.Context, *GetOrgRequest) (*GetOrgReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrg not implemented")
+}
+func (UnimplementedCommanderServer) UpdateOrg(context.Context, *UpdateOrgRequest) (*UpdateOrgReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateOrg not implemented")
+}
+func (UnimplementedCommanderServer) DeleteOrg(context.Context, *DeleteOrgRequest) (*DeleteOrgReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteOrg not implemented")
+}
+func (UnimplementedCommanderServer) CreateGroup(context.Context, *CreateGroupRequest) (*CreateGroupReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGroup not implemented")
+}
+func (UnimplementedCommanderServer) ListGroups(context.Context, *ListGroupsRequest) (*ListGroupsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGroups not implemented")
+}
+func (UnimplementedCommanderServer) GetGroup(context.Context, *GetGroupRequest) (*GetGroupReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGroup not implemented")
+}
+func (UnimplementedCommanderServer) UpdateGroup(context.Context, *UpdateGroupRequest) (*UpdateGroupReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateGroup not implemented")
+}
+func (UnimplementedCommanderServer) DeleteGroup(context.Context, *DeleteGroupRequest) (*DeleteGroupReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteGroup not implemented")
+}
+func (UnimplementedCommanderServer) mustEmbedUnimplementedCommanderServer() {}
+func (UnimplementedCommanderServer) testEmbeddedByValue()                   {}
+
+// UnsafeCommanderServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CommanderServer will
+// result in compilation errors.
+type UnsafeCommanderServer interface {
+	mustEmbedUnimplementedCommanderServer()
+}
+
+func RegisterCommanderServer(s grpc.ServiceRegistrar, srv CommanderServer) {
+	// If the following call pancis, it indicates UnimplementedCommanderServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Commander_ServiceDesc, srv)
+}
+
+func _Commander_ListAliveInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAliveInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).ListAliveInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_ListAliveInstance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).ListAliveInstance(ctx, req.(*ListAliveInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_SearchInstances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchInstancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).SearchInstances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_SearchInstances_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).SearchInstances(ctx, req.(*SearchInstancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_UpdateInstanceLabels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateInstanceLabelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).UpdateInstanceLabels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_UpdateInstanceLabels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).UpdateInstanceLabels(ctx, req.(*UpdateInstanceLabelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_GetInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).GetInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_GetInstance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).GetInstance(ctx, req.(*GetInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_ListInstanceMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInstanceMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).ListInstanceMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_ListInstanceMetrics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).ListInstanceMetrics(ctx, req.(*ListInstanceMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_ListInstanceEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInstanceEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).ListInstanceEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_ListInstanceEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).ListInstanceEvents(ctx, req.(*ListInstanceEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_ListInstanceConnections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInstanceConnectionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).ListInstanceConnections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_ListInstanceConnections_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).ListInstanceConnections(ctx, req.(*ListInstanceConnectionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_GetInstanceUptime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInstanceUptimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).GetInstanceUptime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_GetInstanceUptime_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).GetInstanceUptime(ctx, req.(*GetInstanceUptimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_DecommissionInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecommissionInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).DecommissionInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_DecommissionInstance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).DecommissionInstance(ctx, req.(*DecommissionInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_IssueInstruct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueInstructRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).IssueInstruct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_IssueInstruct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).IssueInstruct(ctx, req.(*IssueInstructRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_ListInstruct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInstructRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).ListInstruct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_ListInstruct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).ListInstruct(ctx, req.(*ListInstructRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_QueryInstructs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryInstructsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).QueryInstructs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_QueryInstructs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).QueryInstructs(ctx, req.(*QueryInstructsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_IssueBatchInstruct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueBatchInstructRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).IssueBatchInstruct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_IssueBatchInstruct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).IssueBatchInstruct(ctx, req.(*IssueBatchInstructRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_CompareBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).CompareBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_CompareBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).CompareBatch(ctx, req.(*CompareBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_QueryResults_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryResultsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).QueryResults(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_QueryResults_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).QueryResults(ctx, req.(*QueryResultsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_GetInstruct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInstructRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).GetInstruct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_GetInstruct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).GetInstruct(ctx, req.(*GetInstructRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_CancelInstruct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelInstructRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).CancelInstruct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_CancelInstruct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).CancelInstruct(ctx, req.(*CancelInstructRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_ListNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).ListNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_ListNodes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).ListNodes(ctx, req.(*ListNodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_CreateOrg_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOrgRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).CreateOrg(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_CreateOrg_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).CreateOrg(ctx, req.(*CreateOrgRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_ListOrgs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOrgsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).ListOrgs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_ListOrgs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).ListOrgs(ctx, req.(*ListOrgsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_GetOrg_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrgRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).GetOrg(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_GetOrg_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).GetOrg(ctx, req.(*GetOrgRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_UpdateOrg_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateOrgRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).UpdateOrg(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_UpdateOrg_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).UpdateOrg(ctx, req.(*UpdateOrgRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_DeleteOrg_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteOrgRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).DeleteOrg(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_DeleteOrg_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).DeleteOrg(ctx, req.(*DeleteOrgRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_CreateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).CreateGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_CreateGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).CreateGroup(ctx, req.(*CreateGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_ListGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).ListGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_ListGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).ListGroups(ctx, req.(*ListGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_GetGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).GetGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_GetGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).GetGroup(ctx, req.(*GetGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_UpdateGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).UpdateGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_UpdateGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).UpdateGroup(ctx, req.(*UpdateGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Commander_DeleteGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommanderServer).DeleteGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Commander_DeleteGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommanderServer).DeleteGroup(ctx, req.(*DeleteGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Commander_ServiceDesc is the grpc.ServiceDesc for Commander service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Commander_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "camp.v1.Commander",
+	HandlerType: (*CommanderServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListAliveInstance",
+			Handler:    _Commander_ListAliveInstance_Handler,
+		},
+		{
+			MethodName: "SearchInstances",
+			Handler:    _Commander_SearchInstances_Handler,
+		},
+		{
+			MethodName: "UpdateInstanceLabels",
+			Handler:    _Commander_UpdateInstanceLabels_Handler,
+		},
+		{
+			MethodName: "GetInstance",
+			Handler:    _Commander_GetInstance_Handler,
+		},
+		{
+			MethodName: "ListInstanceMetrics",
+			Handler:    _Commander_ListInstanceMetrics_Handler,
+		},
+		{
+			MethodName: "ListInstanceEvents",
+			Handler:    _Commander_ListInstanceEvents_Handler,
+		},
+		{
+			MethodName: "ListInstanceConnections",
+			Handler:    _Commander_ListInstanceConnections_Handler,
+		},
+		{
+			MethodName: "GetInstanceUptime",
+			Handler:    _Commander_GetInstanceUptime_Handler,
+		},
+		{
+			MethodName: "DecommissionInstance",
+			Handler:    _Commander_DecommissionInstance_Handler,
+		},
+		{
+			MethodName: "IssueInstruct",
+			Handler:    _Commander_IssueInstruct_Handler,
+		},
+		{
+			MethodName: "ListInstruct",
+			Handler:    _Commander_ListInstruct_Handler,
+		},
+		{
+			MethodName: "QueryInstructs",
+			Handler:    _Commander_QueryInstructs_Handler,
+		},
+		{
+			MethodName: "IssueBatchInstruct",
+			Handler:    _Commander_IssueBatchInstruct_Handler,
+		},
+		{
+			MethodName: "CompareBatch",
+			Handler:    _Commander_CompareBatch_Handler,
+		},
+		{
+			MethodName: "QueryResults",
+			Handler:    _Commander_QueryResults_Handler,
+		},
+		{
+			MethodName: "GetInstruct",
+			Handler:    _Commander_GetInstruct_Handler,
+		},
+		{
+			MethodName: "CancelInstruct",
+			Handler:    _Commander_CancelInstruct_Handler,
+		},
+		{
+			MethodName: "ListNodes",
+			Handler:    _Commander_ListNodes_Handler,
+		},
+		{
+			MethodName: "CreateOrg",
+			Handler:    _Commander_CreateOrg_Handler,
+		},
+		{
+			MethodName: "ListOrgs",
+			Handler:    _Commander_ListOrgs_Handler,
+		},
+		{
+			MethodName: "GetOrg",
+			Handler:    _Commander_GetOrg_Handler,
+		},
+		{
+			MethodName: "UpdateOrg",
+			Handler:    _Commander_UpdateOrg_Handler,
+		},
+		{
+			MethodName: "DeleteOrg",
+			Handler:    _Commander_DeleteOrg_Handler,
+		},
+		{
+			MethodName: "CreateGroup",
+			Handler:    _Commander_CreateGroup_Handler,
+		},
+		{
+			MethodName: "ListGroups",
+			Handler:    _Commander_ListGroups_Handler,
+		},
+		{
+			MethodName: "GetGroup",
+			Handler:    _Commander_GetGroup_Handler,
+		},
+		{
+			MethodName: "UpdateGroup",
+			Handler:    _Commander_UpdateGroup_Handler,
+		},
+		{
+			MethodName: "DeleteGroup",
+			Handler:    _Commander_DeleteGroup_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/camp/v1/commander.proto",
+}
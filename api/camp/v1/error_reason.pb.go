@@ -0,0 +1,211 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v3.14.0
+// source: api/camp/v1/error_reason.proto
+
+package v1
+
+import (
+	_ "github.com/go-kratos/kratos/v2/errors"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ErrorReason int32
+
+const (
+	// 参数异常
+	ErrorReason_INVALID_ARGUMENT ErrorReason = 0
+	// 目标实例不在线
+	ErrorReason_INSTANCE_OFFLINE ErrorReason = 1
+	// 未知指令类型
+	ErrorReason_UNKNOWN_INSTRUCT_TYPE ErrorReason = 2
+	// 指令不存在
+	ErrorReason_INSTRUCT_NOT_FOUND ErrorReason = 3
+	// 系统异常
+	ErrorReason_INTERNAL_ERROR ErrorReason = 4
+	// 目标实例不支持该指令类型
+	ErrorReason_UNSUPPORTED_INSTRUCT_TYPE ErrorReason = 5
+	// 实例不存在
+	ErrorReason_INSTANCE_NOT_FOUND ErrorReason = 6
+	// 指令已结束
+	ErrorReason_INSTRUCT_FINISHED ErrorReason = 7
+	// 组织不存在
+	ErrorReason_ORG_NOT_FOUND ErrorReason = 8
+	// 分组不存在
+	ErrorReason_GROUP_NOT_FOUND ErrorReason = 9
+	// 组织或分组已存在
+	ErrorReason_ALREADY_EXISTS ErrorReason = 10
+	// 组织或分组已删除或正在删除
+	ErrorReason_GROUP_DELETED ErrorReason = 11
+	// 命令不符合分组的命令策略
+	ErrorReason_COMMAND_DENIED ErrorReason = 12
+	// 批次不存在
+	ErrorReason_BATCH_NOT_FOUND ErrorReason = 13
+	// 组织已删除或正在删除
+	ErrorReason_ORG_DELETED ErrorReason = 14
+)
+
+// Enum value maps for ErrorReason.
+var (
+	ErrorReason_name = map[int32]string{
+		0:  "INVALID_ARGUMENT",
+		1:  "INSTANCE_OFFLINE",
+		2:  "UNKNOWN_INSTRUCT_TYPE",
+		3:  "INSTRUCT_NOT_FOUND",
+		4:  "INTERNAL_ERROR",
+		5:  "UNSUPPORTED_INSTRUCT_TYPE",
+		6:  "INSTANCE_NOT_FOUND",
+		7:  "INSTRUCT_FINISHED",
+		8:  "ORG_NOT_FOUND",
+		9:  "GROUP_NOT_FOUND",
+		10: "ALREADY_EXISTS",
+		11: "GROUP_DELETED",
+		12: "COMMAND_DENIED",
+		13: "BATCH_NOT_FOUND",
+		14: "ORG_DELETED",
+	}
+	ErrorReason_value = map[string]int32{
+		"INVALID_ARGUMENT":          0,
+		"INSTANCE_OFFLINE":          1,
+		"UNKNOWN_INSTRUCT_TYPE":     2,
+		"INSTRUCT_NOT_FOUND":        3,
+		"INTERNAL_ERROR":            4,
+		"UNSUPPORTED_INSTRUCT_TYPE": 5,
+		"INSTANCE_NOT_FOUND":        6,
+		"INSTRUCT_FINISHED":         7,
+		"ORG_NOT_FOUND":             8,
+		"GROUP_NOT_FOUND":           9,
+		"ALREADY_EXISTS":            10,
+		"GROUP_DELETED":             11,
+		"COMMAND_DENIED":            12,
+		"BATCH_NOT_FOUND":           13,
+		"ORG_DELETED":               14,
+	}
+)
+
+func (x ErrorReason) Enum() *ErrorReason {
+	p := new(ErrorReason)
+	*p = x
+	return p
+}
+
+func (x ErrorReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_camp_v1_error_reason_proto_enumTypes[0].Descriptor()
+}
+
+func (ErrorReason) Type() protoreflect.EnumType {
+	return &file_api_camp_v1_error_reason_proto_enumTypes[0]
+}
+
+func (x ErrorReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorReason.Descriptor instead.
+func (ErrorReason) EnumDescriptor() ([]byte, []int) {
+	return file_api_camp_v1_error_reason_proto_rawDescGZIP(), []int{0}
+}
+
+var File_api_camp_v1_error_reason_proto protoreflect.FileDescriptor
+
+var file_api_camp_v1_error_reason_proto_rawDesc = []byte{
+	0x0a, 0x1e, 0x61, 0x70, 0x69, 0x2f, 0x63, 0x61, 0x6d, 0x70, 0x2f, 0x76, 0x31, 0x2f, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x07, 0x63, 0x61, 0x6d, 0x70, 0x2e, 0x76, 0x31, 0x1a, 0x13, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x73, 0x2f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2a, 0xb7,
+	0x03, 0x0a, 0x0b, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1a,
+	0x0a, 0x10, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x41, 0x52, 0x47, 0x55, 0x4d, 0x45,
+	0x4e, 0x54, 0x10, 0x00, 0x1a, 0x04, 0xa8, 0x45, 0x90, 0x03, 0x12, 0x1a, 0x0a, 0x10, 0x49, 0x4e,
+	0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x5f, 0x4f, 0x46, 0x46, 0x4c, 0x49, 0x4e, 0x45, 0x10, 0x01,
+	0x1a, 0x04, 0xa8, 0x45, 0x90, 0x03, 0x12, 0x1f, 0x0a, 0x15, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57,
+	0x4e, 0x5f, 0x49, 0x4e, 0x53, 0x54, 0x52, 0x55, 0x43, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x10,
+	0x02, 0x1a, 0x04, 0xa8, 0x45, 0x90, 0x03, 0x12, 0x1c, 0x0a, 0x12, 0x49, 0x4e, 0x53, 0x54, 0x52,
+	0x55, 0x43, 0x54, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x03, 0x1a,
+	0x04, 0xa8, 0x45, 0x94, 0x03, 0x12, 0x18, 0x0a, 0x0e, 0x49, 0x4e, 0x54, 0x45, 0x52, 0x4e, 0x41,
+	0x4c, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x04, 0x1a, 0x04, 0xa8, 0x45, 0xf4, 0x03, 0x12,
+	0x23, 0x0a, 0x19, 0x55, 0x4e, 0x53, 0x55, 0x50, 0x50, 0x4f, 0x52, 0x54, 0x45, 0x44, 0x5f, 0x49,
+	0x4e, 0x53, 0x54, 0x52, 0x55, 0x43, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x10, 0x05, 0x1a, 0x04,
+	0xa8, 0x45, 0x90, 0x03, 0x12, 0x1c, 0x0a, 0x12, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45,
+	0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x06, 0x1a, 0x04, 0xa8, 0x45,
+	0x94, 0x03, 0x12, 0x1b, 0x0a, 0x11, 0x49, 0x4e, 0x53, 0x54, 0x52, 0x55, 0x43, 0x54, 0x5f, 0x46,
+	0x49, 0x4e, 0x49, 0x53, 0x48, 0x45, 0x44, 0x10, 0x07, 0x1a, 0x04, 0xa8, 0x45, 0x99, 0x03, 0x12,
+	0x17, 0x0a, 0x0d, 0x4f, 0x52, 0x47, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44,
+	0x10, 0x08, 0x1a, 0x04, 0xa8, 0x45, 0x94, 0x03, 0x12, 0x19, 0x0a, 0x0f, 0x47, 0x52, 0x4f, 0x55,
+	0x50, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x09, 0x1a, 0x04, 0xa8,
+	0x45, 0x94, 0x03, 0x12, 0x18, 0x0a, 0x0e, 0x41, 0x4c, 0x52, 0x45, 0x41, 0x44, 0x59, 0x5f, 0x45,
+	0x58, 0x49, 0x53, 0x54, 0x53, 0x10, 0x0a, 0x1a, 0x04, 0xa8, 0x45, 0x99, 0x03, 0x12, 0x17, 0x0a,
+	0x0d, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x0b,
+	0x1a, 0x04, 0xa8, 0x45, 0x99, 0x03, 0x12, 0x18, 0x0a, 0x0e, 0x43, 0x4f, 0x4d, 0x4d, 0x41, 0x4e,
+	0x44, 0x5f, 0x44, 0x45, 0x4e, 0x49, 0x45, 0x44, 0x10, 0x0c, 0x1a, 0x04, 0xa8, 0x45, 0x93, 0x03,
+	0x12, 0x19, 0x0a, 0x0f, 0x42, 0x41, 0x54, 0x43, 0x48, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f,
+	0x55, 0x4e, 0x44, 0x10, 0x0d, 0x1a, 0x04, 0xa8, 0x45, 0x94, 0x03, 0x12, 0x15, 0x0a, 0x0b, 0x4f,
+	0x52, 0x47, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x0e, 0x1a, 0x04, 0xa8, 0x45,
+	0x99, 0x03, 0x1a, 0x04, 0xa0, 0x45, 0xf4, 0x03, 0x42, 0x25, 0x5a, 0x23, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x71, 0x78, 0x36, 0x36, 0x2f, 0x63, 0x61, 0x6d, 0x70,
+	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x63, 0x61, 0x6d, 0x70, 0x2f, 0x76, 0x31, 0x3b, 0x76, 0x31, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_camp_v1_error_reason_proto_rawDescOnce sync.Once
+	file_api_camp_v1_error_reason_proto_rawDescData = file_api_camp_v1_error_reason_proto_rawDesc
+)
+
+func file_api_camp_v1_error_reason_proto_rawDescGZIP() []byte {
+	file_api_camp_v1_error_reason_proto_rawDescOnce.Do(func() {
+		file_api_camp_v1_error_reason_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_camp_v1_error_reason_proto_rawDescData)
+	})
+	return file_api_camp_v1_error_reason_proto_rawDescData
+}
+
+var file_api_camp_v1_error_reason_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_camp_v1_error_reason_proto_goTypes = []any{
+	(ErrorReason)(0), // 0: camp.v1.ErrorReason
+}
+var file_api_camp_v1_error_reason_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_api_camp_v1_error_reason_proto_init() }
+func file_api_camp_v1_error_reason_proto_init() {
+	if File_api_camp_v1_error_reason_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_camp_v1_error_reason_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_camp_v1_error_reason_proto_goTypes,
+		DependencyIndexes: file_api_camp_v1_error_reason_proto_depIdxs,
+		EnumInfos:         file_api_camp_v1_error_reason_proto_enumTypes,
+	}.Build()
+	File_api_camp_v1_error_reason_proto = out.File
+	file_api_camp_v1_error_reason_proto_rawDesc = nil
+	file_api_camp_v1_error_reason_proto_goTypes = nil
+	file_api_camp_v1_error_reason_proto_depIdxs = nil
+}
@@ -30,6 +30,9 @@ func newIApp(logger *zap.Logger, webSocketUseCase *biz.WebSocketUseCase) *iApp {
 }
 
 var (
+	// Version 通过 -ldflags "-X main.Version=x.y.z" 设置
+	Version = "dev"
+	
 	webSocketUrl = "ws://camp.startops.com.cn/connect"
 	token        = ""
 	orgUuid      = "mobile"
@@ -48,21 +51,23 @@ func main() {
 	logger.Info("实例信息", zap.String("orgUuid", orgUuid), zap.String("groupUuid", groupUuid), zap.String("instanceName", instanceName))
 	
 	ctx := context.Background()
-	iApp := initApp(logger)
+	iApp := initApp(logger, &biz.FilePolicy{}, &biz.ShellClientPolicy{}, &biz.TunnelClientPolicy{}, &biz.FramePolicy{}, &biz.SpoolPolicy{})
 	
 	websocketUrl := fmt.Sprintf("%s?orgUuid=%s&groupUuid=%s&instanceName=%s",
 		webSocketUrl, orgUuid, groupUuid, instanceName)
 	
-	sendMsg := make(chan biz.ClientMessage)
-	receiveMsg := make(chan []byte)
+	sendChannels := biz.NewClientChannels()
+	receiveQueues := biz.NewServiceQueues()
 	done := make(chan struct{})
 	defer close(done)
 	
-	go iApp.webSocketUseCase.NewWebSocket(ctx, websocketUrl, token, sendMsg, receiveMsg, done)
+	go iApp.webSocketUseCase.NewWebSocket(ctx, websocketUrl, token, Version, sendChannels, receiveQueues, done)
 	
-	go iApp.webSocketUseCase.ProcessServiceMessage(ctx, receiveMsg, sendMsg)
+	for channel, receiveMsg := range receiveQueues {
+		go iApp.webSocketUseCase.ProcessServiceMessage(ctx, biz.Channel(channel), receiveMsg, sendChannels)
+	}
 	
-	go iApp.webSocketUseCase.HelloEcho(ctx, sendMsg)
+	go iApp.webSocketUseCase.ReportMetrics(ctx, sendChannels.Queue(biz.TelemetryChannel))
 	
 	myApp := app.New()
 	//myApp.Settings().SetTheme(&lTheme.MyTheme{})
@@ -9,6 +9,6 @@ import (
 	"go.uber.org/zap"
 )
 
-func initApp(logger *zap.Logger) *iApp {
+func initApp(logger *zap.Logger, filePolicy *biz.FilePolicy, shellClientPolicy *biz.ShellClientPolicy, tunnelClientPolicy *biz.TunnelClientPolicy, framePolicy *biz.FramePolicy, spoolPolicy *biz.SpoolPolicy) *iApp {
 	panic(wire.Build(biz.ProviderSet, newIApp))
 }
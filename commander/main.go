@@ -1,26 +1,54 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"github.com/gin-gonic/gin"
 	"github.com/go-kratos/kratos/v2/config"
 	"github.com/go-kratos/kratos/v2/config/file"
+	v1 "github.com/qx66/camp/api/camp/v1"
+	"github.com/qx66/camp/internal/biz"
 	"github.com/qx66/camp/internal/conf"
 	"github.com/qx66/camp/internal/service"
+	"github.com/qx66/camp/pkg/middleware"
+	"github.com/qx66/camp/pkg/registry"
+	"github.com/qx66/camp/pkg/tracing"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 type app struct {
-	service *service.UseCase
+	service   *service.UseCase
+	commander *service.CommanderService
+	presence  *biz.PresenceUseCase
+	cluster   *biz.ClusterUseCase
+	org       *biz.OrgUseCase
+	archive   *biz.ArchiveUseCase
+	event     *biz.EventUseCase
 }
 
-func newApp(service *service.UseCase) *app {
+func newApp(service *service.UseCase, commander *service.CommanderService, presence *biz.PresenceUseCase, cluster *biz.ClusterUseCase, org *biz.OrgUseCase, archive *biz.ArchiveUseCase, event *biz.EventUseCase) *app {
 	return &app{
-		service: service,
+		service:   service,
+		commander: commander,
+		presence:  presence,
+		cluster:   cluster,
+		org:       org,
+		archive:   archive,
+		event:     event,
 	}
 }
 
 var (
+	// Version 通过 -ldflags "-X main.Version=x.y.z" 设置
+	Version = "dev"
+	
 	configPath = ""
 )
 
@@ -44,7 +72,7 @@ func main() {
 	c := config.New(
 		config.WithSource(
 			configs...,
-			//file.NewSource(flagconf),
+		//file.NewSource(flagconf),
 		),
 	)
 	
@@ -57,7 +85,74 @@ func main() {
 		panic(err)
 	}
 	
-	app, clean, err := initApp(logger, bc.Data)
+	traceCleanup, err := tracing.NewTracerProvider(context.Background(), bc.Trace.GetEndpoint(), "camp-commander", Version)
+	if err != nil {
+		logger.Error("初始化Trace失败", zap.Error(err))
+		return
+	}
+	defer traceCleanup()
+	
+	shellPolicy := &biz.ShellPolicy{
+		AllowedRoles: bc.GetShell().GetAllowedRoles(),
+		IdleTimeout:  bc.GetShell().GetIdleTimeout().AsDuration(),
+	}
+	
+	tunnelPolicy := &biz.TunnelPolicy{AllowedRoles: bc.GetTunnel().GetAllowedRoles()}
+	for _, rule := range bc.GetTunnel().GetRules() {
+		tunnelRule, err := biz.ParseTunnelRule(rule.GetOrgUuid(), rule.GetCidrs(), rule.GetPorts())
+		if err != nil {
+			logger.Error("端口转发规则异常", zap.String("orgUuid", rule.GetOrgUuid()), zap.Error(err))
+			return
+		}
+		tunnelPolicy.Rules = append(tunnelPolicy.Rules, tunnelRule)
+	}
+	
+	framePolicy := &biz.FramePolicy{
+		MaxFrameSize:   int(bc.GetWebsocket().GetMaxFrameSize()),
+		MaxMessageSize: int(bc.GetWebsocket().GetMaxMessageSize()),
+		Compression:    !bc.GetWebsocket().GetDisableCompression(),
+	}
+	
+	heartbeatPolicy := &biz.HeartbeatPolicy{
+		PingInterval: bc.GetHeartbeat().GetPingInterval().AsDuration(),
+		PongTimeout:  bc.GetHeartbeat().GetPongTimeout().AsDuration(),
+		AliveTimeout: bc.GetHeartbeat().GetAliveTimeout().AsDuration(),
+	}
+	
+	clusterPolicy := &biz.ClusterPolicy{
+		DrainTimeout: bc.GetCluster().GetDrainTimeout().AsDuration(),
+	}
+	
+	archivePolicy := &biz.ArchivePolicy{
+		OfflineAfter: time.Duration(bc.GetArchive().GetOfflineDays()) * 24 * time.Hour,
+		Interval:     bc.GetArchive().GetInterval().AsDuration(),
+	}
+	
+	retentionPolicy := &biz.RetentionPolicy{
+		InstructMaxAge: time.Duration(bc.GetRetention().GetInstructDays()) * 24 * time.Hour,
+		Archive:        bc.GetRetention().GetArchive(),
+		ReplyMaxAge:    time.Duration(bc.GetRetention().GetReplyDays()) * 24 * time.Hour,
+		MaxReplySize:   int(bc.GetRetention().GetMaxReplySize()),
+		ResultMaxAge:   time.Duration(bc.GetRetention().GetResultDays()) * 24 * time.Hour,
+		Interval:       bc.GetRetention().GetInterval().AsDuration(),
+	}
+	
+	webhookPolicy := &biz.WebhookPolicy{}
+	for _, hook := range bc.GetWebhooks() {
+		webhook, err := biz.ParseWebhook(hook.GetUrl(), hook.GetSecret(), biz.EventFilter{
+			OrgUuid:      hook.GetOrgUuid(),
+			GroupUuid:    hook.GetGroupUuid(),
+			InstanceName: hook.GetInstanceName(),
+			Types:        hook.GetEvents(),
+		}, hook.GetTimeout().AsDuration())
+		if err != nil {
+			logger.Error("webhook 配置异常", zap.String("url", hook.GetUrl()), zap.Error(err))
+			return
+		}
+		webhookPolicy.Webhooks = append(webhookPolicy.Webhooks, webhook)
+	}
+	
+	app, clean, err := initApp(logger, bc.Data, shellPolicy, tunnelPolicy, framePolicy, heartbeatPolicy, clusterPolicy, archivePolicy, retentionPolicy, webhookPolicy)
 	defer clean()
 	
 	if err != nil {
@@ -65,7 +160,43 @@ func main() {
 		return
 	}
 	
+	// 标记所在 commander 节点异常退出的实例离线
+	reapCtx, reapCancel := context.WithCancel(context.Background())
+	defer reapCancel()
+	go app.presence.Reap(reapCtx)
+	
+	// 清理已删除的组织及分组下的实例及指令记录
+	go app.org.Cleanup(reapCtx)
+	
+	// 归档离线超时的实例
+	go app.archive.Reap(reapCtx)
+	
+	// 按保留策略清理指令记录
+	go app.archive.Prune(reapCtx)
+	
+	// 登记节点并接收其他节点转发的指令通知
+	clusterCtx, clusterCancel := context.WithCancel(context.Background())
+	defer clusterCancel()
+	go app.cluster.Run(clusterCtx)
+	logger.Info("commander 节点", zap.String("node", app.cluster.Node()))
+	
+	// 接收所有节点发布的指令事件，推送给订阅者并投递 webhook
+	go app.event.Run(clusterCtx)
+	
+	// 未配置身份签名密钥时不信任 X-Camp-Role，终端及端口转发不可用
+	identityVerifier := middleware.NewIdentityVerifier(bc.GetServer().GetIdentity().GetSecret(), bc.GetServer().GetIdentity().GetMaxSkew().AsDuration())
+	if identityVerifier == nil && len(shellPolicy.AllowedRoles)+len(tunnelPolicy.AllowedRoles) > 0 {
+		logger.Warn("未配置 server.identity.secret，禁止打开终端及端口转发")
+	}
+	
+	lis, err := net.Listen("tcp", bc.Server.Http.Addr)
+	if err != nil {
+		logger.Error("监听HTTP地址失败", zap.Error(err))
+		return
+	}
+	
 	g := gin.New()
+	g.Use(middleware.OpenTelemetry(), middleware.Recording(logger), middleware.Identity(identityVerifier))
 	
 	g.GET("connect", app.service.Connect)
 	g.GET("instance/alive", app.service.ListAliveInstance)
@@ -74,7 +205,99 @@ func main() {
 	g.GET("instruct", app.service.ListInstruct)
 	g.GET("instruct/:uuid", app.service.GetInstruct)
 	
-	err = g.Run(bc.Server.Http.Addr)
+	// api/camp/v1 定义的接口，未匹配 gin 路由的请求由生成的 handler 处理
+	g.NoRoute(service.CommanderHTTPHandler(service.NewCommanderHTTPServer(lis, app.commander)))
+	g.POST("/v1/blob", app.service.UploadBlob)
+	g.GET("/v1/instruct/:uuid/file", app.service.DownloadFile)
+	g.GET("/v1/instance/shell", app.service.Shell)
+	g.GET("/v1/instance/tunnel", app.service.Tunnel)
+	g.GET("/v1/events", app.service.Events)
+	
+	if bc.Server.GetGrpc().GetAddr() != "" {
+		grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(middleware.UnaryServerTracing(logger), middleware.UnaryServerOperator(identityVerifier)))
+		v1.RegisterCommanderServer(grpcServer, app.commander)
+		
+		lis, err := net.Listen(grpcNetwork(bc.Server.GetGrpc().GetNetwork()), bc.Server.GetGrpc().GetAddr())
+		if err != nil {
+			logger.Error("监听gRPC地址失败", zap.Error(err))
+			return
+		}
+		defer grpcServer.GracefulStop()
+		
+		go func() {
+			logger.Info("启动gRPC服务", zap.String("addr", bc.Server.GetGrpc().GetAddr()))
+			err := grpcServer.Serve(lis)
+			if err != nil {
+				logger.Error("gRPC服务异常", zap.Error(err))
+			}
+		}()
+	}
+	
+	// 登记到 etcd，下线时先注销，不再接收新的流量
+	var etcdRegistry *registry.EtcdRegistry
+	if addrs := bc.GetRegistry().GetEtcd().GetAddress(); len(addrs) > 0 {
+		etcdRegistry = registry.NewEtcdRegistry(addrs, bc.GetRegistry().GetEtcd().GetPrefix(), bc.GetRegistry().GetEtcd().GetTtl().AsDuration())
+		instance := registry.Instance{
+			Id:        app.cluster.Node(),
+			Name:      "camp-commander",
+			Version:   Version,
+			Endpoints: []string{registry.Endpoint("http", bc.Server.Http.Addr)},
+		}
+		if bc.Server.GetGrpc().GetAddr() != "" {
+			instance.Endpoints = append(instance.Endpoints, registry.Endpoint("grpc", bc.Server.GetGrpc().GetAddr()))
+		}
+		
+		err = etcdRegistry.Register(clusterCtx, instance)
+		if err != nil {
+			logger.Error("登记节点到etcd失败", zap.Error(err))
+			return
+		}
+	}
+	
+	server := &http.Server{Handler: g}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(lis)
+	}()
 	
-	logger.Error("程序异常", zap.Error(err))
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	
+	select {
+	case err = <-serveErr:
+		logger.Error("程序异常", zap.Error(err))
+		return
+	case sig := <-quit:
+		logger.Info("接收到退出信号，节点下线", zap.String("signal", sig.String()))
+	}
+	
+	if etcdRegistry != nil {
+		err = etcdRegistry.Deregister(context.Background())
+		if err != nil {
+			logger.Error("从etcd注销节点失败", zap.Error(err))
+		}
+	}
+	
+	// 分批关闭 soldier 连接，soldier 重连到其他节点后恢复会话，再次收到信号时立即退出
+	drainCtx, drainCancel := context.WithCancel(context.Background())
+	defer drainCancel()
+	go func() {
+		<-quit
+		drainCancel()
+	}()
+	app.cluster.Drain(drainCtx)
+	
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	err = server.Shutdown(shutdownCtx)
+	if err != nil {
+		logger.Error("关闭HTTP服务失败", zap.Error(err))
+	}
+}
+
+func grpcNetwork(network string) string {
+	if network == "" {
+		return "tcp"
+	}
+	return network
 }
@@ -12,6 +12,6 @@ import (
 	"go.uber.org/zap"
 )
 
-func initApp(logger *zap.Logger, data2 *conf.Data) (*app, func(), error) {
+func initApp(logger *zap.Logger, data2 *conf.Data, shellPolicy *biz.ShellPolicy, tunnelPolicy *biz.TunnelPolicy, framePolicy *biz.FramePolicy, heartbeatPolicy *biz.HeartbeatPolicy, clusterPolicy *biz.ClusterPolicy, archivePolicy *biz.ArchivePolicy, retentionPolicy *biz.RetentionPolicy, webhookPolicy *biz.WebhookPolicy) (*app, func(), error) {
 	panic(wire.Build(data.ProviderSet, biz.ProviderSet, service.ProviderSet, newApp))
 }
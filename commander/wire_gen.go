@@ -16,18 +16,48 @@ import (
 
 // Injectors from wire.go:
 
-func initApp(logger *zap.Logger, data2 *conf.Data) (*app, func(), error) {
+func initApp(logger *zap.Logger, data2 *conf.Data, shellPolicy *biz.ShellPolicy, tunnelPolicy *biz.TunnelPolicy, framePolicy *biz.FramePolicy, heartbeatPolicy *biz.HeartbeatPolicy, clusterPolicy *biz.ClusterPolicy, archivePolicy *biz.ArchivePolicy, retentionPolicy *biz.RetentionPolicy, webhookPolicy *biz.WebhookPolicy) (*app, func(), error) {
 	dataData, cleanup, err := data.NewData(data2, logger)
 	if err != nil {
 		return nil, nil, err
 	}
 	instructRepo := data.NewInstructDataSource(dataData)
 	instanceRepo := data.NewInstanceDataSource(dataData)
-	messageUseCase := biz.NewMessageUseCase(logger, instructRepo, instanceRepo)
-	instructUseCase := biz.NewInstructUseCase(instructRepo, logger)
-	instanceUseCase := biz.NewInstanceUseCase(instanceRepo, logger)
-	useCase := service.NewUseCase(logger, messageUseCase, instructUseCase, instanceUseCase)
-	mainApp := newApp(useCase)
+	metricsRepo := data.NewMetricsDataSource(dataData)
+	blobRepo, err := data.NewBlobDataSource(data2, dataData)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	connRegistry := biz.NewConnRegistry()
+	shellUseCase := biz.NewShellUseCase(shellPolicy, connRegistry, instructRepo, blobRepo, logger)
+	tunnelUseCase := biz.NewTunnelUseCase(tunnelPolicy, connRegistry, instructRepo, logger)
+	sessionRepo := data.NewSessionDataSource(dataData)
+	eventRepo := data.NewEventDataSource(dataData)
+	resultRepo := data.NewResultDataSource(dataData)
+	eventUseCase := biz.NewEventUseCase(eventRepo, instructRepo, resultRepo, webhookPolicy, logger)
+	sessionUseCase := biz.NewSessionUseCase(sessionRepo, instructRepo, eventUseCase, logger)
+	presenceRepo := data.NewPresenceDataSource(dataData)
+	connectionRepo := data.NewConnectionDataSource(dataData)
+	node := biz.NewNode()
+	presenceUseCase := biz.NewPresenceUseCase(presenceRepo, instanceRepo, connectionRepo, heartbeatPolicy, node, logger)
+	clusterRepo := data.NewClusterDataSource(dataData)
+	clusterUseCase := biz.NewClusterUseCase(clusterRepo, presenceRepo, instanceRepo, connRegistry, node, clusterPolicy, logger)
+	messageUseCase := biz.NewMessageUseCase(logger, instructRepo, instanceRepo, metricsRepo, blobRepo, resultRepo, shellUseCase, tunnelUseCase, sessionUseCase, eventUseCase)
+	orgRepo := data.NewOrgDataSource(dataData)
+	instructUseCase := biz.NewInstructUseCase(instructRepo, blobRepo, orgRepo, sessionUseCase, clusterUseCase, eventUseCase, logger)
+	labelRepo := data.NewLabelDataSource(dataData)
+	instanceUseCase := biz.NewInstanceUseCase(instanceRepo, presenceRepo, connectionRepo, labelRepo, logger)
+	fileUseCase := biz.NewFileUseCase(blobRepo, instructRepo, logger)
+	archiveRepo := data.NewArchiveDataSource(dataData)
+	orgUseCase := biz.NewOrgUseCase(orgRepo, instanceRepo, instructRepo, labelRepo, connectionRepo, sessionRepo, archiveRepo, resultRepo, blobRepo, clusterUseCase, node, logger)
+	archiveUseCase := biz.NewArchiveUseCase(archiveRepo, instanceRepo, instructRepo, sessionRepo, labelRepo, clusterUseCase, archivePolicy, retentionPolicy, node, logger)
+	useCase := service.NewUseCase(logger, messageUseCase, instructUseCase, instanceUseCase, fileUseCase, connRegistry, shellUseCase, tunnelUseCase, sessionUseCase, presenceUseCase, clusterUseCase, orgUseCase, eventUseCase, framePolicy, heartbeatPolicy)
+	metricsUseCase := biz.NewMetricsUseCase(metricsRepo, instanceRepo, logger)
+	resultUseCase := biz.NewResultUseCase(resultRepo, logger)
+	batchUseCase := biz.NewBatchUseCase(instructUseCase, instanceRepo, instructRepo, resultRepo, labelRepo, logger)
+	commanderService := service.NewCommanderService(logger, instructUseCase, instanceUseCase, metricsUseCase, presenceUseCase, clusterUseCase, orgUseCase, archiveUseCase, resultUseCase, batchUseCase)
+	mainApp := newApp(useCase, commanderService, presenceUseCase, clusterUseCase, orgUseCase, archiveUseCase, eventUseCase)
 	return mainApp, func() {
 		cleanup()
 	}, nil
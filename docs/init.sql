@@ -19,10 +19,46 @@ create table if not exists instruct
     reply         longtext comment '指令返回内容',
     create_time   bigint,
     update_time   bigint,
-    key type (type)
+    truncated     tinyint(1) default 0 comment '返回内容超过大小上限或保留策略的大小已被截断',
+    operator      varchar(100) comment '操作人',
+    batch_uuid    varchar(48) comment '批量下发的批次',
+    key type (type),
+    key org_group_instance (org_uuid, group_uuid, instance_name),
+    key create_time (create_time, uuid),
+    key operator (operator),
+    key batch_uuid (batch_uuid)
 ) comment '指令';
 
 
+drop table if exists org;
+create table if not exists org
+(
+    uuid        varchar(48) primary key comment 'uuid',
+    name        varchar(150) comment '名称',
+    description varchar(500) comment '描述',
+    status      varchar(20) comment '状态，active / deleting: 清理中 / deleted',
+    create_time bigint comment '创建时间',
+    update_time bigint comment '更新时间',
+    key status (status)
+) comment '组织';
+
+
+drop table if exists instance_group;
+create table if not exists instance_group
+(
+    org_uuid    varchar(48) comment '组织uuid',
+    uuid        varchar(48) comment '组uuid',
+    name        varchar(150) comment '名称',
+    description varchar(500) comment '描述',
+    settings    text comment '分组默认配置 (json)，指令执行超时、icmp发送次数及命令策略',
+    status      varchar(20) comment '状态，active / deleting: 清理中 / deleted',
+    create_time bigint comment '创建时间',
+    update_time bigint comment '更新时间',
+    primary key (org_uuid, uuid),
+    key status (status)
+) comment '实例分组';
+
+
 drop table if exists instance;
 create table if not exists instance
 (
@@ -32,7 +68,121 @@ create table if not exists instance
     instance_name varchar(150) comment '实例名',
     client_ip     varchar(20) comment '客户端IP',
     create_time   bigint comment '创建时间',
-    update_time   bigint comment '更新时间',
-    unique key org_group_instance (org_uuid, group_uuid, instance_name)
+    update_time   bigint comment '更新时间 (上线、离线时更新)',
+    online        tinyint(1) default 0 comment '是否在线，仅在上线、离线时更新，连接状态保存在 redis',
+    agent_version varchar(50) comment 'soldier版本',
+    agent_info    text comment 'soldier版本及能力信息 (json)',
+    inventory     text comment '主机信息 (json)',
+    os            varchar(20) comment '操作系统，取自主机信息',
+    platform      varchar(50) comment '发行版，取自主机信息',
+    unique key org_group_instance (org_uuid, group_uuid, instance_name),
+    key online (online)
 ) comment '实例';
 
+
+drop table if exists instance_label;
+create table if not exists instance_label
+(
+    instance_uuid varchar(48) comment '实例uuid',
+    label_key     varchar(63) comment '标签key',
+    label_value   varchar(63) comment '标签value',
+    source        varchar(20) comment '来源，agent: soldier上报，operator: 运维人员设置',
+    primary key (instance_uuid, label_key),
+    key label_key_value (label_key, label_value)
+) comment '实例标签';
+
+
+drop table if exists instance_connection;
+create table if not exists instance_connection
+(
+    id                varchar(48) primary key comment '连接id',
+    instance_uuid     varchar(48) comment '实例uuid',
+    org_uuid          varchar(48) comment '组织uuid',
+    group_uuid        varchar(48) comment '组uuid',
+    instance_name     varchar(150) comment '实例名',
+    client_ip         varchar(50) comment '客户端IP',
+    agent_version     varchar(50) comment 'soldier版本',
+    node              varchar(100) comment '处理该连接的commander节点',
+    connect_time      bigint comment '连接时间',
+    disconnect_time   bigint default 0 comment '断开时间，0表示未断开',
+    disconnect_reason varchar(20) comment '断开原因',
+    key instance_connect_time (instance_uuid, connect_time),
+    key instance_disconnect_time (instance_uuid, disconnect_time)
+) comment '实例连接记录';
+
+
+drop table if exists instance_archive;
+create table if not exists instance_archive
+(
+    uuid          varchar(48) primary key comment 'uuid',
+    org_uuid      varchar(48) comment '组织uuid',
+    group_uuid    varchar(48) comment '组uuid',
+    instance_name varchar(150) comment '实例名',
+    client_ip     varchar(20) comment '客户端IP',
+    agent_version varchar(50) comment 'soldier版本',
+    inventory     text comment '主机信息 (json)',
+    labels        text comment '标签 (json)',
+    create_time   bigint comment '创建时间',
+    update_time   bigint comment '归档前最后一次上线或离线的时间',
+    reason        varchar(20) comment '归档原因，decommissioned: 通过接口下线，stale: 离线超时',
+    archive_time  bigint comment '归档时间',
+    key org_group_instance (org_uuid, group_uuid, instance_name)
+) comment '已归档的实例';
+
+
+drop table if exists instruct_archive;
+create table if not exists instruct_archive
+(
+    uuid          varchar(48) primary key comment 'uuid',
+    org_uuid      varchar(48) comment 'uuid',
+    group_uuid    varchar(48) comment 'uuid',
+    instance_name varchar(150),
+    `type`        int comment '类型',
+    content       text comment '指令内容',
+    result        int comment '结果，0执行中，-1失败，1成功',
+    reply         longtext comment '指令返回内容',
+    create_time   bigint,
+    update_time   bigint,
+    truncated     tinyint(1) default 0 comment '返回内容超过大小上限或保留策略的大小已被截断',
+    operator      varchar(100) comment '操作人',
+    batch_uuid    varchar(48) comment '批量下发的批次',
+    archive_time  bigint comment '归档时间',
+    key org_group_instance (org_uuid, group_uuid, instance_name)
+) comment '已归档的指令';
+
+
+drop table if exists instruct_result;
+create table if not exists instruct_result
+(
+    instruct_uuid  varchar(48) primary key comment '指令uuid',
+    instance_uuid  varchar(48) comment '实例uuid',
+    org_uuid       varchar(48) comment 'uuid',
+    group_uuid     varchar(48) comment 'uuid',
+    instance_name  varchar(150),
+    batch_uuid     varchar(48) comment '批量下发的批次',
+    `type`         int comment '指令类型: 2 ChromeDp, 3 Dns, 4 Http, 5 Icmp',
+    schema_version int comment 'detail 的格式版本',
+    target         varchar(500) comment '探测目标: 域名、url 或地址',
+    result         int comment '结果，-1失败，1成功',
+    status_code    int comment 'HTTP 状态码，ChromeDp 主页面状态码',
+    latency        double comment '耗时 (毫秒)',
+    packet_loss    double comment 'ICMP 丢包率 (%)',
+    addrs          json comment 'DNS 解析结果，ICMP 及 ChromeDp 主页面的远端地址',
+    detail         json comment '完整结果',
+    err_msg        text comment '失败原因',
+    create_time    bigint comment '收到结果的时间',
+    key type_target (`type`, target(191), create_time),
+    key org_group_instance (org_uuid, group_uuid, instance_name),
+    key batch_uuid (batch_uuid),
+    key create_time (create_time, instruct_uuid)
+) comment '探测指令的结构化结果';
+
+
+drop table if exists `blob`;
+create table if not exists `blob`
+(
+    uuid        varchar(48) primary key comment 'uuid，文件获取指令使用指令uuid',
+    size        bigint comment '文件大小 (字节)',
+    sha256      varchar(64) comment '文件sha256',
+    create_time bigint comment '创建时间'
+) comment '文件';
@@ -0,0 +1,238 @@
+package biz
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"time"
+)
+
+// 实例下线及归档
+//
+// 实例 (如每次启动生成新实例名的 app) 不再使用时通过 Decommission 下线: 断开连接、删除会话 (soldier 携带的会话 token 失效) 及指令队列，并归档实例及指令记录；
+// 配置 ArchivePolicy.OfflineAfter 后，由 Reap 定时归档离线超过该时间的实例。
+// 归档时指令记录移入 instruct_archive，实例 (含标签) 移入 instance_archive，实例的连接记录删除
+
+const (
+	ArchiveDecommissioned = "decommissioned" // 通过接口下线
+	ArchiveStale          = "stale"          // 离线超时
+	
+	DefaultArchiveInterval = time.Hour
+	
+	archiveBatch = 500 // 每次归档的指令记录数量，及每轮归档的实例数量
+)
+
+type InstanceArchive struct {
+	Uuid         string            `json:"uuid" gorm:"primaryKey"`
+	OrgUuid      string            `json:"orgUuid"`
+	GroupUuid    string            `json:"groupUuid"`
+	InstanceName string            `json:"instanceName"`
+	ClientIp     string            `json:"clientIp"`
+	AgentVersion string            `json:"agentVersion"`
+	Inventory    *HostInventory    `json:"inventory,omitempty" gorm:"serializer:json"`
+	Labels       map[string]string `json:"labels,omitempty" gorm:"serializer:json"`
+	CreateTime   int64             `json:"createTime"`
+	UpdateTime   int64             `json:"updateTime"` // 归档前最后一次上线或离线的时间
+	Reason       string            `json:"reason"`
+	ArchiveTime  int64             `json:"archiveTime"`
+}
+
+func (instanceArchive *InstanceArchive) TableName() string {
+	return "instance_archive"
+}
+
+type ArchiveRepo interface {
+	// 将实例的指令记录移入归档表，每次最多 limit 条，返回移动的数量
+	ArchiveInstructs(ctx context.Context, orgUuid, groupUuid, instanceName string, archiveTime int64, limit int) (int64, error)
+	// 写入实例归档并删除实例、标签及连接记录；offlineBefore 大于 0 时仅在实例仍离线且离线时间早于该时间时归档，返回是否已归档
+	ArchiveInstance(ctx context.Context, archive InstanceArchive, offlineBefore int64) (bool, error)
+	AcquireArchiveLock(ctx context.Context, node string, ttl time.Duration) (bool, error)
+	// 删除分组下的指令归档及实例归档，每次各最多 limit 条，返回删除的数量
+	DeleteGroupInstructArchives(ctx context.Context, orgUuid, groupUuid string, limit int) (int64, error)
+	DeleteGroupInstanceArchives(ctx context.Context, orgUuid, groupUuid string, limit int) (int64, error)
+	
+	// 将创建时间早于 createdBefore 的已结束指令记录移入归档表或删除，每次最多 limit 条
+	ArchiveInstructsBefore(ctx context.Context, createdBefore, archiveTime int64, limit int) (int64, error)
+	DeleteInstructsBefore(ctx context.Context, createdBefore int64, limit int) (int64, error)
+	// 将创建时间早于 createdBefore 且结果超过 maxSize 的已结束指令截断，每次最多 limit 条
+	TruncateReplies(ctx context.Context, createdBefore int64, maxSize, limit int) (int64, error)
+	// 删除早于 createdBefore 的探测结果，每次最多 limit 条
+	DeleteResultsBefore(ctx context.Context, createdBefore int64, limit int) (int64, error)
+	AcquireRetentionLock(ctx context.Context, node string, ttl time.Duration) (bool, error)
+}
+
+type ArchivePolicy struct {
+	OfflineAfter time.Duration // 离线超过该时间的实例归档，为 0 时不归档
+	Interval     time.Duration // 检查间隔，默认 1 小时
+}
+
+func (archivePolicy *ArchivePolicy) Enabled() bool {
+	return archivePolicy.OfflineAfter > 0
+}
+
+func (archivePolicy *ArchivePolicy) interval() time.Duration {
+	if archivePolicy.Interval <= 0 {
+		return DefaultArchiveInterval
+	}
+	return archivePolicy.Interval
+}
+
+type ArchiveUseCase struct {
+	archiveRepo    ArchiveRepo
+	instanceRepo   InstanceRepo
+	instructRepo   InstructRepo
+	sessionRepo    SessionRepo
+	labelRepo      LabelRepo
+	clusterUseCase *ClusterUseCase
+	policy         *ArchivePolicy
+	retention      *RetentionPolicy
+	node           *Node
+	logger         *zap.Logger
+}
+
+func NewArchiveUseCase(archiveRepo ArchiveRepo, instanceRepo InstanceRepo, instructRepo InstructRepo, sessionRepo SessionRepo, labelRepo LabelRepo, clusterUseCase *ClusterUseCase, policy *ArchivePolicy, retention *RetentionPolicy, node *Node, logger *zap.Logger) *ArchiveUseCase {
+	return &ArchiveUseCase{
+		archiveRepo:    archiveRepo,
+		instanceRepo:   instanceRepo,
+		instructRepo:   instructRepo,
+		sessionRepo:    sessionRepo,
+		labelRepo:      labelRepo,
+		clusterUseCase: clusterUseCase,
+		policy:         policy,
+		retention:      retention,
+		node:           node,
+		logger:         logger,
+	}
+}
+
+// 下线实例，返回归档的指令记录数量
+
+func (archiveUseCase *ArchiveUseCase) Decommission(ctx context.Context, orgUuid, groupUuid, instanceName string) (int64, error) {
+	instance, err := archiveUseCase.instanceRepo.Get(ctx, orgUuid, groupUuid, instanceName)
+	if err != nil {
+		return 0, err
+	}
+	
+	if instance.Online {
+		archiveUseCase.clusterUseCase.Route(ctx, NodeMessage{Type: NodeRemove, InstanceUuid: instance.Uuid})
+	}
+	
+	archived, _, err := archiveUseCase.archive(ctx, instance, ArchiveDecommissioned, 0)
+	if err != nil {
+		return archived, err
+	}
+	
+	archiveUseCase.logger.Info("实例已下线", zap.String("uuid", instance.Uuid), zap.String("instanceName", instanceName), zap.Int64("instructs", archived))
+	return archived, nil
+}
+
+// 定时归档离线超时的实例；未配置 OfflineAfter 时直接返回，多个节点时仅获得锁的节点执行
+
+func (archiveUseCase *ArchiveUseCase) Reap(ctx context.Context) {
+	if !archiveUseCase.policy.Enabled() {
+		return
+	}
+	
+	ticker := time.NewTicker(archiveUseCase.policy.interval())
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ticker.C:
+			locked, err := archiveUseCase.archiveRepo.AcquireArchiveLock(ctx, archiveUseCase.node.Id, archiveUseCase.policy.interval())
+			if err != nil {
+				archiveUseCase.logger.Error("获取实例归档锁失败", zap.Error(err))
+				continue
+			}
+			
+			if locked {
+				archiveUseCase.reap(ctx)
+			}
+		
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// 每轮最多归档 archiveBatch 个实例，剩余的在下一轮归档
+
+func (archiveUseCase *ArchiveUseCase) reap(ctx context.Context) {
+	offline := false
+	offlineBefore := time.Now().Add(-archiveUseCase.policy.OfflineAfter).Unix()
+	instances, _, err := archiveUseCase.instanceRepo.Search(ctx, InstanceQuery{Online: &offline, UpdatedBefore: offlineBefore, Limit: archiveBatch})
+	if err != nil {
+		archiveUseCase.logger.Error("列出离线实例失败", zap.Error(err))
+		return
+	}
+	
+	count := 0
+	for _, instance := range instances {
+		_, ok, err := archiveUseCase.archive(ctx, instance, ArchiveStale, offlineBefore)
+		if err != nil {
+			archiveUseCase.logger.Error("归档实例失败", zap.String("uuid", instance.Uuid), zap.Error(err))
+			continue
+		}
+		
+		if ok {
+			count++
+		}
+	}
+	
+	if count > 0 {
+		archiveUseCase.logger.Info("归档离线实例", zap.Int("count", count))
+	}
+}
+
+// 先归档实例，offlineBefore 大于 0 时仅在实例仍离线且未更新时归档，列出实例后重新上线的不归档；
+// 实例已归档后删除会话及指令队列并归档指令记录，返回归档的指令记录数量及实例是否已归档
+
+func (archiveUseCase *ArchiveUseCase) archive(ctx context.Context, instance Instance, reason string, offlineBefore int64) (int64, bool, error) {
+	labels, err := archiveUseCase.labelRepo.ListLabels(ctx, []string{instance.Uuid})
+	if err != nil {
+		return 0, false, err
+	}
+	
+	now := time.Now().Unix()
+	ok, err := archiveUseCase.archiveRepo.ArchiveInstance(ctx, InstanceArchive{
+		Uuid:         instance.Uuid,
+		OrgUuid:      instance.OrgUuid,
+		GroupUuid:    instance.GroupUuid,
+		InstanceName: instance.InstanceName,
+		ClientIp:     instance.ClientIp,
+		AgentVersion: instance.AgentVersion,
+		Inventory:    instance.Inventory,
+		Labels:       labels[instance.Uuid],
+		CreateTime:   instance.CreateTime,
+		UpdateTime:   instance.UpdateTime,
+		Reason:       reason,
+		ArchiveTime:  now,
+	}, offlineBefore)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	
+	err = archiveUseCase.sessionRepo.RevokeInstanceSession(ctx, instance.Uuid)
+	if err != nil {
+		return 0, true, err
+	}
+	
+	err = archiveUseCase.instructRepo.DeleteInstructQueue(ctx, instance.OrgUuid, instance.GroupUuid, instance.InstanceName)
+	if err != nil {
+		return 0, true, err
+	}
+	
+	var archived int64
+	for {
+		count, err := archiveUseCase.archiveRepo.ArchiveInstructs(ctx, instance.OrgUuid, instance.GroupUuid, instance.InstanceName, now, archiveBatch)
+		if err != nil {
+			return archived, true, err
+		}
+		
+		archived += count
+		if count < archiveBatch {
+			break
+		}
+	}
+	
+	return archived, true, nil
+}
@@ -0,0 +1,137 @@
+package biz
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func newTestArchiveUseCase(archiveRepo ArchiveRepo, instanceRepo *fakeInstanceRepo, instructRepo *fakeInstructRepo, sessionRepo SessionRepo, policy *ArchivePolicy, retention *RetentionPolicy) *ArchiveUseCase {
+	node := &Node{Id: "node1"}
+	clusterUseCase := NewClusterUseCase(newFakeClusterRepo(), newFakePresenceRepo(), instanceRepo, NewConnRegistry(), node, &ClusterPolicy{}, zap.NewNop())
+	return NewArchiveUseCase(archiveRepo, instanceRepo, instructRepo, sessionRepo, &fakeLabelRepo{}, clusterUseCase, policy, retention, node, zap.NewNop())
+}
+
+// 下线实例后会话失效，实例及指令记录移入归档
+
+func TestArchiveUseCase_Decommission(t *testing.T) {
+	ctx := context.Background()
+	instance := Instance{Uuid: "i1", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "n1", Online: true}
+	instanceRepo := newFakeInstanceRepo(instance)
+	instructRepo := &fakeInstructRepo{instruct: map[string]Instruct{
+		"a": {Uuid: "a", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "n1"},
+		"b": {Uuid: "b", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "n1"},
+		"c": {Uuid: "c", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "n2"},
+	}}
+	sessionRepo := newFakeSessionRepo()
+	archiveRepo := newFakeArchiveRepo(instanceRepo, instructRepo)
+	archiveUseCase := newTestArchiveUseCase(archiveRepo, instanceRepo, instructRepo, sessionRepo, &ArchivePolicy{}, &RetentionPolicy{})
+	
+	eventUseCase, _ := newTestEventUseCase(instructRepo, &WebhookPolicy{})
+	session, err := NewSessionUseCase(sessionRepo, instructRepo, eventUseCase, zap.NewNop()).Open(ctx, instance, "")
+	assert.NoError(t, err)
+	
+	archived, err := archiveUseCase.Decommission(ctx, "o1", "g1", "n1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), archived)
+	
+	owner, err := sessionRepo.GetSession(ctx, session.Token)
+	assert.NoError(t, err)
+	assert.Empty(t, owner)
+	
+	assert.Equal(t, ArchiveDecommissioned, archiveRepo.instances[instance.Uuid].Reason)
+	assert.Len(t, archiveRepo.instructs, 2)
+	assert.Len(t, instructRepo.instruct, 1)
+	
+	_, err = archiveUseCase.Decommission(ctx, "o1", "g1", "n1")
+	assert.Error(t, err)
+}
+
+// 仅归档离线超过 OfflineAfter 的实例
+
+func TestArchiveUseCase_Reap(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	stale := Instance{Uuid: "i1", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "stale", UpdateTime: now.Add(-48 * time.Hour).Unix()}
+	recent := Instance{Uuid: "i2", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "recent", UpdateTime: now.Add(-time.Hour).Unix()}
+	online := Instance{Uuid: "i3", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "online", UpdateTime: now.Add(-48 * time.Hour).Unix(), Online: true}
+	instanceRepo := newFakeInstanceRepo(stale, recent, online)
+	instructRepo := &fakeInstructRepo{instruct: map[string]Instruct{
+		"a": {Uuid: "a", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "stale"},
+		"b": {Uuid: "b", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "recent"},
+	}}
+	archiveRepo := newFakeArchiveRepo(instanceRepo, instructRepo)
+	archiveUseCase := newTestArchiveUseCase(archiveRepo, instanceRepo, instructRepo, newFakeSessionRepo(), &ArchivePolicy{OfflineAfter: 24 * time.Hour}, &RetentionPolicy{})
+	
+	archiveUseCase.reap(ctx)
+	
+	assert.Len(t, archiveRepo.instances, 1)
+	assert.Equal(t, ArchiveStale, archiveRepo.instances[stale.Uuid].Reason)
+	assert.Contains(t, archiveRepo.instructs, "a")
+	assert.Contains(t, instructRepo.instruct, "b")
+	assert.Len(t, instanceRepo.instances, 2)
+}
+
+// 列出离线实例后实例重新上线，不归档实例，会话、指令队列及指令记录保持不变
+
+func TestArchiveUseCase_ReapRace(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	stale := Instance{Uuid: "i1", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "n1", UpdateTime: now.Add(-48 * time.Hour).Unix()}
+	instanceRepo := newFakeInstanceRepo(stale)
+	instructRepo := &fakeInstructRepo{instruct: map[string]Instruct{
+		"a": {Uuid: "a", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "n1"},
+	}}
+	sessionRepo := newFakeSessionRepo()
+	archiveRepo := newFakeArchiveRepo(instanceRepo, instructRepo)
+	archiveUseCase := newTestArchiveUseCase(archiveRepo, instanceRepo, instructRepo, sessionRepo, &ArchivePolicy{OfflineAfter: 24 * time.Hour}, &RetentionPolicy{})
+	
+	eventUseCase, _ := newTestEventUseCase(instructRepo, &WebhookPolicy{})
+	session, err := NewSessionUseCase(sessionRepo, instructRepo, eventUseCase, zap.NewNop()).Open(ctx, stale, "")
+	assert.NoError(t, err)
+	
+	// 实例在归档前重新上线
+	assert.NoError(t, instanceRepo.Online(ctx, stale.Uuid))
+	
+	archived, ok, err := archiveUseCase.archive(ctx, stale, ArchiveStale, now.Add(-24*time.Hour).Unix())
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, int64(0), archived)
+	
+	owner, err := sessionRepo.GetSession(ctx, session.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, stale.Uuid, owner)
+	assert.Contains(t, instructRepo.instruct, "a")
+	assert.Empty(t, archiveRepo.instances)
+	assert.Empty(t, archiveRepo.instructs)
+}
+
+// 超过保留时间的指令记录移入归档，超过保留时间的大结果截断
+
+func TestArchiveUseCase_Prune(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	instructRepo := &fakeInstructRepo{instruct: map[string]Instruct{
+		"old":     {Uuid: "old", Result: 1, Reply: "ok", CreateTime: now.Add(-40 * 24 * time.Hour).Unix()},
+		"stuck":   {Uuid: "stuck", Result: 0, CreateTime: now.Add(-40 * 24 * time.Hour).Unix()},
+		"large":   {Uuid: "large", Result: 1, Reply: "结果结果结果", CreateTime: now.Add(-10 * 24 * time.Hour).Unix()},
+		"running": {Uuid: "running", Result: 0, Reply: "结果结果结果", CreateTime: now.Add(-10 * 24 * time.Hour).Unix()},
+		"recent":  {Uuid: "recent", Result: 1, Reply: "结果结果结果", CreateTime: now.Unix()},
+	}}
+	archiveRepo := newFakeArchiveRepo(newFakeInstanceRepo(), instructRepo)
+	retention := &RetentionPolicy{InstructMaxAge: 30 * 24 * time.Hour, Archive: true, ReplyMaxAge: 7 * 24 * time.Hour, MaxReplySize: 2}
+	archiveUseCase := newTestArchiveUseCase(archiveRepo, newFakeInstanceRepo(), instructRepo, newFakeSessionRepo(), &ArchivePolicy{}, retention)
+	
+	archiveUseCase.prune(ctx)
+	
+	assert.Contains(t, archiveRepo.instructs, "old")
+	assert.NotContains(t, instructRepo.instruct, "old")
+	assert.Contains(t, instructRepo.instruct, "stuck")
+	assert.NotContains(t, archiveRepo.instructs, "stuck")
+	assert.Equal(t, "结果", instructRepo.instruct["large"].Reply)
+	assert.True(t, instructRepo.instruct["large"].Truncated)
+	assert.Equal(t, "结果结果结果", instructRepo.instruct["running"].Reply)
+	assert.Equal(t, "结果结果结果", instructRepo.instruct["recent"].Reply)
+}
@@ -0,0 +1,483 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"sort"
+	"strings"
+)
+
+// 批量探测及结果对比
+//
+// 将同一个探测指令下发到分组内的多个在线实例 (按实例名或标签选择器选择)，指令及结果记录批次 uuid；
+// 对比时以成功结果的多数值 (状态码、解析地址) 及中位数 (耗时、丢包率) 为基准，标记偏离基准的实例，
+// 并按实例标签 (如 region、isp) 统计偏离的实例数量
+
+const (
+	BatchMaxInstances = 500
+	
+	DeviationFailed     = "failed"     // 执行失败
+	DeviationStatus     = "status"     // 状态码与多数实例不同
+	DeviationAddrs      = "addrs"      // 解析地址与多数实例不同
+	DeviationLatency    = "latency"    // 耗时超过中位数的 latencyOutlierRatio 倍且至少多 latencyOutlierMin 毫秒
+	DeviationPacketLoss = "packetLoss" // 丢包率超过中位数 packetLossOutlier 个百分点
+	
+	latencyOutlierRatio = 2
+	latencyOutlierMin   = 20
+	packetLossOutlier   = 10
+)
+
+var (
+	ErrInvalidBatch        = errors.New("批量下发参数异常")
+	ErrBatchNotFound       = errors.New("批次不存在")
+	ErrInstanceOffline     = errors.New("实例不在线")
+	ErrUnsupportedInstruct = errors.New("实例不支持该指令类型")
+)
+
+// 批量下发的目标，InstanceNames 为空时选择分组内匹配 Selector 的全部在线实例
+
+type BatchTarget struct {
+	OrgUuid       string
+	GroupUuid     string
+	InstanceNames []string
+	Selector      []LabelRequirement
+}
+
+// 单个实例的下发结果，Err 不为空时未下发
+
+type BatchInstruct struct {
+	InstanceName string
+	Uuid         string
+	Err          error
+}
+
+type BatchComparison struct {
+	BatchUuid  string                `json:"batchUuid"`
+	Type       InstructType          `json:"type"`
+	Target     string                `json:"target"`
+	Total      int                   `json:"total"`
+	Running    int                   `json:"running"`
+	Success    int                   `json:"success"`
+	Failed     int                   `json:"failed"`
+	Deviated   int                   `json:"deviated"`
+	Baseline   BatchBaseline         `json:"baseline"`
+	Instances  []BatchInstanceResult `json:"instances"` // 偏离基准的实例在前
+	AddrGroups []AddrGroup           `json:"addrGroups,omitempty"`
+	Labels     []LabelDeviation      `json:"labels,omitempty"` // 存在偏离实例的标签，按偏离比例倒序
+}
+
+// 对比基准，取自成功的结果
+
+type BatchBaseline struct {
+	StatusCode int32    `json:"statusCode,omitempty"` // 最多实例的状态码
+	Addrs      []string `json:"addrs,omitempty"`      // 最多实例的解析地址
+	Latency    float64  `json:"latency,omitempty"`    // 耗时中位数
+	MinLatency float64  `json:"minLatency,omitempty"`
+	MaxLatency float64  `json:"maxLatency,omitempty"`
+	PacketLoss float64  `json:"packetLoss,omitempty"` // 丢包率中位数
+}
+
+type BatchInstanceResult struct {
+	InstructUuid string            `json:"instructUuid"`
+	InstanceUuid string            `json:"instanceUuid,omitempty"`
+	InstanceName string            `json:"instanceName"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	State        string            `json:"state"`
+	StatusCode   int32             `json:"statusCode,omitempty"`
+	Latency      float64           `json:"latency,omitempty"`
+	PacketLoss   float64           `json:"packetLoss,omitempty"`
+	Addrs        []string          `json:"addrs,omitempty"`
+	ErrMsg       string            `json:"errMsg,omitempty"`
+	Deviations   []string          `json:"deviations,omitempty"`
+}
+
+// 解析地址相同 (不区分顺序) 的实例
+
+type AddrGroup struct {
+	Addrs     []string `json:"addrs"`
+	Instances []string `json:"instances"`
+}
+
+type LabelDeviation struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Total    int    `json:"total"` // 有该标签且已结束的实例数量
+	Deviated int    `json:"deviated"`
+}
+
+type BatchUseCase struct {
+	instructUseCase *InstructUseCase
+	instanceRepo    InstanceRepo
+	instructRepo    InstructRepo
+	resultRepo      ResultRepo
+	labelRepo       LabelRepo
+	logger          *zap.Logger
+}
+
+func NewBatchUseCase(instructUseCase *InstructUseCase, instanceRepo InstanceRepo, instructRepo InstructRepo, resultRepo ResultRepo, labelRepo LabelRepo, logger *zap.Logger) *BatchUseCase {
+	return &BatchUseCase{
+		instructUseCase: instructUseCase,
+		instanceRepo:    instanceRepo,
+		instructRepo:    instructRepo,
+		resultRepo:      resultRepo,
+		labelRepo:       labelRepo,
+		logger:          logger,
+	}
+}
+
+// 将探测指令下发到目标实例，返回批次 uuid 及每个实例的下发结果；未选中任何在线实例时返回 ErrInvalidBatch
+
+func (batchUseCase *BatchUseCase) IssueBatch(ctx context.Context, target BatchTarget, instructType InstructType, content string, operator string) (string, []BatchInstruct, error) {
+	if target.OrgUuid == "" || target.GroupUuid == "" || content == "" {
+		return "", nil, fmt.Errorf("%w: 组织、分组及指令内容不能为空", ErrInvalidBatch)
+	}
+	
+	if !IsProbeInstruct(instructType) {
+		return "", nil, fmt.Errorf("%w: 仅支持探测指令 (ChromeDp / Dns / Http / Icmp)", ErrInvalidBatch)
+	}
+	
+	// 去除重复的实例名，避免同一实例重复下发
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range target.InstanceNames {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	
+	if len(names) > BatchMaxInstances {
+		return "", nil, fmt.Errorf("%w: 最多 %d 个实例", ErrInvalidBatch, BatchMaxInstances)
+	}
+	
+	online := true
+	instances, _, err := batchUseCase.instanceRepo.Search(ctx, InstanceQuery{
+		OrgUuid:       target.OrgUuid,
+		GroupUuid:     target.GroupUuid,
+		InstanceNames: names,
+		Online:        &online,
+		Selector:      target.Selector,
+		Limit:         BatchMaxInstances + 1,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	
+	if len(names) == 0 && len(instances) > BatchMaxInstances {
+		return "", nil, fmt.Errorf("%w: 匹配的在线实例超过 %d 个", ErrInvalidBatch, BatchMaxInstances)
+	}
+	
+	selected := make(map[string]Instance)
+	for _, instance := range instances {
+		selected[instance.InstanceName] = instance
+	}
+	
+	if len(names) == 0 {
+		for _, instance := range instances {
+			names = append(names, instance.InstanceName)
+		}
+	}
+	
+	batchUuid := uuid.NewString()
+	batchInstructs := make([]BatchInstruct, 0, len(names))
+	issued := 0
+	for _, name := range names {
+		instance, ok := selected[name]
+		if !ok {
+			batchInstructs = append(batchInstructs, BatchInstruct{InstanceName: name, Err: ErrInstanceOffline})
+			continue
+		}
+		
+		if !instance.SupportInstruct(instructType) {
+			batchInstructs = append(batchInstructs, BatchInstruct{InstanceName: name, Err: ErrUnsupportedInstruct})
+			continue
+		}
+		
+		instructUuid, err := batchUseCase.instructUseCase.issue(ctx, target.OrgUuid, target.GroupUuid, name, instructType, content, operator, batchUuid)
+		if err != nil {
+			batchUseCase.logger.Error("批量下发指令失败", zap.String("batchUuid", batchUuid), zap.String("instanceName", name), zap.Error(err))
+			
+			// 分组配置等错误对所有实例相同，不再继续下发
+			if errors.Is(err, ErrGroupDeleted) {
+				return "", nil, err
+			}
+			batchInstructs = append(batchInstructs, BatchInstruct{InstanceName: name, Err: err})
+			continue
+		}
+		
+		issued++
+		batchInstructs = append(batchInstructs, BatchInstruct{InstanceName: name, Uuid: instructUuid})
+	}
+	
+	if issued == 0 {
+		return "", batchInstructs, fmt.Errorf("%w: 没有可下发的在线实例", ErrInvalidBatch)
+	}
+	
+	batchUseCase.logger.Info("批量下发指令", zap.String("batchUuid", batchUuid), zap.Int("instances", issued), zap.String("operator", operator))
+	return batchUuid, batchInstructs, nil
+}
+
+// 对比批次内各实例的探测结果
+
+func (batchUseCase *BatchUseCase) Compare(ctx context.Context, batchUuid string) (BatchComparison, error) {
+	instructs, err := batchUseCase.instructRepo.QueryInstructs(ctx, InstructQuery{BatchUuid: batchUuid, Limit: BatchMaxInstances})
+	if err != nil {
+		return BatchComparison{}, err
+	}
+	
+	results, err := batchUseCase.resultRepo.QueryResults(ctx, ResultQuery{BatchUuid: batchUuid, Limit: BatchMaxInstances})
+	if err != nil {
+		return BatchComparison{}, err
+	}
+	
+	if len(instructs) == 0 && len(results) == 0 {
+		return BatchComparison{}, ErrBatchNotFound
+	}
+	
+	var uuids []string
+	for _, result := range results {
+		uuids = append(uuids, result.InstanceUuid)
+	}
+	
+	labels, err := batchUseCase.labelRepo.ListLabels(ctx, uuids)
+	if err != nil {
+		return BatchComparison{}, err
+	}
+	
+	return CompareBatch(batchUuid, instructs, results, labels), nil
+}
+
+// 按指令及结构化结果生成对比报告；指令记录已清理的结果仍参与对比，尚无结果的指令为执行中或失败 (下发失败、已取消)
+
+func CompareBatch(batchUuid string, instructs []Instruct, results []InstructResult, labels map[string]map[string]string) BatchComparison {
+	comparison := BatchComparison{BatchUuid: batchUuid}
+	
+	resultMap := make(map[string]InstructResult)
+	for _, result := range results {
+		resultMap[result.InstructUuid] = result
+		comparison.Type, comparison.Target = result.Type, result.Target
+	}
+	
+	for _, instruct := range instructs {
+		comparison.Type, comparison.Target = instruct.Type, instruct.Content
+		if _, ok := resultMap[instruct.Uuid]; ok {
+			continue
+		}
+		
+		instance := BatchInstanceResult{InstructUuid: instruct.Uuid, InstanceName: instruct.InstanceName, State: InstructRunning}
+		if instruct.Result == -1 {
+			instance.State = InstructFailed
+		}
+		comparison.Instances = append(comparison.Instances, instance)
+	}
+	
+	for _, result := range results {
+		instance := BatchInstanceResult{
+			InstructUuid: result.InstructUuid,
+			InstanceUuid: result.InstanceUuid,
+			InstanceName: result.InstanceName,
+			Labels:       labels[result.InstanceUuid],
+			State:        InstructSuccess,
+			StatusCode:   result.StatusCode,
+			Latency:      result.Latency,
+			PacketLoss:   result.PacketLoss,
+			Addrs:        result.Addrs,
+			ErrMsg:       result.ErrMsg,
+		}
+		if result.Result != 1 {
+			instance.State = InstructFailed
+		}
+		comparison.Instances = append(comparison.Instances, instance)
+	}
+	
+	comparison.Baseline, comparison.AddrGroups = newBatchBaseline(comparison.Type, comparison.Instances)
+	baseline := comparison.Baseline
+	addrsKey := strings.Join(baseline.Addrs, ",")
+	
+	for i := range comparison.Instances {
+		instance := &comparison.Instances[i]
+		comparison.Total++
+		switch instance.State {
+		case InstructRunning:
+			comparison.Running++
+			continue
+		case InstructFailed:
+			comparison.Failed++
+			instance.Deviations = []string{DeviationFailed}
+			comparison.Deviated++
+			continue
+		}
+		comparison.Success++
+		
+		if instance.StatusCode != baseline.StatusCode {
+			instance.Deviations = append(instance.Deviations, DeviationStatus)
+		}
+		
+		if len(comparison.AddrGroups) > 1 && sortedAddrsKey(instance.Addrs) != addrsKey {
+			instance.Deviations = append(instance.Deviations, DeviationAddrs)
+		}
+		
+		if baseline.Latency > 0 && instance.Latency > baseline.Latency*latencyOutlierRatio && instance.Latency-baseline.Latency >= latencyOutlierMin {
+			instance.Deviations = append(instance.Deviations, DeviationLatency)
+		}
+		
+		if comparison.Type == IcmpInstruct && instance.PacketLoss-baseline.PacketLoss >= packetLossOutlier {
+			instance.Deviations = append(instance.Deviations, DeviationPacketLoss)
+		}
+		
+		if len(instance.Deviations) > 0 {
+			comparison.Deviated++
+		}
+	}
+	
+	sort.SliceStable(comparison.Instances, func(i, j int) bool {
+		a, b := comparison.Instances[i], comparison.Instances[j]
+		if (len(a.Deviations) > 0) != (len(b.Deviations) > 0) {
+			return len(a.Deviations) > 0
+		}
+		return a.InstanceName < b.InstanceName
+	})
+	
+	comparison.Labels = labelDeviations(comparison.Instances)
+	return comparison
+}
+
+func sortedAddrsKey(addrs []string) string {
+	sorted := append([]string(nil), addrs...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// 以成功的结果计算基准，并按解析地址分组 (实例多的组在前)，HTTP 结果没有解析地址
+
+func newBatchBaseline(instructType InstructType, instances []BatchInstanceResult) (BatchBaseline, []AddrGroup) {
+	var baseline BatchBaseline
+	var latencies, losses []float64
+	statusCount := make(map[int32]int)
+	groups := make(map[string]*AddrGroup)
+	
+	for _, instance := range instances {
+		if instance.State != InstructSuccess {
+			continue
+		}
+		
+		statusCount[instance.StatusCode]++
+		losses = append(losses, instance.PacketLoss)
+		if instance.Latency > 0 {
+			latencies = append(latencies, instance.Latency)
+		}
+		
+		if instructType == HttpInstruct {
+			continue
+		}
+		
+		key := sortedAddrsKey(instance.Addrs)
+		group, ok := groups[key]
+		if !ok {
+			group = &AddrGroup{Addrs: strings.Split(key, ",")}
+			if key == "" {
+				group.Addrs = []string{}
+			}
+			groups[key] = group
+		}
+		group.Instances = append(group.Instances, instance.InstanceName)
+	}
+	
+	// 数量相同时取较小的状态码
+	for statusCode, count := range statusCount {
+		if count > statusCount[baseline.StatusCode] || count == statusCount[baseline.StatusCode] && statusCode < baseline.StatusCode {
+			baseline.StatusCode = statusCode
+		}
+	}
+	
+	baseline.Latency = median(latencies)
+	baseline.PacketLoss = median(losses)
+	if len(latencies) > 0 {
+		baseline.MinLatency = latencies[0]
+		baseline.MaxLatency = latencies[0]
+		for _, latency := range latencies {
+			baseline.MinLatency = min(baseline.MinLatency, latency)
+			baseline.MaxLatency = max(baseline.MaxLatency, latency)
+		}
+	}
+	
+	addrGroups := make([]AddrGroup, 0, len(groups))
+	for _, group := range groups {
+		sort.Strings(group.Instances)
+		addrGroups = append(addrGroups, *group)
+	}
+	
+	sort.Slice(addrGroups, func(i, j int) bool {
+		if len(addrGroups[i].Instances) != len(addrGroups[j].Instances) {
+			return len(addrGroups[i].Instances) > len(addrGroups[j].Instances)
+		}
+		return strings.Join(addrGroups[i].Addrs, ",") < strings.Join(addrGroups[j].Addrs, ",")
+	})
+	
+	if len(addrGroups) > 0 {
+		baseline.Addrs = addrGroups[0].Addrs
+	}
+	return baseline, addrGroups
+}
+
+// 按标签统计已结束实例中偏离基准的数量，仅返回存在偏离实例的标签
+
+func labelDeviations(instances []BatchInstanceResult) []LabelDeviation {
+	deviations := make(map[[2]string]*LabelDeviation)
+	for _, instance := range instances {
+		if instance.State == InstructRunning {
+			continue
+		}
+		
+		for key, value := range instance.Labels {
+			deviation, ok := deviations[[2]string{key, value}]
+			if !ok {
+				deviation = &LabelDeviation{Key: key, Value: value}
+				deviations[[2]string{key, value}] = deviation
+			}
+			
+			deviation.Total++
+			if len(instance.Deviations) > 0 {
+				deviation.Deviated++
+			}
+		}
+	}
+	
+	var labels []LabelDeviation
+	for _, deviation := range deviations {
+		if deviation.Deviated > 0 {
+			labels = append(labels, *deviation)
+		}
+	}
+	
+	sort.Slice(labels, func(i, j int) bool {
+		ri := float64(labels[i].Deviated) / float64(labels[i].Total)
+		rj := float64(labels[j].Deviated) / float64(labels[j].Total)
+		if ri != rj {
+			return ri > rj
+		}
+		
+		if labels[i].Key != labels[j].Key {
+			return labels[i].Key < labels[j].Key
+		}
+		return labels[i].Value < labels[j].Value
+	})
+	return labels
+}
@@ -0,0 +1,102 @@
+package biz
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+// 按实例名下发时不在线的实例不下发，下发的指令记录批次
+
+func TestBatchUseCase_IssueBatch(t *testing.T) {
+	ctx := context.Background()
+	instanceRepo := newFakeInstanceRepo(
+		Instance{Uuid: "i1", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "bj", Online: true},
+		Instance{Uuid: "i2", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "sh", Online: true},
+		Instance{Uuid: "i3", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "gz"},
+		Instance{Uuid: "i4", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "sz", Online: true},
+	)
+	instructRepo := &fakeInstructRepo{instruct: make(map[string]Instruct)}
+	node := &Node{Id: "node1"}
+	clusterUseCase := NewClusterUseCase(newFakeClusterRepo(), newFakePresenceRepo(), instanceRepo, NewConnRegistry(), node, &ClusterPolicy{}, zap.NewNop())
+	eventUseCase, eventRepo := newTestEventUseCase(instructRepo, &WebhookPolicy{})
+	instructUseCase := NewInstructUseCase(instructRepo, nil, newFakeOrgRepo(), nil, clusterUseCase, eventUseCase, zap.NewNop())
+	batchUseCase := NewBatchUseCase(instructUseCase, instanceRepo, instructRepo, newFakeResultRepo(), &fakeLabelRepo{}, zap.NewNop())
+	
+	target := BatchTarget{OrgUuid: "o1", GroupUuid: "g1", InstanceNames: []string{"bj", "sh", "gz", "bj"}}
+	batchUuid, instructs, err := batchUseCase.IssueBatch(ctx, target, HttpInstruct, "https://example.com", "alice")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, batchUuid)
+	assert.Len(t, instructs, 3)
+	assert.ErrorIs(t, instructs[2].Err, ErrInstanceOffline)
+	
+	batch, err := instructRepo.QueryInstructs(ctx, InstructQuery{BatchUuid: batchUuid, Limit: BatchMaxInstances})
+	assert.NoError(t, err)
+	assert.Len(t, batch, 2)
+	assert.Len(t, eventRepo.published(), 2)
+	
+	_, _, err = batchUseCase.IssueBatch(ctx, target, CommandInstruct, "uptime", "alice")
+	assert.ErrorIs(t, err, ErrInvalidBatch)
+	
+	_, _, err = batchUseCase.IssueBatch(ctx, BatchTarget{OrgUuid: "o1", GroupUuid: "g1", InstanceNames: []string{"gz"}}, HttpInstruct, "https://example.com", "alice")
+	assert.ErrorIs(t, err, ErrInvalidBatch)
+}
+
+// 以多数实例的结果为基准，标记状态码、解析地址、耗时及失败的偏离，并按标签统计
+
+func TestCompareBatch(t *testing.T) {
+	instructs := []Instruct{
+		{Uuid: "a", InstanceName: "bj-1", Type: DnsInstruct, Content: "example.com", Result: 1},
+		{Uuid: "b", InstanceName: "bj-2", Type: DnsInstruct, Content: "example.com", Result: 1},
+		{Uuid: "c", InstanceName: "sh-1", Type: DnsInstruct, Content: "example.com", Result: 1},
+		{Uuid: "d", InstanceName: "gz-1", Type: DnsInstruct, Content: "example.com", Result: -1},
+		{Uuid: "e", InstanceName: "gz-2", Type: DnsInstruct, Content: "example.com"},
+	}
+	results := []InstructResult{
+		{InstructUuid: "a", InstanceUuid: "i1", InstanceName: "bj-1", Type: DnsInstruct, Target: "example.com", Result: 1, Addrs: []string{"192.0.2.1", "192.0.2.2"}},
+		{InstructUuid: "b", InstanceUuid: "i2", InstanceName: "bj-2", Type: DnsInstruct, Target: "example.com", Result: 1, Addrs: []string{"192.0.2.2", "192.0.2.1"}},
+		{InstructUuid: "c", InstanceUuid: "i3", InstanceName: "sh-1", Type: DnsInstruct, Target: "example.com", Result: 1, Addrs: []string{"198.51.100.1"}},
+		{InstructUuid: "d", InstanceUuid: "i4", InstanceName: "gz-1", Type: DnsInstruct, Target: "example.com", Result: -1, ErrMsg: "timeout"},
+	}
+	labels := map[string]map[string]string{
+		"i1": {"region": "bj", "isp": "ct"},
+		"i2": {"region": "bj", "isp": "cu"},
+		"i3": {"region": "sh", "isp": "ct"},
+		"i4": {"region": "gz", "isp": "cu"},
+	}
+	
+	comparison := CompareBatch("batch", instructs, results, labels)
+	assert.Equal(t, DnsInstruct, comparison.Type)
+	assert.Equal(t, "example.com", comparison.Target)
+	assert.Equal(t, 5, comparison.Total)
+	assert.Equal(t, 1, comparison.Running)
+	assert.Equal(t, 3, comparison.Success)
+	assert.Equal(t, 1, comparison.Failed)
+	assert.Equal(t, 2, comparison.Deviated)
+	assert.Equal(t, []string{"192.0.2.1", "192.0.2.2"}, comparison.Baseline.Addrs)
+	assert.Len(t, comparison.AddrGroups, 2)
+	assert.Equal(t, []string{"bj-1", "bj-2"}, comparison.AddrGroups[0].Instances)
+	
+	assert.Equal(t, "gz-1", comparison.Instances[0].InstanceName)
+	assert.Equal(t, []string{DeviationFailed}, comparison.Instances[0].Deviations)
+	assert.Equal(t, "sh-1", comparison.Instances[1].InstanceName)
+	assert.Equal(t, []string{DeviationAddrs}, comparison.Instances[1].Deviations)
+	
+	assert.Equal(t, LabelDeviation{Key: "region", Value: "gz", Total: 1, Deviated: 1}, comparison.Labels[0])
+	assert.Equal(t, LabelDeviation{Key: "region", Value: "sh", Total: 1, Deviated: 1}, comparison.Labels[1])
+	assert.Contains(t, comparison.Labels, LabelDeviation{Key: "isp", Value: "ct", Total: 2, Deviated: 1})
+	
+	results = []InstructResult{
+		{InstructUuid: "a", InstanceName: "a", Type: HttpInstruct, Result: 1, StatusCode: 200, Latency: 30},
+		{InstructUuid: "b", InstanceName: "b", Type: HttpInstruct, Result: 1, StatusCode: 200, Latency: 40},
+		{InstructUuid: "c", InstanceName: "c", Type: HttpInstruct, Result: 1, StatusCode: 200, Latency: 35},
+		{InstructUuid: "d", InstanceName: "d", Type: HttpInstruct, Result: 1, StatusCode: 502, Latency: 300},
+	}
+	comparison = CompareBatch("batch", nil, results, nil)
+	assert.Equal(t, int32(200), comparison.Baseline.StatusCode)
+	assert.Equal(t, 37.5, comparison.Baseline.Latency)
+	assert.Empty(t, comparison.AddrGroups)
+	assert.Equal(t, 1, comparison.Deviated)
+	assert.Equal(t, []string{DeviationStatus, DeviationLatency}, comparison.Instances[0].Deviations)
+}
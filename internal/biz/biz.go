@@ -5,11 +5,31 @@ import "github.com/google/wire"
 var ProviderSet = wire.NewSet(NewInstanceUseCase,
 	NewMessageUseCase,
 	NewInstructUseCase,
+	NewSessionUseCase,
 	NewChromeDpClientUseCase,
 	NewDnsInspectUseCase,
 	NewDnsClientInspectUseCase,
 	NewHttpInspectClientUseCase,
 	NewIcmpClientUseCase,
 	NewSocketClientUseCase,
+	NewInventoryClientUseCase,
+	NewMetricsClientUseCase,
+	NewMetricsUseCase,
+	NewFileUseCase,
+	NewFileClientUseCase,
+	NewShellUseCase,
+	NewShellClientUseCase,
+	NewTunnelUseCase,
+	NewTunnelClientUseCase,
+	NewConnRegistry,
+	NewNode,
+	NewPresenceUseCase,
+	NewClusterUseCase,
+	NewOrgUseCase,
+	NewArchiveUseCase,
+	NewResultUseCase,
+	NewBatchUseCase,
+	NewEventUseCase,
+	NewSpool,
 	NewWebSocketUseCase,
 )
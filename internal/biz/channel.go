@@ -0,0 +1,396 @@
+package biz
+
+import (
+	"sync"
+)
+
+// 逻辑通道
+//
+// commander 与 soldier 之间的消息按用途划分为多个逻辑通道，复用同一个 websocket 连接:
+// 发送时按优先级 (控制 > 指令 > 遥测 > 批量数据) 选择下一条消息，大文件、截图等批量数据不会延迟控制消息
+// 接收时每个通道由独立的协程处理，耗时的指令不会阻塞其他通道
+// 背压: 除控制通道外，发送方未确认的消息数不超过对端的接收窗口，接收方处理完成后以 ChannelWindow 消息归还窗口
+// 同一会话 / 传输的消息始终使用同一通道，保证顺序 (如文件分片与对应的指令结果)
+//
+// 双方在 websocket 握手时通过 MuxHeader 请求头 / 响应头协商，旧版本对端不支持时不限制窗口
+
+type Channel int32
+
+const (
+	ControlChannel   Channel = 0 // 握手、版本信息及通道窗口
+	InstructChannel  Channel = 1 // 指令下发及结果
+	TelemetryChannel Channel = 2 // 主机信息及指标
+	BulkChannel      Channel = 3 // 文件分片、截图、交互式终端及端口转发数据
+	
+	channelCount = 4
+)
+
+const MuxHeader = "X-Camp-Mux"
+
+// 各通道接收窗口 (消息数)，即接收队列长度，控制通道不限制窗口
+
+var channelWindow = [channelCount]int{
+	ControlChannel:   256,
+	InstructChannel:  64,
+	TelemetryChannel: 16,
+	BulkChannel:      16,
+}
+
+type ChannelWindow struct {
+	Channel Channel `json:"channel"`
+	Window  uint32  `json:"window"`
+}
+
+func (msg *ServiceMessage) Channel() Channel {
+	switch msg.Type {
+	case ServiceInstruct:
+		// 文件下发指令与随后的分片使用同一通道
+		if msg.InstructMessage.Type == FilePutInstruct {
+			return BulkChannel
+		}
+		return InstructChannel
+	
+	case ServiceFileChunk, ServiceShell, ServiceTunnel:
+		return BulkChannel
+	
+	default:
+		return ControlChannel
+	}
+}
+
+func (msg *ClientMessage) Channel() Channel {
+	switch msg.Type {
+	case ClientInstructReply:
+		// 文件指令结果须在文件分片之后到达
+		if msg.InstructMessage.Type == FileGetInstruct || msg.InstructMessage.Type == FilePutInstruct {
+			return BulkChannel
+		}
+		return InstructChannel
+	
+	case ClientHostInventory, ClientHostMetrics:
+		return TelemetryChannel
+	
+	case ClientChromeDpScreenShot, ClientFileChunk, ClientShell, ClientTunnel:
+		return BulkChannel
+	
+	default:
+		return ControlChannel
+	}
+}
+
+// 发送窗口及接收确认
+
+type channelCredits struct {
+	mu      sync.Mutex
+	limited bool // 对端支持通道窗口
+	credit  [channelCount]int
+	acked   [channelCount]int // 已处理未通知对端的消息数
+	notify  chan struct{}
+}
+
+func newChannelCredits() *channelCredits {
+	return &channelCredits{notify: make(chan struct{}, 1)}
+}
+
+// 对端支持通道窗口，重置发送窗口
+
+func (credits *channelCredits) enable(limited bool) {
+	credits.mu.Lock()
+	credits.limited = limited
+	credits.credit = channelWindow
+	credits.acked = [channelCount]int{}
+	credits.mu.Unlock()
+	credits.wake()
+}
+
+func (credits *channelCredits) available(channel Channel) bool {
+	credits.mu.Lock()
+	defer credits.mu.Unlock()
+	return !credits.limited || channel == ControlChannel || credits.credit[channel] > 0
+}
+
+func (credits *channelCredits) consume(channel Channel) {
+	credits.mu.Lock()
+	credits.credit[channel]--
+	credits.mu.Unlock()
+}
+
+func (credits *channelCredits) release(window ChannelWindow) {
+	if window.Channel < 0 || window.Channel >= channelCount {
+		return
+	}
+	
+	credits.mu.Lock()
+	credits.credit[window.Channel] += int(window.Window)
+	credits.mu.Unlock()
+	credits.wake()
+}
+
+func (credits *channelCredits) wake() {
+	select {
+	case credits.notify <- struct{}{}:
+	default:
+	}
+}
+
+// 处理完成一条消息，累计到半个窗口时返回需要通知对端的窗口
+
+func (credits *channelCredits) ack(channel Channel) (ChannelWindow, bool) {
+	credits.mu.Lock()
+	defer credits.mu.Unlock()
+	
+	if !credits.limited || channel == ControlChannel {
+		return ChannelWindow{}, false
+	}
+	
+	credits.acked[channel]++
+	if credits.acked[channel] < channelWindow[channel]/2 {
+		return ChannelWindow{}, false
+	}
+	
+	window := ChannelWindow{Channel: channel, Window: uint32(credits.acked[channel])}
+	credits.acked[channel] = 0
+	return window, true
+}
+
+// commander -> soldier 的发送队列，消息在发送时按协商的子协议编码
+
+type ServiceChannels struct {
+	queues  [channelCount]chan ServiceMessage
+	credits *channelCredits
+	
+	wake      chan struct{} // 有新指令，立即读取指令队列
+	drain     chan struct{} // 节点下线，关闭连接由 soldier 重连到其他节点
+	drainOnce sync.Once
+	
+	remove     chan struct{} // 实例所属分组已删除，关闭连接
+	removeOnce sync.Once
+}
+
+func NewServiceChannels() *ServiceChannels {
+	serviceChannels := &ServiceChannels{
+		credits: newChannelCredits(),
+		wake:    make(chan struct{}, 1),
+		drain:   make(chan struct{}),
+		remove:  make(chan struct{}),
+	}
+	for channel := range serviceChannels.queues {
+		serviceChannels.queues[channel] = make(chan ServiceMessage, channelWindow[channel])
+	}
+	
+	return serviceChannels
+}
+
+func (serviceChannels *ServiceChannels) Queue(channel Channel) chan ServiceMessage {
+	return serviceChannels.queues[channel]
+}
+
+// 通知连接读取指令队列，已有未处理的通知时合并
+
+func (serviceChannels *ServiceChannels) Wake() {
+	select {
+	case serviceChannels.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (serviceChannels *ServiceChannels) Woken() <-chan struct{} {
+	return serviceChannels.wake
+}
+
+func (serviceChannels *ServiceChannels) Drain() {
+	serviceChannels.drainOnce.Do(func() {
+		close(serviceChannels.drain)
+	})
+}
+
+func (serviceChannels *ServiceChannels) Draining() <-chan struct{} {
+	return serviceChannels.drain
+}
+
+func (serviceChannels *ServiceChannels) Remove() {
+	serviceChannels.removeOnce.Do(func() {
+		close(serviceChannels.remove)
+	})
+}
+
+func (serviceChannels *ServiceChannels) Removed() <-chan struct{} {
+	return serviceChannels.remove
+}
+
+// soldier 握手时声明支持通道窗口
+
+func (serviceChannels *ServiceChannels) EnableWindow() {
+	serviceChannels.credits.enable(true)
+}
+
+// soldier 归还的窗口
+
+func (serviceChannels *ServiceChannels) Release(window ChannelWindow) {
+	serviceChannels.credits.release(window)
+}
+
+// 处理完成 soldier 的一条消息，按需向 soldier 归还窗口
+
+func (serviceChannels *ServiceChannels) Ack(channel Channel, done <-chan struct{}) {
+	window, ok := serviceChannels.credits.ack(channel)
+	if !ok {
+		return
+	}
+	
+	serviceChannels.Send(ServiceMessage{Type: ServiceChannelWindow, ChannelWindow: &window}, done)
+}
+
+// 按消息类型放入对应通道，done 关闭时放弃发送
+
+func (serviceChannels *ServiceChannels) Send(msg ServiceMessage, done <-chan struct{}) {
+	select {
+	case serviceChannels.queues[msg.Channel()] <- msg:
+	case <-done:
+	}
+}
+
+// 按优先级获取下一条可发送的消息，done 关闭时返回 false
+
+func (serviceChannels *ServiceChannels) Next(done <-chan struct{}) (ServiceMessage, bool) {
+	for {
+		var queues [channelCount]chan ServiceMessage
+		for channel := Channel(0); channel < channelCount; channel++ {
+			if !serviceChannels.credits.available(channel) {
+				continue
+			}
+			
+			select {
+			case msg := <-serviceChannels.queues[channel]:
+				serviceChannels.credits.consume(channel)
+				return msg, true
+			default:
+				queues[channel] = serviceChannels.queues[channel]
+			}
+		}
+		
+		// 所有可发送的通道都为空，等待新消息或窗口
+		select {
+		case msg := <-queues[ControlChannel]:
+			serviceChannels.credits.consume(ControlChannel)
+			return msg, true
+		case msg := <-queues[InstructChannel]:
+			serviceChannels.credits.consume(InstructChannel)
+			return msg, true
+		case msg := <-queues[TelemetryChannel]:
+			serviceChannels.credits.consume(TelemetryChannel)
+			return msg, true
+		case msg := <-queues[BulkChannel]:
+			serviceChannels.credits.consume(BulkChannel)
+			return msg, true
+		case <-serviceChannels.credits.notify:
+		case <-done:
+			return ServiceMessage{}, false
+		}
+	}
+}
+
+// soldier -> commander 的发送队列，commander 亦以该结构按通道分发接收到的消息
+
+type ClientChannels struct {
+	queues  [channelCount]chan ClientMessage
+	credits *channelCredits
+}
+
+func NewClientChannels() *ClientChannels {
+	clientChannels := &ClientChannels{credits: newChannelCredits()}
+	for channel := range clientChannels.queues {
+		clientChannels.queues[channel] = make(chan ClientMessage, channelWindow[channel])
+	}
+	
+	return clientChannels
+}
+
+func (clientChannels *ClientChannels) Queue(channel Channel) chan ClientMessage {
+	return clientChannels.queues[channel]
+}
+
+// 按消息类型放入对应通道，通道已满时阻塞
+
+func (clientChannels *ClientChannels) Send(msg ClientMessage) {
+	clientChannels.queues[msg.Channel()] <- msg
+}
+
+// 连接 (重连) 后按 commander 是否支持通道窗口重置发送窗口
+
+func (clientChannels *ClientChannels) EnableWindow(limited bool) {
+	clientChannels.credits.enable(limited)
+}
+
+// commander 归还的窗口
+
+func (clientChannels *ClientChannels) Release(window ChannelWindow) {
+	clientChannels.credits.release(window)
+}
+
+// 处理完成 commander 的一条消息，按需向 commander 归还窗口
+
+func (clientChannels *ClientChannels) Ack(channel Channel, done <-chan struct{}) {
+	window, ok := clientChannels.credits.ack(channel)
+	if !ok {
+		return
+	}
+	
+	select {
+	case clientChannels.queues[ControlChannel] <- ClientMessage{Type: ClientChannelWindow, ChannelWindow: &window}:
+	case <-done:
+	}
+}
+
+// 按优先级获取下一条可发送的消息，done 关闭时返回 false
+
+func (clientChannels *ClientChannels) Next(done <-chan struct{}) (ClientMessage, bool) {
+	for {
+		var queues [channelCount]chan ClientMessage
+		for channel := Channel(0); channel < channelCount; channel++ {
+			if !clientChannels.credits.available(channel) {
+				continue
+			}
+			
+			select {
+			case msg := <-clientChannels.queues[channel]:
+				clientChannels.credits.consume(channel)
+				return msg, true
+			default:
+				queues[channel] = clientChannels.queues[channel]
+			}
+		}
+		
+		// 所有可发送的通道都为空，等待新消息或窗口
+		select {
+		case msg := <-queues[ControlChannel]:
+			clientChannels.credits.consume(ControlChannel)
+			return msg, true
+		case msg := <-queues[InstructChannel]:
+			clientChannels.credits.consume(InstructChannel)
+			return msg, true
+		case msg := <-queues[TelemetryChannel]:
+			clientChannels.credits.consume(TelemetryChannel)
+			return msg, true
+		case msg := <-queues[BulkChannel]:
+			clientChannels.credits.consume(BulkChannel)
+			return msg, true
+		case <-clientChannels.credits.notify:
+		case <-done:
+			return ClientMessage{}, false
+		}
+	}
+}
+
+// soldier 按通道分发接收到的消息
+
+type ServiceQueues [channelCount]chan ServiceMessage
+
+func NewServiceQueues() *ServiceQueues {
+	serviceQueues := &ServiceQueues{}
+	for channel := range serviceQueues {
+		serviceQueues[channel] = make(chan ServiceMessage, channelWindow[channel])
+	}
+	
+	return serviceQueues
+}
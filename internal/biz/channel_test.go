@@ -0,0 +1,100 @@
+package biz
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMessageChannel(t *testing.T) {
+	assert.Equal(t, ControlChannel, (&ClientMessage{Type: ClientHello}).Channel())
+	assert.Equal(t, InstructChannel, (&ClientMessage{Type: ClientInstructReply, InstructMessage: InstructMessage{Type: CommandInstruct}}).Channel())
+	assert.Equal(t, BulkChannel, (&ClientMessage{Type: ClientInstructReply, InstructMessage: InstructMessage{Type: FileGetInstruct}}).Channel())
+	assert.Equal(t, TelemetryChannel, (&ClientMessage{Type: ClientHostMetrics}).Channel())
+	assert.Equal(t, BulkChannel, (&ClientMessage{Type: ClientChromeDpScreenShot}).Channel())
+	
+	assert.Equal(t, InstructChannel, (&ServiceMessage{Type: ServiceInstruct, InstructMessage: InstructMessage{Type: DnsInstruct}}).Channel())
+	assert.Equal(t, BulkChannel, (&ServiceMessage{Type: ServiceInstruct, InstructMessage: InstructMessage{Type: FilePutInstruct}}).Channel())
+	assert.Equal(t, BulkChannel, (&ServiceMessage{Type: ServiceFileChunk}).Channel())
+	assert.Equal(t, ControlChannel, (&ServiceMessage{Type: ServiceChannelWindow}).Channel())
+}
+
+// 控制消息优先于已排队的批量数据发送
+
+func TestClientChannels_Priority(t *testing.T) {
+	clientChannels := NewClientChannels()
+	clientChannels.EnableWindow(true)
+	
+	clientChannels.Send(ClientMessage{Type: ClientFileChunk})
+	clientChannels.Send(ClientMessage{Type: ClientHostMetrics})
+	clientChannels.Send(ClientMessage{Type: ClientInstructReply})
+	clientChannels.Send(ClientMessage{Type: ClientHello})
+	
+	var types []ClientMessageType
+	for i := 0; i < 4; i++ {
+		msg, ok := clientChannels.Next(nil)
+		assert.True(t, ok)
+		types = append(types, msg.Type)
+	}
+	
+	assert.Equal(t, []ClientMessageType{ClientHello, ClientInstructReply, ClientHostMetrics, ClientFileChunk}, types)
+}
+
+// 批量通道窗口耗尽后不再发送批量数据，控制消息不受影响
+
+func TestServiceChannels_Window(t *testing.T) {
+	serviceChannels := NewServiceChannels()
+	serviceChannels.EnableWindow()
+	done := make(chan struct{})
+	defer close(done)
+	
+	bulk := ServiceMessage{Type: ServiceFileChunk, FileChunk: &FileChunk{Uuid: "bulk"}}
+	control := ServiceMessage{Type: ServiceCancel, Cancel: "control"}
+	for i := 0; i < channelWindow[BulkChannel]; i++ {
+		serviceChannels.Queue(BulkChannel) <- bulk
+		msg, ok := serviceChannels.Next(done)
+		assert.True(t, ok)
+		assert.Equal(t, bulk, msg)
+	}
+	
+	serviceChannels.Queue(BulkChannel) <- bulk
+	serviceChannels.Queue(ControlChannel) <- control
+	
+	msg, _ := serviceChannels.Next(done)
+	assert.Equal(t, control, msg)
+	
+	next := make(chan ServiceMessage)
+	go func() {
+		msg, _ := serviceChannels.Next(done)
+		next <- msg
+	}()
+	
+	select {
+	case <-next:
+		t.Fatal("窗口耗尽时不应发送批量数据")
+	case <-time.After(50 * time.Millisecond):
+	}
+	
+	serviceChannels.Release(ChannelWindow{Channel: BulkChannel, Window: 1})
+	assert.Equal(t, bulk, <-next)
+}
+
+// 处理完成半个窗口的消息后归还窗口
+
+func TestServiceChannels_Ack(t *testing.T) {
+	serviceChannels := NewServiceChannels()
+	
+	// 对端不支持通道窗口时不归还
+	serviceChannels.Ack(BulkChannel, nil)
+	assert.Equal(t, 0, len(serviceChannels.Queue(ControlChannel)))
+	
+	serviceChannels.EnableWindow()
+	for i := 0; i < channelWindow[BulkChannel]/2; i++ {
+		serviceChannels.Ack(BulkChannel, nil)
+	}
+	assert.Equal(t, 1, len(serviceChannels.Queue(ControlChannel)))
+	
+	serviceMessage := <-serviceChannels.Queue(ControlChannel)
+	assert.Equal(t, ServiceChannelWindow, serviceMessage.Type)
+	assert.Equal(t, ChannelWindow{Channel: BulkChannel, Window: uint32(channelWindow[BulkChannel] / 2)}, *serviceMessage.ChannelWindow)
+}
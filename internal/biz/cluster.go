@@ -0,0 +1,261 @@
+package biz
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"sync/atomic"
+	"time"
+)
+
+// commander 集群
+//
+// 多个 commander 节点共享 MySQL 及 Redis，soldier 可连接到任一节点，连接所在节点记录在实例在线状态中 (Presence.Node)
+// 下发及取消指令时通过节点消息 (Redis Pub/Sub，每个节点一个频道) 直接通知连接所在节点；下发的指令仍写入实例指令队列，
+// 节点收到通知后立即读取，未收到通知时 (节点重启、消息丢失) 由定时轮询兜底
+// 节点下线时先标记为排空 (不再接受新连接)，在 DrainTimeout 内分批以 CloseServiceRestart 关闭连接，
+// soldier 携带会话 token 重连到其他节点后恢复会话
+
+const (
+	NodeInstruct = "instruct" // 有新指令，读取实例指令队列
+	NodeCancel   = "cancel"   // 取消指令，转发给 soldier
+	NodeRemove   = "remove"   // 实例所属分组已删除，关闭连接
+	
+	DefaultDrainTimeout = 30 * time.Second
+	
+	nodeTTL             = 30 * time.Second
+	nodeRefreshInterval = 10 * time.Second
+	nodeResubscribeWait = time.Second
+	nodeDispatchTimeout = 5 * time.Second
+)
+
+type NodeInfo struct {
+	Id          string `json:"id"`
+	StartTime   int64  `json:"startTime"`
+	UpdateTime  int64  `json:"updateTime"`
+	Connections int    `json:"connections"` // 当前连接的 soldier 数量
+	Draining    bool   `json:"draining"`    // 正在下线，不再接受新连接
+}
+
+type NodeMessage struct {
+	Type         string `json:"type"`
+	InstanceUuid string `json:"instanceUuid"`
+	InstructUuid string `json:"instructUuid,omitempty"`
+}
+
+type ClusterRepo interface {
+	SetNode(ctx context.Context, node NodeInfo, ttl time.Duration) error
+	DeleteNode(ctx context.Context, id string) error
+	ListNodes(ctx context.Context) ([]NodeInfo, error)
+	PublishNodeMessage(ctx context.Context, node string, msg NodeMessage) (bool, error)
+	SubscribeNodeMessages(ctx context.Context, node string, handler func(msg NodeMessage)) error
+}
+
+type ClusterPolicy struct {
+	DrainTimeout time.Duration // 下线时关闭全部连接的时间
+}
+
+type ClusterUseCase struct {
+	clusterRepo  ClusterRepo
+	presenceRepo PresenceRepo
+	instanceRepo InstanceRepo
+	connRegistry *ConnRegistry
+	node         *Node
+	policy       *ClusterPolicy
+	startTime    int64
+	draining     atomic.Bool
+	logger       *zap.Logger
+}
+
+func NewClusterUseCase(clusterRepo ClusterRepo, presenceRepo PresenceRepo, instanceRepo InstanceRepo, connRegistry *ConnRegistry, node *Node, policy *ClusterPolicy, logger *zap.Logger) *ClusterUseCase {
+	return &ClusterUseCase{
+		clusterRepo:  clusterRepo,
+		presenceRepo: presenceRepo,
+		instanceRepo: instanceRepo,
+		connRegistry: connRegistry,
+		node:         node,
+		policy:       policy,
+		startTime:    time.Now().Unix(),
+		logger:       logger,
+	}
+}
+
+func (clusterUseCase *ClusterUseCase) Node() string {
+	return clusterUseCase.node.Id
+}
+
+func (clusterUseCase *ClusterUseCase) Draining() bool {
+	return clusterUseCase.draining.Load()
+}
+
+// 登记节点并接收节点消息，ctx 结束后删除节点登记
+
+func (clusterUseCase *ClusterUseCase) Run(ctx context.Context) {
+	go clusterUseCase.refresh(ctx)
+	
+	for {
+		err := clusterUseCase.clusterRepo.SubscribeNodeMessages(ctx, clusterUseCase.node.Id, func(msg NodeMessage) {
+			clusterUseCase.dispatch(ctx, msg)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		
+		clusterUseCase.logger.Error("接收节点消息失败，重新订阅", zap.String("node", clusterUseCase.node.Id), zap.Error(err))
+		select {
+		case <-time.After(nodeResubscribeWait):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (clusterUseCase *ClusterUseCase) refresh(ctx context.Context) {
+	ticker := time.NewTicker(nodeRefreshInterval)
+	defer ticker.Stop()
+	
+	clusterUseCase.register(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			clusterUseCase.register(ctx)
+		
+		case <-ctx.Done():
+			err := clusterUseCase.clusterRepo.DeleteNode(context.Background(), clusterUseCase.node.Id)
+			if err != nil {
+				clusterUseCase.logger.Error("删除节点登记失败", zap.String("node", clusterUseCase.node.Id), zap.Error(err))
+			}
+			return
+		}
+	}
+}
+
+func (clusterUseCase *ClusterUseCase) register(ctx context.Context) {
+	err := clusterUseCase.clusterRepo.SetNode(ctx, NodeInfo{
+		Id:          clusterUseCase.node.Id,
+		StartTime:   clusterUseCase.startTime,
+		UpdateTime:  time.Now().Unix(),
+		Connections: clusterUseCase.connRegistry.Len(),
+		Draining:    clusterUseCase.Draining(),
+	}, nodeTTL)
+	if err != nil {
+		clusterUseCase.logger.Error("登记节点失败", zap.String("node", clusterUseCase.node.Id), zap.Error(err))
+	}
+}
+
+func (clusterUseCase *ClusterUseCase) ListNodes(ctx context.Context) ([]NodeInfo, error) {
+	return clusterUseCase.clusterRepo.ListNodes(ctx)
+}
+
+// 处理发送到本节点的消息，实例不在本节点时忽略
+
+func (clusterUseCase *ClusterUseCase) dispatch(ctx context.Context, msg NodeMessage) {
+	serviceChannels, ok := clusterUseCase.connRegistry.Get(msg.InstanceUuid)
+	if !ok {
+		return
+	}
+	
+	switch msg.Type {
+	case NodeInstruct:
+		serviceChannels.Wake()
+	
+	case NodeCancel:
+		go func() {
+			ctx, cancel := context.WithTimeout(ctx, nodeDispatchTimeout)
+			defer cancel()
+			serviceChannels.Send(ServiceMessage{Type: ServiceCancel, Cancel: msg.InstructUuid}, ctx.Done())
+		}()
+	
+	case NodeRemove:
+		serviceChannels.Remove()
+	}
+}
+
+// 将消息发送到实例连接所在的节点，实例不在线时忽略 (指令保留在队列中)
+
+func (clusterUseCase *ClusterUseCase) Route(ctx context.Context, msg NodeMessage) {
+	presences, err := clusterUseCase.presenceRepo.ListPresence(ctx, []string{msg.InstanceUuid})
+	if err != nil {
+		clusterUseCase.logger.Error("获取实例所在节点失败", zap.String("uuid", msg.InstanceUuid), zap.Error(err))
+		return
+	}
+	
+	presence, ok := presences[msg.InstanceUuid]
+	if !ok {
+		return
+	}
+	
+	if presence.Node == clusterUseCase.node.Id {
+		clusterUseCase.dispatch(ctx, msg)
+		return
+	}
+	
+	received, err := clusterUseCase.clusterRepo.PublishNodeMessage(ctx, presence.Node, msg)
+	if err != nil {
+		clusterUseCase.logger.Error("发送节点消息失败", zap.String("node", presence.Node), zap.String("type", msg.Type), zap.Error(err))
+		return
+	}
+	
+	if !received {
+		clusterUseCase.logger.Warn("实例所在节点未接收消息", zap.String("node", presence.Node), zap.String("uuid", msg.InstanceUuid), zap.String("type", msg.Type))
+	}
+}
+
+// 按实例名路由，实例不存在时忽略
+
+func (clusterUseCase *ClusterUseCase) RouteInstance(ctx context.Context, orgUuid, groupUuid, instanceName string, msg NodeMessage) {
+	instance, err := clusterUseCase.instanceRepo.Get(ctx, orgUuid, groupUuid, instanceName)
+	if err != nil {
+		clusterUseCase.logger.Error("获取实例失败", zap.String("instanceName", instanceName), zap.Error(err))
+		return
+	}
+	
+	msg.InstanceUuid = instance.Uuid
+	clusterUseCase.Route(ctx, msg)
+}
+
+// 节点下线: 不再接受新连接，在 DrainTimeout 内分批关闭连接，等待连接全部关闭、ctx 结束或再超过 DrainTimeout
+
+func (clusterUseCase *ClusterUseCase) Drain(ctx context.Context) {
+	clusterUseCase.draining.Store(true)
+	clusterUseCase.register(ctx)
+	
+	drainTimeout := clusterUseCase.policy.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = DefaultDrainTimeout
+	}
+	
+	conns := clusterUseCase.connRegistry.List()
+	clusterUseCase.logger.Info("节点下线，关闭连接", zap.String("node", clusterUseCase.node.Id), zap.Int("connections", len(conns)), zap.Duration("drainTimeout", drainTimeout))
+	
+	// 分批关闭，避免 soldier 同时重连到其他节点
+	if len(conns) > 0 {
+		interval := drainTimeout / time.Duration(len(conns))
+		for _, serviceChannels := range conns {
+			serviceChannels.Drain()
+			
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+	
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	
+	// 未能及时关闭的连接不再等待，由 HTTP 服务关闭
+	deadline := time.NewTimer(drainTimeout)
+	defer deadline.Stop()
+	
+	for clusterUseCase.connRegistry.Len() > 0 {
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			clusterUseCase.logger.Warn("等待连接关闭超时", zap.String("node", clusterUseCase.node.Id), zap.Int("connections", clusterUseCase.connRegistry.Len()))
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package biz
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+// 实例在本节点时直接处理，在其他节点时发送到该节点，不在线时忽略
+
+func TestClusterUseCase_Route(t *testing.T) {
+	ctx := context.Background()
+	clusterRepo := newFakeClusterRepo()
+	presenceRepo := newFakePresenceRepo()
+	connRegistry := NewConnRegistry()
+	clusterUseCase := NewClusterUseCase(clusterRepo, presenceRepo, newFakeInstanceRepo(), connRegistry, &Node{Id: "node1"}, &ClusterPolicy{}, zap.NewNop())
+	
+	serviceChannels := NewServiceChannels()
+	connRegistry.Attach("i1", serviceChannels)
+	presenceRepo.SetPresence(ctx, Presence{InstanceUuid: "i1", Node: "node1"}, time.Minute)
+	presenceRepo.SetPresence(ctx, Presence{InstanceUuid: "i2", Node: "node2"}, time.Minute)
+	
+	clusterUseCase.Route(ctx, NodeMessage{Type: NodeInstruct, InstanceUuid: "i1"})
+	select {
+	case <-serviceChannels.Woken():
+	default:
+		t.Fatal("本节点的连接未被唤醒")
+	}
+	
+	clusterUseCase.Route(ctx, NodeMessage{Type: NodeCancel, InstanceUuid: "i1", InstructUuid: "a"})
+	done := make(chan struct{})
+	time.AfterFunc(time.Second, func() { close(done) })
+	msg, ok := serviceChannels.Next(done)
+	assert.True(t, ok)
+	assert.Equal(t, ServiceCancel, msg.Type)
+	assert.Equal(t, "a", msg.Cancel)
+	
+	clusterUseCase.Route(ctx, NodeMessage{Type: NodeInstruct, InstanceUuid: "i2"})
+	clusterUseCase.Route(ctx, NodeMessage{Type: NodeInstruct, InstanceUuid: "i3"})
+	assert.Equal(t, []NodeMessage{{Type: NodeInstruct, InstanceUuid: "i2"}}, clusterRepo.published["node2"])
+	assert.Len(t, clusterRepo.published, 1)
+}
+
+// 下线时通知全部连接关闭，连接全部断开后返回
+
+func TestClusterUseCase_Drain(t *testing.T) {
+	ctx := context.Background()
+	clusterRepo := newFakeClusterRepo()
+	connRegistry := NewConnRegistry()
+	clusterUseCase := NewClusterUseCase(clusterRepo, newFakePresenceRepo(), newFakeInstanceRepo(), connRegistry, &Node{Id: "node1"}, &ClusterPolicy{DrainTimeout: 100 * time.Millisecond}, zap.NewNop())
+	
+	uuids := []string{"i1", "i2"}
+	for _, uuid := range uuids {
+		serviceChannels := NewServiceChannels()
+		connRegistry.Attach(uuid, serviceChannels)
+		go func(uuid string) {
+			<-serviceChannels.Draining()
+			connRegistry.Detach(uuid, serviceChannels)
+		}(uuid)
+	}
+	
+	drained := make(chan struct{})
+	go func() {
+		clusterUseCase.Drain(ctx)
+		close(drained)
+	}()
+	
+	select {
+	case <-drained:
+	case <-time.After(2 * time.Second):
+		t.Fatal("连接未全部关闭")
+	}
+	
+	assert.True(t, clusterUseCase.Draining())
+	assert.Equal(t, 0, connRegistry.Len())
+	nodes, err := clusterUseCase.ListNodes(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 1)
+	assert.True(t, nodes[0].Draining)
+}
+
+// 连接未关闭时等待不超过 DrainTimeout
+
+func TestClusterUseCase_DrainTimeout(t *testing.T) {
+	connRegistry := NewConnRegistry()
+	clusterUseCase := NewClusterUseCase(newFakeClusterRepo(), newFakePresenceRepo(), newFakeInstanceRepo(), connRegistry, &Node{Id: "node1"}, &ClusterPolicy{DrainTimeout: 100 * time.Millisecond}, zap.NewNop())
+	connRegistry.Attach("i1", NewServiceChannels())
+	
+	drained := make(chan struct{})
+	go func() {
+		clusterUseCase.Drain(context.Background())
+		close(drained)
+	}()
+	
+	select {
+	case <-drained:
+	case <-time.After(2 * time.Second):
+		t.Fatal("等待连接关闭未超时")
+	}
+	assert.Equal(t, 1, connRegistry.Len())
+}
@@ -0,0 +1,72 @@
+package biz
+
+import "sync"
+
+// 当前节点上的 soldier 连接，交互式终端及端口转发等会话通过该连接转发
+
+type ConnRegistry struct {
+	mu       sync.Mutex
+	conns    map[string]*ServiceChannels // instanceUuid -> 发送队列
+	onDetach []func(serviceChannels *ServiceChannels)
+}
+
+func NewConnRegistry() *ConnRegistry {
+	return &ConnRegistry{
+		conns: make(map[string]*ServiceChannels),
+	}
+}
+
+// soldier 连接建立后登记发送队列
+
+func (connRegistry *ConnRegistry) Attach(instanceUuid string, serviceChannels *ServiceChannels) {
+	connRegistry.mu.Lock()
+	connRegistry.conns[instanceUuid] = serviceChannels
+	connRegistry.mu.Unlock()
+}
+
+// soldier 连接断开，通知各会话关闭该连接上的会话
+
+func (connRegistry *ConnRegistry) Detach(instanceUuid string, serviceChannels *ServiceChannels) {
+	connRegistry.mu.Lock()
+	if connRegistry.conns[instanceUuid] == serviceChannels {
+		delete(connRegistry.conns, instanceUuid)
+	}
+	onDetach := connRegistry.onDetach
+	connRegistry.mu.Unlock()
+	
+	for _, f := range onDetach {
+		f(serviceChannels)
+	}
+}
+
+func (connRegistry *ConnRegistry) Get(instanceUuid string) (*ServiceChannels, bool) {
+	connRegistry.mu.Lock()
+	defer connRegistry.mu.Unlock()
+	serviceChannels, ok := connRegistry.conns[instanceUuid]
+	return serviceChannels, ok
+}
+
+// 当前节点上的所有连接
+
+func (connRegistry *ConnRegistry) List() []*ServiceChannels {
+	connRegistry.mu.Lock()
+	defer connRegistry.mu.Unlock()
+	
+	conns := make([]*ServiceChannels, 0, len(connRegistry.conns))
+	for _, serviceChannels := range connRegistry.conns {
+		conns = append(conns, serviceChannels)
+	}
+	return conns
+}
+
+func (connRegistry *ConnRegistry) Len() int {
+	connRegistry.mu.Lock()
+	defer connRegistry.mu.Unlock()
+	return len(connRegistry.conns)
+}
+
+func (connRegistry *ConnRegistry) OnDetach(f func(serviceChannels *ServiceChannels)) {
+	connRegistry.mu.Lock()
+	connRegistry.onDetach = append(connRegistry.onDetach, f)
+	connRegistry.mu.Unlock()
+}
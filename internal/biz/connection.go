@@ -0,0 +1,207 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"github.com/gorilla/websocket"
+	"net"
+	"sort"
+	"time"
+)
+
+// 实例连接历史
+//
+// 每次 soldier 连接记录一条连接记录 (id 与在线记录的连接 id 相同)，断开时记录断开时间及原因；
+// 实例在其他连接上线时之前未断开的连接记录以 replaced 结束，所在 commander 节点异常退出的连接由 Reap 以 expired 结束
+// 在线时长按连接记录计算，多条连接重叠的时间只计算一次
+
+const (
+	DisconnectClosed      = "closed"       // soldier 关闭连接
+	DisconnectReadTimeout = "read_timeout" // 超过读超时未收到任何消息
+	DisconnectReadError   = "read_error"   // 读取失败 (网络中断等)
+	DisconnectPingFailed  = "ping_failed"  // 发送心跳失败
+	DisconnectWriteError  = "write_error"  // 发送消息失败
+	DisconnectShutdown    = "shutdown"     // commander 关闭连接 (请求结束)
+	DisconnectDraining    = "draining"     // commander 节点下线
+	DisconnectReplaced    = "replaced"     // 实例已在其他连接上线
+	DisconnectExpired     = "expired"      // 所在 commander 节点异常退出，在线记录过期
+	DisconnectRemoved     = "removed"      // 实例所属组织或分组已删除
+	
+	DefaultUptimeWindow = 24 * time.Hour
+	MaxUptimeWindow     = 31 * 24 * time.Hour
+	
+	connectionDefaultLimit = 100
+	connectionMaxLimit     = 1000
+)
+
+var ErrInvalidTimeRange = errors.New("时间范围异常")
+
+type Connection struct {
+	Id               string `json:"id" gorm:"primaryKey"`
+	InstanceUuid     string `json:"instanceUuid"`
+	OrgUuid          string `json:"orgUuid"`
+	GroupUuid        string `json:"groupUuid"`
+	InstanceName     string `json:"instanceName"`
+	ClientIp         string `json:"clientIp"`
+	AgentVersion     string `json:"agentVersion"`
+	Node             string `json:"node"` // 处理该连接的 commander 节点
+	ConnectTime      int64  `json:"connectTime"`
+	DisconnectTime   int64  `json:"disconnectTime"` // 0 表示未断开
+	DisconnectReason string `json:"disconnectReason"`
+}
+
+func (connection *Connection) TableName() string {
+	return "instance_connection"
+}
+
+type ConnectionRepo interface {
+	CreateConnection(ctx context.Context, connection Connection) error
+	CloseConnection(ctx context.Context, id string, disconnectTime int64, reason string) error
+	CloseInstanceConnections(ctx context.Context, instanceUuid string, disconnectTime int64, reason string) error
+	ListConnections(ctx context.Context, instanceUuid string, startTime, endTime int64, limit int) ([]Connection, error)
+	DeleteConnections(ctx context.Context, instanceUuid string) error
+}
+
+// 连接断开原因，err 为读取消息返回的错误
+
+func DisconnectReason(err error) string {
+	if _, ok := err.(*websocket.CloseError); ok {
+		return DisconnectClosed
+	}
+	
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return DisconnectReadTimeout
+	}
+	
+	return DisconnectReadError
+}
+
+type UptimeSegment struct {
+	Online    bool  `json:"online"`
+	StartTime int64 `json:"startTime"`
+	EndTime   int64 `json:"endTime"`
+}
+
+type InstanceUptime struct {
+	StartTime     int64           `json:"startTime"`
+	EndTime       int64           `json:"endTime"`
+	OnlineSeconds int64           `json:"onlineSeconds"`
+	Uptime        float64         `json:"uptime"`       // 在线时间占比 (百分比)
+	ConnectCount  int             `json:"connectCount"` // 时间范围内的连接次数
+	Timeline      []UptimeSegment `json:"timeline"`
+}
+
+// 按连接记录计算 [startTime, endTime) 内的在线时间线，未断开的连接视为在线到 now
+
+func ComputeUptime(connections []Connection, startTime, endTime, now int64) InstanceUptime {
+	uptime := InstanceUptime{StartTime: startTime, EndTime: endTime, Timeline: []UptimeSegment{}}
+	
+	// 合并重叠的在线区间
+	var online []UptimeSegment
+	sorted := append([]Connection(nil), connections...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ConnectTime < sorted[j].ConnectTime
+	})
+	
+	for _, connection := range sorted {
+		start, end := connection.ConnectTime, connection.DisconnectTime
+		if end == 0 {
+			end = now
+		}
+		
+		if start >= startTime && start < endTime {
+			uptime.ConnectCount++
+		}
+		
+		start, end = max(start, startTime), min(end, endTime)
+		if start >= end {
+			continue
+		}
+		
+		if n := len(online); n > 0 && start <= online[n-1].EndTime {
+			online[n-1].EndTime = max(online[n-1].EndTime, end)
+			continue
+		}
+		online = append(online, UptimeSegment{Online: true, StartTime: start, EndTime: end})
+	}
+	
+	// 在线区间之间补充离线区间
+	cursor := startTime
+	for _, segment := range online {
+		if segment.StartTime > cursor {
+			uptime.Timeline = append(uptime.Timeline, UptimeSegment{StartTime: cursor, EndTime: segment.StartTime})
+		}
+		uptime.Timeline = append(uptime.Timeline, segment)
+		uptime.OnlineSeconds += segment.EndTime - segment.StartTime
+		cursor = segment.EndTime
+	}
+	
+	if cursor < endTime {
+		uptime.Timeline = append(uptime.Timeline, UptimeSegment{StartTime: cursor, EndTime: endTime})
+	}
+	
+	if endTime > startTime {
+		uptime.Uptime = float64(uptime.OnlineSeconds) * 100 / float64(endTime-startTime)
+	}
+	
+	return uptime
+}
+
+// 列出实例在时间范围内的连接记录，按连接时间倒序
+
+func (instanceUseCase *InstanceUseCase) ListConnections(ctx context.Context, orgUuid, groupUuid, instanceName string, startTime, endTime int64, limit int) ([]Connection, error) {
+	instance, err := instanceUseCase.instanceRepo.Get(ctx, orgUuid, groupUuid, instanceName)
+	if err != nil {
+		return nil, err
+	}
+	
+	if endTime == 0 {
+		endTime = time.Now().Unix()
+	}
+	
+	if startTime > endTime {
+		return nil, ErrInvalidTimeRange
+	}
+	
+	if limit <= 0 {
+		limit = connectionDefaultLimit
+	}
+	
+	if limit > connectionMaxLimit {
+		limit = connectionMaxLimit
+	}
+	
+	return instanceUseCase.connectionRepo.ListConnections(ctx, instance.Uuid, startTime, endTime, limit)
+}
+
+// 计算实例在时间范围内的在线时间线及在线时间占比，默认最近 24 小时，最长 31 天；早于实例首次连接的时间不计入
+
+func (instanceUseCase *InstanceUseCase) GetUptime(ctx context.Context, orgUuid, groupUuid, instanceName string, startTime, endTime int64) (InstanceUptime, error) {
+	instance, err := instanceUseCase.instanceRepo.Get(ctx, orgUuid, groupUuid, instanceName)
+	if err != nil {
+		return InstanceUptime{}, err
+	}
+	
+	now := time.Now().Unix()
+	if endTime == 0 || endTime > now {
+		endTime = now
+	}
+	
+	if startTime == 0 {
+		startTime = endTime - int64(DefaultUptimeWindow.Seconds())
+	}
+	
+	if startTime >= endTime || endTime-startTime > int64(MaxUptimeWindow.Seconds()) {
+		return InstanceUptime{}, ErrInvalidTimeRange
+	}
+	
+	startTime = max(startTime, min(instance.CreateTime, endTime))
+	
+	connections, err := instanceUseCase.connectionRepo.ListConnections(ctx, instance.Uuid, startTime, endTime, 0)
+	if err != nil {
+		return InstanceUptime{}, err
+	}
+	
+	return ComputeUptime(connections, startTime, endTime, now), nil
+}
@@ -0,0 +1,41 @@
+package biz
+
+import (
+	"errors"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestComputeUptime(t *testing.T) {
+	connections := []Connection{
+		{ConnectTime: 50, DisconnectTime: 150},
+		{ConnectTime: 140, DisconnectTime: 200}, // 与上一个连接重叠
+		{ConnectTime: 300, DisconnectTime: 350},
+		{ConnectTime: 900}, // 未断开，在线到 now
+	}
+	
+	uptime := ComputeUptime(connections, 100, 1000, 950)
+	assert.Equal(t, int64(100+50+50), uptime.OnlineSeconds)
+	assert.InDelta(t, 200.0/9, uptime.Uptime, 0.001)
+	assert.Equal(t, 3, uptime.ConnectCount)
+	assert.Equal(t, []UptimeSegment{
+		{Online: true, StartTime: 100, EndTime: 200},
+		{StartTime: 200, EndTime: 300},
+		{Online: true, StartTime: 300, EndTime: 350},
+		{StartTime: 350, EndTime: 900},
+		{Online: true, StartTime: 900, EndTime: 950},
+		{StartTime: 950, EndTime: 1000},
+	}, uptime.Timeline)
+	
+	uptime = ComputeUptime(nil, 100, 200, 200)
+	assert.Equal(t, float64(0), uptime.Uptime)
+	assert.Equal(t, []UptimeSegment{{StartTime: 100, EndTime: 200}}, uptime.Timeline)
+}
+
+func TestDisconnectReason(t *testing.T) {
+	assert.Equal(t, DisconnectClosed, DisconnectReason(&websocket.CloseError{Code: websocket.CloseNormalClosure}))
+	assert.Equal(t, DisconnectReadTimeout, DisconnectReason(os.ErrDeadlineExceeded))
+	assert.Equal(t, DisconnectReadError, DisconnectReason(errors.New("connection reset by peer")))
+}
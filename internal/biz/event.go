@@ -0,0 +1,426 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// 指令事件
+//
+// 指令下发、结束 (收到结果、下发失败、会话中断) 及取消时发布事件，通过 Redis Pub/Sub 广播到所有 commander 节点，
+// 各节点推送给本节点的订阅者 (GET /v1/events，SSE)；webhook 由发布事件的节点投递，每个事件只投递一次，失败时重试
+// 事件不持久化，订阅者断开期间的事件需通过指令查询接口补齐
+//
+// 同步下发 (IssueAndWait) 在下发前订阅实例的事件，soldier 连接在任一节点时都能收到指令结束的事件；
+// 事件可能丢失 (订阅者未及时读取、重新订阅期间)，等待期间另按 instructWaitPollInterval 读取指令记录
+
+const (
+	EventIssued    = "issued"    // 指令已下发到实例指令队列
+	EventCompleted = "completed" // 指令已结束，Result 为执行结果
+	EventCancelled = "cancelled" // 指令已取消
+	
+	WebhookEventHeader     = "X-Camp-Event"
+	WebhookSignatureHeader = "X-Camp-Signature" // sha256=<hex>，请求体的 HMAC-SHA256
+	
+	DefaultWebhookTimeout = 5 * time.Second
+	MaxInstructWait       = 5 * time.Minute
+	
+	eventReplyMaxSize     = 64 * 1024 // 事件中的指令结果超过该长度时截断，完整结果通过指令接口获取
+	eventSubscriberBuffer = 64        // 订阅者未及时读取时丢弃事件
+	eventResubscribeWait  = time.Second
+	
+	instructWaitPollInterval = 2 * time.Second
+	
+	webhookQueueSize = 1024
+	webhookWorkers   = 4
+	webhookRetries   = 3
+	webhookRetryWait = 2 * time.Second
+)
+
+type InstructEvent struct {
+	Type         string          `json:"type"`
+	InstructUuid string          `json:"instructUuid"`
+	OrgUuid      string          `json:"orgUuid"`
+	GroupUuid    string          `json:"groupUuid"`
+	InstanceName string          `json:"instanceName"`
+	BatchUuid    string          `json:"batchUuid,omitempty"`
+	InstructType InstructType    `json:"instructType"`
+	Operator     string          `json:"operator,omitempty"`
+	Result       int32           `json:"result"` // 0 执行中，1 成功，-1 失败
+	Reply        string          `json:"reply,omitempty"`
+	Truncated    bool            `json:"truncated,omitempty"`
+	ProbeResult  *InstructResult `json:"probeResult,omitempty"` // 探测指令的结构化结果
+	Time         int64           `json:"time"`
+}
+
+// 事件的过滤条件，为空的条件不过滤
+
+type EventFilter struct {
+	OrgUuid      string
+	GroupUuid    string
+	InstanceName string
+	BatchUuid    string
+	InstructUuid string
+	Types        []string
+}
+
+func (eventFilter EventFilter) Match(event InstructEvent) bool {
+	if eventFilter.OrgUuid != "" && eventFilter.OrgUuid != event.OrgUuid ||
+		eventFilter.GroupUuid != "" && eventFilter.GroupUuid != event.GroupUuid ||
+		eventFilter.InstanceName != "" && eventFilter.InstanceName != event.InstanceName ||
+		eventFilter.BatchUuid != "" && eventFilter.BatchUuid != event.BatchUuid ||
+		eventFilter.InstructUuid != "" && eventFilter.InstructUuid != event.InstructUuid {
+		return false
+	}
+	
+	if len(eventFilter.Types) == 0 {
+		return true
+	}
+	
+	for _, t := range eventFilter.Types {
+		if t == event.Type {
+			return true
+		}
+	}
+	return false
+}
+
+type Webhook struct {
+	Url     string
+	Secret  string
+	Filter  EventFilter
+	Timeout time.Duration
+}
+
+func ParseWebhook(rawUrl, secret string, filter EventFilter, timeout time.Duration) (Webhook, error) {
+	webhook := Webhook{Url: rawUrl, Secret: secret, Filter: filter, Timeout: timeout}
+	
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return webhook, err
+	}
+	
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return webhook, fmt.Errorf("webhook 地址异常: %s", rawUrl)
+	}
+	
+	for _, t := range filter.Types {
+		if t != EventIssued && t != EventCompleted && t != EventCancelled {
+			return webhook, fmt.Errorf("未知的事件类型: %s", t)
+		}
+	}
+	
+	if webhook.Timeout <= 0 {
+		webhook.Timeout = DefaultWebhookTimeout
+	}
+	return webhook, nil
+}
+
+// 请求体签名，接收方以相同密钥计算后比较
+
+func (webhook *Webhook) Sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(webhook.Secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+type WebhookPolicy struct {
+	Webhooks []Webhook
+}
+
+type EventRepo interface {
+	PublishEvent(ctx context.Context, event InstructEvent) error
+	// 接收所有节点发布的事件，订阅断开或 ctx 结束时返回
+	SubscribeEvents(ctx context.Context, handler func(event InstructEvent)) error
+}
+
+type eventSubscriber struct {
+	filter EventFilter
+	events chan InstructEvent
+}
+
+type webhookDelivery struct {
+	webhook *Webhook
+	event   InstructEvent
+	body    []byte
+}
+
+type EventUseCase struct {
+	eventRepo    EventRepo
+	instructRepo InstructRepo
+	resultRepo   ResultRepo
+	policy       *WebhookPolicy
+	client       *http.Client
+	deliveries   chan webhookDelivery
+	mu           sync.Mutex
+	subscribers  map[*eventSubscriber]struct{}
+	logger       *zap.Logger
+}
+
+func NewEventUseCase(eventRepo EventRepo, instructRepo InstructRepo, resultRepo ResultRepo, policy *WebhookPolicy, logger *zap.Logger) *EventUseCase {
+	return &EventUseCase{
+		eventRepo:    eventRepo,
+		instructRepo: instructRepo,
+		resultRepo:   resultRepo,
+		policy:       policy,
+		client:       &http.Client{},
+		deliveries:   make(chan webhookDelivery, webhookQueueSize),
+		subscribers:  make(map[*eventSubscriber]struct{}),
+		logger:       logger,
+	}
+}
+
+// 接收所有节点发布的事件并推送给本节点的订阅者，同时投递 webhook
+
+func (eventUseCase *EventUseCase) Run(ctx context.Context) {
+	for i := 0; i < webhookWorkers; i++ {
+		go eventUseCase.deliverWebhooks(ctx)
+	}
+	
+	for {
+		err := eventUseCase.eventRepo.SubscribeEvents(ctx, eventUseCase.dispatch)
+		if ctx.Err() != nil {
+			return
+		}
+		
+		eventUseCase.logger.Error("接收指令事件失败，重新订阅", zap.Error(err))
+		select {
+		case <-time.After(eventResubscribeWait):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// 订阅本节点收到的事件，返回的取消函数须在订阅结束时调用
+
+func (eventUseCase *EventUseCase) Subscribe(filter EventFilter) (<-chan InstructEvent, func()) {
+	subscriber := &eventSubscriber{filter: filter, events: make(chan InstructEvent, eventSubscriberBuffer)}
+	
+	eventUseCase.mu.Lock()
+	eventUseCase.subscribers[subscriber] = struct{}{}
+	eventUseCase.mu.Unlock()
+	
+	return subscriber.events, func() {
+		eventUseCase.mu.Lock()
+		delete(eventUseCase.subscribers, subscriber)
+		eventUseCase.mu.Unlock()
+	}
+}
+
+func (eventUseCase *EventUseCase) dispatch(event InstructEvent) {
+	eventUseCase.mu.Lock()
+	defer eventUseCase.mu.Unlock()
+	
+	for subscriber := range eventUseCase.subscribers {
+		if !subscriber.filter.Match(event) {
+			continue
+		}
+		
+		select {
+		case subscriber.events <- event:
+		default:
+			eventUseCase.logger.Warn("订阅者未及时读取，丢弃指令事件", zap.String("uuid", event.InstructUuid), zap.String("type", event.Type))
+		}
+	}
+}
+
+// 发布指令事件，probe 为探测指令的结构化结果
+
+func (eventUseCase *EventUseCase) Publish(ctx context.Context, eventType string, instruct Instruct, probe *InstructResult) {
+	event := InstructEvent{
+		Type:         eventType,
+		InstructUuid: instruct.Uuid,
+		OrgUuid:      instruct.OrgUuid,
+		GroupUuid:    instruct.GroupUuid,
+		InstanceName: instruct.InstanceName,
+		BatchUuid:    instruct.BatchUuid,
+		InstructType: instruct.Type,
+		Operator:     instruct.Operator,
+		Result:       instruct.Result,
+		Reply:        instruct.Reply,
+		Truncated:    instruct.Truncated,
+		Time:         time.Now().Unix(),
+	}
+	
+	if len(event.Reply) > eventReplyMaxSize {
+		event.Reply, _ = truncateString(event.Reply, len(event.Reply)-eventReplyMaxSize)
+		event.Truncated = true
+	}
+	
+	if probe != nil {
+		result := *probe
+		result.OrgUuid = instruct.OrgUuid
+		result.GroupUuid = instruct.GroupUuid
+		result.InstanceName = instruct.InstanceName
+		result.BatchUuid = instruct.BatchUuid
+		event.ProbeResult = &result
+	}
+	
+	err := eventUseCase.eventRepo.PublishEvent(ctx, event)
+	if err != nil {
+		eventUseCase.logger.Error("发布指令事件失败", zap.String("uuid", event.InstructUuid), zap.String("type", eventType), zap.Error(err))
+	}
+	
+	eventUseCase.enqueueWebhooks(event)
+}
+
+// 指令结果已更新，读取指令记录后发布事件
+
+func (eventUseCase *EventUseCase) PublishUpdate(ctx context.Context, eventType string, instructUuid string, probe *InstructResult) {
+	instruct, err := eventUseCase.instructRepo.FindInstruct(ctx, instructUuid)
+	if err != nil {
+		eventUseCase.logger.Error("获取指令失败，未发布指令事件", zap.String("uuid", instructUuid), zap.Error(err))
+		return
+	}
+	eventUseCase.Publish(ctx, eventType, instruct, probe)
+}
+
+// 等待指令结束，events 为下发前的订阅；超过 timeout 时返回仍在执行的指令
+
+func (eventUseCase *EventUseCase) Wait(ctx context.Context, events <-chan InstructEvent, instructUuid string, timeout time.Duration) (Instruct, *InstructResult, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	
+	ticker := time.NewTicker(instructWaitPollInterval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case event := <-events:
+			if event.InstructUuid == instructUuid {
+				return eventUseCase.load(ctx, instructUuid)
+			}
+		
+		case <-ticker.C:
+			instruct, probe, err := eventUseCase.load(ctx, instructUuid)
+			if err != nil || instruct.Result != 0 {
+				return instruct, probe, err
+			}
+		
+		case <-timer.C:
+			return eventUseCase.load(ctx, instructUuid)
+		
+		case <-ctx.Done():
+			return Instruct{Uuid: instructUuid}, nil, ctx.Err()
+		}
+	}
+}
+
+// 读取指令记录 (事件中的结果可能已截断)，已结束的探测指令同时读取结构化结果，下发失败等没有结果的为 nil
+
+func (eventUseCase *EventUseCase) load(ctx context.Context, instructUuid string) (Instruct, *InstructResult, error) {
+	instruct, err := eventUseCase.instructRepo.FindInstruct(ctx, instructUuid)
+	if err != nil || instruct.Result == 0 || !IsProbeInstruct(instruct.Type) {
+		return instruct, nil, err
+	}
+	
+	result, err := eventUseCase.resultRepo.GetResult(ctx, instructUuid)
+	if errors.Is(err, ErrResultNotFound) {
+		return instruct, nil, nil
+	}
+	
+	if err != nil {
+		return instruct, nil, err
+	}
+	return instruct, &result, nil
+}
+
+func (eventUseCase *EventUseCase) enqueueWebhooks(event InstructEvent) {
+	var body []byte
+	for i := range eventUseCase.policy.Webhooks {
+		webhook := &eventUseCase.policy.Webhooks[i]
+		if !webhook.Filter.Match(event) {
+			continue
+		}
+		
+		if body == nil {
+			var err error
+			body, err = json.Marshal(event)
+			if err != nil {
+				eventUseCase.logger.Error("序列化指令事件失败", zap.String("uuid", event.InstructUuid), zap.Error(err))
+				return
+			}
+		}
+		
+		select {
+		case eventUseCase.deliveries <- webhookDelivery{webhook: webhook, event: event, body: body}:
+		default:
+			eventUseCase.logger.Warn("webhook 队列已满，丢弃指令事件", zap.String("url", webhook.Url), zap.String("uuid", event.InstructUuid))
+		}
+	}
+}
+
+func (eventUseCase *EventUseCase) deliverWebhooks(ctx context.Context) {
+	for {
+		select {
+		case delivery := <-eventUseCase.deliveries:
+			eventUseCase.deliver(ctx, delivery)
+		
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (eventUseCase *EventUseCase) deliver(ctx context.Context, delivery webhookDelivery) {
+	var err error
+	for attempt := 0; attempt <= webhookRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(webhookRetryWait * time.Duration(attempt)):
+			case <-ctx.Done():
+				return
+			}
+		}
+		
+		err = eventUseCase.post(ctx, delivery)
+		if err == nil {
+			return
+		}
+	}
+	
+	eventUseCase.logger.Error("投递 webhook 失败",
+		zap.String("url", delivery.webhook.Url),
+		zap.String("uuid", delivery.event.InstructUuid),
+		zap.String("type", delivery.event.Type),
+		zap.Error(err))
+}
+
+func (eventUseCase *EventUseCase) post(ctx context.Context, delivery webhookDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, delivery.webhook.Timeout)
+	defer cancel()
+	
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.webhook.Url, bytes.NewReader(delivery.body))
+	if err != nil {
+		return err
+	}
+	
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.event.Type)
+	if delivery.webhook.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, delivery.webhook.Sign(delivery.body))
+	}
+	
+	resp, err := eventUseCase.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook 响应状态码: %d", resp.StatusCode)
+	}
+	return nil
+}
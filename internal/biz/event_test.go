@@ -0,0 +1,129 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestEventUseCase(instructRepo InstructRepo, policy *WebhookPolicy) (*EventUseCase, *fakeEventRepo) {
+	eventRepo := &fakeEventRepo{}
+	eventUseCase := NewEventUseCase(eventRepo, instructRepo, newFakeResultRepo(), policy, zap.NewNop())
+	eventRepo.handler = eventUseCase.dispatch
+	return eventUseCase, eventRepo
+}
+
+func TestEventFilter_Match(t *testing.T) {
+	event := InstructEvent{Type: EventCompleted, InstructUuid: "u1", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "n1", BatchUuid: "b1"}
+	
+	assert.True(t, EventFilter{}.Match(event))
+	assert.True(t, EventFilter{OrgUuid: "o1", GroupUuid: "g1", InstanceName: "n1"}.Match(event))
+	assert.True(t, EventFilter{BatchUuid: "b1", Types: []string{EventIssued, EventCompleted}}.Match(event))
+	assert.False(t, EventFilter{OrgUuid: "o2"}.Match(event))
+	assert.False(t, EventFilter{OrgUuid: "o1", InstanceName: "n2"}.Match(event))
+	assert.False(t, EventFilter{InstructUuid: "u2"}.Match(event))
+	assert.False(t, EventFilter{Types: []string{EventCancelled}}.Match(event))
+}
+
+// 结果更新后按过滤条件推送给订阅者，探测结果补齐实例信息，超长的结果截断
+
+func TestEventUseCase_PublishUpdate(t *testing.T) {
+	ctx := context.Background()
+	instructRepo := &fakeInstructRepo{instruct: map[string]Instruct{
+		"u1": {Uuid: "u1", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "n1", Type: HttpInstruct, Result: 1, Reply: "ok"},
+		"u2": {Uuid: "u2", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "n2", Type: CommandInstruct, Result: 1, Reply: strings.Repeat("中", eventReplyMaxSize)},
+	}}
+	eventUseCase, _ := newTestEventUseCase(instructRepo, &WebhookPolicy{})
+	
+	n1, cancel1 := eventUseCase.Subscribe(EventFilter{OrgUuid: "o1", InstanceName: "n1"})
+	defer cancel1()
+	all, cancel2 := eventUseCase.Subscribe(EventFilter{OrgUuid: "o1"})
+	other, cancel3 := eventUseCase.Subscribe(EventFilter{OrgUuid: "o2"})
+	defer cancel3()
+	
+	eventUseCase.PublishUpdate(ctx, EventCompleted, "u1", &InstructResult{InstructUuid: "u1", Type: HttpInstruct, StatusCode: 200})
+	eventUseCase.PublishUpdate(ctx, EventCompleted, "u2", nil)
+	eventUseCase.PublishUpdate(ctx, EventCompleted, "missing", nil)
+	
+	event := <-n1
+	assert.Equal(t, "u1", event.InstructUuid)
+	assert.Equal(t, int32(1), event.Result)
+	assert.Equal(t, "n1", event.ProbeResult.InstanceName)
+	assert.Equal(t, int32(200), event.ProbeResult.StatusCode)
+	assert.Len(t, n1, 0)
+	
+	assert.Equal(t, "u1", (<-all).InstructUuid)
+	event = <-all
+	assert.Equal(t, "u2", event.InstructUuid)
+	assert.True(t, event.Truncated)
+	assert.LessOrEqual(t, len(event.Reply), eventReplyMaxSize)
+	assert.True(t, strings.HasSuffix(event.Reply, "中"))
+	assert.Len(t, other, 0)
+	
+	// 取消订阅后不再推送
+	cancel2()
+	eventUseCase.PublishUpdate(ctx, EventCompleted, "u2", nil)
+	assert.Len(t, all, 0)
+}
+
+// webhook 按过滤条件投递，请求体以密钥签名
+
+func TestEventUseCase_Webhook(t *testing.T) {
+	_, err := ParseWebhook("ftp://example.com", "", EventFilter{}, 0)
+	assert.Error(t, err)
+	_, err = ParseWebhook("https://example.com", "", EventFilter{Types: []string{"unknown"}}, 0)
+	assert.Error(t, err)
+	
+	received := make(chan *http.Request, 2)
+	bodies := make(chan []byte, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer server.Close()
+	
+	completed, err := ParseWebhook(server.URL+"/completed", "secret", EventFilter{OrgUuid: "o1", Types: []string{EventCompleted}}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultWebhookTimeout, completed.Timeout)
+	other, err := ParseWebhook(server.URL+"/other", "", EventFilter{OrgUuid: "o2"}, time.Second)
+	assert.NoError(t, err)
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	eventUseCase, _ := newTestEventUseCase(&fakeInstructRepo{instruct: make(map[string]Instruct)}, &WebhookPolicy{Webhooks: []Webhook{completed, other}})
+	go eventUseCase.Run(ctx)
+	
+	instruct := Instruct{Uuid: "u1", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "n1", Type: CommandInstruct}
+	eventUseCase.Publish(ctx, EventIssued, instruct, nil)
+	instruct.Result = 1
+	eventUseCase.Publish(ctx, EventCompleted, instruct, nil)
+	
+	select {
+	case r := <-received:
+		body := <-bodies
+		assert.Equal(t, "/completed", r.URL.Path)
+		assert.Equal(t, EventCompleted, r.Header.Get(WebhookEventHeader))
+		assert.Equal(t, completed.Sign(body), r.Header.Get(WebhookSignatureHeader))
+		
+		var event InstructEvent
+		assert.NoError(t, json.Unmarshal(body, &event))
+		assert.Equal(t, "u1", event.InstructUuid)
+		assert.Equal(t, int32(1), event.Result)
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook 未投递")
+	}
+	
+	select {
+	case r := <-received:
+		t.Fatalf("不应投递: %s", r.URL.Path)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
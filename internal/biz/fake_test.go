@@ -0,0 +1,912 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+type fakeInstructRepo struct {
+	InstructRepo
+	mu       sync.Mutex
+	instruct map[string]Instruct
+}
+
+func (fakeInstructRepo *fakeInstructRepo) RecordInstruct(ctx context.Context, instruct Instruct) error {
+	fakeInstructRepo.mu.Lock()
+	defer fakeInstructRepo.mu.Unlock()
+	fakeInstructRepo.instruct[instruct.Uuid] = instruct
+	return nil
+}
+
+func (fakeInstructRepo *fakeInstructRepo) UpdateInstruct(ctx context.Context, uuid, reply string, result int32) (int64, error) {
+	fakeInstructRepo.mu.Lock()
+	defer fakeInstructRepo.mu.Unlock()
+	instruct, ok := fakeInstructRepo.instruct[uuid]
+	if !ok || instruct.Result != 0 {
+		return 0, nil
+	}
+	instruct.Reply = reply
+	instruct.Result = result
+	fakeInstructRepo.instruct[uuid] = instruct
+	return 1, nil
+}
+
+func (fakeInstructRepo *fakeInstructRepo) UpdateInterruptedInstruct(ctx context.Context, uuid, reply string, result int32) (int64, error) {
+	fakeInstructRepo.mu.Lock()
+	defer fakeInstructRepo.mu.Unlock()
+	instruct, ok := fakeInstructRepo.instruct[uuid]
+	if !ok || !instruct.Interrupted() {
+		return 0, nil
+	}
+	instruct.Reply = reply
+	instruct.Result = result
+	fakeInstructRepo.instruct[uuid] = instruct
+	return 1, nil
+}
+
+func (fakeInstructRepo *fakeInstructRepo) DeleteInstructQueue(ctx context.Context, orgUuid, groupUuid, instanceName string) error {
+	return nil
+}
+
+func (fakeInstructRepo *fakeInstructRepo) DeleteGroupInstructs(ctx context.Context, orgUuid, groupUuid string, limit int) (int64, error) {
+	fakeInstructRepo.mu.Lock()
+	defer fakeInstructRepo.mu.Unlock()
+	var deleted int64
+	for uuid, instruct := range fakeInstructRepo.instruct {
+		if instruct.OrgUuid == orgUuid && instruct.GroupUuid == groupUuid && deleted < int64(limit) {
+			delete(fakeInstructRepo.instruct, uuid)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (fakeInstructRepo *fakeInstructRepo) FindInstruct(ctx context.Context, uuid string) (Instruct, error) {
+	fakeInstructRepo.mu.Lock()
+	defer fakeInstructRepo.mu.Unlock()
+	instruct, ok := fakeInstructRepo.instruct[uuid]
+	if !ok {
+		return instruct, gorm.ErrRecordNotFound
+	}
+	return instruct, nil
+}
+
+func (fakeInstructRepo *fakeInstructRepo) IssueInstructions(ctx context.Context, orgUuid, groupUuid, instanceName string, instruct []byte) error {
+	return nil
+}
+
+func (fakeInstructRepo *fakeInstructRepo) QueryInstructs(ctx context.Context, query InstructQuery) ([]Instruct, error) {
+	fakeInstructRepo.mu.Lock()
+	defer fakeInstructRepo.mu.Unlock()
+	var instructs []Instruct
+	for _, instruct := range fakeInstructRepo.instruct {
+		if query.Type != 0 && instruct.Type != query.Type || query.Result != nil && instruct.Result != *query.Result || query.Operator != "" && instruct.Operator != query.Operator || query.BatchUuid != "" && instruct.BatchUuid != query.BatchUuid {
+			continue
+		}
+		
+		if query.After != nil && (instruct.CreateTime > query.After.CreateTime || instruct.CreateTime == query.After.CreateTime && instruct.Uuid >= query.After.Uuid) {
+			continue
+		}
+		instructs = append(instructs, instruct)
+	}
+	
+	sort.Slice(instructs, func(i, j int) bool {
+		if instructs[i].CreateTime != instructs[j].CreateTime {
+			return instructs[i].CreateTime > instructs[j].CreateTime
+		}
+		return instructs[i].Uuid > instructs[j].Uuid
+	})
+	return instructs[:min(len(instructs), query.Limit)], nil
+}
+
+type fakeBlobRepo struct {
+	BlobRepo
+	mu           sync.Mutex
+	staging      map[string][]byte
+	blobs        map[string][]byte
+	instructRepo *fakeInstructRepo
+}
+
+func (fakeBlobRepo *fakeBlobRepo) WriteChunk(ctx context.Context, uuid string, offset int64, data []byte) error {
+	fakeBlobRepo.mu.Lock()
+	defer fakeBlobRepo.mu.Unlock()
+	fakeBlobRepo.staging[uuid] = append(fakeBlobRepo.staging[uuid][:offset], data...)
+	return nil
+}
+
+func (fakeBlobRepo *fakeBlobRepo) Verify(ctx context.Context, uuid string, size int64, sha256 string) error {
+	fakeBlobRepo.mu.Lock()
+	defer fakeBlobRepo.mu.Unlock()
+	if int64(len(fakeBlobRepo.staging[uuid])) != size {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
+
+func (fakeBlobRepo *fakeBlobRepo) Discard(ctx context.Context, uuid string) error {
+	fakeBlobRepo.mu.Lock()
+	defer fakeBlobRepo.mu.Unlock()
+	delete(fakeBlobRepo.staging, uuid)
+	return nil
+}
+
+func (fakeBlobRepo *fakeBlobRepo) Commit(ctx context.Context, uuid string, size int64, sha256 string) (Blob, error) {
+	fakeBlobRepo.mu.Lock()
+	defer fakeBlobRepo.mu.Unlock()
+	fakeBlobRepo.blobs[uuid] = fakeBlobRepo.staging[uuid]
+	delete(fakeBlobRepo.staging, uuid)
+	return Blob{Uuid: uuid, Size: size, Sha256: sha256}, nil
+}
+
+func (fakeBlobRepo *fakeBlobRepo) DeleteGroupBlobs(ctx context.Context, orgUuid, groupUuid string, limit int) (int64, error) {
+	fakeBlobRepo.mu.Lock()
+	defer fakeBlobRepo.mu.Unlock()
+	fakeBlobRepo.instructRepo.mu.Lock()
+	defer fakeBlobRepo.instructRepo.mu.Unlock()
+	var deleted int64
+	for uuid := range fakeBlobRepo.blobs {
+		instruct, ok := fakeBlobRepo.instructRepo.instruct[uuid]
+		if ok && instruct.OrgUuid == orgUuid && instruct.GroupUuid == groupUuid && instruct.Type != FilePutInstruct && deleted < int64(limit) {
+			delete(fakeBlobRepo.blobs, uuid)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func newFakeRepos() (*fakeInstructRepo, *fakeBlobRepo) {
+	instructRepo := &fakeInstructRepo{instruct: make(map[string]Instruct)}
+	return instructRepo, &fakeBlobRepo{staging: make(map[string][]byte), blobs: make(map[string][]byte), instructRepo: instructRepo}
+}
+
+type fakePresenceRepo struct {
+	mu        sync.Mutex
+	presences map[string]Presence
+	events    []PresenceEvent
+	locked    bool
+}
+
+func newFakePresenceRepo() *fakePresenceRepo {
+	return &fakePresenceRepo{presences: make(map[string]Presence)}
+}
+
+func (fakePresenceRepo *fakePresenceRepo) SetPresence(ctx context.Context, presence Presence, ttl time.Duration) error {
+	fakePresenceRepo.mu.Lock()
+	defer fakePresenceRepo.mu.Unlock()
+	fakePresenceRepo.presences[presence.InstanceUuid] = presence
+	return nil
+}
+
+func (fakePresenceRepo *fakePresenceRepo) RefreshPresence(ctx context.Context, presence Presence, ttl time.Duration) (bool, error) {
+	fakePresenceRepo.mu.Lock()
+	defer fakePresenceRepo.mu.Unlock()
+	current, ok := fakePresenceRepo.presences[presence.InstanceUuid]
+	if ok && current != presence {
+		return false, nil
+	}
+	fakePresenceRepo.presences[presence.InstanceUuid] = presence
+	return true, nil
+}
+
+func (fakePresenceRepo *fakePresenceRepo) DeletePresence(ctx context.Context, presence Presence) (bool, error) {
+	fakePresenceRepo.mu.Lock()
+	defer fakePresenceRepo.mu.Unlock()
+	if fakePresenceRepo.presences[presence.InstanceUuid] != presence {
+		return false, nil
+	}
+	delete(fakePresenceRepo.presences, presence.InstanceUuid)
+	return true, nil
+}
+
+func (fakePresenceRepo *fakePresenceRepo) ListPresence(ctx context.Context, instanceUuids []string) (map[string]Presence, error) {
+	fakePresenceRepo.mu.Lock()
+	defer fakePresenceRepo.mu.Unlock()
+	presences := make(map[string]Presence)
+	for _, instanceUuid := range instanceUuids {
+		if presence, ok := fakePresenceRepo.presences[instanceUuid]; ok {
+			presences[instanceUuid] = presence
+		}
+	}
+	return presences, nil
+}
+
+func (fakePresenceRepo *fakePresenceRepo) AddPresenceEvent(ctx context.Context, event PresenceEvent) error {
+	fakePresenceRepo.mu.Lock()
+	defer fakePresenceRepo.mu.Unlock()
+	fakePresenceRepo.events = append(fakePresenceRepo.events, event)
+	return nil
+}
+
+func (fakePresenceRepo *fakePresenceRepo) ListPresenceEvents(ctx context.Context, after string, count int64, wait time.Duration) ([]PresenceEvent, error) {
+	fakePresenceRepo.mu.Lock()
+	defer fakePresenceRepo.mu.Unlock()
+	return fakePresenceRepo.events, nil
+}
+
+func (fakePresenceRepo *fakePresenceRepo) AcquireReapLock(ctx context.Context, node string, ttl time.Duration) (bool, error) {
+	fakePresenceRepo.mu.Lock()
+	defer fakePresenceRepo.mu.Unlock()
+	if fakePresenceRepo.locked {
+		return false, nil
+	}
+	fakePresenceRepo.locked = true
+	return true, nil
+}
+
+type fakeInstanceRepo struct {
+	InstanceRepo
+	
+	mu        sync.Mutex
+	instances map[string]*Instance
+}
+
+func newFakeInstanceRepo(instances ...Instance) *fakeInstanceRepo {
+	fakeInstanceRepo := &fakeInstanceRepo{instances: make(map[string]*Instance)}
+	for i := range instances {
+		fakeInstanceRepo.instances[instances[i].Uuid] = &instances[i]
+	}
+	return fakeInstanceRepo
+}
+
+func (fakeInstanceRepo *fakeInstanceRepo) ListOnline(ctx context.Context, orgUuid, groupUuid string) ([]Instance, error) {
+	fakeInstanceRepo.mu.Lock()
+	defer fakeInstanceRepo.mu.Unlock()
+	var instances []Instance
+	for _, instance := range fakeInstanceRepo.instances {
+		if instance.Online {
+			instances = append(instances, *instance)
+		}
+	}
+	return instances, nil
+}
+
+func (fakeInstanceRepo *fakeInstanceRepo) Online(ctx context.Context, uuid string) error {
+	fakeInstanceRepo.mu.Lock()
+	defer fakeInstanceRepo.mu.Unlock()
+	fakeInstanceRepo.instances[uuid].Online = true
+	return nil
+}
+
+func (fakeInstanceRepo *fakeInstanceRepo) Offline(ctx context.Context, uuid string) error {
+	fakeInstanceRepo.mu.Lock()
+	defer fakeInstanceRepo.mu.Unlock()
+	fakeInstanceRepo.instances[uuid].Online = false
+	return nil
+}
+
+func (fakeInstanceRepo *fakeInstanceRepo) online(uuid string) bool {
+	fakeInstanceRepo.mu.Lock()
+	defer fakeInstanceRepo.mu.Unlock()
+	return fakeInstanceRepo.instances[uuid].Online
+}
+
+func (fakeInstanceRepo *fakeInstanceRepo) Search(ctx context.Context, query InstanceQuery) ([]Instance, int64, error) {
+	fakeInstanceRepo.mu.Lock()
+	defer fakeInstanceRepo.mu.Unlock()
+	var instances []Instance
+	for _, instance := range fakeInstanceRepo.instances {
+		if query.OrgUuid != "" && instance.OrgUuid != query.OrgUuid || query.GroupUuid != "" && instance.GroupUuid != query.GroupUuid {
+			continue
+		}
+		
+		if query.Online != nil && instance.Online != *query.Online || query.UpdatedBefore > 0 && instance.UpdateTime >= query.UpdatedBefore {
+			continue
+		}
+		
+		if len(query.InstanceNames) > 0 && !slices.Contains(query.InstanceNames, instance.InstanceName) {
+			continue
+		}
+		
+		if len(instances) < query.Limit {
+			instances = append(instances, *instance)
+		}
+	}
+	return instances, int64(len(instances)), nil
+}
+
+func (fakeInstanceRepo *fakeInstanceRepo) Delete(ctx context.Context, uuid string) error {
+	fakeInstanceRepo.mu.Lock()
+	defer fakeInstanceRepo.mu.Unlock()
+	delete(fakeInstanceRepo.instances, uuid)
+	return nil
+}
+
+func (fakeInstanceRepo *fakeInstanceRepo) Get(ctx context.Context, orgUuid, groupUuid, instanceName string) (Instance, error) {
+	fakeInstanceRepo.mu.Lock()
+	defer fakeInstanceRepo.mu.Unlock()
+	for _, instance := range fakeInstanceRepo.instances {
+		if instance.OrgUuid == orgUuid && instance.GroupUuid == groupUuid && instance.InstanceName == instanceName {
+			return *instance, nil
+		}
+	}
+	return Instance{}, errors.New("record not found")
+}
+
+type fakeOrgRepo struct {
+	mu     sync.Mutex
+	orgs   map[string]Org
+	groups map[string]Group
+}
+
+func newFakeOrgRepo() *fakeOrgRepo {
+	return &fakeOrgRepo{orgs: make(map[string]Org), groups: make(map[string]Group)}
+}
+
+func (fakeOrgRepo *fakeOrgRepo) CreateOrg(ctx context.Context, org Org) error {
+	fakeOrgRepo.mu.Lock()
+	defer fakeOrgRepo.mu.Unlock()
+	fakeOrgRepo.orgs[org.Uuid] = org
+	return nil
+}
+
+func (fakeOrgRepo *fakeOrgRepo) UpdateOrg(ctx context.Context, org Org) error {
+	return fakeOrgRepo.CreateOrg(ctx, org)
+}
+
+func (fakeOrgRepo *fakeOrgRepo) GetOrg(ctx context.Context, uuid string) (Org, error) {
+	fakeOrgRepo.mu.Lock()
+	defer fakeOrgRepo.mu.Unlock()
+	org, ok := fakeOrgRepo.orgs[uuid]
+	if !ok {
+		return org, ErrOrgNotFound
+	}
+	return org, nil
+}
+
+func (fakeOrgRepo *fakeOrgRepo) ListOrgs(ctx context.Context, offset, limit int) ([]Org, int64, error) {
+	fakeOrgRepo.mu.Lock()
+	defer fakeOrgRepo.mu.Unlock()
+	var orgs []Org
+	for _, org := range fakeOrgRepo.orgs {
+		if org.Status != OrgDeleted {
+			orgs = append(orgs, org)
+		}
+	}
+	return orgs, int64(len(orgs)), nil
+}
+
+func (fakeOrgRepo *fakeOrgRepo) ListOrgsByStatus(ctx context.Context, status string) ([]Org, error) {
+	fakeOrgRepo.mu.Lock()
+	defer fakeOrgRepo.mu.Unlock()
+	var orgs []Org
+	for _, org := range fakeOrgRepo.orgs {
+		if org.Status == status {
+			orgs = append(orgs, org)
+		}
+	}
+	return orgs, nil
+}
+
+func (fakeOrgRepo *fakeOrgRepo) CreateGroup(ctx context.Context, group Group) error {
+	fakeOrgRepo.mu.Lock()
+	defer fakeOrgRepo.mu.Unlock()
+	fakeOrgRepo.groups[group.OrgUuid+"/"+group.Uuid] = group
+	return nil
+}
+
+func (fakeOrgRepo *fakeOrgRepo) UpdateGroup(ctx context.Context, group Group) error {
+	return fakeOrgRepo.CreateGroup(ctx, group)
+}
+
+func (fakeOrgRepo *fakeOrgRepo) GetGroup(ctx context.Context, orgUuid, uuid string) (Group, error) {
+	fakeOrgRepo.mu.Lock()
+	defer fakeOrgRepo.mu.Unlock()
+	group, ok := fakeOrgRepo.groups[orgUuid+"/"+uuid]
+	if !ok {
+		return group, ErrGroupNotFound
+	}
+	return group, nil
+}
+
+func (fakeOrgRepo *fakeOrgRepo) ListGroups(ctx context.Context, orgUuid string, offset, limit int) ([]Group, int64, error) {
+	fakeOrgRepo.mu.Lock()
+	defer fakeOrgRepo.mu.Unlock()
+	var groups []Group
+	for _, group := range fakeOrgRepo.groups {
+		if group.OrgUuid == orgUuid && group.Status != OrgDeleted {
+			groups = append(groups, group)
+		}
+	}
+	return groups, int64(len(groups)), nil
+}
+
+func (fakeOrgRepo *fakeOrgRepo) ListGroupsByStatus(ctx context.Context, status string) ([]Group, error) {
+	fakeOrgRepo.mu.Lock()
+	defer fakeOrgRepo.mu.Unlock()
+	var groups []Group
+	for _, group := range fakeOrgRepo.groups {
+		if group.Status == status {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+func (fakeOrgRepo *fakeOrgRepo) CountGroups(ctx context.Context, orgUuid string, excludeStatus string) (int64, error) {
+	fakeOrgRepo.mu.Lock()
+	defer fakeOrgRepo.mu.Unlock()
+	var count int64
+	for _, group := range fakeOrgRepo.groups {
+		if group.OrgUuid == orgUuid && group.Status != excludeStatus {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (fakeOrgRepo *fakeOrgRepo) SetGroupsStatus(ctx context.Context, orgUuid string, status string, updateTime int64) error {
+	fakeOrgRepo.mu.Lock()
+	defer fakeOrgRepo.mu.Unlock()
+	for key, group := range fakeOrgRepo.groups {
+		if group.OrgUuid == orgUuid && group.Status != OrgDeleted {
+			group.Status, group.UpdateTime = status, updateTime
+			fakeOrgRepo.groups[key] = group
+		}
+	}
+	return nil
+}
+
+func (fakeOrgRepo *fakeOrgRepo) EnsureGroup(ctx context.Context, org Org, group Group) error {
+	fakeOrgRepo.mu.Lock()
+	defer fakeOrgRepo.mu.Unlock()
+	if _, ok := fakeOrgRepo.orgs[org.Uuid]; !ok {
+		fakeOrgRepo.orgs[org.Uuid] = org
+	}
+	if _, ok := fakeOrgRepo.groups[group.OrgUuid+"/"+group.Uuid]; !ok {
+		fakeOrgRepo.groups[group.OrgUuid+"/"+group.Uuid] = group
+	}
+	return nil
+}
+
+func (fakeOrgRepo *fakeOrgRepo) AcquireCleanupLock(ctx context.Context, node string, ttl time.Duration) (bool, error) {
+	return true, nil
+}
+
+type fakeLabelRepo struct {
+	LabelRepo
+}
+
+func (fakeLabelRepo *fakeLabelRepo) DeleteLabels(ctx context.Context, instanceUuid string) error {
+	return nil
+}
+
+func (fakeLabelRepo *fakeLabelRepo) ListLabels(ctx context.Context, instanceUuids []string) (map[string]map[string]string, error) {
+	return map[string]map[string]string{}, nil
+}
+
+type fakeArchiveRepo struct {
+	mu           sync.Mutex
+	instanceRepo *fakeInstanceRepo
+	instructRepo *fakeInstructRepo
+	instances    map[string]InstanceArchive
+	instructs    map[string]Instruct
+}
+
+func newFakeArchiveRepo(instanceRepo *fakeInstanceRepo, instructRepo *fakeInstructRepo) *fakeArchiveRepo {
+	return &fakeArchiveRepo{
+		instanceRepo: instanceRepo,
+		instructRepo: instructRepo,
+		instances:    make(map[string]InstanceArchive),
+		instructs:    make(map[string]Instruct),
+	}
+}
+
+func (fakeArchiveRepo *fakeArchiveRepo) ArchiveInstructs(ctx context.Context, orgUuid, groupUuid, instanceName string, archiveTime int64, limit int) (int64, error) {
+	fakeArchiveRepo.mu.Lock()
+	defer fakeArchiveRepo.mu.Unlock()
+	fakeArchiveRepo.instructRepo.mu.Lock()
+	defer fakeArchiveRepo.instructRepo.mu.Unlock()
+	var archived int64
+	for uuid, instruct := range fakeArchiveRepo.instructRepo.instruct {
+		if instruct.OrgUuid == orgUuid && instruct.GroupUuid == groupUuid && instruct.InstanceName == instanceName && archived < int64(limit) {
+			fakeArchiveRepo.instructs[uuid] = instruct
+			delete(fakeArchiveRepo.instructRepo.instruct, uuid)
+			archived++
+		}
+	}
+	return archived, nil
+}
+
+func (fakeArchiveRepo *fakeArchiveRepo) ArchiveInstance(ctx context.Context, archive InstanceArchive, offlineBefore int64) (bool, error) {
+	fakeArchiveRepo.mu.Lock()
+	defer fakeArchiveRepo.mu.Unlock()
+	fakeArchiveRepo.instanceRepo.mu.Lock()
+	defer fakeArchiveRepo.instanceRepo.mu.Unlock()
+	instance, ok := fakeArchiveRepo.instanceRepo.instances[archive.Uuid]
+	if !ok || offlineBefore > 0 && (instance.Online || instance.UpdateTime >= offlineBefore) {
+		return false, nil
+	}
+	delete(fakeArchiveRepo.instanceRepo.instances, archive.Uuid)
+	fakeArchiveRepo.instances[archive.Uuid] = archive
+	return true, nil
+}
+
+func (fakeArchiveRepo *fakeArchiveRepo) AcquireArchiveLock(ctx context.Context, node string, ttl time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (fakeArchiveRepo *fakeArchiveRepo) ArchiveInstructsBefore(ctx context.Context, createdBefore, archiveTime int64, limit int) (int64, error) {
+	fakeArchiveRepo.mu.Lock()
+	defer fakeArchiveRepo.mu.Unlock()
+	fakeArchiveRepo.instructRepo.mu.Lock()
+	defer fakeArchiveRepo.instructRepo.mu.Unlock()
+	var archived int64
+	for uuid, instruct := range fakeArchiveRepo.instructRepo.instruct {
+		if instruct.CreateTime < createdBefore && instruct.Result != 0 && archived < int64(limit) {
+			fakeArchiveRepo.instructs[uuid] = instruct
+			delete(fakeArchiveRepo.instructRepo.instruct, uuid)
+			archived++
+		}
+	}
+	return archived, nil
+}
+
+func (fakeArchiveRepo *fakeArchiveRepo) DeleteInstructsBefore(ctx context.Context, createdBefore int64, limit int) (int64, error) {
+	fakeArchiveRepo.instructRepo.mu.Lock()
+	defer fakeArchiveRepo.instructRepo.mu.Unlock()
+	var deleted int64
+	for uuid, instruct := range fakeArchiveRepo.instructRepo.instruct {
+		if instruct.CreateTime < createdBefore && instruct.Result != 0 && deleted < int64(limit) {
+			delete(fakeArchiveRepo.instructRepo.instruct, uuid)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (fakeArchiveRepo *fakeArchiveRepo) TruncateReplies(ctx context.Context, createdBefore int64, maxSize, limit int) (int64, error) {
+	fakeArchiveRepo.instructRepo.mu.Lock()
+	defer fakeArchiveRepo.instructRepo.mu.Unlock()
+	var truncated int64
+	for uuid, instruct := range fakeArchiveRepo.instructRepo.instruct {
+		if instruct.CreateTime < createdBefore && instruct.Result != 0 && utf8.RuneCountInString(instruct.Reply) > maxSize && truncated < int64(limit) {
+			instruct.Reply, instruct.Truncated = string([]rune(instruct.Reply)[:maxSize]), true
+			fakeArchiveRepo.instructRepo.instruct[uuid] = instruct
+			truncated++
+		}
+	}
+	return truncated, nil
+}
+
+func (fakeArchiveRepo *fakeArchiveRepo) DeleteResultsBefore(ctx context.Context, createdBefore int64, limit int) (int64, error) {
+	return 0, nil
+}
+
+func (fakeArchiveRepo *fakeArchiveRepo) AcquireRetentionLock(ctx context.Context, node string, ttl time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (fakeArchiveRepo *fakeArchiveRepo) DeleteGroupInstructArchives(ctx context.Context, orgUuid, groupUuid string, limit int) (int64, error) {
+	fakeArchiveRepo.mu.Lock()
+	defer fakeArchiveRepo.mu.Unlock()
+	var deleted int64
+	for uuid, instruct := range fakeArchiveRepo.instructs {
+		if instruct.OrgUuid == orgUuid && instruct.GroupUuid == groupUuid && deleted < int64(limit) {
+			delete(fakeArchiveRepo.instructs, uuid)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (fakeArchiveRepo *fakeArchiveRepo) DeleteGroupInstanceArchives(ctx context.Context, orgUuid, groupUuid string, limit int) (int64, error) {
+	fakeArchiveRepo.mu.Lock()
+	defer fakeArchiveRepo.mu.Unlock()
+	var deleted int64
+	for uuid, instance := range fakeArchiveRepo.instances {
+		if instance.OrgUuid == orgUuid && instance.GroupUuid == groupUuid && deleted < int64(limit) {
+			delete(fakeArchiveRepo.instances, uuid)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// 发布的事件直接推送给本节点的订阅者
+
+type fakeEventRepo struct {
+	mu      sync.Mutex
+	events  []InstructEvent
+	handler func(event InstructEvent)
+}
+
+func (fakeEventRepo *fakeEventRepo) PublishEvent(ctx context.Context, event InstructEvent) error {
+	fakeEventRepo.mu.Lock()
+	fakeEventRepo.events = append(fakeEventRepo.events, event)
+	fakeEventRepo.mu.Unlock()
+	fakeEventRepo.handler(event)
+	return nil
+}
+
+func (fakeEventRepo *fakeEventRepo) SubscribeEvents(ctx context.Context, handler func(event InstructEvent)) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (fakeEventRepo *fakeEventRepo) published() []InstructEvent {
+	fakeEventRepo.mu.Lock()
+	defer fakeEventRepo.mu.Unlock()
+	return append([]InstructEvent(nil), fakeEventRepo.events...)
+}
+
+type fakeSessionRepo struct {
+	mu        sync.Mutex
+	sessions  map[string]string
+	instances map[string]string
+	pending   map[string]map[string]string
+	err       error // 设置后移除待确认指令返回该错误
+}
+
+func newFakeSessionRepo() *fakeSessionRepo {
+	return &fakeSessionRepo{
+		sessions:  make(map[string]string),
+		instances: make(map[string]string),
+		pending:   make(map[string]map[string]string),
+	}
+}
+
+func (fakeSessionRepo *fakeSessionRepo) CreateSession(ctx context.Context, instanceUuid, token string, ttl time.Duration) (string, error) {
+	fakeSessionRepo.mu.Lock()
+	defer fakeSessionRepo.mu.Unlock()
+	fakeSessionRepo.sessions[token] = instanceUuid
+	previous := fakeSessionRepo.instances[instanceUuid]
+	fakeSessionRepo.instances[instanceUuid] = token
+	return previous, nil
+}
+
+func (fakeSessionRepo *fakeSessionRepo) GetSession(ctx context.Context, token string) (string, error) {
+	fakeSessionRepo.mu.Lock()
+	defer fakeSessionRepo.mu.Unlock()
+	return fakeSessionRepo.sessions[token], nil
+}
+
+func (fakeSessionRepo *fakeSessionRepo) RefreshSession(ctx context.Context, instanceUuid, token string, ttl time.Duration) error {
+	return nil
+}
+
+func (fakeSessionRepo *fakeSessionRepo) DeleteSession(ctx context.Context, token string) error {
+	fakeSessionRepo.mu.Lock()
+	defer fakeSessionRepo.mu.Unlock()
+	delete(fakeSessionRepo.sessions, token)
+	delete(fakeSessionRepo.pending, token)
+	return nil
+}
+
+func (fakeSessionRepo *fakeSessionRepo) RevokeInstanceSession(ctx context.Context, instanceUuid string) error {
+	fakeSessionRepo.mu.Lock()
+	defer fakeSessionRepo.mu.Unlock()
+	token := fakeSessionRepo.instances[instanceUuid]
+	delete(fakeSessionRepo.instances, instanceUuid)
+	delete(fakeSessionRepo.sessions, token)
+	delete(fakeSessionRepo.pending, token)
+	return nil
+}
+
+func (fakeSessionRepo *fakeSessionRepo) AddPendingInstruct(ctx context.Context, token, uuid, instruction string, ttl time.Duration) error {
+	fakeSessionRepo.mu.Lock()
+	defer fakeSessionRepo.mu.Unlock()
+	if fakeSessionRepo.pending[token] == nil {
+		fakeSessionRepo.pending[token] = make(map[string]string)
+	}
+	fakeSessionRepo.pending[token][uuid] = instruction
+	return nil
+}
+
+func (fakeSessionRepo *fakeSessionRepo) RemovePendingInstruct(ctx context.Context, token, uuid string) (bool, error) {
+	fakeSessionRepo.mu.Lock()
+	defer fakeSessionRepo.mu.Unlock()
+	if fakeSessionRepo.err != nil {
+		return false, fakeSessionRepo.err
+	}
+	_, ok := fakeSessionRepo.pending[token][uuid]
+	delete(fakeSessionRepo.pending[token], uuid)
+	return ok, nil
+}
+
+func (fakeSessionRepo *fakeSessionRepo) ListPendingInstructs(ctx context.Context, token string) (map[string]string, error) {
+	fakeSessionRepo.mu.Lock()
+	defer fakeSessionRepo.mu.Unlock()
+	pending := make(map[string]string)
+	for uuid, instruction := range fakeSessionRepo.pending[token] {
+		pending[uuid] = instruction
+	}
+	return pending, nil
+}
+
+type fakeClusterRepo struct {
+	mu        sync.Mutex
+	nodes     map[string]NodeInfo
+	published map[string][]NodeMessage
+}
+
+func newFakeClusterRepo() *fakeClusterRepo {
+	return &fakeClusterRepo{
+		nodes:     make(map[string]NodeInfo),
+		published: make(map[string][]NodeMessage),
+	}
+}
+
+func (fakeClusterRepo *fakeClusterRepo) SetNode(ctx context.Context, node NodeInfo, ttl time.Duration) error {
+	fakeClusterRepo.mu.Lock()
+	defer fakeClusterRepo.mu.Unlock()
+	fakeClusterRepo.nodes[node.Id] = node
+	return nil
+}
+
+func (fakeClusterRepo *fakeClusterRepo) DeleteNode(ctx context.Context, id string) error {
+	fakeClusterRepo.mu.Lock()
+	defer fakeClusterRepo.mu.Unlock()
+	delete(fakeClusterRepo.nodes, id)
+	return nil
+}
+
+func (fakeClusterRepo *fakeClusterRepo) ListNodes(ctx context.Context) ([]NodeInfo, error) {
+	fakeClusterRepo.mu.Lock()
+	defer fakeClusterRepo.mu.Unlock()
+	var nodes []NodeInfo
+	for _, node := range fakeClusterRepo.nodes {
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+func (fakeClusterRepo *fakeClusterRepo) PublishNodeMessage(ctx context.Context, node string, msg NodeMessage) (bool, error) {
+	fakeClusterRepo.mu.Lock()
+	defer fakeClusterRepo.mu.Unlock()
+	fakeClusterRepo.published[node] = append(fakeClusterRepo.published[node], msg)
+	return true, nil
+}
+
+func (fakeClusterRepo *fakeClusterRepo) SubscribeNodeMessages(ctx context.Context, node string, handler func(msg NodeMessage)) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+type fakeConnectionRepo struct {
+	mu          sync.Mutex
+	connections map[string]Connection
+}
+
+func newFakeConnectionRepo() *fakeConnectionRepo {
+	return &fakeConnectionRepo{connections: make(map[string]Connection)}
+}
+
+func (fakeConnectionRepo *fakeConnectionRepo) CreateConnection(ctx context.Context, connection Connection) error {
+	fakeConnectionRepo.mu.Lock()
+	defer fakeConnectionRepo.mu.Unlock()
+	fakeConnectionRepo.connections[connection.Id] = connection
+	return nil
+}
+
+func (fakeConnectionRepo *fakeConnectionRepo) CloseConnection(ctx context.Context, id string, disconnectTime int64, reason string) error {
+	fakeConnectionRepo.mu.Lock()
+	defer fakeConnectionRepo.mu.Unlock()
+	connection, ok := fakeConnectionRepo.connections[id]
+	if ok && connection.DisconnectTime == 0 {
+		connection.DisconnectTime, connection.DisconnectReason = disconnectTime, reason
+		fakeConnectionRepo.connections[id] = connection
+	}
+	return nil
+}
+
+func (fakeConnectionRepo *fakeConnectionRepo) CloseInstanceConnections(ctx context.Context, instanceUuid string, disconnectTime int64, reason string) error {
+	fakeConnectionRepo.mu.Lock()
+	defer fakeConnectionRepo.mu.Unlock()
+	for id, connection := range fakeConnectionRepo.connections {
+		if connection.InstanceUuid == instanceUuid && connection.DisconnectTime == 0 {
+			connection.DisconnectTime, connection.DisconnectReason = disconnectTime, reason
+			fakeConnectionRepo.connections[id] = connection
+		}
+	}
+	return nil
+}
+
+func (fakeConnectionRepo *fakeConnectionRepo) ListConnections(ctx context.Context, instanceUuid string, startTime, endTime int64, limit int) ([]Connection, error) {
+	fakeConnectionRepo.mu.Lock()
+	defer fakeConnectionRepo.mu.Unlock()
+	var connections []Connection
+	for _, connection := range fakeConnectionRepo.connections {
+		if connection.InstanceUuid == instanceUuid && connection.ConnectTime < endTime && (connection.DisconnectTime == 0 || connection.DisconnectTime > startTime) {
+			connections = append(connections, connection)
+		}
+	}
+	sort.Slice(connections, func(i, j int) bool {
+		return connections[i].ConnectTime > connections[j].ConnectTime
+	})
+	if limit > 0 && len(connections) > limit {
+		connections = connections[:limit]
+	}
+	return connections, nil
+}
+
+func (fakeConnectionRepo *fakeConnectionRepo) DeleteConnections(ctx context.Context, instanceUuid string) error {
+	fakeConnectionRepo.mu.Lock()
+	defer fakeConnectionRepo.mu.Unlock()
+	for id, connection := range fakeConnectionRepo.connections {
+		if connection.InstanceUuid == instanceUuid {
+			delete(fakeConnectionRepo.connections, id)
+		}
+	}
+	return nil
+}
+
+type fakeResultRepo struct {
+	mu      sync.Mutex
+	results map[string]InstructResult
+}
+
+func newFakeResultRepo(results ...InstructResult) *fakeResultRepo {
+	fakeResultRepo := &fakeResultRepo{results: make(map[string]InstructResult)}
+	for _, result := range results {
+		fakeResultRepo.results[result.InstructUuid] = result
+	}
+	return fakeResultRepo
+}
+
+func (fakeResultRepo *fakeResultRepo) SaveResult(ctx context.Context, result InstructResult) error {
+	fakeResultRepo.mu.Lock()
+	defer fakeResultRepo.mu.Unlock()
+	fakeResultRepo.results[result.InstructUuid] = result
+	return nil
+}
+
+func (fakeResultRepo *fakeResultRepo) GetResult(ctx context.Context, instructUuid string) (InstructResult, error) {
+	fakeResultRepo.mu.Lock()
+	defer fakeResultRepo.mu.Unlock()
+	result, ok := fakeResultRepo.results[instructUuid]
+	if !ok {
+		return result, ErrResultNotFound
+	}
+	return result, nil
+}
+
+func (fakeResultRepo *fakeResultRepo) QueryResults(ctx context.Context, query ResultQuery) ([]InstructResult, error) {
+	fakeResultRepo.mu.Lock()
+	defer fakeResultRepo.mu.Unlock()
+	var results []InstructResult
+	for _, result := range fakeResultRepo.results {
+		if query.Type != 0 && result.Type != query.Type || query.Target != "" && result.Target != query.Target || query.Result != nil && result.Result != *query.Result || query.BatchUuid != "" && result.BatchUuid != query.BatchUuid {
+			continue
+		}
+		
+		if query.MinStatus > 0 && result.StatusCode < query.MinStatus || query.MaxStatus > 0 && result.StatusCode > query.MaxStatus || result.Latency < query.MinLatency {
+			continue
+		}
+		
+		if query.StartTime > 0 && result.CreateTime < query.StartTime || query.EndTime > 0 && result.CreateTime >= query.EndTime {
+			continue
+		}
+		
+		if query.After != nil && (result.CreateTime > query.After.CreateTime || result.CreateTime == query.After.CreateTime && result.InstructUuid >= query.After.Uuid) {
+			continue
+		}
+		results = append(results, result)
+	}
+	
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].CreateTime != results[j].CreateTime {
+			return results[i].CreateTime > results[j].CreateTime
+		}
+		return results[i].InstructUuid > results[j].InstructUuid
+	})
+	return results[:min(len(results), query.Limit)], nil
+}
+
+func (fakeResultRepo *fakeResultRepo) DeleteGroupResults(ctx context.Context, orgUuid, groupUuid string, limit int) (int64, error) {
+	fakeResultRepo.mu.Lock()
+	defer fakeResultRepo.mu.Unlock()
+	var deleted int64
+	for uuid, result := range fakeResultRepo.results {
+		if result.OrgUuid == orgUuid && result.GroupUuid == groupUuid && deleted < int64(limit) {
+			delete(fakeResultRepo.results, uuid)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
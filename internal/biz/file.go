@@ -0,0 +1,432 @@
+package biz
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 文件获取 / 下发指令，文件内容通过 websocket 分片传输
+//
+// 文件获取: commander 下发 FileGetInstruct -> soldier 回传 ClientFileChunk 分片 -> soldier 回传指令结果 (大小及 sha256)
+// 文件下发: 通过 /v1/blob 上传文件 -> commander 下发 FilePutInstruct 及 ServiceFileChunk 分片 -> soldier 校验后回传指令结果
+
+const (
+	fileChunkSize = 256 * 1024
+	// 文件下发超过该时间未收到分片时中止传输，删除临时文件
+	fileTransferIdleTimeout = 2 * time.Minute
+)
+
+var (
+	ErrBlobNotFound        = errors.New("文件不存在")
+	ErrBlobTooLarge        = errors.New("文件超过大小限制")
+	ErrChecksumMismatch    = errors.New("文件校验失败")
+	ErrInvalidFileContent  = errors.New("文件下发指令内容异常")
+	ErrFileNotReady        = errors.New("文件尚未获取完成")
+	ErrFilePathNotAllowed  = errors.New("文件路径不在允许范围内")
+	ErrFileTransferUnknown = errors.New("未知的文件传输")
+)
+
+type FileChunk struct {
+	Uuid   string `json:"uuid,omitempty"` // 对应的指令 uuid
+	Offset int64  `json:"offset"`
+	Data   []byte `json:"data,omitempty"`
+	Eof    bool   `json:"eof,omitempty"`
+}
+
+// commander 保存的文件，获取到的文件以指令 uuid 作为 blob uuid
+
+type Blob struct {
+	Uuid       string `json:"uuid,omitempty"`
+	Size       int64  `json:"size"`
+	Sha256     string `json:"sha256,omitempty"`
+	CreateTime int64  `json:"createTime,omitempty"`
+}
+
+func (blob *Blob) TableName() string {
+	return "blob"
+}
+
+// 文件下发指令内容，即 POST /instruct 中的 content 字段
+
+type FilePutContent struct {
+	BlobUuid string `json:"blobUuid"`
+	Path     string `json:"path"`
+}
+
+// 文件指令结果，序列化后保存到指令 reply 中
+
+type FileReply struct {
+	Path   string `json:"path,omitempty"`
+	Size   int64  `json:"size"`
+	Sha256 string `json:"sha256,omitempty"`
+}
+
+func (fileReply *FileReply) String() string {
+	b, err := json.Marshal(fileReply)
+	if err != nil {
+		return err.Error()
+	}
+	
+	return string(b)
+}
+
+type BlobRepo interface {
+	// 保存上传的文件，超过大小限制时返回 ErrBlobTooLarge
+	Put(ctx context.Context, r io.Reader) (Blob, error)
+	// 按偏移暂存接收到的分片
+	WriteChunk(ctx context.Context, uuid string, offset int64, data []byte) error
+	// 校验暂存分片的大小及 sha256，不一致时返回 ErrChecksumMismatch
+	Verify(ctx context.Context, uuid string, size int64, sha256 string) error
+	// 分片接收完成后校验大小及 sha256，并保存
+	Commit(ctx context.Context, uuid string, size int64, sha256 string) (Blob, error)
+	// 丢弃暂存的分片
+	Discard(ctx context.Context, uuid string) error
+	Stat(ctx context.Context, uuid string) (Blob, error)
+	Open(ctx context.Context, uuid string) (io.ReadCloser, Blob, error)
+	// 删除分组下文件获取指令及终端录像的文件 (含归档的指令)，每次最多 limit 个，返回删除的数量
+	// 通过 /v1/blob 上传的下发文件可能被多个分组的指令引用，不删除
+	DeleteGroupBlobs(ctx context.Context, orgUuid, groupUuid string, limit int) (int64, error)
+}
+
+type FileUseCase struct {
+	blobRepo     BlobRepo
+	instructRepo InstructRepo
+	logger       *zap.Logger
+}
+
+func NewFileUseCase(blobRepo BlobRepo, instructRepo InstructRepo, logger *zap.Logger) *FileUseCase {
+	return &FileUseCase{
+		blobRepo:     blobRepo,
+		instructRepo: instructRepo,
+		logger:       logger,
+	}
+}
+
+// 上传待下发的文件
+
+func (fileUseCase *FileUseCase) Upload(ctx context.Context, r io.Reader) (Blob, error) {
+	return fileUseCase.blobRepo.Put(ctx, r)
+}
+
+// 下载文件获取指令获取到的文件或终端会话录像，返回文件内容、文件信息及文件名
+
+func (fileUseCase *FileUseCase) Download(ctx context.Context, orgUuid, groupUuid, instanceName, instructUuid string) (io.ReadCloser, Blob, string, error) {
+	instruct, err := fileUseCase.instructRepo.GetInstruct(ctx, orgUuid, groupUuid, instanceName, instructUuid)
+	if err != nil {
+		return nil, Blob{}, "", err
+	}
+	
+	if instruct.Result != 1 {
+		return nil, Blob{}, "", ErrFileNotReady
+	}
+	
+	// 文件获取指令以源文件名下载，终端会话下载录像
+	var name string
+	switch instruct.Type {
+	case FileGetInstruct:
+		name = filepath.Base(instruct.Content)
+	case ShellInstruct:
+		name = instruct.Uuid + ".cast"
+	default:
+		return nil, Blob{}, "", ErrFileNotReady
+	}
+	
+	rc, blob, err := fileUseCase.blobRepo.Open(ctx, instruct.Uuid)
+	if err != nil {
+		return nil, Blob{}, "", err
+	}
+	
+	return rc, blob, name, nil
+}
+
+// soldier 本地文件策略，AllowPaths 为空时禁止所有文件指令
+
+type FilePolicy struct {
+	AllowPaths []string
+	MaxSize    int64
+}
+
+type FileClientUseCase struct {
+	logger *zap.Logger
+	policy FilePolicy
+	
+	mu          sync.Mutex
+	transfers   map[string]*fileTransfer // 接收中的文件下发
+	idleTimeout time.Duration
+}
+
+type fileTransfer struct {
+	path     string
+	tempPath string
+	size     int64
+	sha256   string
+	file     *os.File
+	timer    *time.Timer
+}
+
+func NewFileClientUseCase(logger *zap.Logger, policy *FilePolicy) *FileClientUseCase {
+	return &FileClientUseCase{
+		logger:      logger,
+		policy:      *policy,
+		transfers:   make(map[string]*fileTransfer),
+		idleTimeout: fileTransferIdleTimeout,
+	}
+}
+
+func (fileClientUseCase *FileClientUseCase) Enabled() bool {
+	return len(fileClientUseCase.policy.AllowPaths) > 0
+}
+
+// 检查路径是否在允许范围内，返回解析符号链接后的绝对路径
+
+func (fileClientUseCase *FileClientUseCase) allowed(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		return "", ErrFilePathNotAllowed
+	}
+	
+	// 目标文件可能不存在 (文件下发)，解析所在目录的符号链接
+	dir, err := filepath.EvalSymlinks(filepath.Dir(filepath.Clean(path)))
+	if err != nil {
+		return "", err
+	}
+	resolved := filepath.Join(dir, filepath.Base(path))
+	
+	target, err := filepath.EvalSymlinks(resolved)
+	if err == nil {
+		resolved = target
+	}
+	
+	for _, allowPath := range fileClientUseCase.policy.AllowPaths {
+		allowPath = filepath.Clean(allowPath)
+		if resolved == allowPath || strings.HasPrefix(resolved, allowPath+string(filepath.Separator)) {
+			return resolved, nil
+		}
+	}
+	
+	return "", ErrFilePathNotAllowed
+}
+
+// 读取文件并分片发送到 sendMsg，返回文件大小及 sha256
+
+func (fileClientUseCase *FileClientUseCase) Get(ctx context.Context, uuid, path string, sendMsg chan ClientMessage) (FileReply, error) {
+	reply := FileReply{Path: path}
+	
+	resolved, err := fileClientUseCase.allowed(path)
+	if err != nil {
+		return reply, err
+	}
+	
+	file, err := os.Open(resolved)
+	if err != nil {
+		return reply, err
+	}
+	defer file.Close()
+	
+	stat, err := file.Stat()
+	if err != nil {
+		return reply, err
+	}
+	
+	if stat.IsDir() {
+		return reply, fmt.Errorf("%s 是目录", path)
+	}
+	
+	if fileClientUseCase.policy.MaxSize > 0 && stat.Size() > fileClientUseCase.policy.MaxSize {
+		return reply, ErrBlobTooLarge
+	}
+	
+	hash := sha256.New()
+	buf := make([]byte, fileChunkSize)
+	var offset int64
+	
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			hash.Write(buf[:n])
+			data := make([]byte, n)
+			copy(data, buf[:n])
+			
+			select {
+			case sendMsg <- ClientMessage{
+				Type:      ClientFileChunk,
+				FileChunk: &FileChunk{Uuid: uuid, Offset: offset, Data: data},
+			}:
+			case <-ctx.Done():
+				return reply, ctx.Err()
+			}
+			offset += int64(n)
+		}
+		
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return reply, err
+		}
+	}
+	
+	reply.Size = offset
+	reply.Sha256 = hex.EncodeToString(hash.Sum(nil))
+	return reply, nil
+}
+
+// 开始接收文件下发，文件先写入同目录下的临时文件，校验通过后重命名
+
+func (fileClientUseCase *FileClientUseCase) BeginPut(uuid, path string, size int64, sha256 string) error {
+	resolved, err := fileClientUseCase.allowed(path)
+	if err != nil {
+		return err
+	}
+	
+	if fileClientUseCase.policy.MaxSize > 0 && size > fileClientUseCase.policy.MaxSize {
+		return ErrBlobTooLarge
+	}
+	
+	tempPath := fmt.Sprintf("%s.camp-%s.part", resolved, uuid)
+	file, err := os.OpenFile(tempPath, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	
+	transfer := &fileTransfer{
+		path:     resolved,
+		tempPath: tempPath,
+		size:     size,
+		sha256:   sha256,
+		file:     file,
+	}
+	
+	// commander 读取文件失败或连接中断后不再发送分片
+	transfer.timer = time.AfterFunc(fileClientUseCase.idleTimeout, func() {
+		if fileClientUseCase.abort(uuid, transfer) {
+			fileClientUseCase.logger.Warn("文件下发超时未收到分片，中止传输", zap.String("uuid", uuid), zap.String("FilePath", resolved))
+		}
+	})
+	
+	fileClientUseCase.mu.Lock()
+	previous := fileClientUseCase.transfers[uuid]
+	fileClientUseCase.transfers[uuid] = transfer
+	fileClientUseCase.mu.Unlock()
+	
+	if previous != nil {
+		previous.close()
+	}
+	
+	return nil
+}
+
+// 写入文件下发分片，最后一个分片写入后校验并重命名，done 表示传输结束 (成功或失败)
+
+func (fileClientUseCase *FileClientUseCase) WriteChunk(chunk FileChunk) (FileReply, bool, error) {
+	fileClientUseCase.mu.Lock()
+	transfer, ok := fileClientUseCase.transfers[chunk.Uuid]
+	fileClientUseCase.mu.Unlock()
+	
+	if !ok {
+		return FileReply{}, true, ErrFileTransferUnknown
+	}
+	
+	reply := FileReply{Path: transfer.path, Size: transfer.size, Sha256: transfer.sha256}
+	transfer.timer.Reset(fileClientUseCase.idleTimeout)
+	
+	if chunk.Offset+int64(len(chunk.Data)) > transfer.size {
+		fileClientUseCase.abort(chunk.Uuid, transfer)
+		return reply, true, ErrBlobTooLarge
+	}
+	
+	_, err := transfer.file.WriteAt(chunk.Data, chunk.Offset)
+	if err != nil {
+		fileClientUseCase.abort(chunk.Uuid, transfer)
+		return reply, true, err
+	}
+	
+	if !chunk.Eof {
+		return reply, false, nil
+	}
+	
+	err = fileClientUseCase.finish(chunk.Uuid, transfer)
+	return reply, true, err
+}
+
+func (fileClientUseCase *FileClientUseCase) finish(uuid string, transfer *fileTransfer) error {
+	if !fileClientUseCase.take(uuid, transfer) {
+		return ErrFileTransferUnknown
+	}
+	transfer.timer.Stop()
+	
+	defer os.Remove(transfer.tempPath)
+	
+	_, err := transfer.file.Seek(0, io.SeekStart)
+	if err != nil {
+		transfer.file.Close()
+		return err
+	}
+	
+	hash := sha256.New()
+	size, err := io.Copy(hash, transfer.file)
+	transfer.file.Close()
+	if err != nil {
+		return err
+	}
+	
+	if size != transfer.size || hex.EncodeToString(hash.Sum(nil)) != transfer.sha256 {
+		return ErrChecksumMismatch
+	}
+	
+	return os.Rename(transfer.tempPath, transfer.path)
+}
+
+// 中止传输并删除临时文件，传输已结束时返回 false
+
+func (fileClientUseCase *FileClientUseCase) abort(uuid string, transfer *fileTransfer) bool {
+	if !fileClientUseCase.take(uuid, transfer) {
+		return false
+	}
+	
+	transfer.close()
+	return true
+}
+
+// 与 commander 的连接断开时中止所有文件下发，重连后不会补发分片
+
+func (fileClientUseCase *FileClientUseCase) CloseAll() {
+	fileClientUseCase.mu.Lock()
+	transfers := fileClientUseCase.transfers
+	fileClientUseCase.transfers = make(map[string]*fileTransfer)
+	fileClientUseCase.mu.Unlock()
+	
+	for _, transfer := range transfers {
+		transfer.close()
+	}
+}
+
+// 从接收中的传输移除，仅第一个调用方 (完成、中止或超时) 返回 true
+
+func (fileClientUseCase *FileClientUseCase) take(uuid string, transfer *fileTransfer) bool {
+	fileClientUseCase.mu.Lock()
+	defer fileClientUseCase.mu.Unlock()
+	
+	if fileClientUseCase.transfers[uuid] != transfer {
+		return false
+	}
+	
+	delete(fileClientUseCase.transfers, uuid)
+	return true
+}
+
+func (transfer *fileTransfer) close() {
+	transfer.timer.Stop()
+	transfer.file.Close()
+	os.Remove(transfer.tempPath)
+}
@@ -0,0 +1,130 @@
+package biz
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileClientUseCase_GetPut(t *testing.T) {
+	dir := t.TempDir()
+	fileClientUseCase := NewFileClientUseCase(zap.NewNop(), &FilePolicy{AllowPaths: []string{dir}, MaxSize: 1024 * 1024})
+	
+	content := make([]byte, fileChunkSize+100)
+	for i := range content {
+		content[i] = byte(i)
+	}
+	sum := sha256.Sum256(content)
+	
+	src := filepath.Join(dir, "src")
+	assert.NoError(t, os.WriteFile(src, content, 0644))
+	
+	sendMsg := make(chan ClientMessage, 10)
+	reply, err := fileClientUseCase.Get(context.Background(), "uuid", src, sendMsg)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(content)), reply.Size)
+	assert.Equal(t, hex.EncodeToString(sum[:]), reply.Sha256)
+	assert.Len(t, sendMsg, 2)
+	
+	// 将获取到的分片下发到新文件
+	dst := filepath.Join(dir, "dst")
+	assert.NoError(t, fileClientUseCase.BeginPut("uuid", dst, reply.Size, reply.Sha256))
+	
+	close(sendMsg)
+	var chunks []FileChunk
+	for msg := range sendMsg {
+		chunks = append(chunks, *msg.FileChunk)
+	}
+	chunks[len(chunks)-1].Eof = true
+	
+	for _, chunk := range chunks {
+		_, done, err := fileClientUseCase.WriteChunk(chunk)
+		assert.NoError(t, err)
+		assert.Equal(t, chunk.Eof, done)
+	}
+	
+	b, err := os.ReadFile(dst)
+	assert.NoError(t, err)
+	assert.Equal(t, content, b)
+}
+
+func TestFileClientUseCase_PutChecksumMismatch(t *testing.T) {
+	dir := t.TempDir()
+	fileClientUseCase := NewFileClientUseCase(zap.NewNop(), &FilePolicy{AllowPaths: []string{dir}})
+	
+	dst := filepath.Join(dir, "dst")
+	assert.NoError(t, fileClientUseCase.BeginPut("uuid", dst, 3, "invalid"))
+	
+	_, done, err := fileClientUseCase.WriteChunk(FileChunk{Uuid: "uuid", Data: []byte("abc"), Eof: true})
+	assert.True(t, done)
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	
+	_, err = os.Stat(dst)
+	assert.True(t, os.IsNotExist(err))
+}
+
+// 超时未收到分片或连接断开时中止文件下发，删除临时文件
+
+func TestFileClientUseCase_PutAbort(t *testing.T) {
+	dir := t.TempDir()
+	fileClientUseCase := NewFileClientUseCase(zap.NewNop(), &FilePolicy{AllowPaths: []string{dir}})
+	fileClientUseCase.idleTimeout = 50 * time.Millisecond
+	dst := filepath.Join(dir, "dst")
+	
+	assert.NoError(t, fileClientUseCase.BeginPut("u1", dst, 6, "invalid"))
+	_, done, err := fileClientUseCase.WriteChunk(FileChunk{Uuid: "u1", Data: []byte("abc")})
+	assert.NoError(t, err)
+	assert.False(t, done)
+	
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(dst + ".camp-u1.part")
+		return os.IsNotExist(err)
+	}, time.Second, 10*time.Millisecond)
+	_, done, err = fileClientUseCase.WriteChunk(FileChunk{Uuid: "u1", Offset: 3, Data: []byte("def"), Eof: true})
+	assert.True(t, done)
+	assert.ErrorIs(t, err, ErrFileTransferUnknown)
+	
+	fileClientUseCase.idleTimeout = time.Minute
+	assert.NoError(t, fileClientUseCase.BeginPut("u2", dst, 6, "invalid"))
+	fileClientUseCase.CloseAll()
+	_, err = os.Stat(dst + ".camp-u2.part")
+	assert.True(t, os.IsNotExist(err))
+	assert.Empty(t, fileClientUseCase.transfers)
+	
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestFileClientUseCase_PathNotAllowed(t *testing.T) {
+	dir := t.TempDir()
+	fileClientUseCase := NewFileClientUseCase(zap.NewNop(), &FilePolicy{AllowPaths: []string{filepath.Join(dir, "allow")}})
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "allow"), 0755))
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "allow2"), 0755))
+	
+	_, err := fileClientUseCase.allowed(filepath.Join(dir, "allow2", "file"))
+	assert.ErrorIs(t, err, ErrFilePathNotAllowed)
+	
+	_, err = fileClientUseCase.allowed(filepath.Join(dir, "allow", "..", "file"))
+	assert.ErrorIs(t, err, ErrFilePathNotAllowed)
+	
+	// 通过符号链接指向允许范围外
+	assert.NoError(t, os.Symlink(filepath.Join(dir, "allow2"), filepath.Join(dir, "allow", "link")))
+	_, err = fileClientUseCase.allowed(filepath.Join(dir, "allow", "link", "file"))
+	assert.ErrorIs(t, err, ErrFilePathNotAllowed)
+	
+	_, err = fileClientUseCase.allowed(filepath.Join(dir, "allow", "file"))
+	assert.NoError(t, err)
+	
+	// 未配置允许路径时禁止所有文件
+	fileClientUseCase = NewFileClientUseCase(zap.NewNop(), &FilePolicy{})
+	assert.False(t, fileClientUseCase.Enabled())
+	_, err = fileClientUseCase.allowed(filepath.Join(dir, "allow", "file"))
+	assert.ErrorIs(t, err, ErrFilePathNotAllowed)
+}
@@ -0,0 +1,381 @@
+package biz
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// websocket 消息压缩、分片及大小限制
+//
+// 压缩: 双方协商 permessage-deflate，小于 compressionThreshold 的消息不压缩
+// 分片: 握手时通过 MaxFrameHeader 通告本端可接收的单条 websocket 消息大小，超过对端上限的消息拆分为 Fragment 连续发送，
+// 接收方重组后再按原消息类型解码，重组后的消息不超过本端的 MaxMessageSize
+// 截断: soldier 上报的消息超过双方的 MaxMessageSize 时截断其中的结果或截图并设置 ClientMessage.Truncated，不再因超限断开连接
+//
+// 旧版本对端未通告时不分片，接收上限为 MaxMessageSize
+
+const (
+	MaxFrameHeader   = "X-Camp-Max-Frame"
+	MaxMessageHeader = "X-Camp-Max-Message"
+)
+
+const (
+	DefaultMaxFrameSize   = 1 << 20  // 1MB
+	DefaultMaxMessageSize = 16 << 20 // 16MB
+	
+	minFrameSize         = 16 << 10 // 对端通告的单条消息大小下限
+	fragmentOverhead     = 1 << 10  // 分片消息除数据外的编码开销
+	compressionThreshold = 1 << 10  // 小于该大小的消息不压缩
+	truncateAttempts     = 4
+)
+
+var (
+	ErrMessageTooLarge    = errors.New("消息超过大小上限")
+	ErrFragmentOutOfOrder = errors.New("消息分片顺序异常")
+)
+
+type Fragment struct {
+	Id          uint64 `json:"id"`
+	Seq         uint32 `json:"seq"`
+	Last        bool   `json:"last,omitempty"`
+	MessageType int    `json:"messageType"`    // 原消息的 websocket 消息类型
+	Size        int64  `json:"size,omitempty"` // 原消息大小，首个分片设置
+	Data        []byte `json:"data"`
+}
+
+// 本端的消息大小限制，为 0 时使用默认值
+
+type FramePolicy struct {
+	MaxFrameSize   int  // 可接收的单条 websocket 消息大小
+	MaxMessageSize int  // 分片重组后的消息大小上限
+	Compression    bool // 协商 permessage-deflate
+}
+
+func (framePolicy *FramePolicy) FrameSize() int {
+	if framePolicy.MaxFrameSize <= 0 {
+		return DefaultMaxFrameSize
+	}
+	
+	return max(framePolicy.MaxFrameSize, minFrameSize)
+}
+
+func (framePolicy *FramePolicy) MessageSize() int {
+	if framePolicy.MaxMessageSize <= 0 {
+		return DefaultMaxMessageSize
+	}
+	
+	return max(framePolicy.MaxMessageSize, framePolicy.FrameSize())
+}
+
+// 握手时通告的本端限制
+
+func (framePolicy *FramePolicy) SetHeader(header http.Header) {
+	header.Set(MaxFrameHeader, strconv.Itoa(framePolicy.FrameSize()))
+	header.Set(MaxMessageHeader, strconv.Itoa(framePolicy.MessageSize()))
+}
+
+// 对端通告分片大小时 (支持分片) 仅接收不超过本端分片大小的消息，否则按消息大小上限接收
+
+func (framePolicy *FramePolicy) ReadLimit(peerFrameSize int) int64 {
+	if peerFrameSize > 0 {
+		return int64(framePolicy.FrameSize())
+	}
+	
+	return int64(framePolicy.MessageSize())
+}
+
+// 解析对端通告的大小，未通告或格式错误时返回 0
+
+func ParseFrameHeader(value string) int {
+	size, err := strconv.Atoi(value)
+	if err != nil || size <= 0 {
+		return 0
+	}
+	
+	return max(size, minFrameSize)
+}
+
+// 按对端通告的大小分片发送，同一连接仅由一个协程写入
+
+type FrameWriter struct {
+	framePolicy     *FramePolicy
+	peerFrameSize   atomic.Int64 // 为 0 时对端不支持分片
+	peerMessageSize atomic.Int64
+	id              uint64
+	encodeFragment  func(subprotocol string, fragment *Fragment) (int, []byte, error)
+}
+
+func NewServiceFrameWriter(framePolicy *FramePolicy) *FrameWriter {
+	return &FrameWriter{
+		framePolicy: framePolicy,
+		encodeFragment: func(subprotocol string, fragment *Fragment) (int, []byte, error) {
+			return EncodeServiceMessage(subprotocol, ServiceMessage{Type: ServiceFragment, Fragment: fragment})
+		},
+	}
+}
+
+func NewClientFrameWriter(framePolicy *FramePolicy) *FrameWriter {
+	return &FrameWriter{
+		framePolicy: framePolicy,
+		encodeFragment: func(subprotocol string, fragment *Fragment) (int, []byte, error) {
+			return EncodeClientMessage(subprotocol, ClientMessage{Type: ClientFragment, Fragment: fragment})
+		},
+	}
+}
+
+// 连接 (重连) 后设置对端通告的大小
+
+func (frameWriter *FrameWriter) SetPeer(header http.Header) {
+	frameWriter.peerFrameSize.Store(int64(ParseFrameHeader(header.Get(MaxFrameHeader))))
+	frameWriter.peerMessageSize.Store(int64(ParseFrameHeader(header.Get(MaxMessageHeader))))
+}
+
+func (frameWriter *FrameWriter) PeerFrameSize() int {
+	return int(frameWriter.peerFrameSize.Load())
+}
+
+// 发送消息的大小上限，取双方上限的较小值
+
+func (frameWriter *FrameWriter) MessageSize() int {
+	size := frameWriter.framePolicy.MessageSize()
+	peer := int(frameWriter.peerMessageSize.Load())
+	if peer > 0 && peer < size {
+		return peer
+	}
+	
+	return size
+}
+
+func (frameWriter *FrameWriter) WriteMessage(conn *websocket.Conn, messageType int, data []byte) error {
+	peerFrameSize := frameWriter.PeerFrameSize()
+	if peerFrameSize == 0 || len(data) <= peerFrameSize {
+		return frameWriter.write(conn, messageType, data)
+	}
+	
+	// JSON 协议中分片数据以 base64 编码
+	chunkSize := peerFrameSize - fragmentOverhead
+	if conn.Subprotocol() != ProtocolProto {
+		chunkSize = chunkSize * 3 / 4
+	}
+	
+	frameWriter.id++
+	for seq, offset := 0, 0; offset < len(data); seq, offset = seq+1, offset+chunkSize {
+		end := min(offset+chunkSize, len(data))
+		fragment := &Fragment{
+			Id:          frameWriter.id,
+			Seq:         uint32(seq),
+			Last:        end == len(data),
+			MessageType: messageType,
+			Data:        data[offset:end],
+		}
+		if seq == 0 {
+			fragment.Size = int64(len(data))
+		}
+		
+		fragmentType, b, err := frameWriter.encodeFragment(conn.Subprotocol(), fragment)
+		if err != nil {
+			return err
+		}
+		
+		err = frameWriter.write(conn, fragmentType, b)
+		if err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (frameWriter *FrameWriter) write(conn *websocket.Conn, messageType int, data []byte) error {
+	conn.EnableWriteCompression(frameWriter.framePolicy.Compression && len(data) >= compressionThreshold)
+	return conn.WriteMessage(messageType, data)
+}
+
+// 重组分片，同一连接仅由一个协程读取
+
+type FrameReader struct {
+	maxMessageSize int
+	active         bool
+	id             uint64
+	seq            uint32
+	messageType    int
+	buf            []byte
+}
+
+func NewFrameReader(framePolicy *FramePolicy) *FrameReader {
+	return &FrameReader{maxMessageSize: framePolicy.MessageSize()}
+}
+
+// 连接断开时丢弃未完成的消息
+
+func (frameReader *FrameReader) Reset() {
+	frameReader.active = false
+	frameReader.buf = nil
+}
+
+// 添加一个分片，消息完整时返回原消息类型及内容
+
+func (frameReader *FrameReader) Add(fragment *Fragment) (int, []byte, bool, error) {
+	if fragment.Seq == 0 {
+		if fragment.Size > int64(frameReader.maxMessageSize) {
+			frameReader.Reset()
+			return 0, nil, false, ErrMessageTooLarge
+		}
+		
+		frameReader.active = true
+		frameReader.id = fragment.Id
+		frameReader.messageType = fragment.MessageType
+		frameReader.buf = make([]byte, 0, max(fragment.Size, 0))
+	} else if !frameReader.active || fragment.Id != frameReader.id || fragment.Seq != frameReader.seq+1 {
+		frameReader.Reset()
+		return 0, nil, false, ErrFragmentOutOfOrder
+	}
+	
+	if len(frameReader.buf)+len(fragment.Data) > frameReader.maxMessageSize {
+		frameReader.Reset()
+		return 0, nil, false, ErrMessageTooLarge
+	}
+	
+	frameReader.seq = fragment.Seq
+	frameReader.buf = append(frameReader.buf, fragment.Data...)
+	if !fragment.Last {
+		return 0, nil, false, nil
+	}
+	
+	data := frameReader.buf
+	frameReader.Reset()
+	return frameReader.messageType, data, true, nil
+}
+
+// 解码客户端消息，分片消息重组完成前返回 false
+
+func (frameReader *FrameReader) DecodeClientMessage(subprotocol string, messageType int, data []byte) (ClientMessage, bool, error) {
+	msg, err := DecodeClientMessage(subprotocol, messageType, data)
+	if err != nil || msg.Type != ClientFragment || msg.Fragment == nil {
+		return msg, err == nil, err
+	}
+	
+	messageType, data, complete, err := frameReader.Add(msg.Fragment)
+	if !complete {
+		return ClientMessage{}, false, err
+	}
+	
+	msg, err = DecodeClientMessage(subprotocol, messageType, data)
+	return msg, err == nil, err
+}
+
+// 解码服务端消息，分片消息重组完成前返回 false
+
+func (frameReader *FrameReader) DecodeServiceMessage(subprotocol string, messageType int, data []byte) (ServiceMessage, bool, error) {
+	msg, err := DecodeServiceMessage(subprotocol, messageType, data)
+	if err != nil || msg.Type != ServiceFragment || msg.Fragment == nil {
+		return msg, err == nil, err
+	}
+	
+	messageType, data, complete, err := frameReader.Add(msg.Fragment)
+	if !complete {
+		return ServiceMessage{}, false, err
+	}
+	
+	msg, err = DecodeServiceMessage(subprotocol, messageType, data)
+	return msg, err == nil, err
+}
+
+// 编码客户端消息，超过 maxMessageSize 时截断结果或截图
+
+func EncodeTruncatedClientMessage(subprotocol string, msg ClientMessage, maxMessageSize int) (int, []byte, error) {
+	messageType, b, err := EncodeClientMessage(subprotocol, msg)
+	for attempt := 0; err == nil && len(b) > maxMessageSize; attempt++ {
+		if attempt == truncateAttempts || !truncateClientMessage(&msg, len(b)-maxMessageSize) {
+			return 0, nil, ErrMessageTooLarge
+		}
+		
+		msg.Truncated = true
+		messageType, b, err = EncodeClientMessage(subprotocol, msg)
+	}
+	
+	return messageType, b, err
+}
+
+// 截断消息中的大字段，使编码后的大小至少减少 excess，无可截断的字段时返回 false
+
+func truncateClientMessage(msg *ClientMessage, excess int) bool {
+	// 截图无法部分保留，整体丢弃
+	if len(msg.ChromeDpScreenShot) > 0 {
+		msg.ChromeDpScreenShot = nil
+		return true
+	}
+	
+	instruct := &msg.InstructMessage
+	truncated := false
+	switch instruct.Type {
+	case CommandInstruct:
+		instruct.CommandReply, truncated = truncateString(instruct.CommandReply, excess)
+	case ChromeDpInspectInstruct:
+		resources := instruct.ChromeDpInspectReply.ResourcePageInspect
+		var end int
+		end, truncated = truncateCount(len(resources), excess, func(i int) interface{} { return resources[i] })
+		instruct.ChromeDpInspectReply.ResourcePageInspect = resources[:end]
+	case DnsInstruct:
+		addrs := instruct.DnsInspectReply
+		var end int
+		end, truncated = truncateCount(len(addrs), excess, func(i int) interface{} { return addrs[i] })
+		instruct.DnsInspectReply = addrs[:end]
+	case HttpInstruct:
+		instruct.HttpInspectReply.Response, truncated = truncateBytes(instruct.HttpInspectReply.Response, excess)
+	}
+	
+	if !truncated {
+		instruct.MtrInspectReply, truncated = truncateString(instruct.MtrInspectReply, excess)
+	}
+	if !truncated {
+		msg.Message, truncated = truncateString(msg.Message, excess)
+	}
+	
+	return truncated
+}
+
+// 保留前 len(s) - excess 字节，不拆分 UTF-8 字符
+
+func truncateString(s string, excess int) (string, bool) {
+	if len(s) == 0 {
+		return s, false
+	}
+	
+	end := max(len(s)-excess, 0)
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	
+	return s[:end], true
+}
+
+func truncateBytes(b []byte, excess int) ([]byte, bool) {
+	if len(b) == 0 {
+		return b, false
+	}
+	
+	return b[:max(len(b)-excess, 0)], true
+}
+
+// 从尾部移除元素，直到移除元素编码后的大小不小于 excess，返回保留的元素数
+
+func truncateCount(count int, excess int, item func(i int) interface{}) (int, bool) {
+	if count == 0 {
+		return 0, false
+	}
+	
+	removed := 0
+	for count > 0 && removed < excess {
+		count--
+		b, _ := json.Marshal(item(count))
+		removed += len(b) + 1
+	}
+	
+	return count, true
+}
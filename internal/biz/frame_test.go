@@ -0,0 +1,145 @@
+package biz
+
+import (
+	"bytes"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// 超过对端单条消息上限的消息分片发送，接收方重组，双方协商压缩
+
+func TestFrameWriter_Fragment(t *testing.T) {
+	for _, subprotocol := range []string{ProtocolProto, ProtocolJson} {
+		t.Run(subprotocol, func(t *testing.T) {
+			framePolicy := &FramePolicy{MaxFrameSize: minFrameSize, Compression: true}
+			
+			received := make(chan ClientMessage, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				upgrader := websocket.Upgrader{Subprotocols: Subprotocols, EnableCompression: true}
+				conn, err := upgrader.Upgrade(w, r, nil)
+				if !assert.NoError(t, err) {
+					return
+				}
+				defer conn.Close()
+				
+				peer := NewServiceFrameWriter(framePolicy)
+				peer.SetPeer(r.Header)
+				conn.SetReadLimit(framePolicy.ReadLimit(peer.PeerFrameSize()))
+				
+				frameReader := NewFrameReader(framePolicy)
+				for {
+					messageType, data, err := conn.ReadMessage()
+					if err != nil {
+						return
+					}
+					
+					msg, complete, err := frameReader.DecodeClientMessage(conn.Subprotocol(), messageType, data)
+					assert.NoError(t, err)
+					if complete {
+						received <- msg
+					}
+				}
+			}))
+			defer server.Close()
+			
+			header := http.Header{}
+			framePolicy.SetHeader(header)
+			dialer := websocket.Dialer{Subprotocols: []string{subprotocol}, EnableCompression: true}
+			conn, resp, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), header)
+			assert.NoError(t, err)
+			defer conn.Close()
+			assert.Contains(t, resp.Header.Get("Sec-Websocket-Extensions"), "permessage-deflate")
+			
+			frameWriter := NewClientFrameWriter(framePolicy)
+			frameWriter.SetPeer(header)
+			
+			msg := ClientMessage{
+				Type: ClientInstructReply,
+				InstructMessage: InstructMessage{
+					Uuid:         "uuid",
+					Type:         CommandInstruct,
+					CommandReply: strings.Repeat("0123456789abcdef\n", 4*minFrameSize/16),
+					Result:       true,
+				},
+			}
+			messageType, b, err := EncodeTruncatedClientMessage(conn.Subprotocol(), msg, frameWriter.MessageSize())
+			assert.NoError(t, err)
+			assert.Greater(t, len(b), minFrameSize)
+			assert.NoError(t, frameWriter.WriteMessage(conn, messageType, b))
+			
+			decoded := <-received
+			assert.Equal(t, msg.InstructMessage.CommandReply, decoded.InstructMessage.CommandReply)
+			assert.False(t, decoded.Truncated)
+		})
+	}
+}
+
+func TestFrameReader_Add(t *testing.T) {
+	frameReader := NewFrameReader(&FramePolicy{MaxFrameSize: minFrameSize, MaxMessageSize: minFrameSize})
+	
+	_, _, complete, err := frameReader.Add(&Fragment{Id: 1, Seq: 0, MessageType: websocket.BinaryMessage, Size: 6, Data: []byte("abc")})
+	assert.NoError(t, err)
+	assert.False(t, complete)
+	
+	messageType, data, complete, err := frameReader.Add(&Fragment{Id: 1, Seq: 1, Last: true, Data: []byte("def")})
+	assert.NoError(t, err)
+	assert.True(t, complete)
+	assert.Equal(t, websocket.BinaryMessage, messageType)
+	assert.Equal(t, []byte("abcdef"), data)
+	
+	// 分片缺失
+	_, _, _, err = frameReader.Add(&Fragment{Id: 2, Seq: 0, Data: []byte("abc")})
+	assert.NoError(t, err)
+	_, _, _, err = frameReader.Add(&Fragment{Id: 2, Seq: 2, Data: []byte("abc")})
+	assert.ErrorIs(t, err, ErrFragmentOutOfOrder)
+	
+	// 超过消息大小上限
+	_, _, _, err = frameReader.Add(&Fragment{Id: 3, Seq: 0, Size: minFrameSize + 1})
+	assert.ErrorIs(t, err, ErrMessageTooLarge)
+	_, _, _, err = frameReader.Add(&Fragment{Id: 4, Seq: 0, Data: bytes.Repeat([]byte("a"), minFrameSize+1)})
+	assert.ErrorIs(t, err, ErrMessageTooLarge)
+}
+
+// 超过消息大小上限时截断结果并设置标记
+
+func TestEncodeTruncatedClientMessage(t *testing.T) {
+	maxMessageSize := 64 << 10
+	
+	for _, subprotocol := range []string{ProtocolProto, ProtocolJson} {
+		resources := make([]UrlInspectInfo, 2000)
+		for i := range resources {
+			resources[i] = UrlInspectInfo{Url: "https://www.example.com/static/" + strings.Repeat("a", 64)}
+		}
+		
+		messages := []ClientMessage{
+			{Type: ClientInstructReply, InstructMessage: InstructMessage{Type: CommandInstruct, CommandReply: strings.Repeat("输出", maxMessageSize)}},
+			{Type: ClientInstructReply, InstructMessage: InstructMessage{Type: HttpInstruct, HttpInspectReply: HttpInspectReply{StatusCode: 200, Response: bytes.Repeat([]byte("a"), 2*maxMessageSize)}}},
+			{Type: ClientInstructReply, InstructMessage: InstructMessage{Type: ChromeDpInspectInstruct, ChromeDpInspectReply: InspectSinglePageResp{ResourcePageInspect: resources}}},
+			{Type: ClientChromeDpScreenShot, ChromeDpScreenShot: bytes.Repeat([]byte("a"), 2*maxMessageSize)},
+		}
+		
+		for _, msg := range messages {
+			messageType, b, err := EncodeTruncatedClientMessage(subprotocol, msg, maxMessageSize)
+			assert.NoError(t, err)
+			assert.LessOrEqual(t, len(b), maxMessageSize)
+			
+			decoded, err := DecodeClientMessage(subprotocol, messageType, b)
+			assert.NoError(t, err)
+			assert.True(t, decoded.Truncated)
+			assert.Equal(t, msg.InstructMessage.Type, decoded.InstructMessage.Type)
+		}
+	}
+	
+	// 未超过上限时不截断
+	msg := ClientMessage{Type: ClientInstructReply, InstructMessage: InstructMessage{Type: CommandInstruct, CommandReply: "ok"}}
+	messageType, b, err := EncodeTruncatedClientMessage(ProtocolProto, msg, maxMessageSize)
+	assert.NoError(t, err)
+	decoded, err := DecodeClientMessage(ProtocolProto, messageType, b)
+	assert.NoError(t, err)
+	assert.False(t, decoded.Truncated)
+	assert.Equal(t, "ok", decoded.InstructMessage.CommandReply)
+}
@@ -0,0 +1,108 @@
+package biz
+
+import (
+	"context"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// 连接心跳
+//
+// commander 按 PingInterval 发送 websocket ping，超过 PongTimeout 未收到 pong 或任何消息时视为连接已断开 (半开连接)，
+// 关闭连接并清理该连接上的指令轮询、会话及在线状态；握手时通过 HeartbeatHeader 响应头通告 ping 间隔，
+// soldier 据此设置读超时，未通告时 (旧版本 commander) 不设置
+
+const (
+	HeartbeatHeader = "X-Camp-Heartbeat"
+	
+	DefaultPingInterval = 10 * time.Second
+	DefaultAliveTimeout = 20 * time.Second
+	
+	pongTimeoutFactor = 3
+	pingWriteTimeout  = 5 * time.Second
+)
+
+type HeartbeatPolicy struct {
+	PingInterval time.Duration // commander 发送 ping 的间隔
+	PongTimeout  time.Duration // 读超时，默认 3 倍 PingInterval
+	AliveTimeout time.Duration // 实例超过该时间未更新状态视为离线
+}
+
+func (heartbeatPolicy *HeartbeatPolicy) Interval() time.Duration {
+	if heartbeatPolicy.PingInterval <= 0 {
+		return DefaultPingInterval
+	}
+	return heartbeatPolicy.PingInterval
+}
+
+func (heartbeatPolicy *HeartbeatPolicy) Timeout() time.Duration {
+	if heartbeatPolicy.PongTimeout <= 0 {
+		return pongTimeoutFactor * heartbeatPolicy.Interval()
+	}
+	return heartbeatPolicy.PongTimeout
+}
+
+func (heartbeatPolicy *HeartbeatPolicy) Alive() time.Duration {
+	if heartbeatPolicy.AliveTimeout <= 0 {
+		return DefaultAliveTimeout
+	}
+	return heartbeatPolicy.AliveTimeout
+}
+
+// 在响应头中通告 ping 间隔 (毫秒)
+
+func (heartbeatPolicy *HeartbeatPolicy) SetHeader(header http.Header) {
+	header.Set(HeartbeatHeader, strconv.FormatInt(heartbeatPolicy.Interval().Milliseconds(), 10))
+}
+
+// soldier 按 commander 通告的 ping 间隔计算读超时，未通告时返回 0
+
+func ParseHeartbeatHeader(header http.Header) time.Duration {
+	interval, err := strconv.ParseInt(header.Get(HeartbeatHeader), 10, 64)
+	if err != nil || interval <= 0 {
+		return 0
+	}
+	return pongTimeoutFactor * time.Duration(interval) * time.Millisecond
+}
+
+// 设置读超时，收到 pong 后延长；读取到其他消息时由调用方调用 ExtendReadDeadline 延长
+
+func WatchReadDeadline(conn *websocket.Conn, timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+	
+	ExtendReadDeadline(conn, timeout)
+	conn.SetPongHandler(func(string) error {
+		ExtendReadDeadline(conn, timeout)
+		return nil
+	})
+}
+
+func ExtendReadDeadline(conn *websocket.Conn, timeout time.Duration) {
+	if timeout > 0 {
+		conn.SetReadDeadline(time.Now().Add(timeout))
+	}
+}
+
+// 定时发送 ping，发送失败时返回错误，ctx 结束时返回 nil
+
+func (heartbeatPolicy *HeartbeatPolicy) Ping(ctx context.Context, conn *websocket.Conn) error {
+	ticker := time.NewTicker(heartbeatPolicy.Interval())
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ticker.C:
+			err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(pingWriteTimeout))
+			if err != nil {
+				return err
+			}
+		
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
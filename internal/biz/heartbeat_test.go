@@ -0,0 +1,85 @@
+package biz
+
+import (
+	"context"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeartbeatPolicy_Header(t *testing.T) {
+	header := http.Header{}
+	heartbeatPolicy := &HeartbeatPolicy{}
+	heartbeatPolicy.SetHeader(header)
+	assert.Equal(t, 3*DefaultPingInterval, ParseHeartbeatHeader(header))
+	assert.Equal(t, 3*DefaultPingInterval, heartbeatPolicy.Timeout())
+	assert.Equal(t, DefaultAliveTimeout, heartbeatPolicy.Alive())
+	
+	// 旧版本 commander 未通告时不设置读超时
+	assert.Equal(t, time.Duration(0), ParseHeartbeatHeader(http.Header{}))
+}
+
+// 对端响应 pong 时连接保持，对端不再读取 (半开连接) 时读超时
+
+func TestHeartbeatPolicy_Ping(t *testing.T) {
+	heartbeatPolicy := &HeartbeatPolicy{PingInterval: 20 * time.Millisecond, PongTimeout: 100 * time.Millisecond}
+	
+	closed := make(chan time.Duration, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+		
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go heartbeatPolicy.Ping(ctx, conn)
+		
+		start := time.Now()
+		WatchReadDeadline(conn, heartbeatPolicy.Timeout())
+		for {
+			_, _, err := conn.ReadMessage()
+			if err != nil {
+				closed <- time.Since(start)
+				return
+			}
+		}
+	}))
+	defer server.Close()
+	
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+	
+	// 读取时自动响应 pong
+	go func() {
+		for {
+			_, _, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+		}
+	}()
+	
+	select {
+	case <-closed:
+		t.Fatal("响应 pong 的连接被关闭")
+	case <-time.After(300 * time.Millisecond):
+	}
+	
+	// 停止读取后不再响应 pong
+	conn.SetReadDeadline(time.Now())
+	
+	select {
+	case elapsed := <-closed:
+		assert.Greater(t, elapsed, 300*time.Millisecond)
+	case <-time.After(2 * time.Second):
+		t.Fatal("未检测到连接断开")
+	}
+}
@@ -0,0 +1,156 @@
+package biz
+
+import (
+	"go.uber.org/zap"
+	"golang.org/x/net/icmp"
+	"os/exec"
+	"runtime"
+	"runtime/debug"
+)
+
+// soldier 连接时通过该请求头携带版本，记录到连接记录中
+const AgentVersionHeader = "X-Camp-Agent-Version"
+
+// soldier 连接成功后上报的版本及能力信息，commander 持久化到 Instance 中
+
+type AgentHello struct {
+	Version      string               `json:"version,omitempty"`
+	GitCommit    string               `json:"gitCommit,omitempty"`
+	BuildTime    string               `json:"buildTime,omitempty"`
+	GoVersion    string               `json:"goVersion,omitempty"`
+	Os           string               `json:"os,omitempty"`
+	Arch         string               `json:"arch,omitempty"`
+	Capabilities []InstructCapability `json:"capabilities,omitempty"`
+}
+
+type InstructCapability struct {
+	Type          InstructType `json:"type,omitempty"`
+	Timeout       int64        `json:"timeout,omitempty"`       // 执行超时时间 (秒)，0 表示不限制
+	MaxOutputSize int64        `json:"maxOutputSize,omitempty"` // 返回内容最大长度 (字节)，0 表示不限制
+}
+
+// 查找指令类型对应的能力
+
+func (agentHello *AgentHello) Capability(instructType InstructType) (InstructCapability, bool) {
+	for _, capability := range agentHello.Capabilities {
+		if capability.Type == instructType {
+			return capability, true
+		}
+	}
+	
+	return InstructCapability{}, false
+}
+
+// chromedp 默认查找的浏览器程序
+
+var chromeExecNames = []string{
+	"headless_shell",
+	"headless-shell",
+	"chromium",
+	"chromium-browser",
+	"google-chrome",
+	"google-chrome-stable",
+	"google-chrome-beta",
+	"google-chrome-unstable",
+	"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+}
+
+// 生成 Hello 消息，探测本地环境支持的指令类型
+
+func (webSocketUseCase *WebSocketUseCase) Hello(version string) ClientMessage {
+	hello := &AgentHello{
+		Version:   version,
+		GoVersion: runtime.Version(),
+		Os:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+	
+	buildInfo, ok := debug.ReadBuildInfo()
+	if ok {
+		for _, setting := range buildInfo.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				hello.GitCommit = setting.Value
+			case "vcs.time":
+				hello.BuildTime = setting.Value
+			}
+		}
+	}
+	
+	_, err := exec.LookPath("bash")
+	if err == nil {
+		hello.Capabilities = append(hello.Capabilities, InstructCapability{Type: CommandInstruct})
+	}
+	
+	if chromeAvailable() {
+		hello.Capabilities = append(hello.Capabilities, InstructCapability{
+			Type:    ChromeDpInspectInstruct,
+			Timeout: int64(singlePageDefaultTimeout.Seconds()),
+		})
+	}
+	
+	hello.Capabilities = append(hello.Capabilities,
+		InstructCapability{Type: DnsInstruct},
+		InstructCapability{Type: HttpInstruct},
+	)
+	
+	if icmpAvailable() {
+		hello.Capabilities = append(hello.Capabilities, InstructCapability{
+			Type:    IcmpInstruct,
+			Timeout: int64(icmpTimeout.Seconds()),
+		})
+	}
+	
+	// 仅配置了允许路径时支持文件指令
+	if webSocketUseCase.fileClientUseCase != nil && webSocketUseCase.fileClientUseCase.Enabled() {
+		maxSize := webSocketUseCase.fileClientUseCase.policy.MaxSize
+		hello.Capabilities = append(hello.Capabilities,
+			InstructCapability{Type: FileGetInstruct, MaxOutputSize: maxSize},
+			InstructCapability{Type: FilePutInstruct, MaxOutputSize: maxSize},
+		)
+	}
+	
+	if webSocketUseCase.shellClientUseCase != nil && webSocketUseCase.shellClientUseCase.Enabled() {
+		hello.Capabilities = append(hello.Capabilities, InstructCapability{Type: ShellInstruct})
+	}
+	
+	if webSocketUseCase.tunnelClientUseCase != nil && webSocketUseCase.tunnelClientUseCase.Enabled() {
+		hello.Capabilities = append(hello.Capabilities, InstructCapability{Type: TunnelInstruct})
+	}
+	
+	webSocketUseCase.logger.Info("本地环境能力探测完成", zap.Any("capabilities", hello.Capabilities))
+	
+	return ClientMessage{
+		Type:  ClientHello,
+		Hello: hello,
+	}
+}
+
+func chromeAvailable() bool {
+	for _, name := range chromeExecNames {
+		_, err := exec.LookPath(name)
+		if err == nil {
+			return true
+		}
+	}
+	
+	return false
+}
+
+// pro-bing 在 linux 下默认使用 udp ping (需 net.ipv4.ping_group_range 允许)，其余情况需要 raw socket 权限
+
+func icmpAvailable() bool {
+	conn, err := icmp.ListenPacket("udp4", "0.0.0.0")
+	if err == nil {
+		conn.Close()
+		return true
+	}
+	
+	conn, err = icmp.ListenPacket("ip4:icmp", "0.0.0.0")
+	if err == nil {
+		conn.Close()
+		return true
+	}
+	
+	return false
+}
@@ -1,10 +1,12 @@
 package biz
 
 import (
+	"context"
 	"encoding/json"
 	"go.uber.org/zap"
 	"io/ioutil"
 	"net/http"
+	"time"
 )
 
 // 未完成 - 建议使用 chromeDp 完成，简单的可以使用curl command完成
@@ -20,9 +22,10 @@ func NewHttpInspectClientUseCase(logger *zap.Logger) *HttpInspectClientUseCase {
 }
 
 type HttpInspectReply struct {
-	Url        string `json:"url,omitempty"`
-	StatusCode int    `json:"statusCode,omitempty"`
-	Response   []byte `json:"response,omitempty"`
+	Url        string  `json:"url,omitempty"`
+	StatusCode int     `json:"statusCode,omitempty"`
+	Response   []byte  `json:"response,omitempty"`
+	Latency    float64 `json:"latency,omitempty"` // 请求耗时 (毫秒)，旧版本 soldier 不上报
 }
 
 func (httpInspectReply *HttpInspectReply) String() string {
@@ -34,20 +37,29 @@ func (httpInspectReply *HttpInspectReply) String() string {
 	return string(b)
 }
 
-func (httpInspectClientUseCase *HttpInspectClientUseCase) GetHttpUrlResponse(url string) (HttpInspectReply, error) {
+// 指令取消或连接断开时 ctx 结束，中止请求
+
+func (httpInspectClientUseCase *HttpInspectClientUseCase) GetHttpUrlResponse(ctx context.Context, url string) (HttpInspectReply, error) {
 	var httpInspectReply HttpInspectReply
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return httpInspectReply, err
 	}
 	
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return httpInspectReply, err
+	}
+	defer resp.Body.Close()
+	
 	respByte, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return httpInspectReply, err
 	}
 	
+	httpInspectReply.Latency = durationMs(time.Since(start))
 	httpInspectReply.Response = respByte
-	defer resp.Body.Close()
 	
 	httpInspectReply.StatusCode = resp.StatusCode
 	httpInspectReply.Url = url
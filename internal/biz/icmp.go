@@ -1,11 +1,17 @@
 package biz
 
 import (
+	"context"
 	"github.com/prometheus-community/pro-bing"
 	"go.uber.org/zap"
 	"time"
 )
 
+const (
+	icmpTimeout      = 5 * time.Second
+	defaultIcmpCount = 4
+)
+
 type IcmpClientUseCase struct {
 	logger *zap.Logger
 }
@@ -16,7 +22,9 @@ func NewIcmpClientUseCase(logger *zap.Logger) *IcmpClientUseCase {
 	}
 }
 
-func (icmpClientUseCase *IcmpClientUseCase) Icmp(addr string, count int) (*probing.Statistics, error) {
+// 指令取消或连接断开时 ctx 结束，停止探测
+
+func (icmpClientUseCase *IcmpClientUseCase) Icmp(ctx context.Context, addr string, count int) (*probing.Statistics, error) {
 	pinger, err := probing.NewPinger(addr)
 	
 	if err != nil {
@@ -25,9 +33,9 @@ func (icmpClientUseCase *IcmpClientUseCase) Icmp(addr string, count int) (*probi
 	}
 	
 	pinger.Count = count
-	pinger.Timeout = 5 * time.Second
+	pinger.Timeout = icmpTimeout
 	
-	err = pinger.Run()
+	err = pinger.RunWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
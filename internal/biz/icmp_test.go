@@ -1,6 +1,7 @@
 package biz
 
 import (
+	"context"
 	"fmt"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -8,7 +9,7 @@ import (
 
 func TestIcmp(t *testing.T) {
 	icmpClientUseCase := IcmpClientUseCase{}
-	r, err := icmpClientUseCase.Icmp("baidu.com", 4)
+	r, err := icmpClientUseCase.Icmp(context.Background(), "baidu.com", 4)
 	require.NoError(t, err, "执行Icmp失败")
 	fmt.Println(r)
 }
@@ -7,6 +7,11 @@ import (
 	"time"
 )
 
+const (
+	instanceDefaultLimit = 50
+	instanceMaxLimit     = 500
+)
+
 type Instance struct {
 	Uuid         string `json:"uuid,omitempty"`
 	OrgUuid      string `json:"orgUuid,omitempty"`
@@ -15,30 +20,77 @@ type Instance struct {
 	ClientIp     string `json:"clientIp,omitempty"`
 	CreateTime   int64  `json:"createTime,omitempty"`
 	UpdateTime   int64  `json:"updateTime,omitempty"`
+	Online       bool   `json:"online"` // 上线时置为 true，离线时置为 false
+	
+	AgentVersion string      `json:"agentVersion,omitempty"`                     // soldier 版本
+	AgentInfo    *AgentHello `json:"agentInfo,omitempty" gorm:"serializer:json"` // soldier 版本及能力信息
+	
+	Inventory *HostInventory `json:"inventory,omitempty" gorm:"serializer:json"` // 主机信息
+	Os        string         `json:"os,omitempty"`                               // 操作系统，取自主机信息，用于搜索
+	Platform  string         `json:"platform,omitempty"`                         // 发行版，取自主机信息，用于搜索
+	
+	Labels map[string]string `json:"labels,omitempty" gorm:"-"`
+}
+
+// 搜索实例的条件，为空的条件不过滤
+
+type InstanceQuery struct {
+	OrgUuid       string
+	GroupUuid     string
+	Name          string   // 实例名包含该字符串
+	InstanceNames []string // 实例名为其中之一
+	Online        *bool
+	Os            string
+	Platform      string
+	AgentVersion  string
+	UpdatedBefore int64 // 最后一次上线或离线的时间早于该时间 (unix 秒)
+	Selector      []LabelRequirement
+	Offset        int
+	Limit         int
 }
 
 func (instance *Instance) TableName() string {
 	return "instance"
 }
 
+// 检查实例是否支持该指令类型，未上报能力信息的旧版本 soldier 仅支持命令行及探测指令
+
+func (instance *Instance) SupportInstruct(instructType InstructType) bool {
+	if instance.AgentInfo == nil {
+		return instructType >= CommandInstruct && instructType <= IcmpInstruct
+	}
+	
+	_, ok := instance.AgentInfo.Capability(instructType)
+	return ok
+}
+
 type InstanceRepo interface {
 	Register(ctx context.Context, instance Instance) (string, error)
-	List(ctx context.Context, orgUuid, groupUuid string) ([]Instance, error)
-	ListAliveInstance(ctx context.Context, orgUuid, groupUuid string) ([]Instance, error)
+	Search(ctx context.Context, query InstanceQuery) ([]Instance, int64, error)
+	ListOnline(ctx context.Context, orgUuid, groupUuid string) ([]Instance, error)
 	Get(ctx context.Context, orgUuid, groupUuid, instanceName string) (Instance, error)
-	HeartBeat(ctx context.Context, orgUuid string, groupUuid string, instanceName string) error
-	UpdateTime(ctx context.Context, uuid string) error
+	Online(ctx context.Context, uuid string) error
+	Offline(ctx context.Context, uuid string) error
+	UpdateAgentInfo(ctx context.Context, uuid string, hello AgentHello) error
+	UpdateInventory(ctx context.Context, uuid string, inventory HostInventory) error
+	Delete(ctx context.Context, uuid string) error
 }
 
 type InstanceUseCase struct {
-	instanceRepo InstanceRepo
-	logger       *zap.Logger
+	instanceRepo   InstanceRepo
+	presenceRepo   PresenceRepo
+	connectionRepo ConnectionRepo
+	labelRepo      LabelRepo
+	logger         *zap.Logger
 }
 
-func NewInstanceUseCase(instanceRepo InstanceRepo, logger *zap.Logger) *InstanceUseCase {
+func NewInstanceUseCase(instanceRepo InstanceRepo, presenceRepo PresenceRepo, connectionRepo ConnectionRepo, labelRepo LabelRepo, logger *zap.Logger) *InstanceUseCase {
 	return &InstanceUseCase{
-		instanceRepo: instanceRepo,
-		logger:       logger,
+		instanceRepo:   instanceRepo,
+		presenceRepo:   presenceRepo,
+		connectionRepo: connectionRepo,
+		labelRepo:      labelRepo,
+		logger:         logger,
 	}
 }
 
@@ -52,54 +104,71 @@ func (instanceUseCase *InstanceUseCase) Register(ctx context.Context, orgUuid, g
 		ClientIp:     clientIp,
 		CreateTime:   time.Now().Unix(),
 		UpdateTime:   time.Now().Unix(),
+		Online:       true,
 	}
 	
 	return instanceUseCase.instanceRepo.Register(ctx, instance)
 }
 
-// 定时更新状态
-
-func (instanceUseCase *InstanceUseCase) UpdateTime(ctx context.Context, orgUuid, groupUuid, instanceName, clientIp string) {
-	//
-	var uid string
-	var err error
-	
-	for {
-		uid, err = instanceUseCase.Register(ctx, orgUuid, groupUuid, instanceName, clientIp)
-		if err == nil {
-			break
-		} else {
-			instanceUseCase.logger.Error("注册实例失败", zap.Error(err))
-		}
-		time.Sleep(3 * time.Second)
-	}
+// 列出在线实例 (兼容旧接口)，最多返回 instanceMaxLimit 个，更多实例通过 SearchInstances 分页获取
+
+func (instanceUseCase *InstanceUseCase) ListAliveInstance(ctx context.Context, orgUuid, groupUuid string) ([]Instance, error) {
+	instanceUseCase.logger.Info("ListAliveInstance", zap.String("orgUuid", orgUuid), zap.String("groupUuid", groupUuid))
 	
-	//
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-	
-	for {
-		select {
-		case <-ticker.C:
-			//
-			err = instanceUseCase.instanceRepo.UpdateTime(ctx, uid)
-			if err != nil {
-				instanceUseCase.logger.Error("更新实例时间失败", zap.String("uuid", uid), zap.Error(err))
-			}
-		
-		case <-ctx.Done():
-			instanceUseCase.logger.Info("定时更新时间模块关闭消息")
-			return
-		}
+	// MySQL 中在线的实例，排除在线记录已过期 (尚未被标记离线) 的实例
+	online := true
+	instances, _, err := instanceUseCase.instanceRepo.Search(ctx, InstanceQuery{
+		OrgUuid:   orgUuid,
+		GroupUuid: groupUuid,
+		Online:    &online,
+		Limit:     instanceMaxLimit,
+	})
+	if err != nil {
+		return nil, err
 	}
 	
+	return filterAlive(ctx, instanceUseCase.presenceRepo, instances)
 }
 
 //
 
-func (instanceUseCase *InstanceUseCase) ListAliveInstance(ctx context.Context, orgUuid, groupUuid string) ([]Instance, error) {
-	instanceUseCase.logger.Info("ListAliveInstance", zap.String("orgUuid", orgUuid), zap.String("groupUuid", groupUuid))
-	return instanceUseCase.instanceRepo.ListAliveInstance(ctx, orgUuid, groupUuid)
+func (instanceUseCase *InstanceUseCase) GetInstance(ctx context.Context, orgUuid, groupUuid, instanceName string) (Instance, error) {
+	instance, err := instanceUseCase.instanceRepo.Get(ctx, orgUuid, groupUuid, instanceName)
+	if err != nil {
+		return instance, err
+	}
+	
+	instances := []Instance{instance}
+	err = instanceUseCase.fillLabels(ctx, instances)
+	return instances[0], err
+}
+
+// 按条件分页搜索实例，返回当前页的实例 (包含标签) 及总数
+
+func (instanceUseCase *InstanceUseCase) SearchInstances(ctx context.Context, query InstanceQuery) ([]Instance, int64, error) {
+	if query.Limit <= 0 {
+		query.Limit = instanceDefaultLimit
+	}
+	
+	if query.Limit > instanceMaxLimit {
+		query.Limit = instanceMaxLimit
+	}
+	
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+	
+	instances, total, err := instanceUseCase.instanceRepo.Search(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	
+	err = instanceUseCase.fillLabels(ctx, instances)
+	if err != nil {
+		return nil, 0, err
+	}
+	
+	return instances, total, nil
 }
 
 //
@@ -110,9 +179,27 @@ func (instanceUseCase *InstanceUseCase) GetInstanceAlive(ctx context.Context, or
 		return false
 	}
 	
-	if (time.Now().Unix() - instance.UpdateTime) >= 20 {
+	if !instance.Online {
+		return false
+	}
+	
+	presences, err := instanceUseCase.presenceRepo.ListPresence(ctx, []string{instance.Uuid})
+	if err != nil {
+		instanceUseCase.logger.Error("获取实例在线状态失败", zap.String("uuid", instance.Uuid), zap.Error(err))
+		return false
+	}
+	
+	_, ok := presences[instance.Uuid]
+	return ok
+}
+
+// 检查实例是否支持该指令类型
+
+func (instanceUseCase *InstanceUseCase) SupportInstruct(ctx context.Context, orgUuid, groupUuid, instanceName string, instructType InstructType) bool {
+	instance, err := instanceUseCase.instanceRepo.Get(ctx, orgUuid, groupUuid, instanceName)
+	if err != nil {
 		return false
 	}
 	
-	return true
+	return instance.SupportInstruct(instructType)
 }
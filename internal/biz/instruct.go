@@ -2,11 +2,20 @@ package biz
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/prometheus-community/pro-bing"
+	"github.com/qx66/camp/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"io"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -18,13 +27,41 @@ const (
 	DnsInstruct             InstructType = 3 // Dns 指令
 	HttpInstruct            InstructType = 4 // Http 指令
 	IcmpInstruct            InstructType = 5 // Mtr 指令
+	FileGetInstruct         InstructType = 6 // 文件获取指令
+	FilePutInstruct         InstructType = 7 // 文件下发指令
+	ShellInstruct           InstructType = 8 // 交互式终端，仅通过终端接口打开，不可直接下发
+	TunnelInstruct          InstructType = 9 // 端口转发，仅通过端口转发接口打开，不可直接下发
 )
 
+// 指令状态，对应 Instruct.Result
+
+const (
+	InstructRunning = "running" // 0
+	InstructSuccess = "success" // 1
+	InstructFailed  = "failed"  // -1
+	
+	instructDefaultLimit = 50
+	instructMaxLimit     = 500
+)
+
+var (
+	ErrUnknownInstructType  = errors.New("未知指令类型")
+	ErrInstructNotFound     = errors.New("指令不存在")
+	ErrInstructFinished     = errors.New("指令已结束")
+	ErrInvalidInstructQuery = errors.New("指令查询参数异常")
+	ErrInvalidInstructWait  = errors.New("等待时间异常")
+)
+
+// 指令队列的轮询间隔，实例所在节点收到新指令通知 (NodeInstruct) 时立即读取
+
+const instructPollInterval = 5 * time.Second
+
 // DnsInspect: 为了方便管理，以及日常的需求，仅对域名进行 A记录 CName记录解析
 
 type InstructMessage struct {
 	Uuid           string       `json:"uuid,omitempty"`
 	Type           InstructType `json:"type,omitempty"`
+	Timeout        int64        `json:"timeout,omitempty"`        // 执行超时 (秒)，取自分组配置，0 表示不限制
 	CommandContent string       `json:"commandContent,omitempty"` // 命令行指令-命令
 	CommandReply   string       `json:"commandReply,omitempty"`   // 命令行指令-返回内容
 	
@@ -38,8 +75,14 @@ type InstructMessage struct {
 	HttpInspectReply HttpInspectReply `json:"httpInspectReply,omitempty"` // Http访问检测指令-返回内容
 	
 	IcmpInspectAddr  string `json:"icmpInspectAddr,omitempty"`
+	IcmpCount        int32  `json:"icmpCount,omitempty"` // icmp 发送次数，取自分组配置，0 使用默认值
 	IcmpInspectReply *probing.Statistics
 	
+	FilePath     string `json:"filePath,omitempty"`     // 文件指令-目标路径
+	FileSize     int64  `json:"fileSize,omitempty"`     // 文件指令-文件大小
+	FileSha256   string `json:"fileSha256,omitempty"`   // 文件指令-文件 sha256
+	FileBlobUuid string `json:"fileBlobUuid,omitempty"` // 文件下发指令-commander 保存的文件，不下发到 soldier
+	
 	MtrInspectAddr  string `json:"mtrInspectAddr,omitempty"`  // mtr指令
 	MtrInspectReply string `json:"mtrInspectReply,omitempty"` // mtr指令-返回内容
 	Result          bool   `json:"result,omitempty"`          // 指令执行结果
@@ -57,6 +100,82 @@ type Instruct struct {
 	Reply        string       `json:"reply,omitempty"`
 	CreateTime   int64        `json:"createTime,omitempty"`
 	UpdateTime   int64        `json:"updateTime,omitempty"`
+	Truncated    bool         `json:"truncated,omitempty"` // 结果超过消息大小上限或保留策略的大小，已被截断
+	Operator     string       `json:"operator,omitempty"`  // 下发指令、打开终端或端口转发的操作人
+	BatchUuid    string       `json:"batchUuid,omitempty"` // 批量下发时所属的批次
+}
+
+// soldier 以新会话连接时旧会话中未收到结果的指令标记为中断，之后收到 soldier 离线缓存的结果时仍以实际结果更新
+
+const InstructInterruptedReply = "soldier 重新连接，指令执行中断"
+
+func (instruct *Instruct) Interrupted() bool {
+	return instruct.Result == -1 && instruct.Reply == InstructInterruptedReply
+}
+
+// 查询指令的条件，为空的条件不过滤；按创建时间倒序，After 为上一页最后一条指令的位置
+
+type InstructQuery struct {
+	OrgUuid      string
+	GroupUuid    string
+	InstanceName string
+	Type         InstructType
+	Result       *int32
+	Operator     string
+	BatchUuid    string
+	StartTime    int64 // 创建时间范围 [StartTime, EndTime)
+	EndTime      int64
+	After        *InstructCursor
+	Limit        int
+}
+
+// 分页游标，以 base64 编码的 "创建时间,uuid" 表示
+
+type InstructCursor struct {
+	CreateTime int64
+	Uuid       string
+}
+
+func (instructCursor InstructCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d,%s", instructCursor.CreateTime, instructCursor.Uuid)))
+}
+
+func ParseInstructCursor(s string) (*InstructCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cursor", ErrInvalidInstructQuery)
+	}
+	
+	createTime, uid, ok := strings.Cut(string(b), ",")
+	if !ok || uid == "" {
+		return nil, fmt.Errorf("%w: cursor", ErrInvalidInstructQuery)
+	}
+	
+	t, err := strconv.ParseInt(createTime, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cursor", ErrInvalidInstructQuery)
+	}
+	
+	return &InstructCursor{CreateTime: t, Uuid: uid}, nil
+}
+
+// 将状态 (running / success / failed) 转换为 Result，为空时返回 nil
+
+func ParseInstructState(state string) (*int32, error) {
+	var result int32
+	switch state {
+	case "":
+		return nil, nil
+	case InstructRunning:
+		result = 0
+	case InstructSuccess:
+		result = 1
+	case InstructFailed:
+		result = -1
+	default:
+		return nil, fmt.Errorf("%w: state 仅支持 %s / %s / %s", ErrInvalidInstructQuery, InstructRunning, InstructSuccess, InstructFailed)
+	}
+	return &result, nil
 }
 
 func (instruct *Instruct) TableName() string {
@@ -67,29 +186,108 @@ type InstructRepo interface {
 	IssueInstructions(ctx context.Context, orgUuid, groupUuid, instanceName string, instruct []byte) error
 	ReceiveInstructions(ctx context.Context, orgUuid, groupUuid, instanceName string) (string, error)
 	RecordInstruct(ctx context.Context, instruct Instruct) error
-	UpdateInstruct(ctx context.Context, uuid, reply string, result int32) error
+	UpdateInstruct(ctx context.Context, uuid, reply string, result int32) (int64, error)
+	UpdateInterruptedInstruct(ctx context.Context, uuid, reply string, result int32) (int64, error)
+	MarkInstructTruncated(ctx context.Context, uuid string) error
+	CancelInstruct(ctx context.Context, uuid string) (bool, error)
+	InstructCancelled(ctx context.Context, uuid string) (bool, error)
 	ListInstruct(ctx context.Context, orgUuid, groupUuid, instanceName string) ([]Instruct, error)
+	QueryInstructs(ctx context.Context, query InstructQuery) ([]Instruct, error)
 	GetInstruct(ctx context.Context, orgUuid, groupUuid, instanceName, uuid string) (Instruct, error)
+	FindInstruct(ctx context.Context, uuid string) (Instruct, error)
+	DeleteInstructQueue(ctx context.Context, orgUuid, groupUuid, instanceName string) error
+	DeleteGroupInstructs(ctx context.Context, orgUuid, groupUuid string, limit int) (int64, error)
 }
 
 type InstructUseCase struct {
-	instructRepo InstructRepo
-	logger       *zap.Logger
+	instructRepo   InstructRepo
+	blobRepo       BlobRepo
+	orgRepo        OrgRepo
+	sessionUseCase *SessionUseCase
+	clusterUseCase *ClusterUseCase
+	eventUseCase   *EventUseCase
+	logger         *zap.Logger
 }
 
-func NewInstructUseCase(instructRepo InstructRepo, logger *zap.Logger) *InstructUseCase {
+func NewInstructUseCase(instructRepo InstructRepo, blobRepo BlobRepo, orgRepo OrgRepo, sessionUseCase *SessionUseCase, clusterUseCase *ClusterUseCase, eventUseCase *EventUseCase, logger *zap.Logger) *InstructUseCase {
 	return &InstructUseCase{
-		instructRepo: instructRepo,
-		logger:       logger,
+		instructRepo:   instructRepo,
+		blobRepo:       blobRepo,
+		orgRepo:        orgRepo,
+		sessionUseCase: sessionUseCase,
+		clusterUseCase: clusterUseCase,
+		eventUseCase:   eventUseCase,
+		logger:         logger,
 	}
 }
 
-// 发布指令
+// 发布指令，operator 为下发指令的操作人
+
+func (instructUseCase *InstructUseCase) IssueInstructions(ctx context.Context, orgUuid string, groupUuid string, instanceName string, instructType InstructType, instructContent string, operator string) (string, error) {
+	return instructUseCase.issue(ctx, orgUuid, groupUuid, instanceName, instructType, instructContent, operator, "")
+}
+
+// 下发指令并等待结果 (最长 MaxInstructWait)，返回的指令 Result 为 0 时等待超时，指令仍在执行；probe 为探测指令的结构化结果
 
-func (instructUseCase *InstructUseCase) IssueInstructions(ctx context.Context, orgUuid string, groupUuid string, instanceName string, instructType InstructType, instructContent string) (string, error) {
+func (instructUseCase *InstructUseCase) IssueAndWait(ctx context.Context, orgUuid string, groupUuid string, instanceName string, instructType InstructType, instructContent string, operator string, wait time.Duration) (Instruct, *InstructResult, error) {
+	if wait <= 0 || wait > MaxInstructWait {
+		return Instruct{}, nil, ErrInvalidInstructWait
+	}
+	
+	// 下发前订阅，避免错过很快返回的结果
+	events, cancel := instructUseCase.eventUseCase.Subscribe(EventFilter{
+		OrgUuid:      orgUuid,
+		GroupUuid:    groupUuid,
+		InstanceName: instanceName,
+		Types:        []string{EventCompleted, EventCancelled},
+	})
+	defer cancel()
+	
+	instructUuid, err := instructUseCase.issue(ctx, orgUuid, groupUuid, instanceName, instructType, instructContent, operator, "")
+	if err != nil {
+		return Instruct{}, nil, err
+	}
+	
+	// 已下发的指令在等待失败时仍返回 uuid
+	instruct, probe, err := instructUseCase.eventUseCase.Wait(ctx, events, instructUuid, wait)
+	if err != nil {
+		return Instruct{Uuid: instructUuid}, nil, err
+	}
+	return instruct, probe, nil
+}
+
+// 下发指令，batchUuid 为批量下发的批次，单独下发时为空
+
+func (instructUseCase *InstructUseCase) issue(ctx context.Context, orgUuid string, groupUuid string, instanceName string, instructType InstructType, instructContent string, operator string, batchUuid string) (string, error) {
 	instructUuid := uuid.NewString()
 	var serviceMessage ServiceMessage
 	
+	ctx, span := tracing.Tracer().Start(ctx, "IssueInstructions",
+		trace.WithAttributes(
+			attribute.String("instruct.uuid", instructUuid),
+			attribute.Int("instruct.type", int(instructType)),
+			attribute.String("orgUuid", orgUuid),
+			attribute.String("groupUuid", groupUuid),
+			attribute.String("instanceName", instanceName),
+		),
+	)
+	defer span.End()
+	
+	// 分组已删除时拒绝，命令行指令须符合分组的命令策略
+	settings, err := loadGroupSettings(ctx, instructUseCase.orgRepo, orgUuid, groupUuid)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return instructUuid, err
+	}
+	
+	if instructType == CommandInstruct {
+		err = settings.CommandPolicy.Check(instructContent)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return instructUuid, err
+		}
+	}
+	
 	switch instructType {
 	case CommandInstruct:
 		serviceMessage = ServiceMessage{
@@ -144,17 +342,63 @@ func (instructUseCase *InstructUseCase) IssueInstructions(ctx context.Context, o
 		}
 		//return instructUuid, errors.New("暂不支持")
 	
+	case FileGetInstruct:
+		serviceMessage = ServiceMessage{
+			Type: ServiceInstruct,
+			InstructMessage: InstructMessage{
+				Uuid:     instructUuid,
+				Type:     instructType,
+				FilePath: instructContent,
+			},
+		}
+	
+	case FilePutInstruct:
+		var content FilePutContent
+		err := json.Unmarshal([]byte(instructContent), &content)
+		if err != nil || content.BlobUuid == "" || content.Path == "" {
+			span.SetStatus(codes.Error, ErrInvalidFileContent.Error())
+			return instructUuid, ErrInvalidFileContent
+		}
+		
+		blob, err := instructUseCase.blobRepo.Stat(ctx, content.BlobUuid)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return instructUuid, err
+		}
+		
+		serviceMessage = ServiceMessage{
+			Type: ServiceInstruct,
+			InstructMessage: InstructMessage{
+				Uuid:         instructUuid,
+				Type:         instructType,
+				FilePath:     content.Path,
+				FileSize:     blob.Size,
+				FileSha256:   blob.Sha256,
+				FileBlobUuid: blob.Uuid,
+			},
+		}
+	
 	default:
 		instructUseCase.logger.Error("未知指令类型", zap.Any("instructType", instructType))
-		return instructUuid, errors.New("未知指令类型")
+		span.SetStatus(codes.Error, "未知指令类型")
+		return instructUuid, ErrUnknownInstructType
 	}
 	
+	serviceMessage.InstructMessage.Timeout = settings.InstructTimeout
+	if instructType == IcmpInstruct {
+		serviceMessage.InstructMessage.IcmpCount = settings.IcmpCount
+	}
+	
+	// 将 trace 上下文随指令下发，soldier 执行及结果回传可关联到同一个 trace
+	serviceMessage.Trace = tracing.Inject(ctx)
+	
 	jsonByte, err := json.Marshal(serviceMessage)
 	if err != nil {
 		return instructUuid, err
 	}
 	
-	err = instructUseCase.instructRepo.RecordInstruct(ctx, Instruct{
+	instruct := Instruct{
 		Uuid:         instructUuid,
 		OrgUuid:      orgUuid,
 		GroupUuid:    groupUuid,
@@ -165,42 +409,256 @@ func (instructUseCase *InstructUseCase) IssueInstructions(ctx context.Context, o
 		Reply:        "",
 		CreateTime:   time.Now().Unix(),
 		UpdateTime:   time.Now().Unix(),
-	})
+		Operator:     operator,
+		BatchUuid:    batchUuid,
+	}
+	err = instructUseCase.instructRepo.RecordInstruct(ctx, instruct)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return instructUuid, errors.New("记录数据到数据库失败")
 	}
 	
-	return instructUuid, instructUseCase.instructRepo.IssueInstructions(ctx, orgUuid, groupUuid, instanceName, jsonByte)
+	err = instructUseCase.instructRepo.IssueInstructions(ctx, orgUuid, groupUuid, instanceName, jsonByte)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return instructUuid, err
+	}
+	
+	instructUseCase.eventUseCase.Publish(ctx, EventIssued, instruct, nil)
+	
+	// 通知实例连接所在节点读取指令队列
+	instructUseCase.clusterUseCase.RouteInstance(ctx, orgUuid, groupUuid, instanceName, NodeMessage{Type: NodeInstruct, InstructUuid: instructUuid})
+	return instructUuid, nil
+}
+
+// 取消未结束的指令: 标记为失败，通知实例连接所在节点转发给 soldier，尚未下发的指令不再下发
+
+func (instructUseCase *InstructUseCase) CancelInstruct(ctx context.Context, orgUuid, groupUuid, instanceName, uuid string) error {
+	instruct, err := instructUseCase.instructRepo.GetInstruct(ctx, orgUuid, groupUuid, instanceName, uuid)
+	if err != nil {
+		return err
+	}
+	
+	if instruct.Result != 0 {
+		return ErrInstructFinished
+	}
+	
+	// 与结果回传并发时以先更新的为准
+	cancelled, err := instructUseCase.instructRepo.CancelInstruct(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	
+	if !cancelled {
+		return ErrInstructFinished
+	}
+	
+	instructUseCase.eventUseCase.PublishUpdate(ctx, EventCancelled, uuid, nil)
+	instructUseCase.clusterUseCase.RouteInstance(ctx, orgUuid, groupUuid, instanceName, NodeMessage{Type: NodeCancel, InstructUuid: uuid})
+	return nil
 }
 
-// 接收指令 - 采用 redis list 存储指令，建议每秒获取一次
+// 接收指令 - 采用 redis list 存储指令，收到新指令通知时立即读取，否则按 instructPollInterval 轮询，下发的指令记录到会话中直到收到结果
 
-func (instructUseCase *InstructUseCase) ReceiveInstructions(ctx context.Context, orgUuid string, groupUuid string, instanceName string, instructions chan string) {
-	ticker := time.NewTicker(1 * time.Second)
+func (instructUseCase *InstructUseCase) ReceiveInstructions(ctx context.Context, orgUuid string, groupUuid string, instanceName string, session *Session, serviceChannels *ServiceChannels) {
+	ticker := time.NewTicker(instructPollInterval)
 	defer ticker.Stop()
 	
 	for {
 		select {
 		case <-ticker.C:
-			//instructUseCase.logger.Info("定时执行接收到指令消息")
-			instruction, err := instructUseCase.instructRepo.ReceiveInstructions(ctx, orgUuid, groupUuid, instanceName)
-			if err == nil {
-				//instructUseCase.logger.Info("接收到指令消息", zap.String("instruction", instruction))
-				instructions <- instruction
-			}
+		case <-serviceChannels.Woken():
 		case <-ctx.Done():
 			instructUseCase.logger.Info("接收到指令消息结束")
 			return
 		}
+		
+		if !instructUseCase.receive(ctx, orgUuid, groupUuid, instanceName, session, serviceChannels) {
+			return
+		}
 	}
 }
 
+// 读取队列中的全部指令，连接关闭时返回 false
+
+func (instructUseCase *InstructUseCase) receive(ctx context.Context, orgUuid string, groupUuid string, instanceName string, session *Session, serviceChannels *ServiceChannels) bool {
+	for {
+		start := time.Now()
+		instruction, err := instructUseCase.instructRepo.ReceiveInstructions(ctx, orgUuid, groupUuid, instanceName)
+		if err != nil {
+			return true
+		}
+		
+		// 空轮询不产生 span，仅在获取到指令后关联到指令所属 trace
+		serviceMessage := decodeInstruction(instruction)
+		_, span := tracing.Tracer().Start(tracing.Extract(ctx, serviceMessage.Trace), "ReceiveInstructions",
+			trace.WithTimestamp(start),
+			trace.WithAttributes(attribute.String("instanceName", instanceName)),
+		)
+		ok := instructUseCase.deliver(ctx, instruction, serviceMessage, session, serviceChannels)
+		span.End()
+		if !ok {
+			return false
+		}
+	}
+}
+
+// 恢复会话后重新下发待确认的指令
+
+func (instructUseCase *InstructUseCase) Redeliver(ctx context.Context, session *Session, serviceChannels *ServiceChannels) {
+	instructions := instructUseCase.sessionUseCase.Pending(ctx, session)
+	if len(instructions) > 0 {
+		instructUseCase.logger.Info("重新下发待确认指令", zap.String("session", session.Token), zap.Int("count", len(instructions)))
+	}
+	
+	for _, instruction := range instructions {
+		if !instructUseCase.deliver(ctx, instruction, decodeInstruction(instruction), session, serviceChannels) {
+			return
+		}
+	}
+}
+
+// 指令队列及待确认记录中以 JSON 存储服务端消息
+
+func decodeInstruction(instruction string) ServiceMessage {
+	var serviceMessage ServiceMessage
+	json.Unmarshal([]byte(instruction), &serviceMessage)
+	return serviceMessage
+}
+
+// 按指令类型放入对应通道，文件下发指令与分片使用同一通道保证顺序，连接关闭时返回 false
+
+func (instructUseCase *InstructUseCase) deliver(ctx context.Context, instruction string, serviceMessage ServiceMessage, session *Session, serviceChannels *ServiceChannels) bool {
+	if serviceMessage.InstructMessage.Uuid != "" {
+		// 已取消的指令不再下发，恢复会话时同时移除待确认记录
+		cancelled, err := instructUseCase.instructRepo.InstructCancelled(ctx, serviceMessage.InstructMessage.Uuid)
+		if err != nil {
+			instructUseCase.logger.Error("获取指令取消状态失败", zap.String("uuid", serviceMessage.InstructMessage.Uuid), zap.Error(err))
+		}
+		
+		if cancelled {
+			instructUseCase.logger.Info("指令已取消，不再下发", zap.String("uuid", serviceMessage.InstructMessage.Uuid))
+			instructUseCase.sessionUseCase.Complete(ctx, session, serviceMessage.InstructMessage.Uuid)
+			return true
+		}
+		
+		instructUseCase.sessionUseCase.Track(ctx, session, serviceMessage.InstructMessage.Uuid, instruction)
+	}
+	
+	select {
+	case serviceChannels.Queue(serviceMessage.Channel()) <- serviceMessage:
+	case <-ctx.Done():
+		return false
+	}
+	
+	// 文件下发指令，紧随指令发送文件分片
+	instructUseCase.sendFileChunks(ctx, serviceMessage, serviceChannels.Queue(BulkChannel))
+	return true
+}
+
+// 读取文件下发指令对应的文件，分片发送到 instructions
+
+func (instructUseCase *InstructUseCase) sendFileChunks(ctx context.Context, serviceMessage ServiceMessage, instructions chan ServiceMessage) {
+	if serviceMessage.InstructMessage.Type != FilePutInstruct {
+		return
+	}
+	
+	instructUuid := serviceMessage.InstructMessage.Uuid
+	rc, _, err := instructUseCase.blobRepo.Open(ctx, serviceMessage.InstructMessage.FileBlobUuid)
+	if err != nil {
+		instructUseCase.logger.Error("读取下发文件失败", zap.String("uuid", instructUuid), zap.Error(err))
+		instructUseCase.failInstruct(ctx, instructUuid, err)
+		return
+	}
+	defer rc.Close()
+	
+	var offset int64
+	
+	for {
+		// 分片在连接的发送协程中编码，每个分片使用独立的缓冲区
+		buf := make([]byte, fileChunkSize)
+		n, err := io.ReadFull(rc, buf)
+		eof := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !eof {
+			instructUseCase.logger.Error("读取下发文件失败", zap.String("uuid", instructUuid), zap.Error(err))
+			instructUseCase.failInstruct(ctx, instructUuid, err)
+			return
+		}
+		
+		chunk := ServiceMessage{
+			Type:      ServiceFileChunk,
+			FileChunk: &FileChunk{Uuid: instructUuid, Offset: offset, Data: buf[:n], Eof: eof},
+		}
+		
+		select {
+		case instructions <- chunk:
+		case <-ctx.Done():
+			return
+		}
+		
+		if eof {
+			return
+		}
+		offset += int64(n)
+	}
+}
+
+func (instructUseCase *InstructUseCase) failInstruct(ctx context.Context, instructUuid string, err error) {
+	updated, err := instructUseCase.instructRepo.UpdateInstruct(ctx, instructUuid, err.Error(), -1)
+	if err != nil {
+		instructUseCase.logger.Error("更新指令结果失败", zap.String("uuid", instructUuid), zap.Error(err))
+		return
+	}
+	if updated == 0 {
+		return
+	}
+	instructUseCase.eventUseCase.PublishUpdate(ctx, EventCompleted, instructUuid, nil)
+}
+
 // 列出指令
 
 func (instructUseCase *InstructUseCase) ListInstruct(ctx context.Context, orgUuid, groupUuid, instanceName string) ([]Instruct, error) {
 	return instructUseCase.instructRepo.ListInstruct(ctx, orgUuid, groupUuid, instanceName)
 }
 
+// 按条件分页查询指令 (不包含指令结果)，cursor 为上一页返回的游标；返回当前页的指令及下一页的游标，没有下一页时游标为空
+
+func (instructUseCase *InstructUseCase) QueryInstructs(ctx context.Context, query InstructQuery, cursor string) ([]Instruct, string, error) {
+	if query.StartTime < 0 || query.EndTime < 0 || query.EndTime > 0 && query.EndTime <= query.StartTime {
+		return nil, "", fmt.Errorf("%w: 时间范围", ErrInvalidInstructQuery)
+	}
+	
+	if cursor != "" {
+		after, err := ParseInstructCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query.After = after
+	}
+	
+	if query.Limit <= 0 {
+		query.Limit = instructDefaultLimit
+	}
+	limit := min(query.Limit, instructMaxLimit)
+	
+	// 多查询一条以判断是否有下一页
+	query.Limit = limit + 1
+	instructs, err := instructUseCase.instructRepo.QueryInstructs(ctx, query)
+	if err != nil {
+		return nil, "", err
+	}
+	
+	if len(instructs) <= limit {
+		return instructs, "", nil
+	}
+	
+	instructs = instructs[:limit]
+	last := instructs[limit-1]
+	return instructs, InstructCursor{CreateTime: last.CreateTime, Uuid: last.Uuid}.String(), nil
+}
+
 // 获取指令
 
 func (instructUseCase *InstructUseCase) GetInstruct(ctx context.Context, orgUuid, groupUuid, instanceName, uuid string) (Instruct, error) {
@@ -0,0 +1,130 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestParseInstructCursor(t *testing.T) {
+	cursor := InstructCursor{CreateTime: 1700000000, Uuid: "a-b"}
+	parsed, err := ParseInstructCursor(cursor.String())
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, *parsed)
+	
+	for _, s := range []string{"!", "MTIz", "YWJjLGQ"} {
+		_, err = ParseInstructCursor(s)
+		assert.ErrorIs(t, err, ErrInvalidInstructQuery, s)
+	}
+}
+
+// 按游标翻页，同一创建时间的指令按 uuid 排序，不重复也不遗漏
+
+func TestInstructUseCase_QueryInstructs(t *testing.T) {
+	ctx := context.Background()
+	instructRepo := &fakeInstructRepo{instruct: make(map[string]Instruct)}
+	for i := 0; i < 7; i++ {
+		uid := fmt.Sprintf("u%d", i)
+		instructRepo.instruct[uid] = Instruct{Uuid: uid, Type: CommandInstruct, Result: 1, Operator: "alice", CreateTime: int64(100 + i/3)}
+	}
+	instructRepo.instruct["other"] = Instruct{Uuid: "other", Type: CommandInstruct, Result: -1, Operator: "bob", CreateTime: 200}
+	instructUseCase := NewInstructUseCase(instructRepo, nil, nil, nil, nil, nil, zap.NewNop())
+	
+	success, err := ParseInstructState(InstructSuccess)
+	assert.NoError(t, err)
+	
+	var uuids []string
+	cursor := ""
+	for page := 0; ; page++ {
+		instructs, next, err := instructUseCase.QueryInstructs(ctx, InstructQuery{Result: success, Operator: "alice", Limit: 3}, cursor)
+		assert.NoError(t, err)
+		for _, instruct := range instructs {
+			uuids = append(uuids, instruct.Uuid)
+		}
+		
+		if next == "" {
+			assert.Equal(t, 2, page)
+			break
+		}
+		cursor = next
+	}
+	assert.Equal(t, []string{"u6", "u5", "u4", "u3", "u2", "u1", "u0"}, uuids)
+	
+	_, err = ParseInstructState("done")
+	assert.ErrorIs(t, err, ErrInvalidInstructQuery)
+	
+	_, _, err = instructUseCase.QueryInstructs(ctx, InstructQuery{StartTime: 10, EndTime: 5}, "")
+	assert.ErrorIs(t, err, ErrInvalidInstructQuery)
+}
+
+// 等待指令结束: 收到事件时立即返回结果，事件丢失时读取指令记录兜底，超时返回执行中的指令
+
+func TestInstructUseCase_IssueAndWait(t *testing.T) {
+	ctx := context.Background()
+	instanceRepo := newFakeInstanceRepo(Instance{Uuid: "i1", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "n1", Online: true})
+	instructRepo := &fakeInstructRepo{instruct: make(map[string]Instruct)}
+	resultRepo := newFakeResultRepo()
+	eventRepo := &fakeEventRepo{}
+	eventUseCase := NewEventUseCase(eventRepo, instructRepo, resultRepo, &WebhookPolicy{}, zap.NewNop())
+	eventRepo.handler = eventUseCase.dispatch
+	clusterUseCase := NewClusterUseCase(newFakeClusterRepo(), newFakePresenceRepo(), instanceRepo, NewConnRegistry(), &Node{Id: "node1"}, &ClusterPolicy{}, zap.NewNop())
+	instructUseCase := NewInstructUseCase(instructRepo, nil, newFakeOrgRepo(), nil, clusterUseCase, eventUseCase, zap.NewNop())
+	
+	// 模拟其他节点收到 soldier 的结果，publish 为 false 时不发布事件
+	complete := func(publish bool) {
+		for {
+			instructRepo.mu.Lock()
+			var uuid string
+			for _, instruct := range instructRepo.instruct {
+				if instruct.Result == 0 {
+					uuid = instruct.Uuid
+				}
+			}
+			instructRepo.mu.Unlock()
+			
+			if uuid != "" {
+				resultRepo.SaveResult(ctx, InstructResult{InstructUuid: uuid, Type: HttpInstruct, Result: 1, StatusCode: 200})
+				instructRepo.UpdateInstruct(ctx, uuid, "ok", 1)
+				if publish {
+					eventUseCase.PublishUpdate(ctx, EventCompleted, uuid, nil)
+				}
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	
+	go complete(true)
+	instruct, probe, err := instructUseCase.IssueAndWait(ctx, "o1", "g1", "n1", HttpInstruct, "https://example.com", "alice", 5*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), instruct.Result)
+	assert.Equal(t, "ok", instruct.Reply)
+	assert.Equal(t, int32(200), probe.StatusCode)
+	
+	go complete(false)
+	instruct, probe, err = instructUseCase.IssueAndWait(ctx, "o1", "g1", "n1", HttpInstruct, "https://example.com", "alice", 5*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), instruct.Result)
+	assert.NotNil(t, probe)
+	
+	instruct, probe, err = instructUseCase.IssueAndWait(ctx, "o1", "g1", "n1", CommandInstruct, "uptime", "alice", 100*time.Millisecond)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, instruct.Uuid)
+	assert.Equal(t, int32(0), instruct.Result)
+	assert.Nil(t, probe)
+	
+	_, _, err = instructUseCase.IssueAndWait(ctx, "o1", "g1", "n1", CommandInstruct, "uptime", "alice", MaxInstructWait+time.Second)
+	assert.ErrorIs(t, err, ErrInvalidInstructWait)
+	
+	// 调用方断开时返回已下发指令的 uuid
+	waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	instruct, _, err = instructUseCase.IssueAndWait(waitCtx, "o1", "g1", "n1", CommandInstruct, "uptime", "alice", 5*time.Second)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotEmpty(t, instruct.Uuid)
+	_, err = instructRepo.FindInstruct(ctx, instruct.Uuid)
+	assert.NoError(t, err)
+}
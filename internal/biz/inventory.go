@@ -0,0 +1,169 @@
+package biz
+
+import (
+	"context"
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/shirou/gopsutil/v4/host"
+	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/shirou/gopsutil/v4/net"
+	"go.uber.org/zap"
+	"time"
+)
+
+// 主机信息，soldier 连接时及定时上报，commander 持久化到 Instance 中
+
+type HostInventory struct {
+	Hostname        string         `json:"hostname,omitempty"`
+	Os              string         `json:"os,omitempty"`
+	Platform        string         `json:"platform,omitempty"`
+	PlatformVersion string         `json:"platformVersion,omitempty"`
+	KernelVersion   string         `json:"kernelVersion,omitempty"`
+	Arch            string         `json:"arch,omitempty"`
+	CpuModel        string         `json:"cpuModel,omitempty"`
+	CpuCount        int32          `json:"cpuCount,omitempty"`    // 逻辑核数
+	MemoryTotal     uint64         `json:"memoryTotal,omitempty"` // 字节
+	Disks           []DiskInfo     `json:"disks,omitempty"`
+	Interfaces      []NetInterface `json:"interfaces,omitempty"`
+	BootTime        int64          `json:"bootTime,omitempty"`
+	Timezone        string         `json:"timezone,omitempty"`
+	CollectTime     int64          `json:"collectTime,omitempty"`
+}
+
+type DiskInfo struct {
+	Device     string `json:"device,omitempty"`
+	Mountpoint string `json:"mountpoint,omitempty"`
+	Fstype     string `json:"fstype,omitempty"`
+	Total      uint64 `json:"total,omitempty"`
+	Used       uint64 `json:"used,omitempty"`
+}
+
+type NetInterface struct {
+	Name         string   `json:"name,omitempty"`
+	HardwareAddr string   `json:"hardwareAddr,omitempty"`
+	Addrs        []string `json:"addrs,omitempty"`
+	Mtu          int32    `json:"mtu,omitempty"`
+}
+
+const inventoryReportInterval = 10 * time.Minute
+
+type InventoryClientUseCase struct {
+	logger *zap.Logger
+}
+
+func NewInventoryClientUseCase(logger *zap.Logger) *InventoryClientUseCase {
+	return &InventoryClientUseCase{
+		logger: logger,
+	}
+}
+
+// 采集主机信息，单项采集失败时记录日志并继续
+
+func (inventoryClientUseCase *InventoryClientUseCase) Collect(ctx context.Context) HostInventory {
+	inventory := HostInventory{
+		Timezone:    time.Now().Format("MST -07:00"),
+		CollectTime: time.Now().Unix(),
+	}
+	
+	hostInfo, err := host.InfoWithContext(ctx)
+	if err != nil {
+		inventoryClientUseCase.logger.Warn("获取主机信息失败", zap.Error(err))
+	} else {
+		inventory.Hostname = hostInfo.Hostname
+		inventory.Os = hostInfo.OS
+		inventory.Platform = hostInfo.Platform
+		inventory.PlatformVersion = hostInfo.PlatformVersion
+		inventory.KernelVersion = hostInfo.KernelVersion
+		inventory.Arch = hostInfo.KernelArch
+		inventory.BootTime = int64(hostInfo.BootTime)
+	}
+	
+	cpuInfos, err := cpu.InfoWithContext(ctx)
+	if err != nil {
+		inventoryClientUseCase.logger.Warn("获取CPU信息失败", zap.Error(err))
+	} else if len(cpuInfos) > 0 {
+		inventory.CpuModel = cpuInfos[0].ModelName
+	}
+	
+	cpuCount, err := cpu.CountsWithContext(ctx, true)
+	if err != nil {
+		inventoryClientUseCase.logger.Warn("获取CPU核数失败", zap.Error(err))
+	} else {
+		inventory.CpuCount = int32(cpuCount)
+	}
+	
+	memory, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		inventoryClientUseCase.logger.Warn("获取内存信息失败", zap.Error(err))
+	} else {
+		inventory.MemoryTotal = memory.Total
+	}
+	
+	partitions, err := disk.PartitionsWithContext(ctx, false)
+	if err != nil {
+		inventoryClientUseCase.logger.Warn("获取磁盘分区失败", zap.Error(err))
+	}
+	for _, partition := range partitions {
+		diskInfo := DiskInfo{
+			Device:     partition.Device,
+			Mountpoint: partition.Mountpoint,
+			Fstype:     partition.Fstype,
+		}
+		
+		usage, err := disk.UsageWithContext(ctx, partition.Mountpoint)
+		if err == nil {
+			diskInfo.Total = usage.Total
+			diskInfo.Used = usage.Used
+		}
+		
+		inventory.Disks = append(inventory.Disks, diskInfo)
+	}
+	
+	interfaces, err := net.InterfacesWithContext(ctx)
+	if err != nil {
+		inventoryClientUseCase.logger.Warn("获取网卡信息失败", zap.Error(err))
+	}
+	for _, iface := range interfaces {
+		netInterface := NetInterface{
+			Name:         iface.Name,
+			HardwareAddr: iface.HardwareAddr,
+			Mtu:          int32(iface.MTU),
+		}
+		
+		for _, addr := range iface.Addrs {
+			netInterface.Addrs = append(netInterface.Addrs, addr.Addr)
+		}
+		
+		inventory.Interfaces = append(inventory.Interfaces, netInterface)
+	}
+	
+	return inventory
+}
+
+// 生成主机信息消息
+
+func (webSocketUseCase *WebSocketUseCase) Inventory(ctx context.Context) ClientMessage {
+	inventory := webSocketUseCase.inventoryClientUseCase.Collect(ctx)
+	return ClientMessage{
+		Type:      ClientHostInventory,
+		Inventory: &inventory,
+	}
+}
+
+// 定时上报主机信息，连接及重连时的上报由 NewWebSocket 完成
+
+func (webSocketUseCase *WebSocketUseCase) ReportInventory(ctx context.Context, sendMsg chan ClientMessage) {
+	ticker := time.NewTicker(inventoryReportInterval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ticker.C:
+			sendMsg <- webSocketUseCase.Inventory(ctx)
+		
+		case <-ctx.Done():
+			webSocketUseCase.logger.Info("关闭定时上报主机信息")
+			return
+		}
+	}
+}
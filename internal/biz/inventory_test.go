@@ -0,0 +1,19 @@
+package biz
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestInventoryClientUseCase_Collect(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	inventoryClientUseCase := NewInventoryClientUseCase(logger)
+	
+	inventory := inventoryClientUseCase.Collect(context.Background())
+	assert.NotEmpty(t, inventory.Hostname, "获取主机名失败")
+	assert.NotZero(t, inventory.CpuCount, "获取CPU核数失败")
+	
+	logger.Info("inventory", zap.Any("inventory", inventory))
+}
@@ -0,0 +1,264 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 实例标签
+//
+// soldier 通过 -labels 参数 (k1=v1,k2=v2) 在连接时上报标签，每次连接替换之前上报的标签；
+// 运维人员通过接口修改的标签优先，soldier 上报同名标签时不覆盖
+// 搜索实例时通过标签选择器过滤，语法与 kubernetes 一致: k=v, k!=v, k in (v1,v2), k notin (v1,v2), k, !k，多个条件以逗号分隔
+
+const (
+	LabelSourceAgent    = "agent"    // soldier 上报
+	LabelSourceOperator = "operator" // 运维人员设置
+	
+	MaxLabels = 32
+)
+
+const (
+	SelectorEquals       = "="
+	SelectorNotEquals    = "!="
+	SelectorIn           = "in"
+	SelectorNotIn        = "notin"
+	SelectorExists       = "exists"
+	SelectorDoesNotExist = "!"
+)
+
+var (
+	ErrInvalidLabel    = errors.New("标签格式异常")
+	ErrInvalidSelector = errors.New("标签选择器格式异常")
+	
+	labelKeyPattern   = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]{0,61}[A-Za-z0-9])?$`)
+	labelValuePattern = regexp.MustCompile(`^([A-Za-z0-9]([A-Za-z0-9._-]{0,61}[A-Za-z0-9])?)?$`)
+)
+
+type Label struct {
+	InstanceUuid string `json:"instanceUuid" gorm:"primaryKey"`
+	Key          string `json:"key" gorm:"column:label_key;primaryKey"`
+	Value        string `json:"value" gorm:"column:label_value"`
+	Source       string `json:"source"`
+}
+
+func (label *Label) TableName() string {
+	return "instance_label"
+}
+
+type LabelRepo interface {
+	SetAgentLabels(ctx context.Context, instanceUuid string, labels map[string]string) error
+	UpdateLabels(ctx context.Context, instanceUuid string, set map[string]string, remove []string) error
+	ListLabels(ctx context.Context, instanceUuids []string) (map[string]map[string]string, error)
+	DeleteLabels(ctx context.Context, instanceUuid string) error
+}
+
+type LabelRequirement struct {
+	Key      string
+	Operator string
+	Values   []string
+}
+
+func ValidateLabel(key, value string) error {
+	if !labelKeyPattern.MatchString(key) {
+		return fmt.Errorf("%w: %q", ErrInvalidLabel, key)
+	}
+	
+	if !labelValuePattern.MatchString(value) {
+		return fmt.Errorf("%w: %s=%q", ErrInvalidLabel, key, value)
+	}
+	
+	return nil
+}
+
+// 解析 soldier 上报的标签 k1=v1,k2=v2
+
+func ParseLabels(s string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidLabel, pair)
+		}
+		
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		err := ValidateLabel(key, value)
+		if err != nil {
+			return nil, err
+		}
+		labels[key] = value
+	}
+	
+	if len(labels) > MaxLabels {
+		return nil, fmt.Errorf("%w: 超过 %d 个", ErrInvalidLabel, MaxLabels)
+	}
+	
+	return labels, nil
+}
+
+// 解析标签选择器
+
+func ParseSelector(s string) ([]LabelRequirement, error) {
+	var requirements []LabelRequirement
+	for _, term := range splitSelector(s) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		
+		requirement, err := parseRequirement(term)
+		if err != nil {
+			return nil, err
+		}
+		requirements = append(requirements, requirement)
+	}
+	
+	return requirements, nil
+}
+
+// 按逗号分隔，忽略括号内的逗号
+
+func splitSelector(s string) []string {
+	var terms []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	
+	return append(terms, s[start:])
+}
+
+func parseRequirement(term string) (LabelRequirement, error) {
+	invalid := fmt.Errorf("%w: %q", ErrInvalidSelector, term)
+	
+	if key, ok := strings.CutPrefix(term, "!"); ok {
+		key = strings.TrimSpace(key)
+		if !labelKeyPattern.MatchString(key) {
+			return LabelRequirement{}, invalid
+		}
+		return LabelRequirement{Key: key, Operator: SelectorDoesNotExist}, nil
+	}
+	
+	for _, operator := range []string{"!=", "==", "="} {
+		key, value, ok := strings.Cut(term, operator)
+		if !ok {
+			continue
+		}
+		
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if ValidateLabel(key, value) != nil {
+			return LabelRequirement{}, invalid
+		}
+		
+		if operator == "!=" {
+			return LabelRequirement{Key: key, Operator: SelectorNotEquals, Values: []string{value}}, nil
+		}
+		return LabelRequirement{Key: key, Operator: SelectorEquals, Values: []string{value}}, nil
+	}
+	
+	// k in (v1,v2) / k notin (v1,v2)
+	fields := strings.Fields(strings.Replace(term, "(", " (", 1))
+	if len(fields) >= 3 && (fields[1] == SelectorIn || fields[1] == SelectorNotIn) {
+		set := strings.Join(fields[2:], "")
+		if !strings.HasPrefix(set, "(") || !strings.HasSuffix(set, ")") {
+			return LabelRequirement{}, invalid
+		}
+		
+		requirement := LabelRequirement{Key: fields[0], Operator: fields[1]}
+		for _, value := range strings.Split(set[1:len(set)-1], ",") {
+			if ValidateLabel(requirement.Key, value) != nil {
+				return LabelRequirement{}, invalid
+			}
+			requirement.Values = append(requirement.Values, value)
+		}
+		sort.Strings(requirement.Values)
+		return requirement, nil
+	}
+	
+	if len(fields) == 1 && labelKeyPattern.MatchString(fields[0]) {
+		return LabelRequirement{Key: fields[0], Operator: SelectorExists}, nil
+	}
+	
+	return LabelRequirement{}, invalid
+}
+
+// 记录 soldier 连接时上报的标签
+
+func (instanceUseCase *InstanceUseCase) SetAgentLabels(ctx context.Context, instanceUuid string, labels map[string]string) error {
+	return instanceUseCase.labelRepo.SetAgentLabels(ctx, instanceUuid, labels)
+}
+
+// 运维人员设置及删除标签
+
+func (instanceUseCase *InstanceUseCase) UpdateLabels(ctx context.Context, orgUuid, groupUuid, instanceName string, set map[string]string, remove []string) (map[string]string, error) {
+	for key, value := range set {
+		err := ValidateLabel(key, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	
+	if len(set) > MaxLabels {
+		return nil, fmt.Errorf("%w: 超过 %d 个", ErrInvalidLabel, MaxLabels)
+	}
+	
+	instance, err := instanceUseCase.instanceRepo.Get(ctx, orgUuid, groupUuid, instanceName)
+	if err != nil {
+		return nil, err
+	}
+	
+	err = instanceUseCase.labelRepo.UpdateLabels(ctx, instance.Uuid, set, remove)
+	if err != nil {
+		return nil, err
+	}
+	
+	labels, err := instanceUseCase.labelRepo.ListLabels(ctx, []string{instance.Uuid})
+	if err != nil {
+		return nil, err
+	}
+	
+	return labels[instance.Uuid], nil
+}
+
+// 填充实例的标签
+
+func (instanceUseCase *InstanceUseCase) fillLabels(ctx context.Context, instances []Instance) error {
+	if len(instances) == 0 {
+		return nil
+	}
+	
+	uuids := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		uuids = append(uuids, instance.Uuid)
+	}
+	
+	labels, err := instanceUseCase.labelRepo.ListLabels(ctx, uuids)
+	if err != nil {
+		return err
+	}
+	
+	for i := range instances {
+		instances[i].Labels = labels[instances[i].Uuid]
+	}
+	
+	return nil
+}
@@ -0,0 +1,44 @@
+package biz
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseLabels(t *testing.T) {
+	labels, err := ParseLabels(" env=prod, role=web,empty=,example.com/team=ops ")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"env": "prod", "role": "web", "empty": "", "example.com/team": "ops"}, labels)
+	
+	labels, err = ParseLabels("")
+	assert.NoError(t, err)
+	assert.Empty(t, labels)
+	
+	for _, s := range []string{"env", "=prod", "env=pr od", "-env=prod", "env=prod!"} {
+		_, err = ParseLabels(s)
+		assert.ErrorIs(t, err, ErrInvalidLabel, s)
+	}
+}
+
+func TestParseSelector(t *testing.T) {
+	requirements, err := ParseSelector("env=prod, tier != db,role==web,region in (sh, bj),zone notin (a),gpu,!deprecated")
+	assert.NoError(t, err)
+	assert.Equal(t, []LabelRequirement{
+		{Key: "env", Operator: SelectorEquals, Values: []string{"prod"}},
+		{Key: "tier", Operator: SelectorNotEquals, Values: []string{"db"}},
+		{Key: "role", Operator: SelectorEquals, Values: []string{"web"}},
+		{Key: "region", Operator: SelectorIn, Values: []string{"bj", "sh"}},
+		{Key: "zone", Operator: SelectorNotIn, Values: []string{"a"}},
+		{Key: "gpu", Operator: SelectorExists},
+		{Key: "deprecated", Operator: SelectorDoesNotExist},
+	}, requirements)
+	
+	requirements, err = ParseSelector("")
+	assert.NoError(t, err)
+	assert.Empty(t, requirements)
+	
+	for _, s := range []string{"env in sh", "env in (sh", "a b", "env=prod;drop", "!", "in (a)"} {
+		_, err = ParseSelector(s)
+		assert.ErrorIs(t, err, ErrInvalidSelector, s)
+	}
+}
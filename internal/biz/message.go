@@ -5,6 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/gorilla/websocket"
+	"github.com/qx66/camp/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"strings"
 	"time"
@@ -14,15 +18,37 @@ const (
 	ClientHelloEcho          ClientMessageType = 1
 	ClientInstructReply      ClientMessageType = 2
 	ClientChromeDpScreenShot ClientMessageType = 3
+	ClientHello              ClientMessageType = 4
+	ClientHostInventory      ClientMessageType = 5
+	ClientHostMetrics        ClientMessageType = 6
+	ClientFileChunk          ClientMessageType = 7
+	ClientShell              ClientMessageType = 8
+	ClientTunnel             ClientMessageType = 9
+	ClientChannelWindow      ClientMessageType = 10
+	ClientFragment           ClientMessageType = 11
 	
-	ServiceHelloEcho ServiceMessageType = 1
-	ServiceInstruct  ServiceMessageType = 2
+	ServiceHelloEcho     ServiceMessageType = 1
+	ServiceInstruct      ServiceMessageType = 2
+	ServiceFileChunk     ServiceMessageType = 3
+	ServiceShell         ServiceMessageType = 4
+	ServiceTunnel        ServiceMessageType = 5
+	ServiceChannelWindow ServiceMessageType = 6
+	ServiceFragment      ServiceMessageType = 7
+	ServiceAck           ServiceMessageType = 8
+	ServiceCancel        ServiceMessageType = 9
 )
 
 type MessageUseCase struct {
-	instructRepo InstructRepo
-	instanceRepo InstanceRepo
-	logger       *zap.Logger
+	instructRepo   InstructRepo
+	instanceRepo   InstanceRepo
+	metricsRepo    MetricsRepo
+	blobRepo       BlobRepo
+	resultRepo     ResultRepo
+	shellUseCase   *ShellUseCase
+	tunnelUseCase  *TunnelUseCase
+	sessionUseCase *SessionUseCase
+	eventUseCase   *EventUseCase
+	logger         *zap.Logger
 }
 
 type ClientMessageType int32
@@ -31,6 +57,16 @@ type ClientMessage struct {
 	Message            string            `json:"message"`
 	InstructMessage    InstructMessage   `json:"instructMessage,omitempty"`
 	ChromeDpScreenShot []byte            `json:"chromeDpScreenShot,omitempty"`
+	Trace              map[string]string `json:"trace,omitempty"` // trace 上下文 (W3C traceparent)
+	Hello              *AgentHello       `json:"hello,omitempty"`
+	Inventory          *HostInventory    `json:"inventory,omitempty"`
+	Metrics            *HostMetrics      `json:"metrics,omitempty"`
+	FileChunk          *FileChunk        `json:"fileChunk,omitempty"`
+	Shell              *ShellFrame       `json:"shell,omitempty"`
+	Tunnel             *TunnelFrame      `json:"tunnel,omitempty"`
+	ChannelWindow      *ChannelWindow    `json:"channelWindow,omitempty"`
+	Fragment           *Fragment         `json:"fragment,omitempty"`
+	Truncated          bool              `json:"truncated,omitempty"` // 超过消息大小上限，结果或截图已被截断
 }
 
 type ServiceMessageType int32
@@ -38,13 +74,54 @@ type ServiceMessage struct {
 	Type            ServiceMessageType `json:"type,omitempty"`
 	Message         string             `json:"message"`
 	InstructMessage InstructMessage    `json:"instructMessage,omitempty"`
+	Trace           map[string]string  `json:"trace,omitempty"` // trace 上下文 (W3C traceparent)
+	FileChunk       *FileChunk         `json:"fileChunk,omitempty"`
+	Shell           *ShellFrame        `json:"shell,omitempty"`
+	Tunnel          *TunnelFrame       `json:"tunnel,omitempty"`
+	ChannelWindow   *ChannelWindow     `json:"channelWindow,omitempty"`
+	Fragment        *Fragment          `json:"fragment,omitempty"`
+	Ack             string             `json:"ack,omitempty"`    // 已处理的指令结果 uuid
+	Cancel          string             `json:"cancel,omitempty"` // 取消的指令 uuid
 }
 
-func NewMessageUseCase(logger *zap.Logger, instructRepo InstructRepo, instanceRepo InstanceRepo) *MessageUseCase {
+// 日志中记录的消息摘要，仅包含类型、指令 uuid、会话 id 及负载长度，不记录终端输入、端口转发及文件分片的内容
+
+func (msg *ServiceMessage) LogFields() []zap.Field {
+	uuid := msg.InstructMessage.Uuid
+	sessionId := ""
+	size := 0
+	switch {
+	case msg.Shell != nil:
+		sessionId = msg.Shell.SessionId
+		size = len(msg.Shell.Data)
+	case msg.Tunnel != nil:
+		sessionId = msg.Tunnel.StreamId
+		size = len(msg.Tunnel.Data)
+	case msg.FileChunk != nil:
+		uuid = msg.FileChunk.Uuid
+		size = len(msg.FileChunk.Data)
+	}
+	
+	return []zap.Field{
+		zap.Int32("type", int32(msg.Type)),
+		zap.String("uuid", uuid),
+		zap.String("sessionId", sessionId),
+		zap.Int("size", size),
+	}
+}
+
+func NewMessageUseCase(logger *zap.Logger, instructRepo InstructRepo, instanceRepo InstanceRepo, metricsRepo MetricsRepo, blobRepo BlobRepo, resultRepo ResultRepo, shellUseCase *ShellUseCase, tunnelUseCase *TunnelUseCase, sessionUseCase *SessionUseCase, eventUseCase *EventUseCase) *MessageUseCase {
 	return &MessageUseCase{
-		instructRepo: instructRepo,
-		instanceRepo: instanceRepo,
-		logger:       logger,
+		instructRepo:   instructRepo,
+		instanceRepo:   instanceRepo,
+		metricsRepo:    metricsRepo,
+		blobRepo:       blobRepo,
+		resultRepo:     resultRepo,
+		shellUseCase:   shellUseCase,
+		tunnelUseCase:  tunnelUseCase,
+		sessionUseCase: sessionUseCase,
+		eventUseCase:   eventUseCase,
+		logger:         logger,
 	}
 }
 
@@ -78,52 +155,67 @@ func (messageUseCase *MessageUseCase) HeartBeat(ctx context.Context, sendMsgChan
 	
 }
 
-// 接收socket消息，并传入 receiveMsgChannel 通道中
+// 接收socket消息，重组分片后按逻辑通道传入 receiveChannels 中，通道窗口消息直接归还到 serviceChannels
+// 每收到一条消息延长 readTimeout 读超时，超时未收到消息 (含 pong) 时视为连接已断开
 
-func (messageUseCase *MessageUseCase) ReceiveMessage(ctx context.Context, conn *websocket.Conn, receiveMsgChannel chan string, done chan struct{}) {
+func (messageUseCase *MessageUseCase) ReceiveMessage(ctx context.Context, conn *websocket.Conn, frameReader *FrameReader, readTimeout time.Duration, receiveChannels *ClientChannels, serviceChannels *ServiceChannels, done chan<- error) {
 	for {
 		// ReadMessage
-		_, message, err := conn.ReadMessage()
+		messageType, message, err := conn.ReadMessage()
 		
-		// 接收消息失败
+		// 接收消息失败，将错误发送到 done (缓冲为 1)
 		if err != nil {
 			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
 				messageUseCase.logger.Error("websocket已经关闭", zap.Error(err))
-				close(done)
+				done <- err
 				return
 			} else {
 				messageUseCase.logger.Error("websocket错误", zap.Error(err))
-				close(done)
+				done <- err
 				return
 			}
 		}
 		
-		//
-		clientMsg := &ClientMessage{}
+		ExtendReadDeadline(conn, readTimeout)
 		
-		err = json.Unmarshal(message, clientMsg)
+		// 按握手时协商的子协议解码
+		clientMsg, complete, err := frameReader.DecodeClientMessage(conn.Subprotocol(), messageType, message)
 		if err != nil {
-			messageUseCase.logger.Error("反序列化客户端消息失败", zap.Error(err), zap.String("message", string(message)))
-		} else {
-			receiveMsgChannel <- string(message)
+			messageUseCase.logger.Error("反序列化客户端消息失败", zap.Error(err), zap.String("subprotocol", conn.Subprotocol()))
+			continue
+		}
+		
+		// 分片未接收完整
+		if !complete {
+			continue
+		}
+		
+		if clientMsg.Type == ClientChannelWindow {
+			if clientMsg.ChannelWindow != nil {
+				serviceChannels.Release(*clientMsg.ChannelWindow)
+			}
+			continue
+		}
+		
+		select {
+		case receiveChannels.Queue(clientMsg.Channel()) <- clientMsg:
+		case <-ctx.Done():
+			return
 		}
 	}
 }
 
-// 从 receiveMsgChannel 中获取消息并解析，然后执行对应的行为
+// 从 receiveMsgChannel 中获取消息并解析，然后执行对应的行为，每个逻辑通道由独立的协程处理，处理完成后归还通道窗口
 
-func (messageUseCase *MessageUseCase) ProcessClientMessage(ctx context.Context, receiveMsgChannel chan string) {
+func (messageUseCase *MessageUseCase) ProcessClientMessage(ctx context.Context, session *Session, channel Channel, receiveMsgChannel chan ClientMessage, serviceChannels *ServiceChannels) {
+	instanceUuid := session.InstanceUuid
+	
+	// 已确认属于该实例的文件获取指令，分片与指令结果均在批量通道中按序处理
+	fileOwners := make(map[string]bool)
 	
 	for {
 		select {
-		case m := <-receiveMsgChannel:
-			
-			var clientMsg ClientMessage
-			err := json.Unmarshal([]byte(m), &clientMsg)
-			if err != nil {
-				messageUseCase.logger.Error("反序列化客户端消息失败", zap.Error(err))
-				continue
-			}
+		case clientMsg := <-receiveMsgChannel:
 			
 			//
 			switch clientMsg.Type {
@@ -133,111 +225,39 @@ func (messageUseCase *MessageUseCase) ProcessClientMessage(ctx context.Context,
 				)
 			
 			case ClientInstructReply:
-				switch clientMsg.InstructMessage.Type {
-				case CommandInstruct:
-					var result int32
-					var reply string
-					if clientMsg.InstructMessage.Result {
-						result = 1
-						reply = clientMsg.InstructMessage.CommandReply
-					} else {
-						result = -1
-						reply = clientMsg.InstructMessage.ErrMsg
-					}
-					
-					err = messageUseCase.instructRepo.UpdateInstruct(ctx, clientMsg.InstructMessage.Uuid, reply, result)
-					if err != nil {
-						messageUseCase.logger.Error("更新指令结果失败",
-							zap.String("uuid", clientMsg.InstructMessage.Uuid),
-							zap.Error(err))
-					}
-				
-				case ChromeDpInspectInstruct:
-					var result int32
-					var reply string
-					if clientMsg.InstructMessage.Result {
-						result = 1
-						reply = clientMsg.InstructMessage.ChromeDpInspectReply.String()
-					} else {
-						result = -1
-						reply = clientMsg.InstructMessage.ErrMsg
-					}
-					
-					err = messageUseCase.instructRepo.UpdateInstruct(ctx, clientMsg.InstructMessage.Uuid, reply, result)
-					if err != nil {
-						messageUseCase.logger.Error("更新指令结果失败",
-							zap.String("uuid", clientMsg.InstructMessage.Uuid),
-							zap.Error(err))
-					}
-				
-				case DnsInstruct:
-					var result int32
-					var reply string
-					if clientMsg.InstructMessage.Result {
-						result = 1
-						reply = strings.Join(clientMsg.InstructMessage.DnsInspectReply, ",")
-					} else {
-						result = -1
-						reply = clientMsg.InstructMessage.ErrMsg
-					}
-					
-					err = messageUseCase.instructRepo.UpdateInstruct(ctx, clientMsg.InstructMessage.Uuid, reply, result)
-					if err != nil {
-						messageUseCase.logger.Error("更新指令结果失败",
-							zap.String("uuid", clientMsg.InstructMessage.Uuid),
-							zap.Error(err))
-					}
-				
-				case HttpInstruct:
-					var result int32
-					var reply string
-					if clientMsg.InstructMessage.Result {
-						result = 1
-						reply = clientMsg.InstructMessage.HttpInspectReply.String()
-					} else {
-						result = -1
-						reply = clientMsg.InstructMessage.ErrMsg
-					}
-					
-					err = messageUseCase.instructRepo.UpdateInstruct(ctx, clientMsg.InstructMessage.Uuid, reply, result)
-					if err != nil {
-						messageUseCase.logger.Error("更新指令结果失败",
-							zap.String("uuid", clientMsg.InstructMessage.Uuid),
-							zap.Error(err))
-					}
-				
-				case IcmpInstruct:
-					var result int32
-					var reply string
-					if clientMsg.InstructMessage.Result {
-						replyByte, err := json.Marshal(clientMsg.InstructMessage.IcmpInspectReply)
-						if err != nil {
-							reply = err.Error()
-						} else {
-							reply = string(replyByte)
-						}
-						result = 1
-						//reply = clientMsg.InstructMessage.IcmpInspectReply
-					} else {
-						result = -1
-						reply = clientMsg.InstructMessage.ErrMsg
-					}
-					
-					err = messageUseCase.instructRepo.UpdateInstruct(ctx, clientMsg.InstructMessage.Uuid, reply, result)
-					if err != nil {
-						messageUseCase.logger.Error("更新指令结果失败",
-							zap.String("uuid", clientMsg.InstructMessage.Uuid),
-							zap.Error(err))
-					}
-				
-				default:
-					messageUseCase.logger.Error("未知的指令类型")
+				// 结果已保存或指令已结束时确认以便 soldier 释放缓存，未能保存的结果不确认，soldier 重连后重新发送
+				delete(fileOwners, clientMsg.InstructMessage.Uuid)
+				if messageUseCase.processInstructReply(ctx, session, clientMsg) {
+					messageUseCase.sessionUseCase.Complete(ctx, session, clientMsg.InstructMessage.Uuid)
+					serviceChannels.Send(ServiceMessage{Type: ServiceAck, Ack: clientMsg.InstructMessage.Uuid}, ctx.Done())
 				}
 				
 				//messageUseCase.logger.Info("接收到Client指令响应消息",
 				//	zap.String("message", clientMsg.InstructMessage.Reply),
 				//)
 			
+			case ClientHello:
+				messageUseCase.processHello(ctx, instanceUuid, clientMsg)
+			
+			case ClientHostInventory:
+				messageUseCase.processInventory(ctx, instanceUuid, clientMsg)
+			
+			case ClientHostMetrics:
+				messageUseCase.processMetrics(ctx, instanceUuid, clientMsg)
+			
+			case ClientFileChunk:
+				messageUseCase.processFileChunk(ctx, session, clientMsg, fileOwners)
+			
+			case ClientShell:
+				if clientMsg.Shell != nil {
+					messageUseCase.shellUseCase.Deliver(*clientMsg.Shell)
+				}
+			
+			case ClientTunnel:
+				if clientMsg.Tunnel != nil {
+					messageUseCase.tunnelUseCase.Deliver(*clientMsg.Tunnel)
+				}
+			
 			case ClientChromeDpScreenShot:
 				messageUseCase.logger.Info("接收到Client ChromeDp截图消息",
 					zap.String("message", string(clientMsg.ChromeDpScreenShot)),
@@ -248,9 +268,297 @@ func (messageUseCase *MessageUseCase) ProcessClientMessage(ctx context.Context,
 					zap.Any("Type", clientMsg.Type),
 				)
 			}
+			
+			serviceChannels.Ack(channel, ctx.Done())
 		
 		case <-ctx.Done():
 			return
 		}
 	}
 }
+
+// 保存 soldier 上报的版本及能力信息
+
+func (messageUseCase *MessageUseCase) processHello(ctx context.Context, instanceUuid string, clientMsg ClientMessage) {
+	if clientMsg.Hello == nil {
+		messageUseCase.logger.Warn("Client Hello消息缺少版本信息", zap.String("instanceUuid", instanceUuid))
+		return
+	}
+	
+	messageUseCase.logger.Info("接收到Client版本及能力信息",
+		zap.String("instanceUuid", instanceUuid),
+		zap.String("version", clientMsg.Hello.Version),
+		zap.Any("capabilities", clientMsg.Hello.Capabilities),
+	)
+	
+	err := messageUseCase.instanceRepo.UpdateAgentInfo(ctx, instanceUuid, *clientMsg.Hello)
+	if err != nil {
+		messageUseCase.logger.Error("更新实例版本信息失败", zap.String("instanceUuid", instanceUuid), zap.Error(err))
+	}
+}
+
+// 保存 soldier 上报的主机信息
+
+func (messageUseCase *MessageUseCase) processInventory(ctx context.Context, instanceUuid string, clientMsg ClientMessage) {
+	if clientMsg.Inventory == nil {
+		messageUseCase.logger.Warn("Client主机信息消息内容为空", zap.String("instanceUuid", instanceUuid))
+		return
+	}
+	
+	err := messageUseCase.instanceRepo.UpdateInventory(ctx, instanceUuid, *clientMsg.Inventory)
+	if err != nil {
+		messageUseCase.logger.Error("更新实例主机信息失败", zap.String("instanceUuid", instanceUuid), zap.Error(err))
+	}
+}
+
+// 保存 soldier 上报的监控数据，仅保留最近一段时间
+
+func (messageUseCase *MessageUseCase) processMetrics(ctx context.Context, instanceUuid string, clientMsg ClientMessage) {
+	if clientMsg.Metrics == nil {
+		return
+	}
+	
+	err := messageUseCase.metricsRepo.PushMetrics(ctx, instanceUuid, *clientMsg.Metrics, metricsWindowSize, metricsWindow)
+	if err != nil {
+		messageUseCase.logger.Error("保存实例监控数据失败", zap.String("instanceUuid", instanceUuid), zap.Error(err))
+	}
+}
+
+// 校验指令是否下发给当前连接的实例，soldier 不能更新其他实例的指令
+
+func (messageUseCase *MessageUseCase) ownInstruct(ctx context.Context, session *Session, instructUuid string) (Instruct, bool) {
+	instruct, err := messageUseCase.instructRepo.FindInstruct(ctx, instructUuid)
+	if err != nil {
+		messageUseCase.logger.Warn("获取指令失败",
+			zap.String("instanceUuid", session.InstanceUuid),
+			zap.String("uuid", instructUuid),
+			zap.Error(err))
+		return instruct, false
+	}
+	
+	if !session.Owns(instruct) {
+		messageUseCase.logger.Warn("指令不属于该实例",
+			zap.String("instanceUuid", session.InstanceUuid),
+			zap.String("uuid", instructUuid))
+		return instruct, false
+	}
+	
+	return instruct, true
+}
+
+// 暂存文件获取指令回传的文件分片，fileOwners 缓存已校验的指令
+
+func (messageUseCase *MessageUseCase) processFileChunk(ctx context.Context, session *Session, clientMsg ClientMessage, fileOwners map[string]bool) {
+	if clientMsg.FileChunk == nil {
+		return
+	}
+	
+	if !fileOwners[clientMsg.FileChunk.Uuid] {
+		instruct, ok := messageUseCase.ownInstruct(ctx, session, clientMsg.FileChunk.Uuid)
+		if !ok || instruct.Type != FileGetInstruct || instruct.Result != 0 && !instruct.Interrupted() {
+			return
+		}
+		fileOwners[clientMsg.FileChunk.Uuid] = true
+	}
+	
+	err := messageUseCase.blobRepo.WriteChunk(ctx, clientMsg.FileChunk.Uuid, clientMsg.FileChunk.Offset, clientMsg.FileChunk.Data)
+	if err != nil {
+		messageUseCase.logger.Error("保存文件分片失败",
+			zap.String("instanceUuid", session.InstanceUuid),
+			zap.String("uuid", clientMsg.FileChunk.Uuid),
+			zap.Error(err))
+	}
+}
+
+// 文件获取指令完成后校验已接收的分片，校验失败时丢弃
+
+func (messageUseCase *MessageUseCase) verifyFile(ctx context.Context, instruct InstructMessage) (string, error) {
+	reply := FileReply{Path: instruct.FilePath, Size: instruct.FileSize, Sha256: instruct.FileSha256}
+	
+	err := messageUseCase.blobRepo.Verify(ctx, instruct.Uuid, instruct.FileSize, instruct.FileSha256)
+	if err != nil {
+		messageUseCase.discardFile(ctx, instruct.Uuid)
+		return "", err
+	}
+	
+	return reply.String(), nil
+}
+
+// 指令结果更新后保存文件，指令已结束时丢弃，避免保存不被任何指令引用的文件
+
+func (messageUseCase *MessageUseCase) commitFile(ctx context.Context, instruct InstructMessage) error {
+	_, err := messageUseCase.blobRepo.Commit(ctx, instruct.Uuid, instruct.FileSize, instruct.FileSha256)
+	if err != nil {
+		messageUseCase.discardFile(ctx, instruct.Uuid)
+	}
+	return err
+}
+
+func (messageUseCase *MessageUseCase) discardFile(ctx context.Context, uuid string) {
+	err := messageUseCase.blobRepo.Discard(ctx, uuid)
+	if err != nil {
+		messageUseCase.logger.Error("丢弃文件分片失败", zap.String("uuid", uuid), zap.Error(err))
+	}
+}
+
+// 处理指令执行结果，并将结果持久化
+// 按指令状态处理: 未结束及中断的指令保存结果，已结束 (重复的结果或已取消) 的忽略；返回 false 表示结果未能保存
+
+func (messageUseCase *MessageUseCase) processInstructReply(ctx context.Context, session *Session, clientMsg ClientMessage) bool {
+	ctx = tracing.Extract(ctx, clientMsg.Trace)
+	ctx, span := tracing.Tracer().Start(ctx, "ProcessInstructReply",
+		trace.WithAttributes(
+			attribute.String("instruct.uuid", clientMsg.InstructMessage.Uuid),
+			attribute.Int("instruct.type", int(clientMsg.InstructMessage.Type)),
+			attribute.Bool("instruct.result", clientMsg.InstructMessage.Result),
+		),
+	)
+	defer span.End()
+	
+	instruct, ok := messageUseCase.ownInstruct(ctx, session, clientMsg.InstructMessage.Uuid)
+	if !ok {
+		span.SetStatus(codes.Error, "指令不属于该实例")
+		return false
+	}
+	
+	if instruct.Type != clientMsg.InstructMessage.Type {
+		span.SetStatus(codes.Error, "指令类型不一致")
+		messageUseCase.logger.Warn("指令结果的类型与指令不一致", zap.String("uuid", clientMsg.InstructMessage.Uuid))
+		return false
+	}
+	
+	var result int32 = -1
+	reply := clientMsg.InstructMessage.ErrMsg
+	switch clientMsg.InstructMessage.Type {
+	case CommandInstruct:
+		if clientMsg.InstructMessage.Result {
+			result = 1
+			reply = clientMsg.InstructMessage.CommandReply
+		}
+	
+	case ChromeDpInspectInstruct:
+		if clientMsg.InstructMessage.Result {
+			result = 1
+			reply = clientMsg.InstructMessage.ChromeDpInspectReply.String()
+		}
+	
+	case DnsInstruct:
+		if clientMsg.InstructMessage.Result {
+			result = 1
+			reply = strings.Join(clientMsg.InstructMessage.DnsInspectReply, ",")
+		}
+	
+	case HttpInstruct:
+		if clientMsg.InstructMessage.Result {
+			result = 1
+			reply = clientMsg.InstructMessage.HttpInspectReply.String()
+		}
+	
+	case IcmpInstruct:
+		if clientMsg.InstructMessage.Result {
+			result = 1
+			replyByte, err := json.Marshal(clientMsg.InstructMessage.IcmpInspectReply)
+			if err != nil {
+				reply = err.Error()
+			} else {
+				reply = string(replyByte)
+			}
+		}
+	
+	case FileGetInstruct:
+		if clientMsg.InstructMessage.Result {
+			fileReply, err := messageUseCase.verifyFile(ctx, clientMsg.InstructMessage)
+			if err != nil {
+				reply = err.Error()
+			} else {
+				result = 1
+				reply = fileReply
+			}
+		} else {
+			messageUseCase.discardFile(ctx, clientMsg.InstructMessage.Uuid)
+		}
+	
+	case FilePutInstruct:
+		if clientMsg.InstructMessage.Result {
+			result = 1
+			fileReply := FileReply{
+				Path:   clientMsg.InstructMessage.FilePath,
+				Size:   clientMsg.InstructMessage.FileSize,
+				Sha256: clientMsg.InstructMessage.FileSha256,
+			}
+			reply = fileReply.String()
+		}
+	
+	default:
+		// soldier 不支持该指令类型时回传失败结果
+		if clientMsg.InstructMessage.Result {
+			span.SetStatus(codes.Error, "未知的指令类型")
+			messageUseCase.logger.Error("未知的指令类型")
+			return false
+		}
+	}
+	
+	// soldier 重启或会话过期后发送的离线缓存结果，指令已被标记为中断
+	update := messageUseCase.instructRepo.UpdateInstruct
+	if instruct.Interrupted() {
+		update = messageUseCase.instructRepo.UpdateInterruptedInstruct
+	}
+	
+	updated, err := update(ctx, clientMsg.InstructMessage.Uuid, reply, result)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		messageUseCase.logger.Error("更新指令结果失败",
+			zap.String("uuid", clientMsg.InstructMessage.Uuid),
+			zap.Error(err))
+		return false
+	}
+	
+	// 指令已取消或已有结果，以先更新的为准
+	if updated == 0 {
+		span.SetAttributes(attribute.Bool("instruct.finished", true))
+		messageUseCase.logger.Info("指令已结束，忽略执行结果", zap.String("uuid", clientMsg.InstructMessage.Uuid))
+		if clientMsg.InstructMessage.Type == FileGetInstruct {
+			messageUseCase.discardFile(ctx, clientMsg.InstructMessage.Uuid)
+		}
+		return true
+	}
+	
+	if clientMsg.InstructMessage.Type == FileGetInstruct && result == 1 {
+		err = messageUseCase.commitFile(ctx, clientMsg.InstructMessage)
+		if err != nil {
+			span.RecordError(err)
+			messageUseCase.logger.Error("保存文件失败",
+				zap.String("uuid", clientMsg.InstructMessage.Uuid),
+				zap.Error(err))
+		}
+	}
+	
+	// 探测指令的结果另外结构化保存
+	var probe *InstructResult
+	instructResult, ok := NewInstructResult(session.InstanceUuid, clientMsg.InstructMessage, time.Now().Unix())
+	if ok {
+		probe = &instructResult
+		err = messageUseCase.resultRepo.SaveResult(ctx, instructResult)
+		if err != nil {
+			span.RecordError(err)
+			messageUseCase.logger.Error("保存探测结果失败",
+				zap.String("uuid", clientMsg.InstructMessage.Uuid),
+				zap.Error(err))
+		}
+	}
+	
+	// 结果超过消息大小上限，soldier 已截断
+	if clientMsg.Truncated {
+		span.SetAttributes(attribute.Bool("instruct.truncated", true))
+		err = messageUseCase.instructRepo.MarkInstructTruncated(ctx, clientMsg.InstructMessage.Uuid)
+		if err != nil {
+			messageUseCase.logger.Error("更新指令截断标记失败",
+				zap.String("uuid", clientMsg.InstructMessage.Uuid),
+				zap.Error(err))
+		}
+	}
+	
+	messageUseCase.eventUseCase.PublishUpdate(ctx, EventCompleted, clientMsg.InstructMessage.Uuid, probe)
+	return true
+}
@@ -0,0 +1,214 @@
+package biz
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+func newTestMessageUseCase(instructRepo *fakeInstructRepo, blobRepo *fakeBlobRepo, resultRepo *fakeResultRepo) (*MessageUseCase, *fakeEventRepo) {
+	eventUseCase, eventRepo := newTestEventUseCase(instructRepo, &WebhookPolicy{})
+	messageUseCase := NewMessageUseCase(zap.NewNop(), instructRepo, nil, nil, blobRepo, resultRepo, nil, nil, nil, eventUseCase)
+	return messageUseCase, eventRepo
+}
+
+func httpReply(uuid string) ClientMessage {
+	return ClientMessage{
+		Type: ClientInstructReply,
+		InstructMessage: InstructMessage{
+			Uuid:             uuid,
+			Type:             HttpInstruct,
+			Result:           true,
+			HttpInspectUrl:   "https://example.com",
+			HttpInspectReply: HttpInspectReply{StatusCode: 200},
+		},
+	}
+}
+
+// 只处理下发给当前连接实例的指令结果及文件分片
+
+func TestMessageUseCase_Ownership(t *testing.T) {
+	ctx := context.Background()
+	instructRepo, blobRepo := newFakeRepos()
+	resultRepo := newFakeResultRepo()
+	messageUseCase, eventRepo := newTestMessageUseCase(instructRepo, blobRepo, resultRepo)
+	session := &Session{InstanceUuid: "instance", OrgUuid: "org", GroupUuid: "group", InstanceName: "name"}
+	
+	instructRepo.instruct["other"] = Instruct{Uuid: "other", OrgUuid: "org", GroupUuid: "group", InstanceName: "other", Type: HttpInstruct}
+	instructRepo.instruct["file"] = Instruct{Uuid: "file", OrgUuid: "org", GroupUuid: "group", InstanceName: "other", Type: FileGetInstruct}
+	instructRepo.instruct["own"] = Instruct{Uuid: "own", OrgUuid: "org", GroupUuid: "group", InstanceName: "name", Type: FileGetInstruct}
+	
+	// 未保存的结果不确认
+	assert.False(t, messageUseCase.processInstructReply(ctx, session, httpReply("other")))
+	assert.False(t, messageUseCase.processInstructReply(ctx, session, httpReply("missing")))
+	assert.Equal(t, int32(0), instructRepo.instruct["other"].Result)
+	_, ok := instructRepo.instruct["missing"]
+	assert.False(t, ok)
+	assert.Empty(t, resultRepo.results)
+	assert.Empty(t, eventRepo.published())
+	
+	// 类型与指令不一致
+	reply := httpReply("own")
+	assert.False(t, messageUseCase.processInstructReply(ctx, session, reply))
+	assert.Equal(t, int32(0), instructRepo.instruct["own"].Result)
+	
+	// soldier 不支持的指令类型回传失败结果
+	instructRepo.instruct["unknown"] = Instruct{Uuid: "unknown", OrgUuid: "org", GroupUuid: "group", InstanceName: "name", Type: 99}
+	unsupported := ClientMessage{Type: ClientInstructReply, InstructMessage: InstructMessage{Uuid: "unknown", Type: 99, ErrMsg: "不支持的指令类型: 99"}}
+	assert.True(t, messageUseCase.processInstructReply(ctx, session, unsupported))
+	assert.Equal(t, int32(-1), instructRepo.instruct["unknown"].Result)
+	assert.Equal(t, "不支持的指令类型: 99", instructRepo.instruct["unknown"].Reply)
+	
+	fileOwners := make(map[string]bool)
+	chunk := func(uuid string) ClientMessage {
+		return ClientMessage{Type: ClientFileChunk, FileChunk: &FileChunk{Uuid: uuid, Data: []byte("data")}}
+	}
+	messageUseCase.processFileChunk(ctx, session, chunk("file"), fileOwners)
+	messageUseCase.processFileChunk(ctx, session, chunk("missing"), fileOwners)
+	messageUseCase.processFileChunk(ctx, session, chunk("own"), fileOwners)
+	assert.Equal(t, map[string]bool{"own": true}, fileOwners)
+	assert.Equal(t, map[string][]byte{"own": []byte("data")}, blobRepo.staging)
+}
+
+// 指令已取消后回传的结果不再更新指令、保存探测结果及发布事件，指令已结束时确认结果
+
+func TestMessageUseCase_ReplyAfterCancel(t *testing.T) {
+	ctx := context.Background()
+	instructRepo, blobRepo := newFakeRepos()
+	resultRepo := newFakeResultRepo()
+	messageUseCase, eventRepo := newTestMessageUseCase(instructRepo, blobRepo, resultRepo)
+	session := &Session{InstanceUuid: "instance", OrgUuid: "org", GroupUuid: "group", InstanceName: "name"}
+	
+	instructRepo.instruct["u1"] = Instruct{Uuid: "u1", OrgUuid: "org", GroupUuid: "group", InstanceName: "name", Type: HttpInstruct}
+	instructRepo.instruct["u2"] = Instruct{Uuid: "u2", OrgUuid: "org", GroupUuid: "group", InstanceName: "name", Type: HttpInstruct}
+	
+	// 取消先于结果
+	updated, err := instructRepo.UpdateInstruct(ctx, "u1", "指令已取消", -1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), updated)
+	
+	assert.True(t, messageUseCase.processInstructReply(ctx, session, httpReply("u1")))
+	assert.Equal(t, int32(-1), instructRepo.instruct["u1"].Result)
+	assert.Equal(t, "指令已取消", instructRepo.instruct["u1"].Reply)
+	_, err = resultRepo.GetResult(ctx, "u1")
+	assert.ErrorIs(t, err, ErrResultNotFound)
+	assert.Empty(t, eventRepo.published())
+	
+	// 结果先于取消
+	assert.True(t, messageUseCase.processInstructReply(ctx, session, httpReply("u2")))
+	assert.Equal(t, int32(1), instructRepo.instruct["u2"].Result)
+	_, err = resultRepo.GetResult(ctx, "u2")
+	assert.NoError(t, err)
+	assert.Len(t, eventRepo.published(), 1)
+	
+	// 按指令状态处理其他会话发送的结果: 重复的结果不再处理，未结束的指令保存结果
+	other := &Session{InstanceUuid: "instance", OrgUuid: "org", GroupUuid: "group", InstanceName: "name", Token: "other"}
+	assert.True(t, messageUseCase.processInstructReply(ctx, other, httpReply("u2")))
+	assert.Len(t, eventRepo.published(), 1)
+	
+	instructRepo.instruct["u3"] = Instruct{Uuid: "u3", OrgUuid: "org", GroupUuid: "group", InstanceName: "name", Type: HttpInstruct}
+	assert.True(t, messageUseCase.processInstructReply(ctx, other, httpReply("u3")))
+	assert.Equal(t, int32(1), instructRepo.instruct["u3"].Result)
+	assert.Len(t, eventRepo.published(), 2)
+	
+	updated, err = instructRepo.UpdateInstruct(ctx, "u2", "指令已取消", -1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), updated)
+	assert.Equal(t, int32(1), instructRepo.instruct["u2"].Result)
+}
+
+// soldier 重启后以新会话连接，旧会话中的指令标记为中断，离线缓存中的结果发送后仍保存并确认
+
+func TestMessageUseCase_ReplyAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	instructRepo, blobRepo := newFakeRepos()
+	resultRepo := newFakeResultRepo()
+	messageUseCase, eventRepo := newTestMessageUseCase(instructRepo, blobRepo, resultRepo)
+	sessionUseCase := NewSessionUseCase(newFakeSessionRepo(), instructRepo, messageUseCase.eventUseCase, zap.NewNop())
+	instance := Instance{Uuid: "instance", OrgUuid: "org", GroupUuid: "group", InstanceName: "name"}
+	
+	session, err := sessionUseCase.Open(ctx, instance, "")
+	assert.NoError(t, err)
+	instructRepo.instruct["u1"] = Instruct{Uuid: "u1", OrgUuid: "org", GroupUuid: "group", InstanceName: "name", Type: HttpInstruct}
+	sessionUseCase.Track(ctx, session, "u1", `{"type":2}`)
+	
+	// 断开期间结果写入离线缓存，soldier 重启后重新加载
+	dir := t.TempDir()
+	assert.NoError(t, NewSpool(zap.NewNop(), &SpoolPolicy{Dir: dir}).Append(httpReply("u1")))
+	spool := NewSpool(zap.NewNop(), &SpoolPolicy{Dir: dir})
+	assert.Equal(t, 1, spool.Len())
+	
+	restarted, err := sessionUseCase.Open(ctx, instance, "")
+	assert.NoError(t, err)
+	assert.False(t, restarted.Resumed)
+	interrupted := instructRepo.instruct["u1"]
+	assert.True(t, interrupted.Interrupted())
+	
+	var acked []string
+	err = spool.Flush(func(msg ClientMessage) error {
+		if messageUseCase.processInstructReply(ctx, restarted, msg) {
+			acked = append(acked, msg.InstructMessage.Uuid)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u1"}, acked)
+	assert.Equal(t, int32(1), instructRepo.instruct["u1"].Result)
+	_, err = resultRepo.GetResult(ctx, "u1")
+	assert.NoError(t, err)
+	assert.Len(t, eventRepo.published(), 2)
+	
+	// 重复发送的结果不再处理
+	assert.True(t, messageUseCase.processInstructReply(ctx, restarted, httpReply("u1")))
+	assert.Len(t, eventRepo.published(), 2)
+}
+
+// 文件获取指令的结果更新后保存文件，指令已结束时丢弃已接收的分片
+
+func TestMessageUseCase_FileReply(t *testing.T) {
+	ctx := context.Background()
+	instructRepo, blobRepo := newFakeRepos()
+	messageUseCase, _ := newTestMessageUseCase(instructRepo, blobRepo, newFakeResultRepo())
+	session := &Session{InstanceUuid: "instance", OrgUuid: "org", GroupUuid: "group", InstanceName: "name"}
+	fileReply := func(uuid string) ClientMessage {
+		return ClientMessage{Type: ClientInstructReply, InstructMessage: InstructMessage{Uuid: uuid, Type: FileGetInstruct, Result: true, FilePath: "/etc/hosts", FileSize: 4}}
+	}
+	
+	fileOwners := make(map[string]bool)
+	for _, uuid := range []string{"f1", "f2"} {
+		instructRepo.instruct[uuid] = Instruct{Uuid: uuid, OrgUuid: "org", GroupUuid: "group", InstanceName: "name", Type: FileGetInstruct}
+		messageUseCase.processFileChunk(ctx, session, ClientMessage{Type: ClientFileChunk, FileChunk: &FileChunk{Uuid: uuid, Data: []byte("data")}}, fileOwners)
+	}
+	
+	assert.True(t, messageUseCase.processInstructReply(ctx, session, fileReply("f1")))
+	assert.Equal(t, int32(1), instructRepo.instruct["f1"].Result)
+	
+	// 取消先于结果
+	_, err := instructRepo.UpdateInstruct(ctx, "f2", "指令已取消", -1)
+	assert.NoError(t, err)
+	assert.True(t, messageUseCase.processInstructReply(ctx, session, fileReply("f2")))
+	assert.Equal(t, int32(-1), instructRepo.instruct["f2"].Result)
+	
+	assert.Equal(t, map[string][]byte{"f1": []byte("data")}, blobRepo.blobs)
+	assert.Empty(t, blobRepo.staging)
+}
+
+// 日志中不记录终端输入、端口转发及文件分片的内容
+
+func TestServiceMessage_LogFields(t *testing.T) {
+	for _, msg := range []ServiceMessage{
+		{Type: ServiceShell, Shell: &ShellFrame{SessionId: "s1", Data: []byte("password")}},
+		{Type: ServiceTunnel, Tunnel: &TunnelFrame{StreamId: "s1", Data: []byte("password")}},
+		{Type: ServiceFileChunk, FileChunk: &FileChunk{Uuid: "u1", Data: []byte("password")}},
+	} {
+		encoder := zapcore.NewMapObjectEncoder()
+		for _, field := range msg.LogFields() {
+			field.AddTo(encoder)
+		}
+		assert.Equal(t, int64(len("password")), encoder.Fields["size"])
+		assert.NotContains(t, fmt.Sprint(encoder.Fields), "password")
+	}
+}
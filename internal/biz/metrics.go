@@ -0,0 +1,297 @@
+package biz
+
+import (
+	"context"
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/shirou/gopsutil/v4/load"
+	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/shirou/gopsutil/v4/net"
+	"github.com/shirou/gopsutil/v4/process"
+	"go.uber.org/zap"
+	"sort"
+	"time"
+)
+
+// 主机监控数据，soldier 定时采集并上报，commander 按实例保存最近一段时间的数据
+
+type HostMetrics struct {
+	CollectTime   int64            `json:"collectTime,omitempty"`
+	CpuPercent    float64          `json:"cpuPercent"`
+	Load1         float64          `json:"load1"`
+	Load5         float64          `json:"load5"`
+	Load15        float64          `json:"load15"`
+	MemoryUsed    uint64           `json:"memoryUsed"`
+	MemoryPercent float64          `json:"memoryPercent"`
+	SwapPercent   float64          `json:"swapPercent"`
+	Disks         []DiskUsage      `json:"disks,omitempty"`
+	DiskReadRate  uint64           `json:"diskReadRate"`  // 字节/秒
+	DiskWriteRate uint64           `json:"diskWriteRate"` // 字节/秒
+	NetRecvRate   uint64           `json:"netRecvRate"`   // 字节/秒
+	NetSentRate   uint64           `json:"netSentRate"`   // 字节/秒
+	NetErrIn      uint64           `json:"netErrIn"`      // 采样周期内增量
+	NetErrOut     uint64           `json:"netErrOut"`
+	NetDropIn     uint64           `json:"netDropIn"`
+	NetDropOut    uint64           `json:"netDropOut"`
+	TopProcesses  []ProcessMetrics `json:"topProcesses,omitempty"`
+}
+
+type DiskUsage struct {
+	Mountpoint  string  `json:"mountpoint,omitempty"`
+	Total       uint64  `json:"total"`
+	Used        uint64  `json:"used"`
+	UsedPercent float64 `json:"usedPercent"`
+}
+
+type ProcessMetrics struct {
+	Pid           int32   `json:"pid"`
+	Name          string  `json:"name,omitempty"`
+	CpuPercent    float64 `json:"cpuPercent"`
+	MemoryPercent float32 `json:"memoryPercent"`
+}
+
+const (
+	metricsReportInterval = 5 * time.Second
+	metricsWindow         = 1 * time.Hour // commander 保存最近 1 小时的数据
+	metricsWindowSize     = int64(metricsWindow / metricsReportInterval)
+	topProcessCount       = 5
+)
+
+type MetricsRepo interface {
+	PushMetrics(ctx context.Context, instanceUuid string, metrics HostMetrics, windowSize int64, ttl time.Duration) error
+	ListMetrics(ctx context.Context, instanceUuid string) ([]HostMetrics, error)
+}
+
+type MetricsUseCase struct {
+	metricsRepo  MetricsRepo
+	instanceRepo InstanceRepo
+	logger       *zap.Logger
+}
+
+func NewMetricsUseCase(metricsRepo MetricsRepo, instanceRepo InstanceRepo, logger *zap.Logger) *MetricsUseCase {
+	return &MetricsUseCase{
+		metricsRepo:  metricsRepo,
+		instanceRepo: instanceRepo,
+		logger:       logger,
+	}
+}
+
+// 查询实例监控数据，按采集时间升序返回，startTime / endTime 为 0 时不限制
+
+func (metricsUseCase *MetricsUseCase) ListMetrics(ctx context.Context, orgUuid, groupUuid, instanceName string, startTime, endTime int64) ([]HostMetrics, error) {
+	instance, err := metricsUseCase.instanceRepo.Get(ctx, orgUuid, groupUuid, instanceName)
+	if err != nil {
+		return nil, err
+	}
+	
+	metrics, err := metricsUseCase.metricsRepo.ListMetrics(ctx, instance.Uuid)
+	if err != nil {
+		return nil, err
+	}
+	
+	var result []HostMetrics
+	for _, m := range metrics {
+		if startTime > 0 && m.CollectTime < startTime {
+			continue
+		}
+		if endTime > 0 && m.CollectTime > endTime {
+			continue
+		}
+		result = append(result, m)
+	}
+	
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CollectTime < result[j].CollectTime
+	})
+	
+	return result, nil
+}
+
+// soldier 端监控数据采集，速率类指标基于上一次采样计算
+
+type MetricsClientUseCase struct {
+	logger *zap.Logger
+	
+	lastTime      time.Time
+	lastDiskRead  uint64
+	lastDiskWrite uint64
+	lastNet       net.IOCountersStat
+	processes     map[int32]*process.Process
+}
+
+func NewMetricsClientUseCase(logger *zap.Logger) *MetricsClientUseCase {
+	return &MetricsClientUseCase{
+		logger:    logger,
+		processes: make(map[int32]*process.Process),
+	}
+}
+
+// 采集一次监控数据，非并发安全，仅由 ReportMetrics 调用
+
+func (metricsClientUseCase *MetricsClientUseCase) Sample(ctx context.Context) HostMetrics {
+	now := time.Now()
+	metrics := HostMetrics{CollectTime: now.Unix()}
+	
+	cpuPercent, err := cpu.PercentWithContext(ctx, 0, false)
+	if err == nil && len(cpuPercent) > 0 {
+		metrics.CpuPercent = cpuPercent[0]
+	}
+	
+	avg, err := load.AvgWithContext(ctx)
+	if err == nil {
+		metrics.Load1 = avg.Load1
+		metrics.Load5 = avg.Load5
+		metrics.Load15 = avg.Load15
+	}
+	
+	memory, err := mem.VirtualMemoryWithContext(ctx)
+	if err == nil {
+		metrics.MemoryUsed = memory.Used
+		metrics.MemoryPercent = memory.UsedPercent
+	}
+	
+	swap, err := mem.SwapMemoryWithContext(ctx)
+	if err == nil {
+		metrics.SwapPercent = swap.UsedPercent
+	}
+	
+	partitions, err := disk.PartitionsWithContext(ctx, false)
+	if err != nil {
+		metricsClientUseCase.logger.Warn("获取磁盘分区失败", zap.Error(err))
+	}
+	for _, partition := range partitions {
+		usage, err := disk.UsageWithContext(ctx, partition.Mountpoint)
+		if err != nil {
+			continue
+		}
+		metrics.Disks = append(metrics.Disks, DiskUsage{
+			Mountpoint:  partition.Mountpoint,
+			Total:       usage.Total,
+			Used:        usage.Used,
+			UsedPercent: usage.UsedPercent,
+		})
+	}
+	
+	var diskRead, diskWrite uint64
+	ioCounters, err := disk.IOCountersWithContext(ctx)
+	if err == nil {
+		for _, counter := range ioCounters {
+			diskRead += counter.ReadBytes
+			diskWrite += counter.WriteBytes
+		}
+	}
+	
+	var netCounter net.IOCountersStat
+	netCounters, err := net.IOCountersWithContext(ctx, false)
+	if err == nil && len(netCounters) > 0 {
+		netCounter = netCounters[0]
+	}
+	
+	// 首次采样没有基准数据，速率为 0
+	if !metricsClientUseCase.lastTime.IsZero() {
+		seconds := uint64(now.Sub(metricsClientUseCase.lastTime).Seconds())
+		if seconds == 0 {
+			seconds = 1
+		}
+		
+		metrics.DiskReadRate = delta(diskRead, metricsClientUseCase.lastDiskRead) / seconds
+		metrics.DiskWriteRate = delta(diskWrite, metricsClientUseCase.lastDiskWrite) / seconds
+		metrics.NetRecvRate = delta(netCounter.BytesRecv, metricsClientUseCase.lastNet.BytesRecv) / seconds
+		metrics.NetSentRate = delta(netCounter.BytesSent, metricsClientUseCase.lastNet.BytesSent) / seconds
+		metrics.NetErrIn = delta(netCounter.Errin, metricsClientUseCase.lastNet.Errin)
+		metrics.NetErrOut = delta(netCounter.Errout, metricsClientUseCase.lastNet.Errout)
+		metrics.NetDropIn = delta(netCounter.Dropin, metricsClientUseCase.lastNet.Dropin)
+		metrics.NetDropOut = delta(netCounter.Dropout, metricsClientUseCase.lastNet.Dropout)
+	}
+	
+	metricsClientUseCase.lastTime = now
+	metricsClientUseCase.lastDiskRead = diskRead
+	metricsClientUseCase.lastDiskWrite = diskWrite
+	metricsClientUseCase.lastNet = netCounter
+	
+	metrics.TopProcesses = metricsClientUseCase.topProcesses(ctx)
+	
+	return metrics
+}
+
+// 计算 CPU 占用最高的进程，进程 CPU 占用率基于上一次采样计算，因此需要缓存 process.Process
+
+func (metricsClientUseCase *MetricsClientUseCase) topProcesses(ctx context.Context) []ProcessMetrics {
+	pids, err := process.PidsWithContext(ctx)
+	if err != nil {
+		metricsClientUseCase.logger.Warn("获取进程列表失败", zap.Error(err))
+		return nil
+	}
+	
+	alive := make(map[int32]*process.Process, len(pids))
+	var processes []ProcessMetrics
+	
+	for _, pid := range pids {
+		p, ok := metricsClientUseCase.processes[pid]
+		if !ok {
+			p, err = process.NewProcessWithContext(ctx, pid)
+			if err != nil {
+				continue
+			}
+		}
+		alive[pid] = p
+		
+		cpuPercent, err := p.PercentWithContext(ctx, 0)
+		if err != nil {
+			continue
+		}
+		
+		name, _ := p.NameWithContext(ctx)
+		memoryPercent, _ := p.MemoryPercentWithContext(ctx)
+		
+		processes = append(processes, ProcessMetrics{
+			Pid:           pid,
+			Name:          name,
+			CpuPercent:    cpuPercent,
+			MemoryPercent: memoryPercent,
+		})
+	}
+	
+	metricsClientUseCase.processes = alive
+	
+	sort.Slice(processes, func(i, j int) bool {
+		return processes[i].CpuPercent > processes[j].CpuPercent
+	})
+	
+	if len(processes) > topProcessCount {
+		processes = processes[:topProcessCount]
+	}
+	
+	return processes
+}
+
+// 计数器重置 (如网卡重启) 时返回 0
+
+func delta(current, last uint64) uint64 {
+	if current < last {
+		return 0
+	}
+	return current - last
+}
+
+// 定时采集并上报监控数据
+
+func (webSocketUseCase *WebSocketUseCase) ReportMetrics(ctx context.Context, sendMsg chan ClientMessage) {
+	ticker := time.NewTicker(metricsReportInterval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ticker.C:
+			metrics := webSocketUseCase.metricsClientUseCase.Sample(ctx)
+			sendMsg <- ClientMessage{
+				Type:    ClientHostMetrics,
+				Metrics: &metrics,
+			}
+		
+		case <-ctx.Done():
+			webSocketUseCase.logger.Info("关闭定时上报监控数据")
+			return
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package biz
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestMetricsClientUseCase_Sample(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	metricsClientUseCase := NewMetricsClientUseCase(logger)
+	
+	ctx := context.Background()
+	metricsClientUseCase.Sample(ctx)
+	
+	time.Sleep(1 * time.Second)
+	metrics := metricsClientUseCase.Sample(ctx)
+	assert.NotZero(t, metrics.MemoryUsed, "获取内存使用量失败")
+	assert.LessOrEqual(t, len(metrics.TopProcesses), topProcessCount)
+	
+	logger.Info("metrics", zap.Any("metrics", metrics))
+}
+
+func TestDelta(t *testing.T) {
+	assert.Equal(t, uint64(10), delta(20, 10))
+	assert.Equal(t, uint64(0), delta(10, 20))
+}
@@ -0,0 +1,22 @@
+package biz
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"os"
+)
+
+// commander 节点，记录 soldier 连接所在的节点
+
+type Node struct {
+	Id string
+}
+
+func NewNode() *Node {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "commander"
+	}
+	
+	return &Node{Id: fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8])}
+}
@@ -0,0 +1,635 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// 组织及分组
+//
+// soldier 连接时携带的 orgUuid / groupUuid 不存在时自动创建 (兼容未预先创建的部署)，也可通过接口预先创建并设置分组默认配置
+// 删除组织或分组时先标记为 deleting，断开其下的实例连接并拒绝新的连接及指令，由 Cleanup 分批删除实例、指令及连接记录后标记为 deleted；
+// 已删除的组织及分组保留记录，避免 soldier 重连时重新创建，通过创建接口可重新启用
+
+const (
+	OrgActive   = "active"
+	OrgDeleting = "deleting" // 清理中
+	OrgDeleted  = "deleted"
+	
+	MaxInstructTimeout  = 24 * 60 * 60 // 指令执行超时上限 (秒)
+	MaxIcmpCount        = 100
+	MaxCommandPatterns  = 64
+	DefaultCleanupBatch = 500
+	
+	orgDefaultLimit = 50
+	orgMaxLimit     = 500
+	
+	cleanupInterval = 30 * time.Second
+)
+
+var (
+	ErrOrgNotFound          = errors.New("组织不存在")
+	ErrGroupNotFound        = errors.New("分组不存在")
+	ErrOrgExists            = errors.New("组织已存在")
+	ErrGroupExists          = errors.New("分组已存在")
+	ErrGroupDeleted         = errors.New("组织或分组已删除")
+	ErrOrgDeleted           = errors.New("组织已删除")
+	ErrCommandDenied        = errors.New("命令不符合分组的命令策略")
+	ErrInvalidGroupSettings = errors.New("分组配置异常")
+)
+
+type Org struct {
+	Uuid        string `json:"uuid" gorm:"primaryKey"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	CreateTime  int64  `json:"createTime"`
+	UpdateTime  int64  `json:"updateTime"`
+}
+
+func (org *Org) TableName() string {
+	return "org"
+}
+
+type Group struct {
+	OrgUuid     string        `json:"orgUuid" gorm:"primaryKey"`
+	Uuid        string        `json:"uuid" gorm:"primaryKey"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Settings    GroupSettings `json:"settings" gorm:"serializer:json"`
+	Status      string        `json:"status"`
+	CreateTime  int64         `json:"createTime"`
+	UpdateTime  int64         `json:"updateTime"`
+}
+
+func (group *Group) TableName() string {
+	return "instance_group"
+}
+
+// 分组默认配置，下发指令时生效
+
+type GroupSettings struct {
+	InstructTimeout int64         `json:"instructTimeout,omitempty"` // 指令执行超时 (秒)，0 表示不限制
+	IcmpCount       int32         `json:"icmpCount,omitempty"`       // icmp 指令发送次数，0 使用 soldier 默认值
+	CommandPolicy   CommandPolicy `json:"commandPolicy"`
+}
+
+// 命令行指令策略，命令匹配 Deny 中任一正则时拒绝；Allow 非空时命令须匹配其中之一
+
+type CommandPolicy struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+func (groupSettings GroupSettings) Validate() error {
+	if groupSettings.InstructTimeout < 0 || groupSettings.InstructTimeout > MaxInstructTimeout {
+		return fmt.Errorf("%w: instructTimeout 范围 0-%d", ErrInvalidGroupSettings, MaxInstructTimeout)
+	}
+	
+	if groupSettings.IcmpCount < 0 || groupSettings.IcmpCount > MaxIcmpCount {
+		return fmt.Errorf("%w: icmpCount 范围 0-%d", ErrInvalidGroupSettings, MaxIcmpCount)
+	}
+	
+	policy := groupSettings.CommandPolicy
+	if len(policy.Allow)+len(policy.Deny) > MaxCommandPatterns {
+		return fmt.Errorf("%w: 命令策略超过 %d 条", ErrInvalidGroupSettings, MaxCommandPatterns)
+	}
+	
+	for _, pattern := range append(append([]string(nil), policy.Allow...), policy.Deny...) {
+		_, err := compileCommandPattern(pattern)
+		if err != nil {
+			return fmt.Errorf("%w: %q", ErrInvalidGroupSettings, pattern)
+		}
+	}
+	
+	return nil
+}
+
+// 检查命令是否符合策略
+
+func (commandPolicy CommandPolicy) Check(command string) error {
+	for _, pattern := range commandPolicy.Deny {
+		re, err := compileCommandPattern(pattern)
+		if err != nil || re.MatchString(command) {
+			return ErrCommandDenied
+		}
+	}
+	
+	if len(commandPolicy.Allow) == 0 {
+		return nil
+	}
+	
+	for _, pattern := range commandPolicy.Allow {
+		re, err := compileCommandPattern(pattern)
+		if err == nil && re.MatchString(command) {
+			return nil
+		}
+	}
+	
+	return ErrCommandDenied
+}
+
+// 分组配置在每次下发指令时读取，编译后的正则按表达式缓存
+
+var commandPatterns sync.Map
+
+func compileCommandPattern(pattern string) (*regexp.Regexp, error) {
+	cached, ok := commandPatterns.Load(pattern)
+	if ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	
+	commandPatterns.Store(pattern, re)
+	return re, nil
+}
+
+type OrgRepo interface {
+	CreateOrg(ctx context.Context, org Org) error
+	UpdateOrg(ctx context.Context, org Org) error
+	GetOrg(ctx context.Context, uuid string) (Org, error)
+	ListOrgs(ctx context.Context, offset, limit int) ([]Org, int64, error)
+	ListOrgsByStatus(ctx context.Context, status string) ([]Org, error)
+	CreateGroup(ctx context.Context, group Group) error
+	UpdateGroup(ctx context.Context, group Group) error
+	GetGroup(ctx context.Context, orgUuid, uuid string) (Group, error)
+	ListGroups(ctx context.Context, orgUuid string, offset, limit int) ([]Group, int64, error)
+	ListGroupsByStatus(ctx context.Context, status string) ([]Group, error)
+	CountGroups(ctx context.Context, orgUuid string, excludeStatus string) (int64, error)
+	SetGroupsStatus(ctx context.Context, orgUuid string, status string, updateTime int64) error
+	EnsureGroup(ctx context.Context, org Org, group Group) error
+	AcquireCleanupLock(ctx context.Context, node string, ttl time.Duration) (bool, error)
+}
+
+type OrgUseCase struct {
+	orgRepo        OrgRepo
+	instanceRepo   InstanceRepo
+	instructRepo   InstructRepo
+	labelRepo      LabelRepo
+	connectionRepo ConnectionRepo
+	sessionRepo    SessionRepo
+	archiveRepo    ArchiveRepo
+	resultRepo     ResultRepo
+	blobRepo       BlobRepo
+	clusterUseCase *ClusterUseCase
+	node           *Node
+	logger         *zap.Logger
+}
+
+func NewOrgUseCase(orgRepo OrgRepo, instanceRepo InstanceRepo, instructRepo InstructRepo, labelRepo LabelRepo, connectionRepo ConnectionRepo, sessionRepo SessionRepo, archiveRepo ArchiveRepo, resultRepo ResultRepo, blobRepo BlobRepo, clusterUseCase *ClusterUseCase, node *Node, logger *zap.Logger) *OrgUseCase {
+	return &OrgUseCase{
+		orgRepo:        orgRepo,
+		instanceRepo:   instanceRepo,
+		instructRepo:   instructRepo,
+		labelRepo:      labelRepo,
+		connectionRepo: connectionRepo,
+		sessionRepo:    sessionRepo,
+		archiveRepo:    archiveRepo,
+		resultRepo:     resultRepo,
+		blobRepo:       blobRepo,
+		clusterUseCase: clusterUseCase,
+		node:           node,
+		logger:         logger,
+	}
+}
+
+func pageLimit(offset, limit int) (int, int) {
+	if limit <= 0 {
+		limit = orgDefaultLimit
+	}
+	
+	return max(offset, 0), min(limit, orgMaxLimit)
+}
+
+// 创建组织，uuid 为空时自动生成；已删除的组织重新启用
+
+func (orgUseCase *OrgUseCase) CreateOrg(ctx context.Context, orgUuid, name, description string) (Org, error) {
+	if orgUuid == "" {
+		orgUuid = uuid.NewString()
+	}
+	
+	now := time.Now().Unix()
+	org, err := orgUseCase.orgRepo.GetOrg(ctx, orgUuid)
+	switch {
+	case errors.Is(err, ErrOrgNotFound):
+		org = Org{Uuid: orgUuid, Name: name, Description: description, Status: OrgActive, CreateTime: now, UpdateTime: now}
+		return org, orgUseCase.orgRepo.CreateOrg(ctx, org)
+	
+	case err != nil:
+		return Org{}, err
+	
+	case org.Status != OrgDeleted:
+		return Org{}, ErrOrgExists
+	}
+	
+	org.Name, org.Description, org.Status, org.UpdateTime = name, description, OrgActive, now
+	return org, orgUseCase.orgRepo.UpdateOrg(ctx, org)
+}
+
+func (orgUseCase *OrgUseCase) UpdateOrg(ctx context.Context, orgUuid, name, description string) (Org, error) {
+	org, err := orgUseCase.GetOrg(ctx, orgUuid)
+	if err != nil {
+		return Org{}, err
+	}
+	
+	if org.Status != OrgActive {
+		return Org{}, ErrOrgDeleted
+	}
+	
+	org.Name, org.Description, org.UpdateTime = name, description, time.Now().Unix()
+	return org, orgUseCase.orgRepo.UpdateOrg(ctx, org)
+}
+
+// 获取组织，已删除的组织视为不存在
+
+func (orgUseCase *OrgUseCase) GetOrg(ctx context.Context, orgUuid string) (Org, error) {
+	org, err := orgUseCase.orgRepo.GetOrg(ctx, orgUuid)
+	if err != nil {
+		return Org{}, err
+	}
+	
+	if org.Status == OrgDeleted {
+		return Org{}, ErrOrgNotFound
+	}
+	
+	return org, nil
+}
+
+// 列出未删除的组织
+
+func (orgUseCase *OrgUseCase) ListOrgs(ctx context.Context, offset, limit int) ([]Org, int64, error) {
+	offset, limit = pageLimit(offset, limit)
+	return orgUseCase.orgRepo.ListOrgs(ctx, offset, limit)
+}
+
+// 删除组织，组织下的分组一并删除，重复删除时忽略
+
+func (orgUseCase *OrgUseCase) DeleteOrg(ctx context.Context, orgUuid string) error {
+	org, err := orgUseCase.GetOrg(ctx, orgUuid)
+	if err != nil {
+		return err
+	}
+	
+	if org.Status == OrgDeleting {
+		return nil
+	}
+	
+	org.Status, org.UpdateTime = OrgDeleting, time.Now().Unix()
+	err = orgUseCase.orgRepo.UpdateOrg(ctx, org)
+	if err != nil {
+		return err
+	}
+	
+	err = orgUseCase.orgRepo.SetGroupsStatus(ctx, orgUuid, OrgDeleting, org.UpdateTime)
+	if err != nil {
+		return err
+	}
+	
+	orgUseCase.disconnect(ctx, orgUuid, "")
+	return nil
+}
+
+// 创建分组，所属组织须已存在；已删除的分组重新启用
+
+func (orgUseCase *OrgUseCase) CreateGroup(ctx context.Context, group Group) (Group, error) {
+	err := group.Settings.Validate()
+	if err != nil {
+		return Group{}, err
+	}
+	
+	org, err := orgUseCase.GetOrg(ctx, group.OrgUuid)
+	if err != nil {
+		return Group{}, err
+	}
+	
+	if org.Status != OrgActive {
+		return Group{}, ErrOrgDeleted
+	}
+	
+	if group.Uuid == "" {
+		group.Uuid = uuid.NewString()
+	}
+	
+	now := time.Now().Unix()
+	group.Status, group.UpdateTime = OrgActive, now
+	
+	existing, err := orgUseCase.orgRepo.GetGroup(ctx, group.OrgUuid, group.Uuid)
+	switch {
+	case errors.Is(err, ErrGroupNotFound):
+		group.CreateTime = now
+		return group, orgUseCase.orgRepo.CreateGroup(ctx, group)
+	
+	case err != nil:
+		return Group{}, err
+	
+	case existing.Status != OrgDeleted:
+		return Group{}, ErrGroupExists
+	}
+	
+	group.CreateTime = existing.CreateTime
+	return group, orgUseCase.orgRepo.UpdateGroup(ctx, group)
+}
+
+// 修改分组名称、描述及配置，settings 为 nil 时不修改配置
+
+func (orgUseCase *OrgUseCase) UpdateGroup(ctx context.Context, orgUuid, groupUuid, name, description string, settings *GroupSettings) (Group, error) {
+	if settings != nil {
+		err := settings.Validate()
+		if err != nil {
+			return Group{}, err
+		}
+	}
+	
+	group, err := orgUseCase.GetGroup(ctx, orgUuid, groupUuid)
+	if err != nil {
+		return Group{}, err
+	}
+	
+	if group.Status != OrgActive {
+		return Group{}, ErrGroupDeleted
+	}
+	
+	group.Name, group.Description, group.UpdateTime = name, description, time.Now().Unix()
+	if settings != nil {
+		group.Settings = *settings
+	}
+	
+	return group, orgUseCase.orgRepo.UpdateGroup(ctx, group)
+}
+
+// 获取分组，已删除的分组视为不存在
+
+func (orgUseCase *OrgUseCase) GetGroup(ctx context.Context, orgUuid, groupUuid string) (Group, error) {
+	group, err := orgUseCase.orgRepo.GetGroup(ctx, orgUuid, groupUuid)
+	if err != nil {
+		return Group{}, err
+	}
+	
+	if group.Status == OrgDeleted {
+		return Group{}, ErrGroupNotFound
+	}
+	
+	return group, nil
+}
+
+// 列出组织下未删除的分组
+
+func (orgUseCase *OrgUseCase) ListGroups(ctx context.Context, orgUuid string, offset, limit int) ([]Group, int64, error) {
+	_, err := orgUseCase.GetOrg(ctx, orgUuid)
+	if err != nil {
+		return nil, 0, err
+	}
+	
+	offset, limit = pageLimit(offset, limit)
+	return orgUseCase.orgRepo.ListGroups(ctx, orgUuid, offset, limit)
+}
+
+// 删除分组，重复删除时忽略
+
+func (orgUseCase *OrgUseCase) DeleteGroup(ctx context.Context, orgUuid, groupUuid string) error {
+	group, err := orgUseCase.GetGroup(ctx, orgUuid, groupUuid)
+	if err != nil {
+		return err
+	}
+	
+	if group.Status == OrgDeleting {
+		return nil
+	}
+	
+	group.Status, group.UpdateTime = OrgDeleting, time.Now().Unix()
+	err = orgUseCase.orgRepo.UpdateGroup(ctx, group)
+	if err != nil {
+		return err
+	}
+	
+	orgUseCase.disconnect(ctx, orgUuid, groupUuid)
+	return nil
+}
+
+// soldier 连接时调用，组织及分组不存在时创建，已删除或正在删除时返回 ErrGroupDeleted
+
+func (orgUseCase *OrgUseCase) Join(ctx context.Context, orgUuid, groupUuid string) error {
+	now := time.Now().Unix()
+	err := orgUseCase.orgRepo.EnsureGroup(ctx,
+		Org{Uuid: orgUuid, Name: orgUuid, Status: OrgActive, CreateTime: now, UpdateTime: now},
+		Group{OrgUuid: orgUuid, Uuid: groupUuid, Name: groupUuid, Status: OrgActive, CreateTime: now, UpdateTime: now},
+	)
+	if err != nil {
+		return err
+	}
+	
+	org, err := orgUseCase.orgRepo.GetOrg(ctx, orgUuid)
+	if err != nil {
+		return err
+	}
+	
+	group, err := orgUseCase.orgRepo.GetGroup(ctx, orgUuid, groupUuid)
+	if err != nil {
+		return err
+	}
+	
+	if org.Status != OrgActive || group.Status != OrgActive {
+		return ErrGroupDeleted
+	}
+	
+	return nil
+}
+
+// 下发指令时使用的分组配置，分组不存在时 (升级前注册的实例尚未重连) 使用默认配置
+
+func loadGroupSettings(ctx context.Context, orgRepo OrgRepo, orgUuid, groupUuid string) (GroupSettings, error) {
+	group, err := orgRepo.GetGroup(ctx, orgUuid, groupUuid)
+	if errors.Is(err, ErrGroupNotFound) {
+		return GroupSettings{}, nil
+	}
+	
+	if err != nil {
+		return GroupSettings{}, err
+	}
+	
+	if group.Status != OrgActive {
+		return GroupSettings{}, ErrGroupDeleted
+	}
+	
+	return group.Settings, nil
+}
+
+// 断开组织 (groupUuid 为空时) 或分组下的在线实例，soldier 重连时被拒绝
+
+func (orgUseCase *OrgUseCase) disconnect(ctx context.Context, orgUuid, groupUuid string) {
+	instances, err := orgUseCase.instanceRepo.ListOnline(ctx, orgUuid, groupUuid)
+	if err != nil {
+		orgUseCase.logger.Error("列出在线实例失败", zap.String("orgUuid", orgUuid), zap.String("groupUuid", groupUuid), zap.Error(err))
+		return
+	}
+	
+	for _, instance := range instances {
+		orgUseCase.clusterUseCase.Route(ctx, NodeMessage{Type: NodeRemove, InstanceUuid: instance.Uuid})
+	}
+}
+
+// 定时清理正在删除的分组及组织；多个节点时仅获得锁的节点执行
+
+func (orgUseCase *OrgUseCase) Cleanup(ctx context.Context) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ticker.C:
+			locked, err := orgUseCase.orgRepo.AcquireCleanupLock(ctx, orgUseCase.node.Id, cleanupInterval)
+			if err != nil {
+				orgUseCase.logger.Error("获取分组清理锁失败", zap.Error(err))
+				continue
+			}
+			
+			if locked {
+				orgUseCase.cleanup(ctx)
+			}
+		
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (orgUseCase *OrgUseCase) cleanup(ctx context.Context) {
+	orgs, err := orgUseCase.orgRepo.ListOrgsByStatus(ctx, OrgDeleting)
+	if err != nil {
+		orgUseCase.logger.Error("列出待清理组织失败", zap.Error(err))
+		return
+	}
+	
+	// 删除期间连接时自动创建的分组一并删除
+	for _, org := range orgs {
+		err = orgUseCase.orgRepo.SetGroupsStatus(ctx, org.Uuid, OrgDeleting, time.Now().Unix())
+		if err != nil {
+			orgUseCase.logger.Error("更新分组状态失败", zap.String("orgUuid", org.Uuid), zap.Error(err))
+		}
+	}
+	
+	groups, err := orgUseCase.orgRepo.ListGroupsByStatus(ctx, OrgDeleting)
+	if err != nil {
+		orgUseCase.logger.Error("列出待清理分组失败", zap.Error(err))
+		return
+	}
+	
+	for _, group := range groups {
+		done, err := orgUseCase.purgeGroup(ctx, group, DefaultCleanupBatch)
+		if err != nil {
+			orgUseCase.logger.Error("清理分组失败", zap.String("orgUuid", group.OrgUuid), zap.String("groupUuid", group.Uuid), zap.Error(err))
+			continue
+		}
+		
+		if !done {
+			continue
+		}
+		
+		group.Status, group.UpdateTime = OrgDeleted, time.Now().Unix()
+		err = orgUseCase.orgRepo.UpdateGroup(ctx, group)
+		if err != nil {
+			orgUseCase.logger.Error("更新分组状态失败", zap.String("orgUuid", group.OrgUuid), zap.String("groupUuid", group.Uuid), zap.Error(err))
+			continue
+		}
+		orgUseCase.logger.Info("分组清理完成", zap.String("orgUuid", group.OrgUuid), zap.String("groupUuid", group.Uuid))
+	}
+	
+	// 组织下的分组全部删除后标记组织已删除
+	for _, org := range orgs {
+		remaining, err := orgUseCase.orgRepo.CountGroups(ctx, org.Uuid, OrgDeleted)
+		if err != nil || remaining > 0 {
+			continue
+		}
+		
+		org.Status, org.UpdateTime = OrgDeleted, time.Now().Unix()
+		err = orgUseCase.orgRepo.UpdateOrg(ctx, org)
+		if err != nil {
+			orgUseCase.logger.Error("更新组织状态失败", zap.String("orgUuid", org.Uuid), zap.Error(err))
+			continue
+		}
+		orgUseCase.logger.Info("组织清理完成", zap.String("orgUuid", org.Uuid))
+	}
+}
+
+// 分批删除分组下的实例 (含会话、标签、连接记录及指令队列)、指令记录、文件、探测结果及归档记录，全部删除后返回 true
+
+func (orgUseCase *OrgUseCase) purgeGroup(ctx context.Context, group Group, batch int) (bool, error) {
+	instances, _, err := orgUseCase.instanceRepo.Search(ctx, InstanceQuery{OrgUuid: group.OrgUuid, GroupUuid: group.Uuid, Limit: batch})
+	if err != nil {
+		return false, err
+	}
+	
+	for _, instance := range instances {
+		// 删除前断开仍在线的连接
+		if instance.Online {
+			orgUseCase.clusterUseCase.Route(ctx, NodeMessage{Type: NodeRemove, InstanceUuid: instance.Uuid})
+		}
+		
+		// 会话失效，soldier 重连时不能恢复会话
+		err = orgUseCase.sessionRepo.RevokeInstanceSession(ctx, instance.Uuid)
+		if err != nil {
+			return false, err
+		}
+		
+		err = orgUseCase.instructRepo.DeleteInstructQueue(ctx, instance.OrgUuid, instance.GroupUuid, instance.InstanceName)
+		if err != nil {
+			return false, err
+		}
+		
+		err = orgUseCase.labelRepo.DeleteLabels(ctx, instance.Uuid)
+		if err != nil {
+			return false, err
+		}
+		
+		err = orgUseCase.connectionRepo.DeleteConnections(ctx, instance.Uuid)
+		if err != nil {
+			return false, err
+		}
+		
+		err = orgUseCase.instanceRepo.Delete(ctx, instance.Uuid)
+		if err != nil {
+			return false, err
+		}
+	}
+	
+	// 文件通过指令记录关联到分组，全部删除后再删除指令记录
+	blobs, err := orgUseCase.blobRepo.DeleteGroupBlobs(ctx, group.OrgUuid, group.Uuid, batch)
+	if err != nil {
+		return false, err
+	}
+	if blobs >= int64(batch) {
+		return false, nil
+	}
+	
+	deleted, err := orgUseCase.instructRepo.DeleteGroupInstructs(ctx, group.OrgUuid, group.Uuid, batch)
+	if err != nil {
+		return false, err
+	}
+	
+	results, err := orgUseCase.resultRepo.DeleteGroupResults(ctx, group.OrgUuid, group.Uuid, batch)
+	if err != nil {
+		return false, err
+	}
+	
+	instructArchives, err := orgUseCase.archiveRepo.DeleteGroupInstructArchives(ctx, group.OrgUuid, group.Uuid, batch)
+	if err != nil {
+		return false, err
+	}
+	
+	instanceArchives, err := orgUseCase.archiveRepo.DeleteGroupInstanceArchives(ctx, group.OrgUuid, group.Uuid, batch)
+	if err != nil {
+		return false, err
+	}
+	
+	return len(instances) < batch && deleted < int64(batch) && results < int64(batch) && instructArchives < int64(batch) && instanceArchives < int64(batch), nil
+}
@@ -0,0 +1,159 @@
+package biz
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func newTestOrgUseCase(orgRepo OrgRepo, instanceRepo *fakeInstanceRepo, instructRepo *fakeInstructRepo, sessionRepo SessionRepo, archiveRepo ArchiveRepo, resultRepo ResultRepo, blobRepo BlobRepo) *OrgUseCase {
+	node := &Node{Id: "node1"}
+	clusterUseCase := NewClusterUseCase(newFakeClusterRepo(), newFakePresenceRepo(), instanceRepo, NewConnRegistry(), node, &ClusterPolicy{}, zap.NewNop())
+	return NewOrgUseCase(orgRepo, instanceRepo, instructRepo, &fakeLabelRepo{}, newFakeConnectionRepo(), sessionRepo, archiveRepo, resultRepo, blobRepo, clusterUseCase, node, zap.NewNop())
+}
+
+func TestCommandPolicy_Check(t *testing.T) {
+	policy := CommandPolicy{}
+	assert.NoError(t, policy.Check("rm -rf /"))
+	
+	policy = CommandPolicy{Deny: []string{`\brm\b`, `reboot`}}
+	assert.ErrorIs(t, policy.Check("rm -rf /"), ErrCommandDenied)
+	assert.ErrorIs(t, policy.Check("sudo reboot"), ErrCommandDenied)
+	assert.NoError(t, policy.Check("uptime"))
+	
+	policy = CommandPolicy{Allow: []string{`^(uptime|df -h)$`}, Deny: []string{`-h`}}
+	assert.NoError(t, policy.Check("uptime"))
+	assert.ErrorIs(t, policy.Check("df -h"), ErrCommandDenied)
+	assert.ErrorIs(t, policy.Check("uname -a"), ErrCommandDenied)
+}
+
+func TestGroupSettings_Validate(t *testing.T) {
+	assert.NoError(t, GroupSettings{}.Validate())
+	assert.NoError(t, GroupSettings{InstructTimeout: 60, IcmpCount: 10, CommandPolicy: CommandPolicy{Allow: []string{"^ls"}}}.Validate())
+	assert.ErrorIs(t, GroupSettings{InstructTimeout: -1}.Validate(), ErrInvalidGroupSettings)
+	assert.ErrorIs(t, GroupSettings{IcmpCount: MaxIcmpCount + 1}.Validate(), ErrInvalidGroupSettings)
+	assert.ErrorIs(t, GroupSettings{CommandPolicy: CommandPolicy{Deny: []string{"("}}}.Validate(), ErrInvalidGroupSettings)
+}
+
+// soldier 连接时自动创建组织及分组，删除后拒绝连接，通过创建接口重新启用
+
+func TestOrgUseCase_Join(t *testing.T) {
+	ctx := context.Background()
+	orgRepo := newFakeOrgRepo()
+	instanceRepo := newFakeInstanceRepo()
+	instructRepo, blobRepo := newFakeRepos()
+	orgUseCase := newTestOrgUseCase(orgRepo, instanceRepo, instructRepo, newFakeSessionRepo(), newFakeArchiveRepo(instanceRepo, instructRepo), newFakeResultRepo(), blobRepo)
+	
+	assert.NoError(t, orgUseCase.Join(ctx, "org", "group"))
+	group, err := orgUseCase.GetGroup(ctx, "org", "group")
+	assert.NoError(t, err)
+	assert.Equal(t, OrgActive, group.Status)
+	
+	_, err = orgUseCase.CreateGroup(ctx, Group{OrgUuid: "org", Uuid: "group", Name: "group"})
+	assert.ErrorIs(t, err, ErrGroupExists)
+	
+	assert.NoError(t, orgUseCase.DeleteGroup(ctx, "org", "group"))
+	assert.ErrorIs(t, orgUseCase.Join(ctx, "org", "group"), ErrGroupDeleted)
+	
+	_, err = orgUseCase.CreateGroup(ctx, Group{OrgUuid: "org", Uuid: "group", Name: "group"})
+	assert.ErrorIs(t, err, ErrGroupExists)
+	
+	orgUseCase.cleanup(ctx)
+	_, err = orgUseCase.GetGroup(ctx, "org", "group")
+	assert.ErrorIs(t, err, ErrGroupNotFound)
+	assert.ErrorIs(t, orgUseCase.Join(ctx, "org", "group"), ErrGroupDeleted)
+	
+	group, err = orgUseCase.CreateGroup(ctx, Group{OrgUuid: "org", Uuid: "group", Name: "web", Settings: GroupSettings{InstructTimeout: 30}})
+	assert.NoError(t, err)
+	assert.Equal(t, OrgActive, group.Status)
+	assert.NoError(t, orgUseCase.Join(ctx, "org", "group"))
+	
+	settings, err := loadGroupSettings(ctx, orgRepo, "org", "group")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(30), settings.InstructTimeout)
+	
+	// 未创建的分组使用默认配置
+	settings, err = loadGroupSettings(ctx, orgRepo, "org", "other")
+	assert.NoError(t, err)
+	assert.Equal(t, GroupSettings{}, settings)
+}
+
+// 删除组织后分批清理分组下的实例、指令、文件、探测结果及归档记录，全部清理后标记组织及分组已删除
+
+func TestOrgUseCase_Cleanup(t *testing.T) {
+	ctx := context.Background()
+	orgRepo := newFakeOrgRepo()
+	instanceRepo := newFakeInstanceRepo(
+		Instance{Uuid: "i1", OrgUuid: "org", GroupUuid: "g1", InstanceName: "a"},
+		Instance{Uuid: "i2", OrgUuid: "org", GroupUuid: "g1", InstanceName: "b"},
+		Instance{Uuid: "i3", OrgUuid: "org", GroupUuid: "g2", InstanceName: "c"},
+		Instance{Uuid: "i4", OrgUuid: "other", GroupUuid: "g1", InstanceName: "d"},
+	)
+	instructRepo, blobRepo := newFakeRepos()
+	instructRepo.instruct["x1"] = Instruct{Uuid: "x1", OrgUuid: "org", GroupUuid: "g1", InstanceName: "a"}
+	instructRepo.instruct["x2"] = Instruct{Uuid: "x2", OrgUuid: "other", GroupUuid: "g1", InstanceName: "d"}
+	instructRepo.instruct["f1"] = Instruct{Uuid: "f1", OrgUuid: "org", GroupUuid: "g1", InstanceName: "a", Type: FileGetInstruct}
+	instructRepo.instruct["f2"] = Instruct{Uuid: "f2", OrgUuid: "other", GroupUuid: "g1", InstanceName: "d", Type: FileGetInstruct}
+	blobRepo.blobs["f1"] = []byte("f1")
+	blobRepo.blobs["f2"] = []byte("f2")
+	sessionRepo := newFakeSessionRepo()
+	archiveRepo := newFakeArchiveRepo(instanceRepo, instructRepo)
+	archiveRepo.instances["i5"] = InstanceArchive{Uuid: "i5", OrgUuid: "org", GroupUuid: "g2", InstanceName: "e"}
+	archiveRepo.instances["i6"] = InstanceArchive{Uuid: "i6", OrgUuid: "other", GroupUuid: "g1", InstanceName: "f"}
+	archiveRepo.instructs["y1"] = Instruct{Uuid: "y1", OrgUuid: "org", GroupUuid: "g1", InstanceName: "e"}
+	archiveRepo.instructs["y2"] = Instruct{Uuid: "y2", OrgUuid: "other", GroupUuid: "g1", InstanceName: "f"}
+	resultRepo := newFakeResultRepo(
+		InstructResult{InstructUuid: "x1", OrgUuid: "org", GroupUuid: "g1", InstanceName: "a"},
+		InstructResult{InstructUuid: "x2", OrgUuid: "other", GroupUuid: "g1", InstanceName: "d"},
+	)
+	orgUseCase := newTestOrgUseCase(orgRepo, instanceRepo, instructRepo, sessionRepo, archiveRepo, resultRepo, blobRepo)
+	_, err := sessionRepo.CreateSession(ctx, "i1", "t1", time.Minute)
+	assert.NoError(t, err)
+	
+	for _, key := range [][2]string{{"org", "g1"}, {"org", "g2"}, {"other", "g1"}} {
+		assert.NoError(t, orgUseCase.Join(ctx, key[0], key[1]))
+	}
+	
+	assert.NoError(t, orgUseCase.DeleteOrg(ctx, "org"))
+	assert.ErrorIs(t, orgUseCase.Join(ctx, "org", "g3"), ErrGroupDeleted)
+	_, err = orgUseCase.UpdateOrg(ctx, "org", "org", "")
+	assert.ErrorIs(t, err, ErrOrgDeleted)
+	_, err = orgUseCase.CreateGroup(ctx, Group{OrgUuid: "org", Uuid: "g3", Name: "g3"})
+	assert.ErrorIs(t, err, ErrOrgDeleted)
+	
+	group := Group{OrgUuid: "org", Uuid: "g1"}
+	done, err := orgUseCase.purgeGroup(ctx, group, 1)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	
+	orgUseCase.cleanup(ctx)
+	
+	_, err = orgUseCase.GetOrg(ctx, "org")
+	assert.ErrorIs(t, err, ErrOrgNotFound)
+	for _, groupUuid := range []string{"g1", "g2", "g3"} {
+		group, err := orgRepo.GetGroup(ctx, "org", groupUuid)
+		assert.NoError(t, err)
+		assert.Equal(t, OrgDeleted, group.Status)
+	}
+	
+	assert.Len(t, instanceRepo.instances, 1)
+	assert.Contains(t, instanceRepo.instances, "i4")
+	assert.Len(t, instructRepo.instruct, 2)
+	assert.Contains(t, instructRepo.instruct, "x2")
+	assert.Contains(t, instructRepo.instruct, "f2")
+	assert.Equal(t, map[string][]byte{"f2": []byte("f2")}, blobRepo.blobs)
+	assert.Len(t, archiveRepo.instances, 1)
+	assert.Contains(t, archiveRepo.instances, "i6")
+	assert.Len(t, archiveRepo.instructs, 1)
+	assert.Contains(t, archiveRepo.instructs, "y2")
+	assert.Len(t, resultRepo.results, 1)
+	assert.Contains(t, resultRepo.results, "x2")
+	owner, err := sessionRepo.GetSession(ctx, "t1")
+	assert.NoError(t, err)
+	assert.Empty(t, owner)
+	
+	_, err = orgUseCase.GetOrg(ctx, "other")
+	assert.NoError(t, err)
+}
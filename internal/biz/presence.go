@@ -0,0 +1,289 @@
+package biz
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"time"
+)
+
+// 实例在线状态
+//
+// soldier 连接期间在 Redis 中保存带 TTL 的在线记录 (所在 commander 节点及连接 id)，连接定时续期，
+// MySQL 中的 online 仅在上线 (Register) 及离线时更新；commander 节点异常退出时在线记录过期，由 Reap 标记离线
+// 上线、离线事件写入 Redis Stream，通过 ListInstanceEvents 按事件 id 增量读取
+
+const (
+	PresenceOnline  = "online"
+	PresenceOffline = "offline"
+	
+	presenceEventMaxCount = 1000
+	presenceEventMaxWait  = 30 * time.Second
+)
+
+type Presence struct {
+	InstanceUuid string `json:"instanceUuid"`
+	Node         string `json:"node"`   // 连接所在的 commander 节点
+	ConnId       string `json:"connId"` // 连接 id，同一实例重连后旧连接不再修改在线记录
+	ConnectTime  int64  `json:"connectTime"`
+}
+
+type PresenceEvent struct {
+	Id           string `json:"id"`
+	Event        string `json:"event"`
+	InstanceUuid string `json:"instanceUuid"`
+	OrgUuid      string `json:"orgUuid"`
+	GroupUuid    string `json:"groupUuid"`
+	InstanceName string `json:"instanceName"`
+	Node         string `json:"node"`
+	Time         int64  `json:"time"`
+}
+
+type PresenceRepo interface {
+	SetPresence(ctx context.Context, presence Presence, ttl time.Duration) error
+	RefreshPresence(ctx context.Context, presence Presence, ttl time.Duration) (bool, error)
+	DeletePresence(ctx context.Context, presence Presence) (bool, error)
+	ListPresence(ctx context.Context, instanceUuids []string) (map[string]Presence, error)
+	AddPresenceEvent(ctx context.Context, event PresenceEvent) error
+	ListPresenceEvents(ctx context.Context, after string, count int64, wait time.Duration) ([]PresenceEvent, error)
+	AcquireReapLock(ctx context.Context, node string, ttl time.Duration) (bool, error)
+}
+
+type PresenceUseCase struct {
+	presenceRepo    PresenceRepo
+	instanceRepo    InstanceRepo
+	connectionRepo  ConnectionRepo
+	heartbeatPolicy *HeartbeatPolicy
+	node            *Node
+	logger          *zap.Logger
+}
+
+func NewPresenceUseCase(presenceRepo PresenceRepo, instanceRepo InstanceRepo, connectionRepo ConnectionRepo, heartbeatPolicy *HeartbeatPolicy, node *Node, logger *zap.Logger) *PresenceUseCase {
+	return &PresenceUseCase{
+		presenceRepo:    presenceRepo,
+		instanceRepo:    instanceRepo,
+		connectionRepo:  connectionRepo,
+		heartbeatPolicy: heartbeatPolicy,
+		node:            node,
+		logger:          logger,
+	}
+}
+
+// soldier 连接后记录在线状态及连接记录，覆盖该实例之前的连接
+
+func (presenceUseCase *PresenceUseCase) Connect(ctx context.Context, instance Instance) *Presence {
+	presence := &Presence{
+		InstanceUuid: instance.Uuid,
+		Node:         presenceUseCase.node.Id,
+		ConnId:       uuid.NewString(),
+		ConnectTime:  time.Now().Unix(),
+	}
+	
+	err := presenceUseCase.presenceRepo.SetPresence(ctx, *presence, presenceUseCase.heartbeatPolicy.Alive())
+	if err != nil {
+		presenceUseCase.logger.Error("记录实例在线状态失败", zap.String("uuid", instance.Uuid), zap.Error(err))
+	}
+	
+	// 旧连接在本次注册后标记了离线时恢复在线
+	err = presenceUseCase.instanceRepo.Online(ctx, instance.Uuid)
+	if err != nil {
+		presenceUseCase.logger.Error("更新实例在线状态失败", zap.String("uuid", instance.Uuid), zap.Error(err))
+	}
+	
+	// 之前尚未断开的连接 (旧连接断开前 soldier 已重连) 以 replaced 结束
+	err = presenceUseCase.connectionRepo.CloseInstanceConnections(ctx, instance.Uuid, presence.ConnectTime, DisconnectReplaced)
+	if err != nil {
+		presenceUseCase.logger.Error("结束实例连接记录失败", zap.String("uuid", instance.Uuid), zap.Error(err))
+	}
+	
+	err = presenceUseCase.connectionRepo.CreateConnection(ctx, Connection{
+		Id:           presence.ConnId,
+		InstanceUuid: instance.Uuid,
+		OrgUuid:      instance.OrgUuid,
+		GroupUuid:    instance.GroupUuid,
+		InstanceName: instance.InstanceName,
+		ClientIp:     instance.ClientIp,
+		AgentVersion: instance.AgentVersion,
+		Node:         presence.Node,
+		ConnectTime:  presence.ConnectTime,
+	})
+	if err != nil {
+		presenceUseCase.logger.Error("记录实例连接失败", zap.String("uuid", instance.Uuid), zap.Error(err))
+	}
+	
+	presenceUseCase.publish(ctx, PresenceOnline, instance)
+	return presence
+}
+
+// 连接期间定时续期，间隔为离线判定时间的一半；在线记录已被新连接覆盖时停止
+
+func (presenceUseCase *PresenceUseCase) KeepAlive(ctx context.Context, presence *Presence) {
+	ticker := time.NewTicker(presenceUseCase.heartbeatPolicy.Alive() / 2)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ticker.C:
+			owned, err := presenceUseCase.presenceRepo.RefreshPresence(ctx, *presence, presenceUseCase.heartbeatPolicy.Alive())
+			if err != nil {
+				presenceUseCase.logger.Error("更新实例在线状态失败", zap.String("uuid", presence.InstanceUuid), zap.Error(err))
+				continue
+			}
+			
+			if !owned {
+				presenceUseCase.logger.Info("实例已在其他连接上线", zap.String("uuid", presence.InstanceUuid), zap.String("connId", presence.ConnId))
+				return
+			}
+		
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// 连接断开后记录断开原因，删除在线记录并标记离线，实例已在其他连接上线时不修改
+
+func (presenceUseCase *PresenceUseCase) Disconnect(ctx context.Context, presence *Presence, instance Instance, reason string) {
+	// 已被新连接以 replaced 结束时不修改
+	err := presenceUseCase.connectionRepo.CloseConnection(ctx, presence.ConnId, time.Now().Unix(), reason)
+	if err != nil {
+		presenceUseCase.logger.Error("结束实例连接记录失败", zap.String("uuid", presence.InstanceUuid), zap.Error(err))
+	}
+	
+	owned, err := presenceUseCase.presenceRepo.DeletePresence(ctx, *presence)
+	if err != nil {
+		presenceUseCase.logger.Error("删除实例在线状态失败", zap.String("uuid", presence.InstanceUuid), zap.Error(err))
+		return
+	}
+	
+	if !owned {
+		return
+	}
+	
+	presenceUseCase.offline(ctx, instance)
+}
+
+func (presenceUseCase *PresenceUseCase) offline(ctx context.Context, instance Instance) {
+	err := presenceUseCase.instanceRepo.Offline(ctx, instance.Uuid)
+	if err != nil {
+		presenceUseCase.logger.Error("更新实例离线状态失败", zap.String("uuid", instance.Uuid), zap.Error(err))
+	}
+	
+	presenceUseCase.publish(ctx, PresenceOffline, instance)
+}
+
+func (presenceUseCase *PresenceUseCase) publish(ctx context.Context, event string, instance Instance) {
+	err := presenceUseCase.presenceRepo.AddPresenceEvent(ctx, PresenceEvent{
+		Event:        event,
+		InstanceUuid: instance.Uuid,
+		OrgUuid:      instance.OrgUuid,
+		GroupUuid:    instance.GroupUuid,
+		InstanceName: instance.InstanceName,
+		Node:         presenceUseCase.node.Id,
+		Time:         time.Now().Unix(),
+	})
+	if err != nil {
+		presenceUseCase.logger.Error("记录实例上下线事件失败", zap.String("uuid", instance.Uuid), zap.String("event", event), zap.Error(err))
+	}
+}
+
+// 定时检查 MySQL 中在线但在线记录已过期的实例 (所在 commander 节点异常退出)，标记离线；多个节点时仅获得锁的节点执行
+
+func (presenceUseCase *PresenceUseCase) Reap(ctx context.Context) {
+	ticker := time.NewTicker(presenceUseCase.heartbeatPolicy.Alive())
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ticker.C:
+			locked, err := presenceUseCase.presenceRepo.AcquireReapLock(ctx, presenceUseCase.node.Id, presenceUseCase.heartbeatPolicy.Alive())
+			if err != nil {
+				presenceUseCase.logger.Error("获取在线状态检查锁失败", zap.Error(err))
+				continue
+			}
+			
+			if locked {
+				presenceUseCase.reap(ctx)
+			}
+		
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (presenceUseCase *PresenceUseCase) reap(ctx context.Context) {
+	instances, err := presenceUseCase.instanceRepo.ListOnline(ctx, "", "")
+	if err != nil {
+		presenceUseCase.logger.Error("列出在线实例失败", zap.Error(err))
+		return
+	}
+	
+	alive, err := filterAlive(ctx, presenceUseCase.presenceRepo, instances)
+	if err != nil {
+		presenceUseCase.logger.Error("获取实例在线状态失败", zap.Error(err))
+		return
+	}
+	
+	aliveUuids := make(map[string]bool, len(alive))
+	for _, instance := range alive {
+		aliveUuids[instance.Uuid] = true
+	}
+	
+	for _, instance := range instances {
+		if !aliveUuids[instance.Uuid] {
+			presenceUseCase.logger.Info("实例在线状态已过期，标记离线", zap.String("uuid", instance.Uuid), zap.String("instanceName", instance.InstanceName))
+			
+			err = presenceUseCase.connectionRepo.CloseInstanceConnections(ctx, instance.Uuid, time.Now().Unix(), DisconnectExpired)
+			if err != nil {
+				presenceUseCase.logger.Error("结束实例连接记录失败", zap.String("uuid", instance.Uuid), zap.Error(err))
+			}
+			presenceUseCase.offline(ctx, instance)
+		}
+	}
+}
+
+// 过滤出存在在线记录的实例
+
+func filterAlive(ctx context.Context, presenceRepo PresenceRepo, instances []Instance) ([]Instance, error) {
+	if len(instances) == 0 {
+		return instances, nil
+	}
+	
+	uuids := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		uuids = append(uuids, instance.Uuid)
+	}
+	
+	presences, err := presenceRepo.ListPresence(ctx, uuids)
+	if err != nil {
+		return nil, err
+	}
+	
+	alive := make([]Instance, 0, len(presences))
+	for _, instance := range instances {
+		if _, ok := presences[instance.Uuid]; ok {
+			alive = append(alive, instance)
+		}
+	}
+	
+	return alive, nil
+}
+
+// 读取 after 之后的上下线事件，after 为空时从最早保留的事件开始；没有新事件时最多等待 wait
+
+func (presenceUseCase *PresenceUseCase) ListEvents(ctx context.Context, after string, count int64, wait time.Duration) ([]PresenceEvent, error) {
+	if after == "" {
+		after = "0"
+	}
+	
+	if count <= 0 || count > presenceEventMaxCount {
+		count = presenceEventMaxCount
+	}
+	
+	if wait > presenceEventMaxWait {
+		wait = presenceEventMaxWait
+	}
+	
+	return presenceUseCase.presenceRepo.ListPresenceEvents(ctx, after, count, wait)
+}
@@ -0,0 +1,78 @@
+package biz
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+// 实例重连到其他连接后，旧连接断开不标记离线
+
+func TestPresenceUseCase_Disconnect(t *testing.T) {
+	ctx := context.Background()
+	instance := Instance{Uuid: "i1", OrgUuid: "o1", GroupUuid: "g1", InstanceName: "n1", Online: true}
+	presenceRepo := newFakePresenceRepo()
+	instanceRepo := newFakeInstanceRepo(instance)
+	connectionRepo := newFakeConnectionRepo()
+	presenceUseCase := NewPresenceUseCase(presenceRepo, instanceRepo, connectionRepo, &HeartbeatPolicy{}, &Node{Id: "node1"}, zap.NewNop())
+	
+	first := presenceUseCase.Connect(ctx, instance)
+	second := presenceUseCase.Connect(ctx, instance)
+	assert.NotEqual(t, first.ConnId, second.ConnId)
+	
+	owned, err := presenceRepo.RefreshPresence(ctx, *first, time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, owned)
+	
+	presenceUseCase.Disconnect(ctx, first, instance, DisconnectReadTimeout)
+	assert.True(t, instanceRepo.online(instance.Uuid))
+	
+	presenceUseCase.Disconnect(ctx, second, instance, DisconnectClosed)
+	assert.False(t, instanceRepo.online(instance.Uuid))
+	
+	// 旧连接在新连接上线时已结束，断开原因不再修改
+	assert.Equal(t, DisconnectReplaced, connectionRepo.connections[first.ConnId].DisconnectReason)
+	assert.Equal(t, DisconnectClosed, connectionRepo.connections[second.ConnId].DisconnectReason)
+	assert.Equal(t, "node1", connectionRepo.connections[second.ConnId].Node)
+	
+	var events []string
+	for _, event := range presenceRepo.events {
+		assert.Equal(t, "node1", event.Node)
+		events = append(events, event.Event)
+	}
+	assert.Equal(t, []string{PresenceOnline, PresenceOnline, PresenceOffline}, events)
+}
+
+// 在线记录已过期 (commander 节点异常退出) 的实例标记离线
+
+func TestPresenceUseCase_Reap(t *testing.T) {
+	ctx := context.Background()
+	alive := Instance{Uuid: "i1", Online: true}
+	expired := Instance{Uuid: "i2", Online: true}
+	presenceRepo := newFakePresenceRepo()
+	instanceRepo := newFakeInstanceRepo(alive, expired)
+	connectionRepo := newFakeConnectionRepo()
+	presenceUseCase := NewPresenceUseCase(presenceRepo, instanceRepo, connectionRepo, &HeartbeatPolicy{}, &Node{Id: "node1"}, zap.NewNop())
+	
+	presenceUseCase.Connect(ctx, alive)
+	connectionRepo.CreateConnection(ctx, Connection{Id: "c2", InstanceUuid: expired.Uuid, Node: "node2", ConnectTime: 1})
+	
+	instances, err := instanceRepo.ListOnline(ctx, "", "")
+	assert.NoError(t, err)
+	filtered, err := filterAlive(ctx, presenceRepo, instances)
+	assert.NoError(t, err)
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, alive.Uuid, filtered[0].Uuid)
+	
+	presenceUseCase.reap(ctx)
+	assert.True(t, instanceRepo.online(alive.Uuid))
+	assert.False(t, instanceRepo.online(expired.Uuid))
+	assert.Equal(t, DisconnectExpired, connectionRepo.connections["c2"].DisconnectReason)
+	
+	instanceUseCase := NewInstanceUseCase(instanceRepo, presenceRepo, connectionRepo, nil, zap.NewNop())
+	instances, err = instanceUseCase.ListAliveInstance(ctx, "", "")
+	assert.NoError(t, err)
+	assert.Len(t, instances, 1)
+}
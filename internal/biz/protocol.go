@@ -0,0 +1,799 @@
+package biz
+
+import (
+	"encoding/json"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/security"
+	"github.com/gorilla/websocket"
+	"github.com/prometheus-community/pro-bing"
+	agentv1 "github.com/qx66/camp/api/agent/v1"
+	"google.golang.org/protobuf/proto"
+	"net"
+	"strings"
+	"time"
+)
+
+// websocket 子协议，Connect 握手时协商
+
+const (
+	ProtocolJson  = "camp.v1.json"  // JSON 编码，未声明子协议的旧版本 soldier 使用该协议
+	ProtocolProto = "camp.v2.proto" // protobuf 编码，使用 binary frame
+)
+
+// 支持的子协议，按优先级排列
+
+var Subprotocols = []string{ProtocolProto, ProtocolJson}
+
+// 编码服务端消息，返回 websocket 消息类型及内容
+
+func EncodeServiceMessage(subprotocol string, msg ServiceMessage) (int, []byte, error) {
+	if subprotocol != ProtocolProto {
+		b, err := json.Marshal(msg)
+		return websocket.TextMessage, b, err
+	}
+	
+	b, err := proto.Marshal(toProtoServiceMessage(msg))
+	return websocket.BinaryMessage, b, err
+}
+
+// 解码服务端消息，协商为 protobuf 协议时仍兼容 JSON text frame
+
+func DecodeServiceMessage(subprotocol string, messageType int, data []byte) (ServiceMessage, error) {
+	var msg ServiceMessage
+	if subprotocol != ProtocolProto || messageType != websocket.BinaryMessage {
+		err := json.Unmarshal(data, &msg)
+		return msg, err
+	}
+	
+	pb := &agentv1.ServiceMessage{}
+	err := proto.Unmarshal(data, pb)
+	if err != nil {
+		return msg, err
+	}
+	
+	return fromProtoServiceMessage(pb), nil
+}
+
+// 编码客户端消息，旧协议沿用 binary frame 发送 JSON
+
+func EncodeClientMessage(subprotocol string, msg ClientMessage) (int, []byte, error) {
+	if subprotocol != ProtocolProto {
+		b, err := json.Marshal(msg)
+		return websocket.BinaryMessage, b, err
+	}
+	
+	b, err := proto.Marshal(toProtoClientMessage(msg))
+	return websocket.BinaryMessage, b, err
+}
+
+// 解码客户端消息
+
+func DecodeClientMessage(subprotocol string, messageType int, data []byte) (ClientMessage, error) {
+	var msg ClientMessage
+	if subprotocol != ProtocolProto || messageType != websocket.BinaryMessage {
+		err := json.Unmarshal(data, &msg)
+		return msg, err
+	}
+	
+	pb := &agentv1.ClientMessage{}
+	err := proto.Unmarshal(data, pb)
+	if err != nil {
+		return msg, err
+	}
+	
+	return fromProtoClientMessage(pb), nil
+}
+
+func toProtoServiceMessage(msg ServiceMessage) *agentv1.ServiceMessage {
+	pb := &agentv1.ServiceMessage{
+		Type:          agentv1.ServiceMessageType(msg.Type),
+		Message:       msg.Message,
+		Trace:         msg.Trace,
+		FileChunk:     toProtoFileChunk(msg.FileChunk),
+		Shell:         toProtoShellFrame(msg.Shell),
+		Tunnel:        toProtoTunnelFrame(msg.Tunnel),
+		ChannelWindow: toProtoChannelWindow(msg.ChannelWindow),
+		Fragment:      toProtoFragment(msg.Fragment),
+		Ack:           msg.Ack,
+		Cancel:        msg.Cancel,
+	}
+	
+	instruct := msg.InstructMessage
+	if instruct.Uuid == "" && instruct.Type == 0 {
+		return pb
+	}
+	
+	pb.Instruct = &agentv1.Instruct{Uuid: instruct.Uuid, Timeout: instruct.Timeout}
+	switch instruct.Type {
+	case CommandInstruct:
+		pb.Instruct.Content = &agentv1.Instruct_Command{Command: &agentv1.CommandInstruct{Content: instruct.CommandContent}}
+	case ChromeDpInspectInstruct:
+		pb.Instruct.Content = &agentv1.Instruct_ChromeDpInspect{ChromeDpInspect: &agentv1.ChromeDpInspectInstruct{Url: instruct.ChromeDpInspectUrl}}
+	case DnsInstruct:
+		pb.Instruct.Content = &agentv1.Instruct_Dns{Dns: &agentv1.DnsInstruct{Domain: instruct.DnsInspectDomain}}
+	case HttpInstruct:
+		pb.Instruct.Content = &agentv1.Instruct_Http{Http: &agentv1.HttpInstruct{Url: instruct.HttpInspectUrl}}
+	case IcmpInstruct:
+		pb.Instruct.Content = &agentv1.Instruct_Icmp{Icmp: &agentv1.IcmpInstruct{Addr: instruct.IcmpInspectAddr, Count: instruct.IcmpCount}}
+	case FileGetInstruct:
+		pb.Instruct.Content = &agentv1.Instruct_FileGet{FileGet: &agentv1.FileGetInstruct{Path: instruct.FilePath}}
+	case FilePutInstruct:
+		pb.Instruct.Content = &agentv1.Instruct_FilePut{FilePut: &agentv1.FilePutInstruct{
+			Path:   instruct.FilePath,
+			Size:   instruct.FileSize,
+			Sha256: instruct.FileSha256,
+		}}
+	}
+	
+	return pb
+}
+
+func fromProtoServiceMessage(pb *agentv1.ServiceMessage) ServiceMessage {
+	msg := ServiceMessage{
+		Type:          ServiceMessageType(pb.GetType()),
+		Message:       pb.GetMessage(),
+		Trace:         pb.GetTrace(),
+		FileChunk:     fromProtoFileChunk(pb.GetFileChunk()),
+		Shell:         fromProtoShellFrame(pb.GetShell()),
+		Tunnel:        fromProtoTunnelFrame(pb.GetTunnel()),
+		ChannelWindow: fromProtoChannelWindow(pb.GetChannelWindow()),
+		Fragment:      fromProtoFragment(pb.GetFragment()),
+		Ack:           pb.GetAck(),
+		Cancel:        pb.GetCancel(),
+	}
+	
+	instruct := pb.GetInstruct()
+	if instruct == nil {
+		return msg
+	}
+	
+	msg.InstructMessage.Uuid = instruct.GetUuid()
+	msg.InstructMessage.Timeout = instruct.GetTimeout()
+	switch content := instruct.GetContent().(type) {
+	case *agentv1.Instruct_Command:
+		msg.InstructMessage.Type = CommandInstruct
+		msg.InstructMessage.CommandContent = content.Command.GetContent()
+	case *agentv1.Instruct_ChromeDpInspect:
+		msg.InstructMessage.Type = ChromeDpInspectInstruct
+		msg.InstructMessage.ChromeDpInspectUrl = content.ChromeDpInspect.GetUrl()
+	case *agentv1.Instruct_Dns:
+		msg.InstructMessage.Type = DnsInstruct
+		msg.InstructMessage.DnsInspectDomain = content.Dns.GetDomain()
+	case *agentv1.Instruct_Http:
+		msg.InstructMessage.Type = HttpInstruct
+		msg.InstructMessage.HttpInspectUrl = content.Http.GetUrl()
+	case *agentv1.Instruct_Icmp:
+		msg.InstructMessage.Type = IcmpInstruct
+		msg.InstructMessage.IcmpInspectAddr = content.Icmp.GetAddr()
+		msg.InstructMessage.IcmpCount = content.Icmp.GetCount()
+	case *agentv1.Instruct_FileGet:
+		msg.InstructMessage.Type = FileGetInstruct
+		msg.InstructMessage.FilePath = content.FileGet.GetPath()
+	case *agentv1.Instruct_FilePut:
+		msg.InstructMessage.Type = FilePutInstruct
+		msg.InstructMessage.FilePath = content.FilePut.GetPath()
+		msg.InstructMessage.FileSize = content.FilePut.GetSize()
+		msg.InstructMessage.FileSha256 = content.FilePut.GetSha256()
+	}
+	
+	return msg
+}
+
+func toProtoClientMessage(msg ClientMessage) *agentv1.ClientMessage {
+	pb := &agentv1.ClientMessage{
+		Type:               agentv1.ClientMessageType(msg.Type),
+		Message:            msg.Message,
+		ChromeDpScreenShot: msg.ChromeDpScreenShot,
+		Trace:              msg.Trace,
+		Hello:              toProtoAgentHello(msg.Hello),
+		Inventory:          toProtoHostInventory(msg.Inventory),
+		Metrics:            toProtoHostMetrics(msg.Metrics),
+		FileChunk:          toProtoFileChunk(msg.FileChunk),
+		Shell:              toProtoShellFrame(msg.Shell),
+		Tunnel:             toProtoTunnelFrame(msg.Tunnel),
+		ChannelWindow:      toProtoChannelWindow(msg.ChannelWindow),
+		Fragment:           toProtoFragment(msg.Fragment),
+		Truncated:          msg.Truncated,
+	}
+	
+	instruct := msg.InstructMessage
+	if instruct.Uuid == "" && instruct.Type == 0 {
+		return pb
+	}
+	
+	pb.InstructReply = &agentv1.InstructReply{
+		Uuid:   instruct.Uuid,
+		Result: instruct.Result,
+		ErrMsg: instruct.ErrMsg,
+	}
+	
+	switch instruct.Type {
+	case CommandInstruct:
+		pb.InstructReply.Reply = &agentv1.InstructReply_Command{Command: &agentv1.CommandReply{
+			Content: instruct.CommandContent,
+			Output:  instruct.CommandReply,
+		}}
+	case ChromeDpInspectInstruct:
+		reply := &agentv1.ChromeDpInspectReply{
+			Url:             instruct.ChromeDpInspectUrl,
+			HomePageInspect: toProtoUrlInspectInfo(instruct.ChromeDpInspectReply.HomePageInspect),
+		}
+		for _, info := range instruct.ChromeDpInspectReply.ResourcePageInspect {
+			reply.ResourcePageInspect = append(reply.ResourcePageInspect, toProtoUrlInspectInfo(info))
+		}
+		pb.InstructReply.Reply = &agentv1.InstructReply_ChromeDpInspect{ChromeDpInspect: reply}
+	case DnsInstruct:
+		pb.InstructReply.Reply = &agentv1.InstructReply_Dns{Dns: &agentv1.DnsReply{
+			Domain: instruct.DnsInspectDomain,
+			Addrs:  instruct.DnsInspectReply,
+		}}
+	case HttpInstruct:
+		pb.InstructReply.Reply = &agentv1.InstructReply_Http{Http: &agentv1.HttpReply{
+			Url:        instruct.HttpInspectUrl,
+			StatusCode: int32(instruct.HttpInspectReply.StatusCode),
+			Response:   instruct.HttpInspectReply.Response,
+			Latency:    instruct.HttpInspectReply.Latency,
+		}}
+	case IcmpInstruct:
+		pb.InstructReply.Reply = &agentv1.InstructReply_Icmp{Icmp: &agentv1.IcmpReply{
+			Addr:       instruct.IcmpInspectAddr,
+			Statistics: toProtoIcmpStatistics(instruct.IcmpInspectReply),
+		}}
+	case FileGetInstruct:
+		pb.InstructReply.Reply = &agentv1.InstructReply_FileGet{FileGet: toProtoFileReply(instruct)}
+	case FilePutInstruct:
+		pb.InstructReply.Reply = &agentv1.InstructReply_FilePut{FilePut: toProtoFileReply(instruct)}
+	}
+	
+	return pb
+}
+
+func fromProtoClientMessage(pb *agentv1.ClientMessage) ClientMessage {
+	msg := ClientMessage{
+		Type:               ClientMessageType(pb.GetType()),
+		Message:            pb.GetMessage(),
+		ChromeDpScreenShot: pb.GetChromeDpScreenShot(),
+		Trace:              pb.GetTrace(),
+		Hello:              fromProtoAgentHello(pb.GetHello()),
+		Inventory:          fromProtoHostInventory(pb.GetInventory()),
+		Metrics:            fromProtoHostMetrics(pb.GetMetrics()),
+		FileChunk:          fromProtoFileChunk(pb.GetFileChunk()),
+		Shell:              fromProtoShellFrame(pb.GetShell()),
+		Tunnel:             fromProtoTunnelFrame(pb.GetTunnel()),
+		ChannelWindow:      fromProtoChannelWindow(pb.GetChannelWindow()),
+		Fragment:           fromProtoFragment(pb.GetFragment()),
+		Truncated:          pb.GetTruncated(),
+	}
+	
+	reply := pb.GetInstructReply()
+	if reply == nil {
+		return msg
+	}
+	
+	msg.InstructMessage.Uuid = reply.GetUuid()
+	msg.InstructMessage.Result = reply.GetResult()
+	msg.InstructMessage.ErrMsg = reply.GetErrMsg()
+	
+	switch r := reply.GetReply().(type) {
+	case *agentv1.InstructReply_Command:
+		msg.InstructMessage.Type = CommandInstruct
+		msg.InstructMessage.CommandContent = r.Command.GetContent()
+		msg.InstructMessage.CommandReply = r.Command.GetOutput()
+	case *agentv1.InstructReply_ChromeDpInspect:
+		msg.InstructMessage.Type = ChromeDpInspectInstruct
+		msg.InstructMessage.ChromeDpInspectUrl = r.ChromeDpInspect.GetUrl()
+		msg.InstructMessage.ChromeDpInspectReply = InspectSinglePageResp{
+			Url:             r.ChromeDpInspect.GetUrl(),
+			HomePageInspect: fromProtoUrlInspectInfo(r.ChromeDpInspect.GetHomePageInspect()),
+		}
+		for _, info := range r.ChromeDpInspect.GetResourcePageInspect() {
+			msg.InstructMessage.ChromeDpInspectReply.ResourcePageInspect = append(msg.InstructMessage.ChromeDpInspectReply.ResourcePageInspect, fromProtoUrlInspectInfo(info))
+		}
+	case *agentv1.InstructReply_Dns:
+		msg.InstructMessage.Type = DnsInstruct
+		msg.InstructMessage.DnsInspectDomain = r.Dns.GetDomain()
+		msg.InstructMessage.DnsInspectReply = r.Dns.GetAddrs()
+	case *agentv1.InstructReply_Http:
+		msg.InstructMessage.Type = HttpInstruct
+		msg.InstructMessage.HttpInspectUrl = r.Http.GetUrl()
+		msg.InstructMessage.HttpInspectReply = HttpInspectReply{
+			Url:        r.Http.GetUrl(),
+			StatusCode: int(r.Http.GetStatusCode()),
+			Response:   r.Http.GetResponse(),
+			Latency:    r.Http.GetLatency(),
+		}
+	case *agentv1.InstructReply_Icmp:
+		msg.InstructMessage.Type = IcmpInstruct
+		msg.InstructMessage.IcmpInspectAddr = r.Icmp.GetAddr()
+		msg.InstructMessage.IcmpInspectReply = fromProtoIcmpStatistics(r.Icmp.GetStatistics())
+	case *agentv1.InstructReply_FileGet:
+		msg.InstructMessage.Type = FileGetInstruct
+		msg.InstructMessage.FilePath = r.FileGet.GetPath()
+		msg.InstructMessage.FileSize = r.FileGet.GetSize()
+		msg.InstructMessage.FileSha256 = r.FileGet.GetSha256()
+	case *agentv1.InstructReply_FilePut:
+		msg.InstructMessage.Type = FilePutInstruct
+		msg.InstructMessage.FilePath = r.FilePut.GetPath()
+		msg.InstructMessage.FileSize = r.FilePut.GetSize()
+		msg.InstructMessage.FileSha256 = r.FilePut.GetSha256()
+	}
+	
+	return msg
+}
+
+func toProtoAgentHello(hello *AgentHello) *agentv1.AgentHello {
+	if hello == nil {
+		return nil
+	}
+	
+	pb := &agentv1.AgentHello{
+		Version:   hello.Version,
+		GitCommit: hello.GitCommit,
+		BuildTime: hello.BuildTime,
+		GoVersion: hello.GoVersion,
+		Os:        hello.Os,
+		Arch:      hello.Arch,
+	}
+	
+	for _, capability := range hello.Capabilities {
+		pb.Capabilities = append(pb.Capabilities, &agentv1.InstructCapability{
+			Type:          int32(capability.Type),
+			Timeout:       capability.Timeout,
+			MaxOutputSize: capability.MaxOutputSize,
+		})
+	}
+	
+	return pb
+}
+
+func fromProtoAgentHello(pb *agentv1.AgentHello) *AgentHello {
+	if pb == nil {
+		return nil
+	}
+	
+	hello := &AgentHello{
+		Version:   pb.GetVersion(),
+		GitCommit: pb.GetGitCommit(),
+		BuildTime: pb.GetBuildTime(),
+		GoVersion: pb.GetGoVersion(),
+		Os:        pb.GetOs(),
+		Arch:      pb.GetArch(),
+	}
+	
+	for _, capability := range pb.GetCapabilities() {
+		hello.Capabilities = append(hello.Capabilities, InstructCapability{
+			Type:          InstructType(capability.GetType()),
+			Timeout:       capability.GetTimeout(),
+			MaxOutputSize: capability.GetMaxOutputSize(),
+		})
+	}
+	
+	return hello
+}
+
+func toProtoHostInventory(inventory *HostInventory) *agentv1.HostInventory {
+	if inventory == nil {
+		return nil
+	}
+	
+	pb := &agentv1.HostInventory{
+		Hostname:        inventory.Hostname,
+		Os:              inventory.Os,
+		Platform:        inventory.Platform,
+		PlatformVersion: inventory.PlatformVersion,
+		KernelVersion:   inventory.KernelVersion,
+		Arch:            inventory.Arch,
+		CpuModel:        inventory.CpuModel,
+		CpuCount:        inventory.CpuCount,
+		MemoryTotal:     inventory.MemoryTotal,
+		BootTime:        inventory.BootTime,
+		Timezone:        inventory.Timezone,
+		CollectTime:     inventory.CollectTime,
+	}
+	
+	for _, d := range inventory.Disks {
+		pb.Disks = append(pb.Disks, &agentv1.DiskInfo{
+			Device:     d.Device,
+			Mountpoint: d.Mountpoint,
+			Fstype:     d.Fstype,
+			Total:      d.Total,
+			Used:       d.Used,
+		})
+	}
+	
+	for _, i := range inventory.Interfaces {
+		pb.Interfaces = append(pb.Interfaces, &agentv1.NetInterface{
+			Name:         i.Name,
+			HardwareAddr: i.HardwareAddr,
+			Addrs:        i.Addrs,
+			Mtu:          i.Mtu,
+		})
+	}
+	
+	return pb
+}
+
+func fromProtoHostInventory(pb *agentv1.HostInventory) *HostInventory {
+	if pb == nil {
+		return nil
+	}
+	
+	inventory := &HostInventory{
+		Hostname:        pb.GetHostname(),
+		Os:              pb.GetOs(),
+		Platform:        pb.GetPlatform(),
+		PlatformVersion: pb.GetPlatformVersion(),
+		KernelVersion:   pb.GetKernelVersion(),
+		Arch:            pb.GetArch(),
+		CpuModel:        pb.GetCpuModel(),
+		CpuCount:        pb.GetCpuCount(),
+		MemoryTotal:     pb.GetMemoryTotal(),
+		BootTime:        pb.GetBootTime(),
+		Timezone:        pb.GetTimezone(),
+		CollectTime:     pb.GetCollectTime(),
+	}
+	
+	for _, d := range pb.GetDisks() {
+		inventory.Disks = append(inventory.Disks, DiskInfo{
+			Device:     d.GetDevice(),
+			Mountpoint: d.GetMountpoint(),
+			Fstype:     d.GetFstype(),
+			Total:      d.GetTotal(),
+			Used:       d.GetUsed(),
+		})
+	}
+	
+	for _, i := range pb.GetInterfaces() {
+		inventory.Interfaces = append(inventory.Interfaces, NetInterface{
+			Name:         i.GetName(),
+			HardwareAddr: i.GetHardwareAddr(),
+			Addrs:        i.GetAddrs(),
+			Mtu:          i.GetMtu(),
+		})
+	}
+	
+	return inventory
+}
+
+func toProtoHostMetrics(metrics *HostMetrics) *agentv1.HostMetrics {
+	if metrics == nil {
+		return nil
+	}
+	
+	pb := &agentv1.HostMetrics{
+		CollectTime:   metrics.CollectTime,
+		CpuPercent:    metrics.CpuPercent,
+		Load1:         metrics.Load1,
+		Load5:         metrics.Load5,
+		Load15:        metrics.Load15,
+		MemoryUsed:    metrics.MemoryUsed,
+		MemoryPercent: metrics.MemoryPercent,
+		SwapPercent:   metrics.SwapPercent,
+		DiskReadRate:  metrics.DiskReadRate,
+		DiskWriteRate: metrics.DiskWriteRate,
+		NetRecvRate:   metrics.NetRecvRate,
+		NetSentRate:   metrics.NetSentRate,
+		NetErrIn:      metrics.NetErrIn,
+		NetErrOut:     metrics.NetErrOut,
+		NetDropIn:     metrics.NetDropIn,
+		NetDropOut:    metrics.NetDropOut,
+	}
+	
+	for _, d := range metrics.Disks {
+		pb.Disks = append(pb.Disks, &agentv1.DiskUsage{
+			Mountpoint:  d.Mountpoint,
+			Total:       d.Total,
+			Used:        d.Used,
+			UsedPercent: d.UsedPercent,
+		})
+	}
+	
+	for _, p := range metrics.TopProcesses {
+		pb.TopProcesses = append(pb.TopProcesses, &agentv1.ProcessMetrics{
+			Pid:           p.Pid,
+			Name:          p.Name,
+			CpuPercent:    p.CpuPercent,
+			MemoryPercent: p.MemoryPercent,
+		})
+	}
+	
+	return pb
+}
+
+func fromProtoHostMetrics(pb *agentv1.HostMetrics) *HostMetrics {
+	if pb == nil {
+		return nil
+	}
+	
+	metrics := &HostMetrics{
+		CollectTime:   pb.GetCollectTime(),
+		CpuPercent:    pb.GetCpuPercent(),
+		Load1:         pb.GetLoad1(),
+		Load5:         pb.GetLoad5(),
+		Load15:        pb.GetLoad15(),
+		MemoryUsed:    pb.GetMemoryUsed(),
+		MemoryPercent: pb.GetMemoryPercent(),
+		SwapPercent:   pb.GetSwapPercent(),
+		DiskReadRate:  pb.GetDiskReadRate(),
+		DiskWriteRate: pb.GetDiskWriteRate(),
+		NetRecvRate:   pb.GetNetRecvRate(),
+		NetSentRate:   pb.GetNetSentRate(),
+		NetErrIn:      pb.GetNetErrIn(),
+		NetErrOut:     pb.GetNetErrOut(),
+		NetDropIn:     pb.GetNetDropIn(),
+		NetDropOut:    pb.GetNetDropOut(),
+	}
+	
+	for _, d := range pb.GetDisks() {
+		metrics.Disks = append(metrics.Disks, DiskUsage{
+			Mountpoint:  d.GetMountpoint(),
+			Total:       d.GetTotal(),
+			Used:        d.GetUsed(),
+			UsedPercent: d.GetUsedPercent(),
+		})
+	}
+	
+	for _, p := range pb.GetTopProcesses() {
+		metrics.TopProcesses = append(metrics.TopProcesses, ProcessMetrics{
+			Pid:           p.GetPid(),
+			Name:          p.GetName(),
+			CpuPercent:    p.GetCpuPercent(),
+			MemoryPercent: p.GetMemoryPercent(),
+		})
+	}
+	
+	return metrics
+}
+
+func toProtoUrlInspectInfo(info UrlInspectInfo) *agentv1.UrlInspectInfo {
+	pb := &agentv1.UrlInspectInfo{
+		Url:           info.Url,
+		RemoteAddr:    info.RemoteAddr,
+		RemotePort:    info.RemotePort,
+		Status:        info.Status,
+		Protocol:      info.Protocol,
+		FromDiskCache: info.FromDiskCache,
+		SecurityState: string(info.SecurityState),
+	}
+	
+	if info.Timing != nil {
+		pb.Timing, _ = json.Marshal(info.Timing)
+	}
+	
+	if info.SecurityDetails != nil {
+		pb.SecurityDetails, _ = json.Marshal(info.SecurityDetails)
+	}
+	
+	return pb
+}
+
+func fromProtoUrlInspectInfo(pb *agentv1.UrlInspectInfo) UrlInspectInfo {
+	info := UrlInspectInfo{
+		Url:           pb.GetUrl(),
+		RemoteAddr:    pb.GetRemoteAddr(),
+		RemotePort:    pb.GetRemotePort(),
+		Status:        pb.GetStatus(),
+		Protocol:      pb.GetProtocol(),
+		FromDiskCache: pb.GetFromDiskCache(),
+		SecurityState: security.State(pb.GetSecurityState()),
+	}
+	
+	if len(pb.GetTiming()) > 0 {
+		timing := &network.ResourceTiming{}
+		if json.Unmarshal(pb.GetTiming(), timing) == nil {
+			info.Timing = timing
+		}
+	}
+	
+	if len(pb.GetSecurityDetails()) > 0 {
+		securityDetails := &network.SecurityDetails{}
+		if json.Unmarshal(pb.GetSecurityDetails(), securityDetails) == nil {
+			info.SecurityDetails = securityDetails
+		}
+	}
+	
+	return info
+}
+
+func toProtoIcmpStatistics(statistics *probing.Statistics) *agentv1.IcmpStatistics {
+	if statistics == nil {
+		return nil
+	}
+	
+	pb := &agentv1.IcmpStatistics{
+		PacketsRecv:           int64(statistics.PacketsRecv),
+		PacketsSent:           int64(statistics.PacketsSent),
+		PacketsRecvDuplicates: int64(statistics.PacketsRecvDuplicates),
+		PacketLoss:            statistics.PacketLoss,
+		Addr:                  statistics.Addr,
+		MinRtt:                int64(statistics.MinRtt),
+		MaxRtt:                int64(statistics.MaxRtt),
+		AvgRtt:                int64(statistics.AvgRtt),
+		StdDevRtt:             int64(statistics.StdDevRtt),
+	}
+	
+	if statistics.IPAddr != nil {
+		pb.IpAddr = statistics.IPAddr.String()
+	}
+	
+	for _, rtt := range statistics.Rtts {
+		pb.Rtts = append(pb.Rtts, int64(rtt))
+	}
+	
+	return pb
+}
+
+func fromProtoIcmpStatistics(pb *agentv1.IcmpStatistics) *probing.Statistics {
+	if pb == nil {
+		return nil
+	}
+	
+	statistics := &probing.Statistics{
+		PacketsRecv:           int(pb.GetPacketsRecv()),
+		PacketsSent:           int(pb.GetPacketsSent()),
+		PacketsRecvDuplicates: int(pb.GetPacketsRecvDuplicates()),
+		PacketLoss:            pb.GetPacketLoss(),
+		Addr:                  pb.GetAddr(),
+		MinRtt:                time.Duration(pb.GetMinRtt()),
+		MaxRtt:                time.Duration(pb.GetMaxRtt()),
+		AvgRtt:                time.Duration(pb.GetAvgRtt()),
+		StdDevRtt:             time.Duration(pb.GetStdDevRtt()),
+	}
+	
+	if pb.GetIpAddr() != "" {
+		ip, zone, _ := strings.Cut(pb.GetIpAddr(), "%")
+		statistics.IPAddr = &net.IPAddr{IP: net.ParseIP(ip), Zone: zone}
+	}
+	
+	for _, rtt := range pb.GetRtts() {
+		statistics.Rtts = append(statistics.Rtts, time.Duration(rtt))
+	}
+	
+	return statistics
+}
+
+func toProtoFileChunk(chunk *FileChunk) *agentv1.FileChunk {
+	if chunk == nil {
+		return nil
+	}
+	
+	return &agentv1.FileChunk{
+		Uuid:   chunk.Uuid,
+		Offset: chunk.Offset,
+		Data:   chunk.Data,
+		Eof:    chunk.Eof,
+	}
+}
+
+func fromProtoFileChunk(pb *agentv1.FileChunk) *FileChunk {
+	if pb == nil {
+		return nil
+	}
+	
+	return &FileChunk{
+		Uuid:   pb.GetUuid(),
+		Offset: pb.GetOffset(),
+		Data:   pb.GetData(),
+		Eof:    pb.GetEof(),
+	}
+}
+
+func toProtoFileReply(instruct InstructMessage) *agentv1.FileReply {
+	return &agentv1.FileReply{
+		Path:   instruct.FilePath,
+		Size:   instruct.FileSize,
+		Sha256: instruct.FileSha256,
+	}
+}
+
+func toProtoShellFrame(frame *ShellFrame) *agentv1.ShellFrame {
+	if frame == nil {
+		return nil
+	}
+	
+	return &agentv1.ShellFrame{
+		SessionId: frame.SessionId,
+		Type:      agentv1.ShellFrameType(frame.Type),
+		Data:      frame.Data,
+		Cols:      frame.Cols,
+		Rows:      frame.Rows,
+		Reason:    frame.Reason,
+	}
+}
+
+func fromProtoShellFrame(pb *agentv1.ShellFrame) *ShellFrame {
+	if pb == nil {
+		return nil
+	}
+	
+	return &ShellFrame{
+		SessionId: pb.GetSessionId(),
+		Type:      ShellFrameType(pb.GetType()),
+		Data:      pb.GetData(),
+		Cols:      pb.GetCols(),
+		Rows:      pb.GetRows(),
+		Reason:    pb.GetReason(),
+	}
+}
+
+func toProtoTunnelFrame(frame *TunnelFrame) *agentv1.TunnelFrame {
+	if frame == nil {
+		return nil
+	}
+	
+	return &agentv1.TunnelFrame{
+		StreamId: frame.StreamId,
+		Type:     agentv1.TunnelFrameType(frame.Type),
+		Addr:     frame.Addr,
+		Data:     frame.Data,
+		Window:   frame.Window,
+		Reason:   frame.Reason,
+	}
+}
+
+func fromProtoTunnelFrame(pb *agentv1.TunnelFrame) *TunnelFrame {
+	if pb == nil {
+		return nil
+	}
+	
+	return &TunnelFrame{
+		StreamId: pb.GetStreamId(),
+		Type:     TunnelFrameType(pb.GetType()),
+		Addr:     pb.GetAddr(),
+		Data:     pb.GetData(),
+		Window:   pb.GetWindow(),
+		Reason:   pb.GetReason(),
+	}
+}
+
+func toProtoChannelWindow(window *ChannelWindow) *agentv1.ChannelWindow {
+	if window == nil {
+		return nil
+	}
+	
+	return &agentv1.ChannelWindow{
+		Channel: agentv1.Channel(window.Channel),
+		Window:  window.Window,
+	}
+}
+
+func fromProtoChannelWindow(pb *agentv1.ChannelWindow) *ChannelWindow {
+	if pb == nil {
+		return nil
+	}
+	
+	return &ChannelWindow{
+		Channel: Channel(pb.GetChannel()),
+		Window:  pb.GetWindow(),
+	}
+}
+
+func toProtoFragment(fragment *Fragment) *agentv1.Fragment {
+	if fragment == nil {
+		return nil
+	}
+	
+	return &agentv1.Fragment{
+		Id:          fragment.Id,
+		Seq:         fragment.Seq,
+		Last:        fragment.Last,
+		MessageType: int32(fragment.MessageType),
+		Size:        fragment.Size,
+		Data:        fragment.Data,
+	}
+}
+
+func fromProtoFragment(pb *agentv1.Fragment) *Fragment {
+	if pb == nil {
+		return nil
+	}
+	
+	return &Fragment{
+		Id:          pb.GetId(),
+		Seq:         pb.GetSeq(),
+		Last:        pb.GetLast(),
+		MessageType: int(pb.GetMessageType()),
+		Size:        pb.GetSize(),
+		Data:        pb.GetData(),
+	}
+}
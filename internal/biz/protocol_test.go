@@ -0,0 +1,175 @@
+package biz
+
+import (
+	"github.com/gorilla/websocket"
+	"github.com/prometheus-community/pro-bing"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestServiceMessage_ProtoRoundTrip(t *testing.T) {
+	msg := ServiceMessage{
+		Type: ServiceInstruct,
+		InstructMessage: InstructMessage{
+			Uuid:             "uuid",
+			Type:             DnsInstruct,
+			Timeout:          30,
+			DnsInspectDomain: "www.baidu.com",
+		},
+		Trace: map[string]string{"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+	}
+	
+	messageType, b, err := EncodeServiceMessage(ProtocolProto, msg)
+	assert.NoError(t, err)
+	assert.Equal(t, websocket.BinaryMessage, messageType)
+	
+	decoded, err := DecodeServiceMessage(ProtocolProto, messageType, b)
+	assert.NoError(t, err)
+	assert.Equal(t, msg, decoded)
+	
+	msg.InstructMessage = InstructMessage{Uuid: "uuid", Type: IcmpInstruct, IcmpInspectAddr: "127.0.0.1", IcmpCount: 10}
+	messageType, b, err = EncodeServiceMessage(ProtocolProto, msg)
+	assert.NoError(t, err)
+	
+	decoded, err = DecodeServiceMessage(ProtocolProto, messageType, b)
+	assert.NoError(t, err)
+	assert.Equal(t, msg, decoded)
+}
+
+func TestServiceMessage_JsonFallback(t *testing.T) {
+	msg := ServiceMessage{
+		Type: ServiceInstruct,
+		InstructMessage: InstructMessage{
+			Uuid:           "uuid",
+			Type:           CommandInstruct,
+			CommandContent: "uptime",
+		},
+	}
+	
+	messageType, b, err := EncodeServiceMessage("", msg)
+	assert.NoError(t, err)
+	assert.Equal(t, websocket.TextMessage, messageType)
+	
+	// 协商为 protobuf 协议后仍能解析 JSON text frame
+	decoded, err := DecodeServiceMessage(ProtocolProto, messageType, b)
+	assert.NoError(t, err)
+	assert.Equal(t, msg, decoded)
+}
+
+func TestClientMessage_ProtoRoundTrip(t *testing.T) {
+	msg := ClientMessage{
+		Type: ClientInstructReply,
+		InstructMessage: InstructMessage{
+			Uuid:            "uuid",
+			Type:            IcmpInstruct,
+			IcmpInspectAddr: "127.0.0.1",
+			IcmpInspectReply: &probing.Statistics{
+				PacketsRecv: 4,
+				PacketsSent: 4,
+				IPAddr:      &net.IPAddr{IP: net.ParseIP("127.0.0.1")},
+				Addr:        "127.0.0.1",
+				Rtts:        []time.Duration{time.Millisecond, 2 * time.Millisecond},
+				MinRtt:      time.Millisecond,
+				MaxRtt:      2 * time.Millisecond,
+			},
+			Result: true,
+		},
+	}
+	
+	messageType, b, err := EncodeClientMessage(ProtocolProto, msg)
+	assert.NoError(t, err)
+	
+	decoded, err := DecodeClientMessage(ProtocolProto, messageType, b)
+	assert.NoError(t, err)
+	assert.Equal(t, msg.InstructMessage.Uuid, decoded.InstructMessage.Uuid)
+	assert.Equal(t, IcmpInstruct, decoded.InstructMessage.Type)
+	assert.Equal(t, msg.InstructMessage.IcmpInspectReply.Rtts, decoded.InstructMessage.IcmpInspectReply.Rtts)
+	assert.Equal(t, "127.0.0.1", decoded.InstructMessage.IcmpInspectReply.IPAddr.String())
+}
+
+func TestEncodeServiceMessage(t *testing.T) {
+	msg := ServiceMessage{
+		Type:            ServiceInstruct,
+		InstructMessage: InstructMessage{Uuid: "uuid", Type: HttpInstruct, HttpInspectUrl: "https://www.baidu.com"},
+		Trace:           map[string]string{"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+	}
+	
+	for _, subprotocol := range []string{"", ProtocolProto} {
+		messageType, b, err := EncodeServiceMessage(subprotocol, msg)
+		assert.NoError(t, err)
+		
+		decoded, err := DecodeServiceMessage(subprotocol, messageType, b)
+		assert.NoError(t, err)
+		assert.Equal(t, HttpInstruct, decoded.InstructMessage.Type)
+		assert.Equal(t, "https://www.baidu.com", decoded.InstructMessage.HttpInspectUrl)
+		assert.Equal(t, msg.Trace, decoded.Trace)
+	}
+}
+
+func TestClientMessage_HelloRoundTrip(t *testing.T) {
+	msg := ClientMessage{
+		Type: ClientHello,
+		Hello: &AgentHello{
+			Version: "v1.0.0",
+			Os:      "linux",
+			Arch:    "amd64",
+			Capabilities: []InstructCapability{
+				{Type: CommandInstruct},
+				{Type: IcmpInstruct, Timeout: 5},
+			},
+		},
+	}
+	
+	messageType, b, err := EncodeClientMessage(ProtocolProto, msg)
+	assert.NoError(t, err)
+	
+	decoded, err := DecodeClientMessage(ProtocolProto, messageType, b)
+	assert.NoError(t, err)
+	assert.Equal(t, msg, decoded)
+	
+	instance := Instance{AgentInfo: decoded.Hello}
+	assert.True(t, instance.SupportInstruct(IcmpInstruct))
+	assert.False(t, instance.SupportInstruct(ChromeDpInspectInstruct))
+	
+	// 旧版本 soldier 未上报能力信息
+	instance = Instance{}
+	assert.True(t, instance.SupportInstruct(ChromeDpInspectInstruct))
+	assert.True(t, instance.SupportInstruct(IcmpInstruct))
+	assert.False(t, instance.SupportInstruct(FileGetInstruct))
+	assert.False(t, instance.SupportInstruct(ShellInstruct))
+	assert.False(t, instance.SupportInstruct(TunnelInstruct))
+}
+
+func TestServiceMessage_FilePutRoundTrip(t *testing.T) {
+	msg := ServiceMessage{
+		Type: ServiceInstruct,
+		InstructMessage: InstructMessage{
+			Uuid:       "uuid",
+			Type:       FilePutInstruct,
+			FilePath:   "/tmp/file",
+			FileSize:   3,
+			FileSha256: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		},
+	}
+	
+	messageType, b, err := EncodeServiceMessage(ProtocolProto, msg)
+	assert.NoError(t, err)
+	
+	decoded, err := DecodeServiceMessage(ProtocolProto, messageType, b)
+	assert.NoError(t, err)
+	assert.Equal(t, msg, decoded)
+	
+	chunk := ServiceMessage{
+		Type:      ServiceFileChunk,
+		FileChunk: &FileChunk{Uuid: "uuid", Offset: 0, Data: []byte("abc"), Eof: true},
+	}
+	
+	messageType, b, err = EncodeServiceMessage(ProtocolProto, chunk)
+	assert.NoError(t, err)
+	
+	decoded, err = DecodeServiceMessage(ProtocolProto, messageType, b)
+	assert.NoError(t, err)
+	assert.Equal(t, chunk, decoded)
+}
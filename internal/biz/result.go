@@ -0,0 +1,274 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/prometheus-community/pro-bing"
+	"go.uber.org/zap"
+	"time"
+)
+
+// 探测结果的结构化存储
+//
+// DNS、HTTP、ICMP 及 ChromeDp 指令的结果除写入 instruct.reply 外，另写入 instruct_result：
+// 常用的查询条件 (目标、状态码、耗时、丢包率、解析地址) 提取为类型化字段，完整结果以 JSON 保存在 detail 中，
+// detail 的格式由 SchemaVersion 标识，格式变化时递增，读取方按版本解析
+
+const ResultSchemaVersion = 1
+
+var ErrResultNotFound = errors.New("探测结果不存在")
+
+type InstructResult struct {
+	InstructUuid  string          `json:"instructUuid" gorm:"primaryKey"`
+	InstanceUuid  string          `json:"instanceUuid"`
+	OrgUuid       string          `json:"orgUuid"`
+	GroupUuid     string          `json:"groupUuid"`
+	InstanceName  string          `json:"instanceName"`
+	BatchUuid     string          `json:"batchUuid,omitempty"`
+	Type          InstructType    `json:"type"`
+	SchemaVersion int32           `json:"schemaVersion"`
+	Target        string          `json:"target"`                                 // 探测目标: 域名、url 或地址
+	Result        int32           `json:"result"`                                 // 1 成功，-1 失败
+	StatusCode    int32           `json:"statusCode,omitempty"`                   // HTTP 状态码，ChromeDp 主页面状态码
+	Latency       float64         `json:"latency,omitempty"`                      // 毫秒: HTTP 请求耗时，ICMP 平均往返时间，ChromeDp 主页面响应耗时
+	PacketLoss    float64         `json:"packetLoss,omitempty"`                   // ICMP 丢包率 (%)
+	Addrs         []string        `json:"addrs,omitempty" gorm:"serializer:json"` // DNS 解析结果，ICMP 及 ChromeDp 主页面的远端地址
+	Detail        json.RawMessage `json:"detail,omitempty"`
+	ErrMsg        string          `json:"errMsg,omitempty"`
+	CreateTime    int64           `json:"createTime"` // 收到结果的时间
+}
+
+func (instructResult *InstructResult) TableName() string {
+	return "instruct_result"
+}
+
+// detail 的格式 (SchemaVersion 1)，ChromeDp 为 InspectSinglePageResp
+
+type HttpResultDetail struct {
+	Url          string  `json:"url"`
+	StatusCode   int     `json:"statusCode"`
+	Latency      float64 `json:"latency"`
+	ResponseSize int     `json:"responseSize"`
+}
+
+type DnsResultDetail struct {
+	Domain  string   `json:"domain"`
+	Answers []string `json:"answers"`
+}
+
+type IcmpResultDetail struct {
+	Addr           string    `json:"addr"`
+	IpAddr         string    `json:"ipAddr"`
+	PacketsSent    int       `json:"packetsSent"`
+	PacketsRecv    int       `json:"packetsRecv"`
+	PacketsRecvDup int       `json:"packetsRecvDup"`
+	PacketLoss     float64   `json:"packetLoss"`
+	MinRtt         float64   `json:"minRtt"` // 毫秒
+	AvgRtt         float64   `json:"avgRtt"`
+	MaxRtt         float64   `json:"maxRtt"`
+	StdDevRtt      float64   `json:"stdDevRtt"`
+	Rtts           []float64 `json:"rtts"`
+}
+
+// 查询探测结果的条件，为空的条件不过滤；按收到结果的时间倒序，分页游标与指令查询相同 (uuid 为指令 uuid)
+
+type ResultQuery struct {
+	OrgUuid      string
+	GroupUuid    string
+	InstanceName string
+	BatchUuid    string
+	Type         InstructType
+	Target       string
+	Result       *int32
+	MinStatus    int32 // 状态码范围 [MinStatus, MaxStatus]
+	MaxStatus    int32
+	MinLatency   float64 // 耗时不小于该值 (毫秒)
+	StartTime    int64   // 时间范围 [StartTime, EndTime)
+	EndTime      int64
+	After        *InstructCursor
+	Limit        int
+}
+
+type ResultRepo interface {
+	// 写入探测结果，组织、分组、实例名及批次取自指令记录
+	SaveResult(ctx context.Context, result InstructResult) error
+	QueryResults(ctx context.Context, query ResultQuery) ([]InstructResult, error)
+	GetResult(ctx context.Context, instructUuid string) (InstructResult, error)
+	// 删除分组下的探测结果，每次最多 limit 条，返回删除的数量
+	DeleteGroupResults(ctx context.Context, orgUuid, groupUuid string, limit int) (int64, error)
+}
+
+type ResultUseCase struct {
+	resultRepo ResultRepo
+	logger     *zap.Logger
+}
+
+func NewResultUseCase(resultRepo ResultRepo, logger *zap.Logger) *ResultUseCase {
+	return &ResultUseCase{
+		resultRepo: resultRepo,
+		logger:     logger,
+	}
+}
+
+// 是否为需要结构化存储结果的探测指令
+
+func IsProbeInstruct(instructType InstructType) bool {
+	switch instructType {
+	case ChromeDpInspectInstruct, DnsInstruct, HttpInstruct, IcmpInstruct:
+		return true
+	}
+	return false
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// 将探测指令的结果转换为结构化结果，其它类型的指令返回 false
+
+func NewInstructResult(instanceUuid string, instruct InstructMessage, now int64) (InstructResult, bool) {
+	if !IsProbeInstruct(instruct.Type) {
+		return InstructResult{}, false
+	}
+	
+	result := InstructResult{
+		InstructUuid:  instruct.Uuid,
+		InstanceUuid:  instanceUuid,
+		Type:          instruct.Type,
+		SchemaVersion: ResultSchemaVersion,
+		Result:        1,
+		CreateTime:    now,
+	}
+	
+	switch instruct.Type {
+	case ChromeDpInspectInstruct:
+		result.Target = instruct.ChromeDpInspectUrl
+	case DnsInstruct:
+		result.Target = instruct.DnsInspectDomain
+	case HttpInstruct:
+		result.Target = instruct.HttpInspectUrl
+	case IcmpInstruct:
+		result.Target = instruct.IcmpInspectAddr
+	}
+	
+	if !instruct.Result {
+		result.Result = -1
+		result.ErrMsg = instruct.ErrMsg
+		return result, true
+	}
+	
+	var detail interface{}
+	switch instruct.Type {
+	case ChromeDpInspectInstruct:
+		home := instruct.ChromeDpInspectReply.HomePageInspect
+		result.StatusCode = int32(home.Status)
+		if home.Timing != nil {
+			result.Latency = home.Timing.ReceiveHeadersEnd
+		}
+		
+		if home.RemoteAddr != "" {
+			result.Addrs = []string{home.RemoteAddr}
+		}
+		detail = instruct.ChromeDpInspectReply
+	
+	case DnsInstruct:
+		result.Addrs = instruct.DnsInspectReply
+		detail = DnsResultDetail{Domain: instruct.DnsInspectDomain, Answers: instruct.DnsInspectReply}
+	
+	case HttpInstruct:
+		reply := instruct.HttpInspectReply
+		result.StatusCode = int32(reply.StatusCode)
+		result.Latency = reply.Latency
+		detail = HttpResultDetail{Url: reply.Url, StatusCode: reply.StatusCode, Latency: reply.Latency, ResponseSize: len(reply.Response)}
+	
+	case IcmpInstruct:
+		if instruct.IcmpInspectReply == nil {
+			break
+		}
+		icmpDetail := newIcmpResultDetail(instruct.IcmpInspectReply)
+		result.Latency = icmpDetail.AvgRtt
+		result.PacketLoss = icmpDetail.PacketLoss
+		if icmpDetail.IpAddr != "" {
+			result.Addrs = []string{icmpDetail.IpAddr}
+		}
+		detail = icmpDetail
+	}
+	
+	if detail != nil {
+		b, err := json.Marshal(detail)
+		if err == nil {
+			result.Detail = b
+		}
+	}
+	return result, true
+}
+
+func newIcmpResultDetail(statistics *probing.Statistics) IcmpResultDetail {
+	detail := IcmpResultDetail{
+		Addr:           statistics.Addr,
+		PacketsSent:    statistics.PacketsSent,
+		PacketsRecv:    statistics.PacketsRecv,
+		PacketsRecvDup: statistics.PacketsRecvDuplicates,
+		PacketLoss:     statistics.PacketLoss,
+		MinRtt:         durationMs(statistics.MinRtt),
+		AvgRtt:         durationMs(statistics.AvgRtt),
+		MaxRtt:         durationMs(statistics.MaxRtt),
+		StdDevRtt:      durationMs(statistics.StdDevRtt),
+		Rtts:           make([]float64, 0, len(statistics.Rtts)),
+	}
+	
+	if statistics.IPAddr != nil {
+		detail.IpAddr = statistics.IPAddr.String()
+	}
+	
+	for _, rtt := range statistics.Rtts {
+		detail.Rtts = append(detail.Rtts, durationMs(rtt))
+	}
+	return detail
+}
+
+// 分页查询探测结果，返回下一页的游标
+
+func (resultUseCase *ResultUseCase) QueryResults(ctx context.Context, query ResultQuery, cursor string) ([]InstructResult, string, error) {
+	if query.StartTime < 0 || query.EndTime < 0 || query.EndTime > 0 && query.EndTime <= query.StartTime {
+		return nil, "", fmt.Errorf("%w: 时间范围", ErrInvalidInstructQuery)
+	}
+	
+	if query.MinStatus < 0 || query.MaxStatus < 0 || query.MaxStatus > 0 && query.MaxStatus < query.MinStatus || query.MinLatency < 0 {
+		return nil, "", fmt.Errorf("%w: 状态码或耗时范围", ErrInvalidInstructQuery)
+	}
+	
+	if query.Type != 0 && !IsProbeInstruct(query.Type) {
+		return nil, "", fmt.Errorf("%w: 仅探测指令 (ChromeDp / Dns / Http / Icmp) 有结构化结果", ErrInvalidInstructQuery)
+	}
+	
+	if cursor != "" {
+		after, err := ParseInstructCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query.After = after
+	}
+	
+	if query.Limit <= 0 {
+		query.Limit = instructDefaultLimit
+	}
+	limit := min(query.Limit, instructMaxLimit)
+	
+	// 多查询一条以判断是否有下一页
+	query.Limit = limit + 1
+	results, err := resultUseCase.resultRepo.QueryResults(ctx, query)
+	if err != nil {
+		return nil, "", err
+	}
+	
+	if len(results) <= limit {
+		return results, "", nil
+	}
+	
+	results = results[:limit]
+	last := results[limit-1]
+	return results, InstructCursor{CreateTime: last.CreateTime, Uuid: last.InstructUuid}.String(), nil
+}
@@ -0,0 +1,100 @@
+package biz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/prometheus-community/pro-bing"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestNewInstructResult(t *testing.T) {
+	result, ok := NewInstructResult("i1", InstructMessage{Uuid: "a", Type: CommandInstruct, Result: true}, 100)
+	assert.False(t, ok)
+	
+	result, ok = NewInstructResult("i1", InstructMessage{
+		Uuid:             "a",
+		Type:             HttpInstruct,
+		HttpInspectUrl:   "https://example.com",
+		HttpInspectReply: HttpInspectReply{Url: "https://example.com", StatusCode: 502, Response: []byte("bad gateway"), Latency: 12.5},
+		Result:           true,
+	}, 100)
+	assert.True(t, ok)
+	assert.Equal(t, "https://example.com", result.Target)
+	assert.Equal(t, int32(1), result.Result)
+	assert.Equal(t, int32(502), result.StatusCode)
+	assert.Equal(t, 12.5, result.Latency)
+	assert.Equal(t, int32(ResultSchemaVersion), result.SchemaVersion)
+	
+	var httpDetail HttpResultDetail
+	assert.NoError(t, json.Unmarshal(result.Detail, &httpDetail))
+	assert.Equal(t, len("bad gateway"), httpDetail.ResponseSize)
+	
+	result, ok = NewInstructResult("i1", InstructMessage{
+		Uuid:            "b",
+		Type:            IcmpInstruct,
+		IcmpInspectAddr: "example.com",
+		IcmpInspectReply: &probing.Statistics{
+			Addr:        "example.com",
+			IPAddr:      &net.IPAddr{IP: net.ParseIP("192.0.2.1")},
+			PacketsSent: 4,
+			PacketsRecv: 3,
+			PacketLoss:  25,
+			AvgRtt:      1500 * time.Microsecond,
+			Rtts:        []time.Duration{time.Millisecond, 2 * time.Millisecond},
+		},
+		Result: true,
+	}, 100)
+	assert.True(t, ok)
+	assert.Equal(t, 1.5, result.Latency)
+	assert.Equal(t, float64(25), result.PacketLoss)
+	assert.Equal(t, []string{"192.0.2.1"}, result.Addrs)
+	
+	var icmpDetail IcmpResultDetail
+	assert.NoError(t, json.Unmarshal(result.Detail, &icmpDetail))
+	assert.Equal(t, []float64{1, 2}, icmpDetail.Rtts)
+	
+	result, ok = NewInstructResult("i1", InstructMessage{Uuid: "c", Type: DnsInstruct, DnsInspectDomain: "example.com", Result: false, ErrMsg: "no such host"}, 100)
+	assert.True(t, ok)
+	assert.Equal(t, "example.com", result.Target)
+	assert.Equal(t, int32(-1), result.Result)
+	assert.Equal(t, "no such host", result.ErrMsg)
+	assert.Empty(t, result.Detail)
+}
+
+// 按目标及状态码查询探测结果，按游标翻页
+
+func TestResultUseCase_QueryResults(t *testing.T) {
+	ctx := context.Background()
+	resultRepo := newFakeResultRepo()
+	for i := 0; i < 5; i++ {
+		uid := fmt.Sprintf("u%d", i)
+		resultRepo.results[uid] = InstructResult{InstructUuid: uid, Type: HttpInstruct, Target: "https://example.com", Result: 1, StatusCode: int32(200 + 100*(i%4)), CreateTime: int64(100 + i)}
+	}
+	resultRepo.results["other"] = InstructResult{InstructUuid: "other", Type: HttpInstruct, Target: "https://example.org", Result: 1, StatusCode: 503, CreateTime: 200}
+	resultUseCase := NewResultUseCase(resultRepo, zap.NewNop())
+	
+	query := ResultQuery{Type: HttpInstruct, Target: "https://example.com", MinStatus: 300, Limit: 2}
+	results, next, err := resultUseCase.QueryResults(ctx, query, "")
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "u3", results[0].InstructUuid)
+	assert.Equal(t, "u2", results[1].InstructUuid)
+	assert.NotEmpty(t, next)
+	
+	results, next, err = resultUseCase.QueryResults(ctx, query, next)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "u1", results[0].InstructUuid)
+	assert.Empty(t, next)
+	
+	_, _, err = resultUseCase.QueryResults(ctx, ResultQuery{Type: CommandInstruct}, "")
+	assert.ErrorIs(t, err, ErrInvalidInstructQuery)
+	
+	_, _, err = resultUseCase.QueryResults(ctx, ResultQuery{MinStatus: 500, MaxStatus: 400}, "")
+	assert.ErrorIs(t, err, ErrInvalidInstructQuery)
+}
@@ -0,0 +1,143 @@
+package biz
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"time"
+)
+
+// 指令记录保留策略
+//
+// 创建超过 InstructMaxAge 的指令记录删除 (Archive 为 true 时移入 instruct_archive)；
+// 创建超过 ReplyMaxAge 且结果超过 MaxReplySize 的指令结果截断到 MaxReplySize 并标记为已截断；
+// 超过 ResultMaxAge 的结构化探测结果删除 (不随指令记录删除，可单独保留更长时间用于统计)。
+// 终端录像及文件等 blob 不随指令记录删除
+
+const (
+	DefaultRetentionInterval = time.Hour
+	DefaultMaxReplySize      = 64 * 1024 // 字符
+)
+
+type RetentionPolicy struct {
+	InstructMaxAge time.Duration // 为 0 时不清理指令记录
+	Archive        bool          // 清理时移入 instruct_archive，否则删除
+	ReplyMaxAge    time.Duration // 为 0 时不截断指令结果
+	MaxReplySize   int
+	ResultMaxAge   time.Duration // 为 0 时不清理探测结果
+	Interval       time.Duration // 检查间隔，默认 1 小时
+}
+
+func (retentionPolicy *RetentionPolicy) Enabled() bool {
+	return retentionPolicy.InstructMaxAge > 0 || retentionPolicy.ReplyMaxAge > 0 || retentionPolicy.ResultMaxAge > 0
+}
+
+func (retentionPolicy *RetentionPolicy) interval() time.Duration {
+	if retentionPolicy.Interval <= 0 {
+		return DefaultRetentionInterval
+	}
+	return retentionPolicy.Interval
+}
+
+func (retentionPolicy *RetentionPolicy) maxReplySize() int {
+	if retentionPolicy.MaxReplySize <= 0 {
+		return DefaultMaxReplySize
+	}
+	return retentionPolicy.MaxReplySize
+}
+
+// 定时按保留策略清理指令记录；未配置时直接返回，多个节点时仅获得锁的节点执行
+
+func (archiveUseCase *ArchiveUseCase) Prune(ctx context.Context) {
+	if !archiveUseCase.retention.Enabled() {
+		return
+	}
+	
+	ticker := time.NewTicker(archiveUseCase.retention.interval())
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ticker.C:
+			locked, err := archiveUseCase.archiveRepo.AcquireRetentionLock(ctx, archiveUseCase.node.Id, archiveUseCase.retention.interval())
+			if err != nil {
+				archiveUseCase.logger.Error("获取指令清理锁失败", zap.Error(err))
+				continue
+			}
+			
+			if locked {
+				archiveUseCase.prune(ctx)
+			}
+		
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (archiveUseCase *ArchiveUseCase) prune(ctx context.Context) {
+	now := time.Now()
+	retention := archiveUseCase.retention
+	
+	if retention.InstructMaxAge > 0 {
+		before := now.Add(-retention.InstructMaxAge).Unix()
+		count, err := archiveUseCase.batch(ctx, func() (int64, error) {
+			if retention.Archive {
+				return archiveUseCase.archiveRepo.ArchiveInstructsBefore(ctx, before, now.Unix(), archiveBatch)
+			}
+			return archiveUseCase.archiveRepo.DeleteInstructsBefore(ctx, before, archiveBatch)
+		})
+		if err != nil {
+			archiveUseCase.logger.Error("清理指令记录失败", zap.Error(err))
+		}
+		
+		if count > 0 {
+			archiveUseCase.logger.Info("清理指令记录", zap.Int64("count", count), zap.Bool("archive", retention.Archive))
+		}
+	}
+	
+	if retention.ReplyMaxAge > 0 {
+		before := now.Add(-retention.ReplyMaxAge).Unix()
+		count, err := archiveUseCase.batch(ctx, func() (int64, error) {
+			return archiveUseCase.archiveRepo.TruncateReplies(ctx, before, retention.maxReplySize(), archiveBatch)
+		})
+		if err != nil {
+			archiveUseCase.logger.Error("截断指令结果失败", zap.Error(err))
+		}
+		
+		if count > 0 {
+			archiveUseCase.logger.Info("截断指令结果", zap.Int64("count", count))
+		}
+	}
+	
+	if retention.ResultMaxAge > 0 {
+		before := now.Add(-retention.ResultMaxAge).Unix()
+		count, err := archiveUseCase.batch(ctx, func() (int64, error) {
+			return archiveUseCase.archiveRepo.DeleteResultsBefore(ctx, before, archiveBatch)
+		})
+		if err != nil {
+			archiveUseCase.logger.Error("清理探测结果失败", zap.Error(err))
+		}
+		
+		if count > 0 {
+			archiveUseCase.logger.Info("清理探测结果", zap.Int64("count", count))
+		}
+	}
+}
+
+// 重复执行 fn 直到处理的数量少于 archiveBatch，返回处理的总数
+
+func (archiveUseCase *ArchiveUseCase) batch(ctx context.Context, fn func() (int64, error)) (int64, error) {
+	var total int64
+	for ctx.Err() == nil {
+		count, err := fn()
+		if err != nil {
+			return total, err
+		}
+		
+		total += count
+		if count < archiveBatch {
+			break
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,345 @@
+package biz
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// 会话恢复
+//
+// commander 为 soldier 连接分配会话 token，通过 SessionHeader 响应头返回，soldier 重连时在请求头中携带以恢复会话:
+// 会话内已下发但未收到结果的指令记录为待确认，恢复会话后重新下发，soldier 按指令 uuid 去重
+// soldier 缓存已发送的指令结果，重连后重新发送，commander 保存后以 ServiceAck 确认，指令已结束时重复的结果不再处理
+// 会话在连接断开 sessionTTL 后过期；soldier 以新会话连接 (重启或会话过期) 时，旧会话中待确认的指令标记为中断 (失败)，
+// soldier 离线缓存的结果发送后仍以实际结果更新
+
+const (
+	SessionHeader        = "X-Camp-Session"
+	SessionResumedHeader = "X-Camp-Session-Resumed"
+	
+	sessionTTL               = 10 * time.Minute
+	sessionKeepAliveInterval = time.Minute
+	
+	sessionReplayBufferSize = 256  // soldier 缓存的未确认指令结果数量
+	sessionCompletedSize    = 1024 // soldier 记录的已完成指令数量，用于忽略重新下发的指令
+)
+
+type Session struct {
+	Token        string
+	InstanceUuid string
+	OrgUuid      string
+	GroupUuid    string
+	InstanceName string
+	Resumed      bool // 重连恢复的会话
+}
+
+// 指令是否下发给该会话所属的实例
+
+func (session *Session) Owns(instruct Instruct) bool {
+	return instruct.OrgUuid == session.OrgUuid && instruct.GroupUuid == session.GroupUuid && instruct.InstanceName == session.InstanceName
+}
+
+type SessionRepo interface {
+	CreateSession(ctx context.Context, instanceUuid, token string, ttl time.Duration) (string, error)
+	GetSession(ctx context.Context, token string) (string, error)
+	RefreshSession(ctx context.Context, instanceUuid, token string, ttl time.Duration) error
+	DeleteSession(ctx context.Context, token string) error
+	RevokeInstanceSession(ctx context.Context, instanceUuid string) error
+	AddPendingInstruct(ctx context.Context, token, uuid, instruction string, ttl time.Duration) error
+	RemovePendingInstruct(ctx context.Context, token, uuid string) (bool, error)
+	ListPendingInstructs(ctx context.Context, token string) (map[string]string, error)
+}
+
+type SessionUseCase struct {
+	sessionRepo  SessionRepo
+	instructRepo InstructRepo
+	eventUseCase *EventUseCase
+	logger       *zap.Logger
+}
+
+func NewSessionUseCase(sessionRepo SessionRepo, instructRepo InstructRepo, eventUseCase *EventUseCase, logger *zap.Logger) *SessionUseCase {
+	return &SessionUseCase{
+		sessionRepo:  sessionRepo,
+		instructRepo: instructRepo,
+		eventUseCase: eventUseCase,
+		logger:       logger,
+	}
+}
+
+// 按 soldier 携带的 token 恢复会话，token 为空、已过期或不属于该实例时创建新会话
+
+func (sessionUseCase *SessionUseCase) Open(ctx context.Context, instance Instance, token string) (*Session, error) {
+	session := &Session{
+		InstanceUuid: instance.Uuid,
+		OrgUuid:      instance.OrgUuid,
+		GroupUuid:    instance.GroupUuid,
+		InstanceName: instance.InstanceName,
+	}
+	
+	if token != "" {
+		owner, err := sessionUseCase.sessionRepo.GetSession(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		
+		if owner == instance.Uuid {
+			session.Token = token
+			session.Resumed = true
+			err = sessionUseCase.sessionRepo.RefreshSession(ctx, instance.Uuid, token, sessionTTL)
+			return session, err
+		}
+	}
+	
+	session.Token = uuid.NewString()
+	previous, err := sessionUseCase.sessionRepo.CreateSession(ctx, instance.Uuid, session.Token, sessionTTL)
+	if err != nil {
+		return nil, err
+	}
+	
+	if previous != "" {
+		sessionUseCase.abandon(ctx, previous)
+	}
+	
+	return session, nil
+}
+
+// 旧会话无法恢复，其中待确认的指令标记为中断
+
+func (sessionUseCase *SessionUseCase) abandon(ctx context.Context, token string) {
+	pending, err := sessionUseCase.sessionRepo.ListPendingInstructs(ctx, token)
+	if err != nil {
+		sessionUseCase.logger.Error("获取待确认指令失败", zap.String("session", token), zap.Error(err))
+		return
+	}
+	
+	for instructUuid := range pending {
+		updated, err := sessionUseCase.instructRepo.UpdateInstruct(ctx, instructUuid, InstructInterruptedReply, -1)
+		if err != nil {
+			sessionUseCase.logger.Error("更新指令结果失败", zap.String("uuid", instructUuid), zap.Error(err))
+			continue
+		}
+		if updated == 0 {
+			continue
+		}
+		sessionUseCase.eventUseCase.PublishUpdate(ctx, EventCompleted, instructUuid, nil)
+	}
+	
+	err = sessionUseCase.sessionRepo.DeleteSession(ctx, token)
+	if err != nil {
+		sessionUseCase.logger.Error("删除会话失败", zap.String("session", token), zap.Error(err))
+	}
+}
+
+// 连接期间定时延长会话有效期
+
+func (sessionUseCase *SessionUseCase) KeepAlive(ctx context.Context, session *Session) {
+	ticker := time.NewTicker(sessionKeepAliveInterval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ticker.C:
+			err := sessionUseCase.sessionRepo.RefreshSession(ctx, session.InstanceUuid, session.Token, sessionTTL)
+			if err != nil {
+				sessionUseCase.logger.Error("延长会话有效期失败", zap.String("session", session.Token), zap.Error(err))
+			}
+		
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// 记录已下发的指令
+
+func (sessionUseCase *SessionUseCase) Track(ctx context.Context, session *Session, instructUuid, instruction string) {
+	err := sessionUseCase.sessionRepo.AddPendingInstruct(ctx, session.Token, instructUuid, instruction, sessionTTL)
+	if err != nil {
+		sessionUseCase.logger.Error("记录待确认指令失败", zap.String("uuid", instructUuid), zap.Error(err))
+	}
+}
+
+// 指令结果已处理，恢复会话时不再重新下发；结果是否处理由指令状态决定，与下发的会话无关
+
+func (sessionUseCase *SessionUseCase) Complete(ctx context.Context, session *Session, instructUuid string) {
+	_, err := sessionUseCase.sessionRepo.RemovePendingInstruct(ctx, session.Token, instructUuid)
+	if err != nil {
+		sessionUseCase.logger.Error("移除待确认指令失败", zap.String("uuid", instructUuid), zap.Error(err))
+	}
+}
+
+// 恢复会话时返回待确认的指令
+
+func (sessionUseCase *SessionUseCase) Pending(ctx context.Context, session *Session) []string {
+	pending, err := sessionUseCase.sessionRepo.ListPendingInstructs(ctx, session.Token)
+	if err != nil {
+		sessionUseCase.logger.Error("获取待确认指令失败", zap.String("session", session.Token), zap.Error(err))
+		return nil
+	}
+	
+	instructions := make([]string, 0, len(pending))
+	for _, instruction := range pending {
+		instructions = append(instructions, instruction)
+	}
+	
+	return instructions
+}
+
+// soldier 端会话，由 WebSocketUseCase 持有，跨越重连
+
+type ClientSession struct {
+	mu             sync.Mutex
+	token          string
+	replies        []ClientMessage // 已发送未确认的指令结果
+	running        map[string]bool
+	cancels        map[string]context.CancelFunc // 正在执行的指令
+	completed      map[string]bool
+	completedOrder []string
+}
+
+func NewClientSession() *ClientSession {
+	return &ClientSession{
+		running:   make(map[string]bool),
+		cancels:   make(map[string]context.CancelFunc),
+		completed: make(map[string]bool),
+	}
+}
+
+func (clientSession *ClientSession) Token() string {
+	clientSession.mu.Lock()
+	defer clientSession.mu.Unlock()
+	return clientSession.token
+}
+
+// 连接成功后记录 commander 分配的会话 token，旧版本 commander 不返回 token，此时不缓存指令结果
+
+func (clientSession *ClientSession) SetToken(token string) {
+	clientSession.mu.Lock()
+	defer clientSession.mu.Unlock()
+	
+	clientSession.token = token
+	if token == "" {
+		clientSession.replies = nil
+	}
+}
+
+// 开始执行指令，返回 false 表示指令正在执行或已完成 (重新下发的指令)；restart 为 true 时允许重新执行未完成的指令
+
+func (clientSession *ClientSession) Begin(instructUuid string, restart bool) bool {
+	clientSession.mu.Lock()
+	defer clientSession.mu.Unlock()
+	
+	if clientSession.completed[instructUuid] {
+		return false
+	}
+	
+	if clientSession.running[instructUuid] && !restart {
+		return false
+	}
+	
+	clientSession.running[instructUuid] = true
+	return true
+}
+
+// 登记正在执行的指令，执行结束后调用 Finish
+
+func (clientSession *ClientSession) Run(instructUuid string, cancel context.CancelFunc) {
+	clientSession.mu.Lock()
+	defer clientSession.mu.Unlock()
+	clientSession.cancels[instructUuid] = cancel
+}
+
+func (clientSession *ClientSession) Finish(instructUuid string) {
+	clientSession.mu.Lock()
+	defer clientSession.mu.Unlock()
+	delete(clientSession.cancels, instructUuid)
+}
+
+// 取消指令，正在执行时终止并返回 true；尚未开始执行的指令标记为已完成，之后收到时不再执行
+
+func (clientSession *ClientSession) Cancel(instructUuid string) bool {
+	clientSession.mu.Lock()
+	defer clientSession.mu.Unlock()
+	
+	cancel, ok := clientSession.cancels[instructUuid]
+	if ok {
+		cancel()
+		return true
+	}
+	
+	if !clientSession.running[instructUuid] {
+		clientSession.complete(instructUuid)
+	}
+	return false
+}
+
+// 指令结果写出前缓存，直到 commander 确认
+
+func (clientSession *ClientSession) Sent(msg ClientMessage) {
+	instructUuid := msg.InstructMessage.Uuid
+	
+	clientSession.mu.Lock()
+	defer clientSession.mu.Unlock()
+	
+	delete(clientSession.running, instructUuid)
+	clientSession.complete(instructUuid)
+	
+	if clientSession.token == "" {
+		return
+	}
+	
+	for _, reply := range clientSession.replies {
+		if reply.InstructMessage.Uuid == instructUuid {
+			return
+		}
+	}
+	
+	// 超过缓存数量时丢弃最早的结果
+	clientSession.replies = append(clientSession.replies, msg)
+	if len(clientSession.replies) > sessionReplayBufferSize {
+		clientSession.replies = clientSession.replies[1:]
+	}
+}
+
+// 记录已完成的指令，超过数量时删除最早的记录，调用方持有锁
+
+func (clientSession *ClientSession) complete(instructUuid string) {
+	if clientSession.completed[instructUuid] {
+		return
+	}
+	
+	clientSession.completed[instructUuid] = true
+	clientSession.completedOrder = append(clientSession.completedOrder, instructUuid)
+	if len(clientSession.completedOrder) > sessionCompletedSize {
+		delete(clientSession.completed, clientSession.completedOrder[0])
+		clientSession.completedOrder = clientSession.completedOrder[1:]
+	}
+}
+
+// commander 已处理指令结果
+
+func (clientSession *ClientSession) Ack(instructUuid string) {
+	clientSession.mu.Lock()
+	defer clientSession.mu.Unlock()
+	
+	for i, reply := range clientSession.replies {
+		if reply.InstructMessage.Uuid == instructUuid {
+			clientSession.replies = append(clientSession.replies[:i:i], clientSession.replies[i+1:]...)
+			return
+		}
+	}
+}
+
+// 重连后需要重新发送的指令结果
+
+func (clientSession *ClientSession) Replies() []ClientMessage {
+	clientSession.mu.Lock()
+	defer clientSession.mu.Unlock()
+	
+	replies := make([]ClientMessage, len(clientSession.replies))
+	copy(replies, clientSession.replies)
+	return replies
+}
@@ -0,0 +1,127 @@
+package biz
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+// 恢复会话时重新下发未处理结果的指令，新会话时旧会话中的指令标记为失败
+
+func TestSessionUseCase_Open(t *testing.T) {
+	ctx := context.Background()
+	instructRepo, _ := newFakeRepos()
+	eventUseCase, eventRepo := newTestEventUseCase(instructRepo, &WebhookPolicy{})
+	sessionRepo := newFakeSessionRepo()
+	sessionUseCase := NewSessionUseCase(sessionRepo, instructRepo, eventUseCase, zap.NewNop())
+	instance := Instance{Uuid: "instance", OrgUuid: "org", GroupUuid: "group", InstanceName: "name"}
+	
+	session, err := sessionUseCase.Open(ctx, instance, "")
+	assert.NoError(t, err)
+	assert.False(t, session.Resumed)
+	assert.True(t, session.Owns(Instruct{OrgUuid: "org", GroupUuid: "group", InstanceName: "name"}))
+	assert.False(t, session.Owns(Instruct{OrgUuid: "org", GroupUuid: "group", InstanceName: "other"}))
+	
+	sessionUseCase.Track(ctx, session, "a", `{"type":2}`)
+	sessionUseCase.Track(ctx, session, "b", `{"type":2}`)
+	sessionUseCase.Complete(ctx, session, "a")
+	sessionUseCase.Complete(ctx, session, "unknown")
+	// 移除失败时保留待确认记录
+	sessionRepo.err = errors.New("redis")
+	sessionUseCase.Complete(ctx, session, "b")
+	sessionRepo.err = nil
+	
+	// 重连恢复会话
+	resumed, err := sessionUseCase.Open(ctx, instance, session.Token)
+	assert.NoError(t, err)
+	assert.True(t, resumed.Resumed)
+	assert.Equal(t, session.Token, resumed.Token)
+	assert.Equal(t, []string{`{"type":2}`}, sessionUseCase.Pending(ctx, resumed))
+	
+	// 其他实例不能恢复该会话
+	other, err := sessionUseCase.Open(ctx, Instance{Uuid: "other"}, session.Token)
+	assert.NoError(t, err)
+	assert.False(t, other.Resumed)
+	assert.NotEqual(t, session.Token, other.Token)
+	
+	// soldier 重启后以新会话连接
+	instructRepo.instruct["b"] = Instruct{Uuid: "b"}
+	restarted, err := sessionUseCase.Open(ctx, instance, "")
+	assert.NoError(t, err)
+	assert.False(t, restarted.Resumed)
+	assert.Equal(t, int32(-1), instructRepo.instruct["b"].Result)
+	events := eventRepo.published()
+	assert.Len(t, events, 1)
+	assert.Equal(t, EventCompleted, events[0].Type)
+	assert.Equal(t, "b", events[0].InstructUuid)
+	
+	_, err = sessionUseCase.Open(ctx, instance, session.Token)
+	assert.NoError(t, err)
+	assert.Empty(t, sessionUseCase.Pending(ctx, session))
+}
+
+func TestClientSession(t *testing.T) {
+	clientSession := NewClientSession()
+	reply := func(uuid string) ClientMessage {
+		return ClientMessage{Type: ClientInstructReply, InstructMessage: InstructMessage{Uuid: uuid}}
+	}
+	
+	// 旧版本 commander 不分配会话时不缓存结果
+	assert.True(t, clientSession.Begin("a", false))
+	assert.False(t, clientSession.Begin("a", false))
+	clientSession.Sent(reply("a"))
+	assert.Empty(t, clientSession.Replies())
+	assert.False(t, clientSession.Begin("a", true))
+	
+	clientSession.SetToken("token")
+	assert.True(t, clientSession.Begin("b", false))
+	assert.True(t, clientSession.Begin("b", true))
+	clientSession.Sent(reply("b"))
+	clientSession.Sent(reply("c"))
+	clientSession.Sent(reply("c"))
+	assert.Len(t, clientSession.Replies(), 2)
+	
+	clientSession.Ack("b")
+	replies := clientSession.Replies()
+	assert.Len(t, replies, 1)
+	assert.Equal(t, "c", replies[0].InstructMessage.Uuid)
+	
+	for i := 0; i < sessionReplayBufferSize+10; i++ {
+		clientSession.Sent(reply(string(rune('A' + i))))
+	}
+	assert.Len(t, clientSession.Replies(), sessionReplayBufferSize)
+}
+
+// 正在执行的指令被终止，尚未执行的指令之后不再执行
+
+func TestClientSession_Cancel(t *testing.T) {
+	clientSession := NewClientSession()
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.True(t, clientSession.Begin("a", false))
+	clientSession.Run("a", cancel)
+	assert.True(t, clientSession.Cancel("a"))
+	assert.Error(t, ctx.Err())
+	clientSession.Finish("a")
+	
+	assert.False(t, clientSession.Cancel("b"))
+	assert.False(t, clientSession.Begin("b", false))
+}
+
+func TestReconnectBackoff(t *testing.T) {
+	backoff := newReconnectBackoff()
+	for i := 0; i < 40; i++ {
+		delay := backoff.Next()
+		expected := reconnectMaxDelay
+		if i < 6 {
+			expected = reconnectBaseDelay << i
+		}
+		assert.GreaterOrEqual(t, delay, expected/2)
+		assert.LessOrEqual(t, delay, expected)
+	}
+	
+	backoff.Reset()
+	assert.LessOrEqual(t, backoff.Next(), reconnectBaseDelay)
+}
@@ -0,0 +1,567 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/creack/pty"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"hash"
+	"os"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 交互式终端
+//
+// 操作端通过 commander 的 websocket 接口打开会话，commander 通过 soldier 连接转发 ShellFrame:
+// commander -> soldier: ShellOpen / ShellData (输入) / ShellResize / ShellClose
+// soldier -> commander: ShellData (输出) / ShellClose
+// 会话以 ShellInstruct 指令记录到指令表中，会话录像 (asciicast v2) 以指令 uuid 保存为 blob
+
+type ShellFrameType int32
+
+const (
+	ShellOpen   ShellFrameType = 1
+	ShellData   ShellFrameType = 2
+	ShellResize ShellFrameType = 3
+	ShellClose  ShellFrameType = 4
+)
+
+const (
+	shellDefaultIdleTimeout = 15 * time.Minute
+	shellOutputBuffer       = 64
+	shellReadBufferSize     = 32 * 1024
+)
+
+var (
+	ErrShellForbidden    = errors.New("当前角色不允许打开终端")
+	ErrShellNotConnected = errors.New("目标实例未连接到当前节点")
+)
+
+type ShellFrame struct {
+	SessionId string         `json:"sessionId,omitempty"`
+	Type      ShellFrameType `json:"type,omitempty"`
+	Data      []byte         `json:"data,omitempty"`
+	Cols      uint32         `json:"cols,omitempty"`
+	Rows      uint32         `json:"rows,omitempty"`
+	Reason    string         `json:"reason,omitempty"`
+}
+
+// 终端会话审计信息，会话开始时作为指令内容，结束时作为指令结果
+
+type ShellSessionInfo struct {
+	Operator          string `json:"operator,omitempty"`
+	Role              string `json:"role,omitempty"`
+	Cols              uint32 `json:"cols,omitempty"`
+	Rows              uint32 `json:"rows,omitempty"`
+	StartTime         int64  `json:"startTime,omitempty"`
+	EndTime           int64  `json:"endTime,omitempty"`
+	Reason            string `json:"reason,omitempty"`
+	RecordingSize     int64  `json:"recordingSize,omitempty"`
+	RecordingSha256   string `json:"recordingSha256,omitempty"`
+	RecordingTruncate bool   `json:"recordingTruncate,omitempty"` // 录像超过大小限制被截断
+}
+
+func (shellSessionInfo *ShellSessionInfo) String() string {
+	b, err := json.Marshal(shellSessionInfo)
+	if err != nil {
+		return err.Error()
+	}
+	
+	return string(b)
+}
+
+// 终端策略，AllowedRoles 为空或角色未经网关签名 (为空) 时禁止打开终端
+
+type ShellPolicy struct {
+	AllowedRoles []string
+	IdleTimeout  time.Duration
+}
+
+func (shellPolicy *ShellPolicy) Allow(role string) bool {
+	if role == "" {
+		return false
+	}
+	
+	for _, allowedRole := range shellPolicy.AllowedRoles {
+		if allowedRole == role {
+			return true
+		}
+	}
+	
+	return false
+}
+
+type ShellUseCase struct {
+	connRegistry *ConnRegistry
+	instructRepo InstructRepo
+	blobRepo     BlobRepo
+	policy       ShellPolicy
+	logger       *zap.Logger
+	
+	mu       sync.Mutex
+	sessions map[string]*ShellSession
+}
+
+func NewShellUseCase(policy *ShellPolicy, connRegistry *ConnRegistry, instructRepo InstructRepo, blobRepo BlobRepo, logger *zap.Logger) *ShellUseCase {
+	shellUseCase := &ShellUseCase{
+		connRegistry: connRegistry,
+		instructRepo: instructRepo,
+		blobRepo:     blobRepo,
+		policy:       *policy,
+		logger:       logger,
+		sessions:     make(map[string]*ShellSession),
+	}
+	
+	if shellUseCase.policy.IdleTimeout <= 0 {
+		shellUseCase.policy.IdleTimeout = shellDefaultIdleTimeout
+	}
+	
+	connRegistry.OnDetach(shellUseCase.detach)
+	return shellUseCase
+}
+
+// soldier 连接断开，关闭该连接上的所有会话
+
+func (shellUseCase *ShellUseCase) detach(serviceChannels *ServiceChannels) {
+	shellUseCase.mu.Lock()
+	var sessions []*ShellSession
+	for _, session := range shellUseCase.sessions {
+		if session.send == serviceChannels.Queue(BulkChannel) {
+			sessions = append(sessions, session)
+		}
+	}
+	shellUseCase.mu.Unlock()
+	
+	for _, session := range sessions {
+		session.finish("soldier 连接断开")
+	}
+}
+
+// 打开终端会话
+
+func (shellUseCase *ShellUseCase) Open(ctx context.Context, instance Instance, operator, role string, cols, rows uint32) (*ShellSession, error) {
+	if !shellUseCase.policy.Allow(role) {
+		return nil, ErrShellForbidden
+	}
+	
+	serviceChannels, ok := shellUseCase.connRegistry.Get(instance.Uuid)
+	if !ok {
+		return nil, ErrShellNotConnected
+	}
+	
+	now := time.Now()
+	session := &ShellSession{
+		Uuid:         uuid.NewString(),
+		InstanceUuid: instance.Uuid,
+		info: ShellSessionInfo{
+			Operator:  operator,
+			Role:      role,
+			Cols:      cols,
+			Rows:      rows,
+			StartTime: now.Unix(),
+		},
+		shellUseCase: shellUseCase,
+		send:         serviceChannels.Queue(BulkChannel),
+		output:       make(chan ShellFrame, shellOutputBuffer),
+		done:         make(chan struct{}),
+	}
+	session.lastInput.Store(now.UnixNano())
+	session.recorder = newShellRecorder(shellUseCase.blobRepo, session.Uuid, now)
+	
+	// 会话记录到指令表，作为审计记录
+	err := shellUseCase.instructRepo.RecordInstruct(ctx, Instruct{
+		Uuid:         session.Uuid,
+		OrgUuid:      instance.OrgUuid,
+		GroupUuid:    instance.GroupUuid,
+		InstanceName: instance.InstanceName,
+		Type:         ShellInstruct,
+		Content:      session.info.String(),
+		CreateTime:   now.Unix(),
+		UpdateTime:   now.Unix(),
+		Operator:     operator,
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	session.recorder.header(cols, rows)
+	
+	shellUseCase.mu.Lock()
+	shellUseCase.sessions[session.Uuid] = session
+	shellUseCase.mu.Unlock()
+	
+	shellUseCase.logger.Info("打开终端会话",
+		zap.String("sessionId", session.Uuid),
+		zap.String("instanceUuid", instance.Uuid),
+		zap.String("operator", operator),
+		zap.String("role", role),
+	)
+	
+	session.sendFrame(ShellFrame{Type: ShellOpen, Cols: cols, Rows: rows})
+	go session.watchIdle(shellUseCase.policy.IdleTimeout)
+	
+	return session, nil
+}
+
+// 处理 soldier 回传的终端帧
+
+func (shellUseCase *ShellUseCase) Deliver(frame ShellFrame) {
+	shellUseCase.mu.Lock()
+	session, ok := shellUseCase.sessions[frame.SessionId]
+	shellUseCase.mu.Unlock()
+	if !ok {
+		return
+	}
+	
+	switch frame.Type {
+	case ShellData:
+		session.recorder.event("o", frame.Data)
+		select {
+		case session.output <- frame:
+		case <-session.done:
+		}
+	
+	case ShellClose:
+		session.finish(frame.Reason)
+	}
+}
+
+type ShellSession struct {
+	Uuid         string
+	InstanceUuid string
+	
+	info         ShellSessionInfo
+	shellUseCase *ShellUseCase
+	send         chan ServiceMessage
+	output       chan ShellFrame
+	recorder     *shellRecorder
+	lastInput    atomic.Int64
+	
+	closeOnce sync.Once
+	done      chan struct{}
+	reason    string
+}
+
+// 终端输出，会话结束后 Done 关闭，剩余的输出仍可读取
+
+func (shellSession *ShellSession) Output() <-chan ShellFrame {
+	return shellSession.output
+}
+
+func (shellSession *ShellSession) Done() <-chan struct{} {
+	return shellSession.done
+}
+
+// 会话结束原因，Done 之后有效
+
+func (shellSession *ShellSession) Reason() string {
+	return shellSession.reason
+}
+
+func (shellSession *ShellSession) Input(data []byte) {
+	shellSession.lastInput.Store(time.Now().UnixNano())
+	shellSession.recorder.event("i", data)
+	// 消息在连接的发送协程中编码，复制数据以免调用方复用缓冲区
+	shellSession.sendFrame(ShellFrame{Type: ShellData, Data: bytes.Clone(data)})
+}
+
+func (shellSession *ShellSession) Resize(cols, rows uint32) {
+	shellSession.recorder.event("r", []byte(fmt.Sprintf("%dx%d", cols, rows)))
+	shellSession.sendFrame(ShellFrame{Type: ShellResize, Cols: cols, Rows: rows})
+}
+
+// 操作端关闭会话
+
+func (shellSession *ShellSession) Close(reason string) {
+	shellSession.sendFrame(ShellFrame{Type: ShellClose, Reason: reason})
+	shellSession.finish(reason)
+}
+
+func (shellSession *ShellSession) sendFrame(frame ShellFrame) {
+	frame.SessionId = shellSession.Uuid
+	select {
+	case shellSession.send <- ServiceMessage{Type: ServiceShell, Shell: &frame}:
+	case <-shellSession.done:
+	}
+}
+
+func (shellSession *ShellSession) watchIdle(idleTimeout time.Duration) {
+	ticker := time.NewTicker(time.Second * 10)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ticker.C:
+			if time.Since(time.Unix(0, shellSession.lastInput.Load())) >= idleTimeout {
+				shellSession.Close(fmt.Sprintf("超过 %s 无输入", idleTimeout))
+				return
+			}
+		case <-shellSession.done:
+			return
+		}
+	}
+}
+
+// 结束会话，保存录像并更新审计记录
+
+func (shellSession *ShellSession) finish(reason string) {
+	shellSession.closeOnce.Do(func() {
+		shellUseCase := shellSession.shellUseCase
+		
+		shellUseCase.mu.Lock()
+		delete(shellUseCase.sessions, shellSession.Uuid)
+		shellUseCase.mu.Unlock()
+		
+		shellSession.reason = reason
+		close(shellSession.done)
+		
+		ctx := context.Background()
+		info := shellSession.info
+		info.EndTime = time.Now().Unix()
+		info.Reason = reason
+		
+		blob, truncate, err := shellSession.recorder.commit(ctx)
+		if err != nil {
+			shellUseCase.logger.Error("保存终端会话录像失败", zap.String("sessionId", shellSession.Uuid), zap.Error(err))
+		}
+		info.RecordingSize = blob.Size
+		info.RecordingSha256 = blob.Sha256
+		info.RecordingTruncate = truncate
+		
+		_, err = shellUseCase.instructRepo.UpdateInstruct(ctx, shellSession.Uuid, info.String(), 1)
+		if err != nil {
+			shellUseCase.logger.Error("更新终端会话记录失败", zap.String("sessionId", shellSession.Uuid), zap.Error(err))
+		}
+		
+		shellUseCase.logger.Info("关闭终端会话", zap.String("sessionId", shellSession.Uuid), zap.String("reason", reason))
+	})
+}
+
+// asciicast v2 录像，逐条事件以分片写入 blob 暂存区，会话结束后提交
+
+type shellRecorder struct {
+	blobRepo BlobRepo
+	uuid     string
+	start    time.Time
+	
+	mu       sync.Mutex
+	offset   int64
+	hash     hash.Hash
+	truncate bool
+}
+
+func newShellRecorder(blobRepo BlobRepo, uuid string, start time.Time) *shellRecorder {
+	return &shellRecorder{
+		blobRepo: blobRepo,
+		uuid:     uuid,
+		start:    start,
+		hash:     sha256.New(),
+	}
+}
+
+func (shellRecorder *shellRecorder) header(cols, rows uint32) {
+	b, _ := json.Marshal(map[string]interface{}{
+		"version":   2,
+		"width":     cols,
+		"height":    rows,
+		"timestamp": shellRecorder.start.Unix(),
+		"env":       map[string]string{"TERM": "xterm-256color"},
+	})
+	shellRecorder.write(b)
+}
+
+func (shellRecorder *shellRecorder) event(code string, data []byte) {
+	b, _ := json.Marshal([]interface{}{time.Since(shellRecorder.start).Seconds(), code, string(data)})
+	shellRecorder.write(b)
+}
+
+func (shellRecorder *shellRecorder) write(line []byte) {
+	shellRecorder.mu.Lock()
+	defer shellRecorder.mu.Unlock()
+	
+	if shellRecorder.truncate {
+		return
+	}
+	
+	line = append(line, '\n')
+	err := shellRecorder.blobRepo.WriteChunk(context.Background(), shellRecorder.uuid, shellRecorder.offset, line)
+	if err != nil {
+		// 超过大小限制或写入失败后不再记录，已记录的部分仍然保存
+		shellRecorder.truncate = true
+		return
+	}
+	
+	shellRecorder.hash.Write(line)
+	shellRecorder.offset += int64(len(line))
+}
+
+func (shellRecorder *shellRecorder) commit(ctx context.Context) (Blob, bool, error) {
+	shellRecorder.mu.Lock()
+	defer shellRecorder.mu.Unlock()
+	
+	blob, err := shellRecorder.blobRepo.Commit(ctx, shellRecorder.uuid, shellRecorder.offset, hex.EncodeToString(shellRecorder.hash.Sum(nil)))
+	return blob, shellRecorder.truncate, err
+}
+
+// soldier 端终端会话，每个会话对应一个 pty
+
+type ShellClientPolicy struct {
+	Enabled bool
+}
+
+type ShellClientUseCase struct {
+	logger  *zap.Logger
+	enabled bool
+	
+	mu       sync.Mutex
+	sessions map[string]*os.File
+}
+
+func NewShellClientUseCase(logger *zap.Logger, policy *ShellClientPolicy) *ShellClientUseCase {
+	return &ShellClientUseCase{
+		logger:   logger,
+		enabled:  policy.Enabled,
+		sessions: make(map[string]*os.File),
+	}
+}
+
+func (shellClientUseCase *ShellClientUseCase) Enabled() bool {
+	if !shellClientUseCase.enabled {
+		return false
+	}
+	
+	_, err := exec.LookPath("bash")
+	return err == nil
+}
+
+// 处理 commander 下发的终端帧
+
+func (shellClientUseCase *ShellClientUseCase) Handle(ctx context.Context, frame ShellFrame, sendMsg chan ClientMessage) {
+	switch frame.Type {
+	case ShellOpen:
+		err := shellClientUseCase.open(ctx, frame, sendMsg)
+		if err != nil {
+			shellClientUseCase.logger.Error("打开终端失败", zap.String("sessionId", frame.SessionId), zap.Error(err))
+			sendMsg <- ClientMessage{
+				Type:  ClientShell,
+				Shell: &ShellFrame{SessionId: frame.SessionId, Type: ShellClose, Reason: err.Error()},
+			}
+		}
+	
+	case ShellData:
+		tty := shellClientUseCase.get(frame.SessionId)
+		if tty != nil {
+			_, err := tty.Write(frame.Data)
+			if err != nil {
+				shellClientUseCase.logger.Warn("写入终端失败", zap.String("sessionId", frame.SessionId), zap.Error(err))
+			}
+		}
+	
+	case ShellResize:
+		tty := shellClientUseCase.get(frame.SessionId)
+		if tty != nil {
+			pty.Setsize(tty, &pty.Winsize{Cols: uint16(frame.Cols), Rows: uint16(frame.Rows)})
+		}
+	
+	case ShellClose:
+		// 关闭 pty 后 bash 收到 SIGHUP 退出，由读取协程回传 ShellClose
+		tty := shellClientUseCase.get(frame.SessionId)
+		if tty != nil {
+			tty.Close()
+		}
+	}
+}
+
+// 与 commander 的连接断开时关闭所有终端
+
+func (shellClientUseCase *ShellClientUseCase) CloseAll() {
+	shellClientUseCase.mu.Lock()
+	defer shellClientUseCase.mu.Unlock()
+	
+	for _, tty := range shellClientUseCase.sessions {
+		tty.Close()
+	}
+}
+
+func (shellClientUseCase *ShellClientUseCase) get(sessionId string) *os.File {
+	shellClientUseCase.mu.Lock()
+	defer shellClientUseCase.mu.Unlock()
+	return shellClientUseCase.sessions[sessionId]
+}
+
+func (shellClientUseCase *ShellClientUseCase) open(ctx context.Context, frame ShellFrame, sendMsg chan ClientMessage) error {
+	if !shellClientUseCase.Enabled() {
+		return errors.New("soldier 未开启终端")
+	}
+	
+	cmd := exec.Command("bash", "-l")
+	cmd.Env = append(os.Environ(), "TERM=xterm-256color")
+	
+	tty, err := pty.StartWithSize(cmd, &pty.Winsize{Cols: uint16(frame.Cols), Rows: uint16(frame.Rows)})
+	if err != nil {
+		return err
+	}
+	
+	shellClientUseCase.mu.Lock()
+	shellClientUseCase.sessions[frame.SessionId] = tty
+	shellClientUseCase.mu.Unlock()
+	
+	shellClientUseCase.logger.Info("打开终端", zap.String("sessionId", frame.SessionId), zap.Int("pid", cmd.Process.Pid))
+	
+	go func() {
+		buf := make([]byte, shellReadBufferSize)
+		for {
+			n, err := tty.Read(buf)
+			if n > 0 {
+				data := make([]byte, n)
+				copy(data, buf[:n])
+				
+				select {
+				case sendMsg <- ClientMessage{
+					Type:  ClientShell,
+					Shell: &ShellFrame{SessionId: frame.SessionId, Type: ShellData, Data: data},
+				}:
+				case <-ctx.Done():
+				}
+			}
+			
+			if err != nil {
+				break
+			}
+		}
+		
+		tty.Close()
+		cmd.Process.Kill()
+		
+		reason := "exit"
+		err := cmd.Wait()
+		if err != nil {
+			reason = err.Error()
+		}
+		
+		shellClientUseCase.mu.Lock()
+		delete(shellClientUseCase.sessions, frame.SessionId)
+		shellClientUseCase.mu.Unlock()
+		
+		shellClientUseCase.logger.Info("终端退出", zap.String("sessionId", frame.SessionId), zap.String("reason", reason))
+		
+		select {
+		case sendMsg <- ClientMessage{
+			Type:  ClientShell,
+			Shell: &ShellFrame{SessionId: frame.SessionId, Type: ShellClose, Reason: reason},
+		}:
+		case <-ctx.Done():
+		}
+	}()
+	
+	return nil
+}
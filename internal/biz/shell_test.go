@@ -0,0 +1,111 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShellUseCase_Session(t *testing.T) {
+	instructRepo, blobRepo := newFakeRepos()
+	connRegistry := NewConnRegistry()
+	shellUseCase := NewShellUseCase(&ShellPolicy{AllowedRoles: []string{"ops"}}, connRegistry, instructRepo, blobRepo, zap.NewNop())
+	instance := Instance{Uuid: "instance", OrgUuid: "org", GroupUuid: "group", InstanceName: "name"}
+	
+	_, err := shellUseCase.Open(context.Background(), instance, "alice", "dev", 80, 24)
+	assert.ErrorIs(t, err, ErrShellForbidden)
+	
+	_, err = shellUseCase.Open(context.Background(), instance, "alice", "ops", 80, 24)
+	assert.ErrorIs(t, err, ErrShellNotConnected)
+	
+	serviceChannels := NewServiceChannels()
+	connRegistry.Attach(instance.Uuid, serviceChannels)
+	send := serviceChannels.Queue(BulkChannel)
+	
+	session, err := shellUseCase.Open(context.Background(), instance, "alice", "ops", 80, 24)
+	assert.NoError(t, err)
+	assert.Equal(t, ShellInstruct, instructRepo.instruct[session.Uuid].Type)
+	
+	msg := <-send
+	assert.Equal(t, ServiceShell, msg.Type)
+	assert.Equal(t, ShellOpen, msg.Shell.Type)
+	assert.Equal(t, session.Uuid, msg.Shell.SessionId)
+	
+	session.Input([]byte("ls\r"))
+	msg = <-send
+	assert.Equal(t, ShellData, msg.Shell.Type)
+	assert.Equal(t, []byte("ls\r"), msg.Shell.Data)
+	
+	shellUseCase.Deliver(ShellFrame{SessionId: session.Uuid, Type: ShellData, Data: []byte("file\r\n")})
+	frame := <-session.Output()
+	assert.Equal(t, []byte("file\r\n"), frame.Data)
+	
+	shellUseCase.Deliver(ShellFrame{SessionId: session.Uuid, Type: ShellClose, Reason: "exit"})
+	<-session.Done()
+	assert.Equal(t, "exit", session.Reason())
+	
+	// 审计记录及 asciicast 录像
+	instruct := instructRepo.instruct[session.Uuid]
+	assert.Equal(t, int32(1), instruct.Result)
+	assert.Contains(t, instruct.Reply, `"operator":"alice"`)
+	
+	lines := strings.Split(strings.TrimSpace(string(blobRepo.blobs[session.Uuid])), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[0], `"version":2`)
+	assert.Contains(t, lines[1], `"i","ls\r"`)
+	assert.Contains(t, lines[2], `"o","file\r\n"`)
+}
+
+func TestShellUseCase_Detach(t *testing.T) {
+	instructRepo, blobRepo := newFakeRepos()
+	connRegistry := NewConnRegistry()
+	shellUseCase := NewShellUseCase(&ShellPolicy{AllowedRoles: []string{"ops"}}, connRegistry, instructRepo, blobRepo, zap.NewNop())
+	
+	serviceChannels := NewServiceChannels()
+	connRegistry.Attach("instance", serviceChannels)
+	session, err := shellUseCase.Open(context.Background(), Instance{Uuid: "instance"}, "alice", "ops", 80, 24)
+	assert.NoError(t, err)
+	
+	connRegistry.Detach("instance", serviceChannels)
+	<-session.Done()
+	
+	_, err = shellUseCase.Open(context.Background(), Instance{Uuid: "instance"}, "alice", "ops", 80, 24)
+	assert.ErrorIs(t, err, ErrShellNotConnected)
+}
+
+func TestShellClientUseCase_Handle(t *testing.T) {
+	shellClientUseCase := NewShellClientUseCase(zap.NewNop(), &ShellClientPolicy{Enabled: true})
+	if !shellClientUseCase.Enabled() {
+		t.Skip("bash not found")
+	}
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	
+	sendMsg := make(chan ClientMessage, 100)
+	shellClientUseCase.Handle(ctx, ShellFrame{SessionId: "session", Type: ShellOpen, Cols: 80, Rows: 24}, sendMsg)
+	shellClientUseCase.Handle(ctx, ShellFrame{SessionId: "session", Type: ShellData, Data: []byte("echo camp-$((1+1))\rexit\r")}, sendMsg)
+	
+	var output bytes.Buffer
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case msg := <-sendMsg:
+			if msg.Shell.Type == ShellData {
+				output.Write(msg.Shell.Data)
+				continue
+			}
+			
+			assert.Equal(t, ShellClose, msg.Shell.Type)
+			assert.Contains(t, output.String(), "camp-2")
+			return
+		
+		case <-timeout:
+			t.Fatalf("终端未退出, output: %s", output.String())
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package biz
+
+import (
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// soldier 离线缓存
+//
+// 与 commander 断开期间，指令结果及遥测消息按顺序写入本地目录 (每条消息一个文件)，重连后按顺序发送，发送成功后删除
+// 超过 MaxSize 时丢弃最早的消息，超过 MaxAge 的消息不再发送；soldier 重启后继续发送目录中的消息，
+// commander 以新会话接收时，重启前已下发指令的结果仍然保存 (见会话恢复)
+
+const (
+	DefaultSpoolMaxSize = 64 << 20
+	DefaultSpoolMaxAge  = 24 * time.Hour
+	
+	spoolFileSuffix = ".json"
+)
+
+// Dir 为空时不缓存
+
+type SpoolPolicy struct {
+	Dir     string
+	MaxSize int64
+	MaxAge  time.Duration
+}
+
+type Spool struct {
+	logger  *zap.Logger
+	dir     string
+	maxSize int64
+	maxAge  time.Duration
+	
+	mu      sync.Mutex
+	entries []spoolEntry
+	size    int64
+	seq     uint64
+}
+
+type spoolEntry struct {
+	seq     uint64
+	size    int64
+	created time.Time
+}
+
+func NewSpool(logger *zap.Logger, policy *SpoolPolicy) *Spool {
+	spool := &Spool{
+		logger:  logger,
+		maxSize: policy.MaxSize,
+		maxAge:  policy.MaxAge,
+	}
+	
+	if spool.maxSize <= 0 {
+		spool.maxSize = DefaultSpoolMaxSize
+	}
+	
+	if spool.maxAge <= 0 {
+		spool.maxAge = DefaultSpoolMaxAge
+	}
+	
+	if policy.Dir == "" {
+		return spool
+	}
+	
+	err := spool.load(policy.Dir)
+	if err != nil {
+		logger.Error("初始化离线缓存目录失败，不缓存离线消息", zap.String("dir", policy.Dir), zap.Error(err))
+		return spool
+	}
+	
+	if len(spool.entries) > 0 {
+		logger.Info("加载离线缓存消息", zap.String("dir", spool.dir), zap.Int("count", len(spool.entries)), zap.Int64("size", spool.size))
+	}
+	return spool
+}
+
+// 读取目录中上次运行遗留的消息
+
+func (spool *Spool) load(dir string) error {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return err
+	}
+	
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	
+	for _, file := range files {
+		// 上次写入未完成的临时文件
+		if strings.HasSuffix(file.Name(), spoolFileSuffix+".tmp") {
+			os.Remove(filepath.Join(dir, file.Name()))
+			continue
+		}
+		
+		if file.IsDir() || !strings.HasSuffix(file.Name(), spoolFileSuffix) {
+			continue
+		}
+		
+		seq, err := strconv.ParseUint(strings.TrimSuffix(file.Name(), spoolFileSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		
+		spool.entries = append(spool.entries, spoolEntry{seq: seq, size: info.Size(), created: info.ModTime()})
+		spool.size += info.Size()
+		if seq >= spool.seq {
+			spool.seq = seq + 1
+		}
+	}
+	
+	sort.Slice(spool.entries, func(i, j int) bool {
+		return spool.entries[i].seq < spool.entries[j].seq
+	})
+	
+	spool.dir = dir
+	spool.mu.Lock()
+	spool.evict(time.Now())
+	spool.mu.Unlock()
+	return nil
+}
+
+func (spool *Spool) Enabled() bool {
+	return spool.dir != ""
+}
+
+func (spool *Spool) Len() int {
+	spool.mu.Lock()
+	defer spool.mu.Unlock()
+	return len(spool.entries)
+}
+
+func (spool *Spool) path(seq uint64) string {
+	return filepath.Join(spool.dir, fmt.Sprintf("%020d%s", seq, spoolFileSuffix))
+}
+
+// 写入一条消息，先写临时文件再重命名，避免进程退出时留下不完整的消息
+
+func (spool *Spool) Append(msg ClientMessage) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	
+	spool.mu.Lock()
+	defer spool.mu.Unlock()
+	
+	seq := spool.seq
+	tmp := spool.path(seq) + ".tmp"
+	err = os.WriteFile(tmp, b, 0600)
+	if err != nil {
+		return err
+	}
+	
+	err = os.Rename(tmp, spool.path(seq))
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	
+	spool.seq++
+	spool.entries = append(spool.entries, spoolEntry{seq: seq, size: int64(len(b)), created: time.Now()})
+	spool.size += int64(len(b))
+	spool.evict(time.Now())
+	return nil
+}
+
+// 删除超过有效期及超过大小上限的最早消息，调用方持有锁
+
+func (spool *Spool) evict(now time.Time) {
+	dropped := 0
+	for len(spool.entries) > 0 {
+		entry := spool.entries[0]
+		if spool.size <= spool.maxSize && now.Sub(entry.created) <= spool.maxAge {
+			break
+		}
+		
+		os.Remove(spool.path(entry.seq))
+		spool.entries = spool.entries[1:]
+		spool.size -= entry.size
+		dropped++
+	}
+	
+	if dropped > 0 {
+		spool.logger.Warn("离线缓存超过大小或有效期，丢弃最早的消息", zap.Int("count", dropped))
+	}
+}
+
+// 按写入顺序发送缓存的消息，send 成功后删除；send 返回错误时停止，未发送的消息保留到下次连接
+
+func (spool *Spool) Flush(send func(msg ClientMessage) error) error {
+	for {
+		spool.mu.Lock()
+		spool.evict(time.Now())
+		if len(spool.entries) == 0 {
+			spool.mu.Unlock()
+			return nil
+		}
+		entry := spool.entries[0]
+		spool.mu.Unlock()
+		
+		var msg ClientMessage
+		b, err := os.ReadFile(spool.path(entry.seq))
+		if err == nil {
+			err = json.Unmarshal(b, &msg)
+		}
+		
+		if err != nil {
+			spool.logger.Error("读取离线缓存消息失败，丢弃该消息", zap.Uint64("seq", entry.seq), zap.Error(err))
+		} else {
+			err = send(msg)
+			if err != nil {
+				return err
+			}
+		}
+		
+		spool.remove(entry)
+	}
+}
+
+func (spool *Spool) remove(entry spoolEntry) {
+	spool.mu.Lock()
+	defer spool.mu.Unlock()
+	
+	if len(spool.entries) == 0 || spool.entries[0].seq != entry.seq {
+		return
+	}
+	
+	os.Remove(spool.path(entry.seq))
+	spool.entries = spool.entries[1:]
+	spool.size -= entry.size
+}
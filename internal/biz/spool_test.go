@@ -0,0 +1,93 @@
+package biz
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func spoolReply(uuid string) ClientMessage {
+	return ClientMessage{Type: ClientInstructReply, InstructMessage: InstructMessage{Uuid: uuid, Type: CommandInstruct, CommandReply: "ok", Result: true}}
+}
+
+// 按写入顺序发送，发送失败时保留，重启后继续发送
+
+func TestSpool_Flush(t *testing.T) {
+	dir := t.TempDir()
+	spool := NewSpool(zap.NewNop(), &SpoolPolicy{Dir: dir})
+	assert.True(t, spool.Enabled())
+	
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, spool.Append(spoolReply(strconv.Itoa(i))))
+	}
+	assert.NoError(t, spool.Append(ClientMessage{Type: ClientHostMetrics, Metrics: &HostMetrics{CpuPercent: 12.5}}))
+	
+	var sent []string
+	errSend := errors.New("send")
+	err := spool.Flush(func(msg ClientMessage) error {
+		if len(sent) == 2 {
+			return errSend
+		}
+		sent = append(sent, msg.InstructMessage.Uuid)
+		return nil
+	})
+	assert.ErrorIs(t, err, errSend)
+	assert.Equal(t, []string{"0", "1"}, sent)
+	assert.Equal(t, 4, spool.Len())
+	
+	// 重启后加载未发送的消息，忽略未完成的临时文件
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000099.json.tmp"), []byte("{"), 0600))
+	spool = NewSpool(zap.NewNop(), &SpoolPolicy{Dir: dir})
+	assert.Equal(t, 4, spool.Len())
+	
+	var messages []ClientMessage
+	assert.NoError(t, spool.Flush(func(msg ClientMessage) error {
+		messages = append(messages, msg)
+		return nil
+	}))
+	assert.Len(t, messages, 4)
+	assert.Equal(t, "2", messages[0].InstructMessage.Uuid)
+	assert.Equal(t, 12.5, messages[3].Metrics.CpuPercent)
+	assert.Equal(t, 0, spool.Len())
+	
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+// 超过大小上限时丢弃最早的消息，超过有效期的消息不再发送
+
+func TestSpool_Evict(t *testing.T) {
+	dir := t.TempDir()
+	spool := NewSpool(zap.NewNop(), &SpoolPolicy{Dir: dir, MaxSize: 400})
+	
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, spool.Append(spoolReply(strconv.Itoa(i))))
+	}
+	assert.Less(t, spool.Len(), 20)
+	
+	var first string
+	assert.NoError(t, spool.Flush(func(msg ClientMessage) error {
+		if first == "" {
+			first = msg.InstructMessage.Uuid
+		}
+		return nil
+	}))
+	assert.NotEqual(t, "0", first)
+	
+	spool = NewSpool(zap.NewNop(), &SpoolPolicy{Dir: dir, MaxAge: time.Millisecond})
+	assert.NoError(t, spool.Append(spoolReply("expired")))
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, spool.Flush(func(msg ClientMessage) error {
+		t.Errorf("发送了过期的消息 %s", msg.InstructMessage.Uuid)
+		return nil
+	}))
+	
+	// 未指定目录时不缓存
+	assert.False(t, NewSpool(zap.NewNop(), &SpoolPolicy{}).Enabled())
+}
@@ -0,0 +1,714 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 端口转发
+//
+// 操作端通过 commander 的 websocket 接口打开 TCP 流，commander 通过 soldier 连接转发 TunnelFrame，
+// 同一 soldier 连接上的多个流以 streamId 区分:
+// commander -> soldier: TunnelOpen (目标地址及初始窗口) / TunnelData / TunnelWindow / TunnelClose
+// soldier -> commander: TunnelConnected (初始窗口) / TunnelData / TunnelWindow / TunnelClose
+// 流控: 发送方未确认的数据不超过对端通告的窗口，接收方写出数据后以 TunnelWindow 归还窗口
+// 转发以 TunnelInstruct 指令记录到指令表中
+
+type TunnelFrameType int32
+
+const (
+	TunnelOpen      TunnelFrameType = 1
+	TunnelConnected TunnelFrameType = 2
+	TunnelData      TunnelFrameType = 3
+	TunnelWindow    TunnelFrameType = 4
+	TunnelClose     TunnelFrameType = 5
+)
+
+const (
+	tunnelWindowSize    = 256 * 1024
+	tunnelChunkSize     = 32 * 1024
+	tunnelOutputBuffer  = 64
+	tunnelPendingFrames = 1024 // soldier 端每个流待写出的帧数上限，超过说明对端未遵守窗口
+	tunnelOpenTimeout   = 15 * time.Second
+	tunnelDialTimeout   = 10 * time.Second
+)
+
+var (
+	ErrTunnelForbidden        = errors.New("当前角色不允许端口转发")
+	ErrTunnelTargetNotAllowed = errors.New("目标地址不在允许范围内")
+	ErrTunnelNotConnected     = errors.New("目标实例未连接到当前节点")
+	ErrTunnelDialFailed       = errors.New("目标地址连接失败")
+	ErrTunnelClosed           = errors.New("端口转发已关闭")
+)
+
+type TunnelFrame struct {
+	StreamId string          `json:"streamId,omitempty"`
+	Type     TunnelFrameType `json:"type,omitempty"`
+	Addr     string          `json:"addr,omitempty"`
+	Data     []byte          `json:"data,omitempty"`
+	Window   uint32          `json:"window,omitempty"`
+	Reason   string          `json:"reason,omitempty"`
+}
+
+// 端口转发审计信息，打开时作为指令内容，结束时作为指令结果
+
+type TunnelInfo struct {
+	Operator  string `json:"operator,omitempty"`
+	Role      string `json:"role,omitempty"`
+	Addr      string `json:"addr,omitempty"`
+	StartTime int64  `json:"startTime,omitempty"`
+	EndTime   int64  `json:"endTime,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	BytesIn   int64  `json:"bytesIn,omitempty"`  // soldier -> 操作端
+	BytesOut  int64  `json:"bytesOut,omitempty"` // 操作端 -> soldier
+}
+
+func (tunnelInfo *TunnelInfo) String() string {
+	b, err := json.Marshal(tunnelInfo)
+	if err != nil {
+		return err.Error()
+	}
+	
+	return string(b)
+}
+
+// 组织允许转发的目标网段及端口，Ports 为空时允许所有端口
+
+type TunnelRule struct {
+	OrgUuid string
+	Cidrs   []*net.IPNet
+	Ports   [][2]int
+}
+
+func ParseTunnelRule(orgUuid string, cidrs, ports []string) (TunnelRule, error) {
+	rule := TunnelRule{OrgUuid: orgUuid}
+	
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return rule, err
+		}
+		rule.Cidrs = append(rule.Cidrs, ipNet)
+	}
+	
+	for _, port := range ports {
+		from, to, found := strings.Cut(strings.TrimSpace(port), "-")
+		if !found {
+			to = from
+		}
+		
+		fromPort, err := strconv.Atoi(from)
+		if err != nil {
+			return rule, err
+		}
+		
+		toPort, err := strconv.Atoi(to)
+		if err != nil {
+			return rule, err
+		}
+		
+		if fromPort < 1 || toPort > 65535 || fromPort > toPort {
+			return rule, fmt.Errorf("端口范围异常: %s", port)
+		}
+		rule.Ports = append(rule.Ports, [2]int{fromPort, toPort})
+	}
+	
+	return rule, nil
+}
+
+func (tunnelRule *TunnelRule) match(ip net.IP, port int) bool {
+	matchPort := len(tunnelRule.Ports) == 0
+	for _, ports := range tunnelRule.Ports {
+		if port >= ports[0] && port <= ports[1] {
+			matchPort = true
+			break
+		}
+	}
+	
+	if !matchPort {
+		return false
+	}
+	
+	for _, cidr := range tunnelRule.Cidrs {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+	
+	return false
+}
+
+// 端口转发策略，AllowedRoles 为空、角色未经网关签名 (为空) 或组织未配置规则时禁止转发
+
+type TunnelPolicy struct {
+	AllowedRoles []string
+	Rules        []TunnelRule
+}
+
+// 目标地址须为 ip:port，避免 soldier 解析域名后绕过网段限制
+
+func (tunnelPolicy *TunnelPolicy) Allow(orgUuid, role, addr string) error {
+	allowRole := false
+	for _, allowedRole := range tunnelPolicy.AllowedRoles {
+		if role != "" && allowedRole == role {
+			allowRole = true
+			break
+		}
+	}
+	
+	if !allowRole {
+		return ErrTunnelForbidden
+	}
+	
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return ErrTunnelTargetNotAllowed
+	}
+	
+	ip := net.ParseIP(host)
+	port, err := strconv.Atoi(portStr)
+	if ip == nil || err != nil {
+		return ErrTunnelTargetNotAllowed
+	}
+	
+	for _, rule := range tunnelPolicy.Rules {
+		if rule.OrgUuid == orgUuid && rule.match(ip, port) {
+			return nil
+		}
+	}
+	
+	return ErrTunnelTargetNotAllowed
+}
+
+// 发送窗口，acquire 在窗口耗尽时阻塞直到对端归还窗口或关闭
+
+type flowWindow struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	credit int
+	closed bool
+}
+
+func newFlowWindow(credit int) *flowWindow {
+	window := &flowWindow{credit: credit}
+	window.cond = sync.NewCond(&window.mu)
+	return window
+}
+
+// 获取不超过 n 的窗口，关闭后返回 0
+
+func (window *flowWindow) acquire(n int) int {
+	window.mu.Lock()
+	defer window.mu.Unlock()
+	
+	for window.credit <= 0 && !window.closed {
+		window.cond.Wait()
+	}
+	
+	if window.closed {
+		return 0
+	}
+	
+	if n > window.credit {
+		n = window.credit
+	}
+	window.credit -= n
+	return n
+}
+
+func (window *flowWindow) release(n int) {
+	window.mu.Lock()
+	window.credit += n
+	window.mu.Unlock()
+	window.cond.Broadcast()
+}
+
+func (window *flowWindow) close() {
+	window.mu.Lock()
+	window.closed = true
+	window.mu.Unlock()
+	window.cond.Broadcast()
+}
+
+type TunnelUseCase struct {
+	connRegistry *ConnRegistry
+	instructRepo InstructRepo
+	policy       TunnelPolicy
+	logger       *zap.Logger
+	
+	mu      sync.Mutex
+	streams map[string]*TunnelStream
+}
+
+func NewTunnelUseCase(policy *TunnelPolicy, connRegistry *ConnRegistry, instructRepo InstructRepo, logger *zap.Logger) *TunnelUseCase {
+	tunnelUseCase := &TunnelUseCase{
+		connRegistry: connRegistry,
+		instructRepo: instructRepo,
+		policy:       *policy,
+		logger:       logger,
+		streams:      make(map[string]*TunnelStream),
+	}
+	
+	connRegistry.OnDetach(tunnelUseCase.detach)
+	return tunnelUseCase
+}
+
+// soldier 连接断开，关闭该连接上的所有流
+
+func (tunnelUseCase *TunnelUseCase) detach(serviceChannels *ServiceChannels) {
+	tunnelUseCase.mu.Lock()
+	var streams []*TunnelStream
+	for _, stream := range tunnelUseCase.streams {
+		if stream.send == serviceChannels.Queue(BulkChannel) {
+			streams = append(streams, stream)
+		}
+	}
+	tunnelUseCase.mu.Unlock()
+	
+	for _, stream := range streams {
+		stream.finish("soldier 连接断开")
+	}
+}
+
+// 打开到 addr 的 TCP 流，等待 soldier 连接目标地址后返回
+
+func (tunnelUseCase *TunnelUseCase) Open(ctx context.Context, instance Instance, operator, role, addr string) (*TunnelStream, error) {
+	err := tunnelUseCase.policy.Allow(instance.OrgUuid, role, addr)
+	if err != nil {
+		return nil, err
+	}
+	
+	serviceChannels, ok := tunnelUseCase.connRegistry.Get(instance.Uuid)
+	if !ok {
+		return nil, ErrTunnelNotConnected
+	}
+	
+	now := time.Now()
+	stream := &TunnelStream{
+		Uuid:         uuid.NewString(),
+		InstanceUuid: instance.Uuid,
+		info: TunnelInfo{
+			Operator:  operator,
+			Role:      role,
+			Addr:      addr,
+			StartTime: now.Unix(),
+		},
+		tunnelUseCase: tunnelUseCase,
+		send:          serviceChannels.Queue(BulkChannel),
+		sendWindow:    newFlowWindow(0),
+		output:        make(chan []byte, tunnelOutputBuffer),
+		connected:     make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	
+	// 转发记录到指令表，作为审计记录
+	err = tunnelUseCase.instructRepo.RecordInstruct(ctx, Instruct{
+		Uuid:         stream.Uuid,
+		OrgUuid:      instance.OrgUuid,
+		GroupUuid:    instance.GroupUuid,
+		InstanceName: instance.InstanceName,
+		Type:         TunnelInstruct,
+		Content:      stream.info.String(),
+		CreateTime:   now.Unix(),
+		UpdateTime:   now.Unix(),
+		Operator:     operator,
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	tunnelUseCase.mu.Lock()
+	tunnelUseCase.streams[stream.Uuid] = stream
+	tunnelUseCase.mu.Unlock()
+	
+	tunnelUseCase.logger.Info("打开端口转发",
+		zap.String("streamId", stream.Uuid),
+		zap.String("instanceUuid", instance.Uuid),
+		zap.String("addr", addr),
+		zap.String("operator", operator),
+		zap.String("role", role),
+	)
+	
+	stream.sendFrame(TunnelFrame{Type: TunnelOpen, Addr: addr, Window: tunnelWindowSize})
+	
+	timer := time.NewTimer(tunnelOpenTimeout)
+	defer timer.Stop()
+	
+	select {
+	case <-stream.connected:
+		return stream, nil
+	case <-stream.done:
+		return nil, fmt.Errorf("%w: %s", ErrTunnelDialFailed, stream.Reason())
+	case <-timer.C:
+		stream.Close("连接目标地址超时")
+		return nil, fmt.Errorf("%w: 连接超时", ErrTunnelDialFailed)
+	case <-ctx.Done():
+		stream.Close("操作端断开")
+		return nil, ctx.Err()
+	}
+}
+
+// 处理 soldier 回传的端口转发帧
+
+func (tunnelUseCase *TunnelUseCase) Deliver(frame TunnelFrame) {
+	tunnelUseCase.mu.Lock()
+	stream, ok := tunnelUseCase.streams[frame.StreamId]
+	tunnelUseCase.mu.Unlock()
+	if !ok {
+		return
+	}
+	
+	switch frame.Type {
+	case TunnelConnected:
+		stream.sendWindow.release(int(frame.Window))
+		stream.connectOnce.Do(func() {
+			close(stream.connected)
+		})
+	
+	case TunnelData:
+		// soldier 未确认的数据不超过通告的窗口，正常情况下不会阻塞
+		stream.bytesIn.Add(int64(len(frame.Data)))
+		select {
+		case stream.output <- frame.Data:
+		case <-stream.done:
+		}
+	
+	case TunnelWindow:
+		stream.sendWindow.release(int(frame.Window))
+	
+	case TunnelClose:
+		stream.finish(frame.Reason)
+	}
+}
+
+type TunnelStream struct {
+	Uuid         string
+	InstanceUuid string
+	
+	info          TunnelInfo
+	tunnelUseCase *TunnelUseCase
+	send          chan ServiceMessage
+	sendWindow    *flowWindow // soldier 通告的接收窗口
+	output        chan []byte
+	bytesIn       atomic.Int64
+	bytesOut      atomic.Int64
+	
+	connectOnce sync.Once
+	connected   chan struct{}
+	closeOnce   sync.Once
+	done        chan struct{}
+	reason      string
+}
+
+// soldier 回传的数据，读取并写出后须调用 Ack 归还窗口
+
+func (tunnelStream *TunnelStream) Output() <-chan []byte {
+	return tunnelStream.output
+}
+
+func (tunnelStream *TunnelStream) Done() <-chan struct{} {
+	return tunnelStream.done
+}
+
+// 流结束原因，Done 之后有效
+
+func (tunnelStream *TunnelStream) Reason() string {
+	return tunnelStream.reason
+}
+
+// 发送数据到目标地址，窗口耗尽时阻塞
+
+func (tunnelStream *TunnelStream) Write(data []byte) error {
+	for len(data) > 0 {
+		n := len(data)
+		if n > tunnelChunkSize {
+			n = tunnelChunkSize
+		}
+		
+		n = tunnelStream.sendWindow.acquire(n)
+		if n == 0 {
+			return ErrTunnelClosed
+		}
+		
+		// 消息在连接的发送协程中编码，复制数据以免调用方复用缓冲区
+		tunnelStream.sendFrame(TunnelFrame{Type: TunnelData, Data: bytes.Clone(data[:n])})
+		tunnelStream.bytesOut.Add(int64(n))
+		data = data[n:]
+	}
+	
+	return nil
+}
+
+func (tunnelStream *TunnelStream) Ack(n int) {
+	tunnelStream.sendFrame(TunnelFrame{Type: TunnelWindow, Window: uint32(n)})
+}
+
+// 操作端关闭流
+
+func (tunnelStream *TunnelStream) Close(reason string) {
+	tunnelStream.sendFrame(TunnelFrame{Type: TunnelClose, Reason: reason})
+	tunnelStream.finish(reason)
+}
+
+func (tunnelStream *TunnelStream) sendFrame(frame TunnelFrame) {
+	frame.StreamId = tunnelStream.Uuid
+	select {
+	case tunnelStream.send <- ServiceMessage{Type: ServiceTunnel, Tunnel: &frame}:
+	case <-tunnelStream.done:
+	}
+}
+
+// 结束流并更新审计记录
+
+func (tunnelStream *TunnelStream) finish(reason string) {
+	tunnelStream.closeOnce.Do(func() {
+		tunnelUseCase := tunnelStream.tunnelUseCase
+		
+		tunnelUseCase.mu.Lock()
+		delete(tunnelUseCase.streams, tunnelStream.Uuid)
+		tunnelUseCase.mu.Unlock()
+		
+		tunnelStream.reason = reason
+		close(tunnelStream.done)
+		tunnelStream.sendWindow.close()
+		
+		info := tunnelStream.info
+		info.EndTime = time.Now().Unix()
+		info.Reason = reason
+		info.BytesIn = tunnelStream.bytesIn.Load()
+		info.BytesOut = tunnelStream.bytesOut.Load()
+		
+		_, err := tunnelUseCase.instructRepo.UpdateInstruct(context.Background(), tunnelStream.Uuid, info.String(), 1)
+		if err != nil {
+			tunnelUseCase.logger.Error("更新端口转发记录失败", zap.String("streamId", tunnelStream.Uuid), zap.Error(err))
+		}
+		
+		tunnelUseCase.logger.Info("关闭端口转发",
+			zap.String("streamId", tunnelStream.Uuid),
+			zap.String("reason", reason),
+			zap.Int64("bytesIn", info.BytesIn),
+			zap.Int64("bytesOut", info.BytesOut),
+		)
+	})
+}
+
+// soldier 端端口转发，每个流对应一个到目标地址的 TCP 连接
+
+type TunnelClientPolicy struct {
+	Enabled bool
+}
+
+type TunnelClientUseCase struct {
+	logger  *zap.Logger
+	enabled bool
+	
+	mu      sync.Mutex
+	streams map[string]*tunnelClientStream
+}
+
+type tunnelClientStream struct {
+	id         string
+	conn       net.Conn
+	sendWindow *flowWindow // commander 通告的接收窗口
+	pending    chan []byte // 待写出到目标地址的数据
+	closeOnce  sync.Once
+	done       chan struct{}
+}
+
+func NewTunnelClientUseCase(logger *zap.Logger, policy *TunnelClientPolicy) *TunnelClientUseCase {
+	return &TunnelClientUseCase{
+		logger:  logger,
+		enabled: policy.Enabled,
+		streams: make(map[string]*tunnelClientStream),
+	}
+}
+
+func (tunnelClientUseCase *TunnelClientUseCase) Enabled() bool {
+	return tunnelClientUseCase.enabled
+}
+
+// 处理 commander 下发的端口转发帧
+
+func (tunnelClientUseCase *TunnelClientUseCase) Handle(ctx context.Context, frame TunnelFrame, sendMsg chan ClientMessage) {
+	switch frame.Type {
+	case TunnelOpen:
+		if !tunnelClientUseCase.Enabled() {
+			tunnelClientUseCase.sendFrame(ctx, sendMsg, TunnelFrame{StreamId: frame.StreamId, Type: TunnelClose, Reason: "soldier 未开启端口转发"})
+			return
+		}
+		
+		stream := &tunnelClientStream{
+			id:         frame.StreamId,
+			sendWindow: newFlowWindow(int(frame.Window)),
+			pending:    make(chan []byte, tunnelPendingFrames),
+			done:       make(chan struct{}),
+		}
+		
+		tunnelClientUseCase.mu.Lock()
+		tunnelClientUseCase.streams[stream.id] = stream
+		tunnelClientUseCase.mu.Unlock()
+		
+		// 连接目标地址耗时较长，不阻塞消息处理
+		go tunnelClientUseCase.open(ctx, stream, frame.Addr, sendMsg)
+	
+	case TunnelData:
+		stream := tunnelClientUseCase.get(frame.StreamId)
+		if stream == nil {
+			return
+		}
+		
+		select {
+		case stream.pending <- frame.Data:
+		default:
+			tunnelClientUseCase.close(stream)
+			tunnelClientUseCase.sendFrame(ctx, sendMsg, TunnelFrame{StreamId: stream.id, Type: TunnelClose, Reason: "超过接收窗口"})
+		}
+	
+	case TunnelWindow:
+		stream := tunnelClientUseCase.get(frame.StreamId)
+		if stream != nil {
+			stream.sendWindow.release(int(frame.Window))
+		}
+	
+	case TunnelClose:
+		stream := tunnelClientUseCase.get(frame.StreamId)
+		if stream != nil {
+			tunnelClientUseCase.close(stream)
+		}
+	}
+}
+
+// 与 commander 的连接断开时关闭所有流
+
+func (tunnelClientUseCase *TunnelClientUseCase) CloseAll() {
+	tunnelClientUseCase.mu.Lock()
+	streams := make([]*tunnelClientStream, 0, len(tunnelClientUseCase.streams))
+	for _, stream := range tunnelClientUseCase.streams {
+		streams = append(streams, stream)
+	}
+	tunnelClientUseCase.mu.Unlock()
+	
+	for _, stream := range streams {
+		tunnelClientUseCase.close(stream)
+	}
+}
+
+func (tunnelClientUseCase *TunnelClientUseCase) get(streamId string) *tunnelClientStream {
+	tunnelClientUseCase.mu.Lock()
+	defer tunnelClientUseCase.mu.Unlock()
+	return tunnelClientUseCase.streams[streamId]
+}
+
+func (tunnelClientUseCase *TunnelClientUseCase) close(stream *tunnelClientStream) {
+	stream.closeOnce.Do(func() {
+		tunnelClientUseCase.mu.Lock()
+		delete(tunnelClientUseCase.streams, stream.id)
+		conn := stream.conn
+		tunnelClientUseCase.mu.Unlock()
+		
+		close(stream.done)
+		stream.sendWindow.close()
+		if conn != nil {
+			conn.Close()
+		}
+	})
+}
+
+func (tunnelClientUseCase *TunnelClientUseCase) sendFrame(ctx context.Context, sendMsg chan ClientMessage, frame TunnelFrame) {
+	select {
+	case sendMsg <- ClientMessage{Type: ClientTunnel, Tunnel: &frame}:
+	case <-ctx.Done():
+	}
+}
+
+func (tunnelClientUseCase *TunnelClientUseCase) open(ctx context.Context, stream *tunnelClientStream, addr string, sendMsg chan ClientMessage) {
+	dialer := net.Dialer{Timeout: tunnelDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		tunnelClientUseCase.logger.Warn("连接转发目标失败", zap.String("streamId", stream.id), zap.String("addr", addr), zap.Error(err))
+		tunnelClientUseCase.close(stream)
+		tunnelClientUseCase.sendFrame(ctx, sendMsg, TunnelFrame{StreamId: stream.id, Type: TunnelClose, Reason: err.Error()})
+		return
+	}
+	
+	tunnelClientUseCase.mu.Lock()
+	_, ok := tunnelClientUseCase.streams[stream.id]
+	if ok {
+		stream.conn = conn
+	}
+	tunnelClientUseCase.mu.Unlock()
+	
+	// 连接过程中 commander 已关闭流
+	if !ok {
+		conn.Close()
+		return
+	}
+	
+	tunnelClientUseCase.logger.Info("打开端口转发", zap.String("streamId", stream.id), zap.String("addr", addr))
+	tunnelClientUseCase.sendFrame(ctx, sendMsg, TunnelFrame{StreamId: stream.id, Type: TunnelConnected, Window: tunnelWindowSize})
+	
+	// 写出 commander 下发的数据并归还窗口
+	go func() {
+		for {
+			select {
+			case data := <-stream.pending:
+				_, err := conn.Write(data)
+				if err != nil {
+					tunnelClientUseCase.closeWithReason(ctx, stream, sendMsg, err.Error())
+					return
+				}
+				tunnelClientUseCase.sendFrame(ctx, sendMsg, TunnelFrame{StreamId: stream.id, Type: TunnelWindow, Window: uint32(len(data))})
+			
+			case <-stream.done:
+				return
+			}
+		}
+	}()
+	
+	// 读取目标地址返回的数据，不超过 commander 通告的窗口
+	buf := make([]byte, tunnelChunkSize)
+	for {
+		n, err := conn.Read(buf)
+		data := buf[:n]
+		for len(data) > 0 {
+			size := stream.sendWindow.acquire(len(data))
+			if size == 0 {
+				return
+			}
+			
+			chunk := make([]byte, size)
+			copy(chunk, data[:size])
+			tunnelClientUseCase.sendFrame(ctx, sendMsg, TunnelFrame{StreamId: stream.id, Type: TunnelData, Data: chunk})
+			data = data[size:]
+		}
+		
+		if err != nil {
+			tunnelClientUseCase.closeWithReason(ctx, stream, sendMsg, err.Error())
+			return
+		}
+	}
+}
+
+// soldier 端主动关闭流并通知 commander，commander 已关闭的流不再通知
+
+func (tunnelClientUseCase *TunnelClientUseCase) closeWithReason(ctx context.Context, stream *tunnelClientStream, sendMsg chan ClientMessage, reason string) {
+	select {
+	case <-stream.done:
+		return
+	default:
+	}
+	
+	tunnelClientUseCase.close(stream)
+	tunnelClientUseCase.sendFrame(ctx, sendMsg, TunnelFrame{StreamId: stream.id, Type: TunnelClose, Reason: reason})
+}
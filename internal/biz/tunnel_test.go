@@ -0,0 +1,153 @@
+package biz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTunnelPolicy_Allow(t *testing.T) {
+	rule, err := ParseTunnelRule("org", []string{"10.0.0.0/8", "192.168.1.10/32"}, []string{"22", "8000-8080"})
+	assert.NoError(t, err)
+	
+	tunnelPolicy := &TunnelPolicy{AllowedRoles: []string{"ops"}, Rules: []TunnelRule{rule}}
+	
+	assert.NoError(t, tunnelPolicy.Allow("org", "ops", "10.1.2.3:22"))
+	assert.NoError(t, tunnelPolicy.Allow("org", "ops", "192.168.1.10:8080"))
+	assert.ErrorIs(t, tunnelPolicy.Allow("org", "dev", "10.1.2.3:22"), ErrTunnelForbidden)
+	assert.ErrorIs(t, tunnelPolicy.Allow("org", "ops", "10.1.2.3:3306"), ErrTunnelTargetNotAllowed)
+	assert.ErrorIs(t, tunnelPolicy.Allow("org", "ops", "192.168.1.11:22"), ErrTunnelTargetNotAllowed)
+	assert.ErrorIs(t, tunnelPolicy.Allow("other", "ops", "10.1.2.3:22"), ErrTunnelTargetNotAllowed)
+	assert.ErrorIs(t, tunnelPolicy.Allow("org", "ops", "db.internal:22"), ErrTunnelTargetNotAllowed)
+	
+	_, err = ParseTunnelRule("org", []string{"10.0.0.0/8"}, []string{"8080-8000"})
+	assert.Error(t, err)
+}
+
+func TestFlowWindow(t *testing.T) {
+	window := newFlowWindow(10)
+	assert.Equal(t, 4, window.acquire(4))
+	assert.Equal(t, 6, window.acquire(100))
+	
+	acquired := make(chan int)
+	go func() {
+		acquired <- window.acquire(8)
+	}()
+	
+	select {
+	case <-acquired:
+		t.Fatal("窗口耗尽时应阻塞")
+	case <-time.After(50 * time.Millisecond):
+	}
+	
+	window.release(5)
+	assert.Equal(t, 5, <-acquired)
+	
+	go func() {
+		acquired <- window.acquire(1)
+	}()
+	window.close()
+	assert.Equal(t, 0, <-acquired)
+}
+
+// commander 与 soldier 之间通过通道转发消息，soldier 连接本地 echo 服务
+
+func TestTunnelUseCase_Echo(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(conn, conn)
+				conn.Close()
+			}()
+		}
+	}()
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	
+	instructRepo, _ := newFakeRepos()
+	rule, err := ParseTunnelRule("org", []string{"127.0.0.0/8"}, nil)
+	assert.NoError(t, err)
+	
+	connRegistry := NewConnRegistry()
+	tunnelUseCase := NewTunnelUseCase(&TunnelPolicy{AllowedRoles: []string{"ops"}, Rules: []TunnelRule{rule}}, connRegistry, instructRepo, zap.NewNop())
+	tunnelClientUseCase := NewTunnelClientUseCase(zap.NewNop(), &TunnelClientPolicy{Enabled: true})
+	
+	serviceChannels := NewServiceChannels()
+	clientSend := make(chan ClientMessage)
+	connRegistry.Attach("instance", serviceChannels)
+	
+	go func() {
+		for {
+			select {
+			case serviceMessage := <-serviceChannels.Queue(BulkChannel):
+				tunnelClientUseCase.Handle(ctx, *serviceMessage.Tunnel, clientSend)
+			case clientMessage := <-clientSend:
+				tunnelUseCase.Deliver(*clientMessage.Tunnel)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	
+	instance := Instance{Uuid: "instance", OrgUuid: "org", GroupUuid: "group", InstanceName: "name"}
+	stream, err := tunnelUseCase.Open(ctx, instance, "alice", "ops", listener.Addr().String())
+	assert.NoError(t, err)
+	
+	// 超过窗口大小的数据，须依赖窗口归还才能完成
+	payload := bytes.Repeat([]byte("0123456789abcdef"), tunnelWindowSize/4)
+	go func() {
+		assert.NoError(t, stream.Write(payload))
+	}()
+	
+	var received []byte
+	timeout := time.After(10 * time.Second)
+	for len(received) < len(payload) {
+		select {
+		case data := <-stream.Output():
+			received = append(received, data...)
+			stream.Ack(len(data))
+		case <-timeout:
+			t.Fatalf("接收超时: %d/%d", len(received), len(payload))
+		}
+	}
+	assert.Equal(t, payload, received)
+	
+	stream.Close("done")
+	<-stream.Done()
+	
+	// 审计记录在流结束后更新
+	var info TunnelInfo
+	assert.Eventually(t, func() bool {
+		instructRepo.mu.Lock()
+		defer instructRepo.mu.Unlock()
+		instruct := instructRepo.instruct[stream.Uuid]
+		return instruct.Type == TunnelInstruct && json.Unmarshal([]byte(instruct.Reply), &info) == nil
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(len(payload)), info.BytesIn)
+	assert.Equal(t, int64(len(payload)), info.BytesOut)
+	
+	// 目标地址无法连接
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	closedAddr := closed.Addr().String()
+	closed.Close()
+	
+	_, err = tunnelUseCase.Open(ctx, instance, "alice", "ops", closedAddr)
+	assert.True(t, errors.Is(err, ErrTunnelDialFailed))
+}
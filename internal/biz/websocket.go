@@ -2,12 +2,18 @@ package biz
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gorilla/websocket"
+	"github.com/qx66/camp/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"math/rand"
 	"net/http"
 	"os/exec"
+	"sync"
 	"time"
 )
 
@@ -18,6 +24,14 @@ type WebSocketUseCase struct {
 	icmpClientUseCase        *IcmpClientUseCase
 	socketClientUseCase      *SocketClientUseCase
 	chromeDpClientUseCase    *ChromeDpClientUseCase
+	inventoryClientUseCase   *InventoryClientUseCase
+	metricsClientUseCase     *MetricsClientUseCase
+	fileClientUseCase        *FileClientUseCase
+	shellClientUseCase       *ShellClientUseCase
+	tunnelClientUseCase      *TunnelClientUseCase
+	framePolicy              *FramePolicy
+	spool                    *Spool
+	session                  *ClientSession
 }
 
 func NewWebSocketUseCase(logger *zap.Logger,
@@ -25,7 +39,14 @@ func NewWebSocketUseCase(logger *zap.Logger,
 	httpInspectClientUseCase *HttpInspectClientUseCase,
 	icmpClientUseCase *IcmpClientUseCase,
 	socketClientUseCase *SocketClientUseCase,
-	chromeDpClientUseCase *ChromeDpClientUseCase) *WebSocketUseCase {
+	chromeDpClientUseCase *ChromeDpClientUseCase,
+	inventoryClientUseCase *InventoryClientUseCase,
+	metricsClientUseCase *MetricsClientUseCase,
+	fileClientUseCase *FileClientUseCase,
+	shellClientUseCase *ShellClientUseCase,
+	tunnelClientUseCase *TunnelClientUseCase,
+	framePolicy *FramePolicy,
+	spool *Spool) *WebSocketUseCase {
 	return &WebSocketUseCase{
 		logger:                   logger,
 		dnsClientInspectUseCase:  dnsClientInspectUseCase,
@@ -33,41 +54,224 @@ func NewWebSocketUseCase(logger *zap.Logger,
 		icmpClientUseCase:        icmpClientUseCase,
 		socketClientUseCase:      socketClientUseCase,
 		chromeDpClientUseCase:    chromeDpClientUseCase,
+		inventoryClientUseCase:   inventoryClientUseCase,
+		metricsClientUseCase:     metricsClientUseCase,
+		fileClientUseCase:        fileClientUseCase,
+		shellClientUseCase:       shellClientUseCase,
+		tunnelClientUseCase:      tunnelClientUseCase,
+		framePolicy:              framePolicy,
+		spool:                    spool,
+		session:                  NewClientSession(),
 	}
 }
 
-// 创建 webSocket 连接
+// 重连间隔按指数增长并加入随机抖动，连接保持 reconnectStableDuration 以上后重置
 
-func (webSocketUseCase *WebSocketUseCase) connectWebSocket(wsUrl string, header http.Header) *websocket.Conn {
-	var wsConn *websocket.Conn
-	var err error
+const (
+	reconnectBaseDelay      = time.Second
+	reconnectMaxDelay       = time.Minute
+	reconnectStableDuration = time.Minute
+)
+
+type reconnectBackoff struct {
+	base    time.Duration
+	max     time.Duration
+	attempt int
+}
+
+func newReconnectBackoff() *reconnectBackoff {
+	return &reconnectBackoff{base: reconnectBaseDelay, max: reconnectMaxDelay}
+}
+
+// 返回下一次重连前的等待时间，取 [d/2, d) 之间的随机值，d 为不超过上限的指数间隔
+
+func (backoff *reconnectBackoff) Next() time.Duration {
+	delay := backoff.max
+	if backoff.attempt < 32 && backoff.base<<backoff.attempt < backoff.max {
+		delay = backoff.base << backoff.attempt
+	}
+	backoff.attempt++
+	
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+func (backoff *reconnectBackoff) Reset() {
+	backoff.attempt = 0
+}
+
+// 创建 webSocket 连接，携带会话 token 以恢复会话，返回按 commander 心跳间隔计算的读超时
+
+func (webSocketUseCase *WebSocketUseCase) connectWebSocket(ctx context.Context, wsUrl string, header http.Header, sendChannels *ClientChannels, frameWriter *FrameWriter) (*websocket.Conn, time.Duration, error) {
+	// 优先协商 protobuf 协议，旧版本 commander 不支持时回退为 JSON
+	dialer := *websocket.DefaultDialer
+	dialer.Subprotocols = Subprotocols
+	dialer.EnableCompression = webSocketUseCase.framePolicy.Compression
+	
+	token := webSocketUseCase.session.Token()
+	if token != "" {
+		header.Set(SessionHeader, token)
+	} else {
+		header.Del(SessionHeader)
+	}
+	
+	wsConn, resp, err := dialer.DialContext(ctx, wsUrl, header)
+	if err != nil {
+		return nil, 0, err
+	}
+	
+	// commander 确认支持逻辑通道窗口时按窗口发送，新连接重置窗口
+	mux := resp.Header.Get(MuxHeader) == "1"
+	sendChannels.EnableWindow(mux)
+	
+	// 按 commander 通告的大小分片发送，commander 支持分片时仅接收不超过本端分片大小的消息
+	frameWriter.SetPeer(resp.Header)
+	wsConn.SetReadLimit(webSocketUseCase.framePolicy.ReadLimit(frameWriter.PeerFrameSize()))
+	
+	webSocketUseCase.session.SetToken(resp.Header.Get(SessionHeader))
+	readTimeout := ParseHeartbeatHeader(resp.Header)
+	
+	webSocketUseCase.logger.Info("连接成功",
+		zap.String("subprotocol", wsConn.Subprotocol()),
+		zap.Bool("mux", mux),
+		zap.Int("peerFrameSize", frameWriter.PeerFrameSize()),
+		zap.String("session", resp.Header.Get(SessionHeader)),
+		zap.Bool("resumed", resp.Header.Get(SessionResumedHeader) == "1"),
+		zap.Duration("readTimeout", readTimeout),
+	)
+	return wsConn, readTimeout, nil
+}
+
+// 维护与 commander 的连接，断开后按退避间隔重连，ctx 结束后关闭 done
+
+func (webSocketUseCase *WebSocketUseCase) NewWebSocket(ctx context.Context, wsUrl string, token string, version string, sendChannels *ClientChannels, receiveQueues *ServiceQueues, done chan struct{}) {
+	defer close(done)
+	
+	header := http.Header{}
+	header.Set("token", token)
+	header.Set(MuxHeader, "1")
+	header.Set(AgentVersionHeader, version)
+	webSocketUseCase.framePolicy.SetHeader(header)
 	
+	frameWriter := NewClientFrameWriter(webSocketUseCase.framePolicy)
+	frameReader := NewFrameReader(webSocketUseCase.framePolicy)
+	
+	// 未连接期间指令结果及遥测消息写入离线缓存
+	stopSpooling := webSocketUseCase.spoolOutbound(sendChannels)
+	
+	backoff := newReconnectBackoff()
 	for {
-		wsConn, _, err = websocket.DefaultDialer.Dial(wsUrl, header)
-		if err != nil {
+		wsConn, readTimeout, err := webSocketUseCase.connectWebSocket(ctx, wsUrl, header, sendChannels, frameWriter)
+		if err == nil {
+			stopSpooling()
+			
+			connected := time.Now()
+			restart := webSocketUseCase.serveWebSocket(ctx, wsConn, readTimeout, version, sendChannels, receiveQueues, frameReader, frameWriter)
+			// commander 节点下线 (排空) 时立即按最短间隔重连到其他节点
+			if restart || time.Since(connected) >= reconnectStableDuration {
+				backoff.Reset()
+			}
+			
+			stopSpooling = webSocketUseCase.spoolOutbound(sendChannels)
+		} else if ctx.Err() == nil {
 			webSocketUseCase.logger.Error("连接服务器失败", zap.Error(err))
-			time.Sleep(2 * time.Second)
-			continue
 		}
 		
-		break
+		delay := backoff.Next()
+		if ctx.Err() == nil {
+			webSocketUseCase.logger.Info("等待重新连接", zap.Duration("delay", delay))
+		}
+		
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			stopSpooling()
+			webSocketUseCase.spoolPending(sendChannels)
+			webSocketUseCase.logger.Info("接收到关闭信号，程序退出")
+			return
+		}
+	}
+}
+
+// 将发送队列中的指令结果及遥测消息写入离线缓存，返回的函数停止写入并等待协程退出
+
+func (webSocketUseCase *WebSocketUseCase) spoolOutbound(sendChannels *ClientChannels) func() {
+	if !webSocketUseCase.spool.Enabled() {
+		return func() {}
 	}
 	
-	webSocketUseCase.logger.Info("连接成功")
-	return wsConn
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	
+	go func() {
+		defer close(stopped)
+		
+		for {
+			select {
+			case msg := <-sendChannels.Queue(InstructChannel):
+				webSocketUseCase.spoolMessage(msg)
+			case msg := <-sendChannels.Queue(TelemetryChannel):
+				webSocketUseCase.spoolMessage(msg)
+			case <-stop:
+				return
+			}
+		}
+	}()
+	
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-stopped
+		})
+	}
 }
 
-func (webSocketUseCase *WebSocketUseCase) NewWebSocket(ctx context.Context, wsUrl string, token string, sendMsg chan ClientMessage, receiveMsg chan []byte, done chan struct{}) {
+func (webSocketUseCase *WebSocketUseCase) spoolMessage(msg ClientMessage) {
+	err := webSocketUseCase.spool.Append(msg)
+	if err != nil {
+		webSocketUseCase.logger.Error("写入离线缓存失败", zap.Error(err), zap.Any("type", msg.Type))
+	}
+}
+
+// 退出前将未被 commander 确认的指令结果及队列中剩余的消息写入离线缓存，重启后重新发送
+
+func (webSocketUseCase *WebSocketUseCase) spoolPending(sendChannels *ClientChannels) {
+	if !webSocketUseCase.spool.Enabled() {
+		return
+	}
 	
-	header := http.Header{}
-	header.Set("token", token)
+	for _, reply := range webSocketUseCase.session.Replies() {
+		webSocketUseCase.spoolMessage(reply)
+	}
+	
+	for {
+		select {
+		case msg := <-sendChannels.Queue(InstructChannel):
+			webSocketUseCase.spoolMessage(msg)
+		case msg := <-sendChannels.Queue(TelemetryChannel):
+			webSocketUseCase.spoolMessage(msg)
+		default:
+			return
+		}
+	}
+}
+
+// 处理单个连接，连接是读写协程的唯一所有者，任一协程出错时关闭连接并等待两个协程退出
+// commander 通告心跳间隔时，超过 readTimeout 未收到 ping 或任何消息视为连接已断开
+// commander 节点下线时以 CloseServiceRestart 关闭连接，此时返回 true
+
+func (webSocketUseCase *WebSocketUseCase) serveWebSocket(ctx context.Context, wsConn *websocket.Conn, readTimeout time.Duration, version string, sendChannels *ClientChannels, receiveQueues *ServiceQueues, frameReader *FrameReader, frameWriter *FrameWriter) bool {
+	connCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
 	
-	//
-	var wsConn *websocket.Conn
-	wsConn = webSocketUseCase.connectWebSocket(wsUrl, header)
-	defer wsConn.Close()
+	// 丢弃上一连接未完成的分片
+	frameReader.Reset()
 	
+	ExtendReadDeadline(wsConn, readTimeout)
 	wsConn.SetPingHandler(func(appData string) error {
+		ExtendReadDeadline(wsConn, readTimeout)
 		err := wsConn.WriteControl(websocket.PongMessage, []byte(appData), time.Now().Add(1*time.Second))
 		if err != nil {
 			webSocketUseCase.logger.Error("Error sending Pong", zap.Error(err))
@@ -75,110 +279,219 @@ func (webSocketUseCase *WebSocketUseCase) NewWebSocket(ctx context.Context, wsUr
 		return nil
 	})
 	
+	var wg sync.WaitGroup
+	wg.Add(2)
+	restart := false
+	
 	// read
 	go func() {
+		defer wg.Done()
+		defer cancel()
+		
 		for {
+			messageType, message, err := wsConn.ReadMessage()
+			if err != nil {
+				if websocket.IsCloseError(err, websocket.CloseServiceRestart) {
+					webSocketUseCase.logger.Info("commander 节点下线，重新连接")
+					restart = true
+				} else if connCtx.Err() == nil {
+					webSocketUseCase.logger.Error("websocket错误", zap.Error(err))
+				}
+				return
+			}
 			
-			_, message, err := wsConn.ReadMessage()
+			ExtendReadDeadline(wsConn, readTimeout)
 			
-			// 接收消息失败
+			serviceMessage, complete, err := frameReader.DecodeServiceMessage(wsConn.Subprotocol(), messageType, message)
 			if err != nil {
-				if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
-					webSocketUseCase.logger.Error("websocket已经关闭", zap.Error(err))
-					
-					wsConn = webSocketUseCase.connectWebSocket(wsUrl, header)
-					//close(done)
-					//return
-				} else {
-					webSocketUseCase.logger.Error("websocket错误", zap.Error(err))
-					
-					wsConn = webSocketUseCase.connectWebSocket(wsUrl, header)
-					//close(done)
-					//return
+				webSocketUseCase.logger.Error("反序列化服务器消息失败", zap.Error(err), zap.String("subprotocol", wsConn.Subprotocol()))
+				continue
+			}
+			
+			// 分片未接收完整
+			if !complete {
+				continue
+			}
+			
+			// 通道窗口及指令结果确认直接处理，其余消息按逻辑通道分发
+			switch serviceMessage.Type {
+			case ServiceChannelWindow:
+				if serviceMessage.ChannelWindow != nil {
+					sendChannels.Release(*serviceMessage.ChannelWindow)
 				}
+				continue
+			
+			case ServiceAck:
+				webSocketUseCase.session.Ack(serviceMessage.Ack)
+				continue
+			
+			case ServiceCancel:
+				// 指令通道按顺序执行，取消消息不进入队列
+				if webSocketUseCase.session.Cancel(serviceMessage.Cancel) {
+					webSocketUseCase.logger.Info("取消正在执行的指令", zap.String("Uuid", serviceMessage.Cancel))
+				}
+				continue
 			}
 			
-			receiveMsg <- message
-			//app.logger.Info("接收服务端消息", zap.String("message", string(message)))
+			select {
+			case receiveQueues[serviceMessage.Channel()] <- serviceMessage:
+			case <-connCtx.Done():
+				return
+			}
 		}
 	}()
 	
-	// write
+	// write，上报版本、能力及主机信息并重新发送未确认的指令结果后，按通道优先级发送
 	go func() {
+		defer wg.Done()
+		defer cancel()
+		
+		write := func(msg ClientMessage) error {
+			// 超过消息大小上限时截断，超过 commander 单条消息上限时分片发送
+			messageType, b, err := EncodeTruncatedClientMessage(wsConn.Subprotocol(), msg, frameWriter.MessageSize())
+			if err != nil {
+				webSocketUseCase.logger.Error("序列化发送消息失败", zap.Error(err), zap.Any("type", msg.Type))
+				return nil
+			}
+			
+			err = frameWriter.WriteMessage(wsConn, messageType, b)
+			if err != nil {
+				webSocketUseCase.logger.Error("发送websocket消息失败", zap.Error(err))
+			}
+			return err
+		}
+		
+		if write(webSocketUseCase.Hello(version)) != nil || write(webSocketUseCase.Inventory(connCtx)) != nil {
+			return
+		}
+		
+		replies := webSocketUseCase.session.Replies()
+		if len(replies) > 0 {
+			webSocketUseCase.logger.Info("重新发送未确认的指令结果", zap.Int("count", len(replies)))
+		}
+		for _, reply := range replies {
+			sendChannels.credits.consume(reply.Channel())
+			if write(reply) != nil {
+				return
+			}
+		}
+		
+		// 按顺序发送离线期间缓存的消息，发送失败的消息保留到下次连接
+		if webSocketUseCase.spool.Len() > 0 {
+			webSocketUseCase.logger.Info("发送离线缓存消息", zap.Int("count", webSocketUseCase.spool.Len()))
+		}
+		err := webSocketUseCase.spool.Flush(func(msg ClientMessage) error {
+			sendChannels.credits.consume(msg.Channel())
+			if msg.Type == ClientInstructReply {
+				webSocketUseCase.session.Sent(msg)
+			}
+			return write(msg)
+		})
+		if err != nil {
+			return
+		}
+		
 		for {
-			select {
-			case msg := <-sendMsg:
-				//
-				if msg.Type == ClientHelloEcho {
-					err := wsConn.WriteControl(websocket.PongMessage, []byte("1"), time.Now().Add(1*time.Second))
-					if err != nil {
-						webSocketUseCase.logger.Error("Error sending Pong", zap.Error(err))
-					}
-					continue
-				}
-				
-				//
-				b, err := json.Marshal(msg)
+			msg, ok := sendChannels.Next(connCtx.Done())
+			if !ok {
+				return
+			}
+			
+			//
+			if msg.Type == ClientHelloEcho {
+				err := wsConn.WriteControl(websocket.PongMessage, []byte("1"), time.Now().Add(1*time.Second))
 				if err != nil {
-					webSocketUseCase.logger.Error("序列化发送消息失败", zap.Error(err))
-				} else {
-					err = wsConn.WriteMessage(websocket.BinaryMessage, b)
-					if err != nil {
-						webSocketUseCase.logger.Error("发送websocket消息失败", zap.Error(err))
-					}
+					webSocketUseCase.logger.Error("Error sending Pong", zap.Error(err))
 				}
+				continue
+			}
+			
+			// 指令结果在写出前缓存，写出失败或未确认时重连后重新发送
+			if msg.Type == ClientInstructReply {
+				webSocketUseCase.session.Sent(msg)
+			}
 			
-			case <-ctx.Done():
-				wsConn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
-				webSocketUseCase.logger.Info("写通道接收到关闭信号，将关闭通道")
-				close(done)
+			if write(msg) != nil {
 				return
 			}
 		}
 	}()
 	
-	select {
-	case <-ctx.Done():
-		webSocketUseCase.logger.Info("接收到关闭信号，程序退出")
-		close(done)
-		return
+	<-connCtx.Done()
+	if ctx.Err() != nil {
+		wsConn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(1*time.Second))
 	}
+	wsConn.Close()
+	wg.Wait()
+	
+	// commander 端的终端及端口转发随连接关闭，释放本地的 pty 及 TCP 连接，中止未完成的文件下发
+	webSocketUseCase.shellClientUseCase.CloseAll()
+	webSocketUseCase.tunnelClientUseCase.CloseAll()
+	webSocketUseCase.fileClientUseCase.CloseAll()
+	
+	webSocketUseCase.logger.Info("websocket已经关闭")
+	return restart
 }
 
-// 处理服务端消息
+// 处理服务端消息，每个逻辑通道由独立的协程处理，处理完成后归还通道窗口
 
-func (webSocketUseCase *WebSocketUseCase) ProcessServiceMessage(ctx context.Context, serviceMsgChannel chan []byte, sendMsgChannel chan ClientMessage) {
+func (webSocketUseCase *WebSocketUseCase) ProcessServiceMessage(ctx context.Context, channel Channel, serviceMsgChannel chan ServiceMessage, sendChannels *ClientChannels) {
 	for {
 		select {
-		case serviceMsg := <-serviceMsgChannel:
-			
-			// 空字符
-			if string(serviceMsg) == "" {
-				continue
-			}
+		case serviceMessage := <-serviceMsgChannel:
 			
 			//
-			webSocketUseCase.logger.Info("处理服务器消息", zap.String("serviceMsg", string(serviceMsg)))
-			
-			serviceMessage := &ServiceMessage{}
-			err := json.Unmarshal(serviceMsg, serviceMessage)
-			if err != nil {
-				webSocketUseCase.logger.Error("反序列化服务器消息失败", zap.Error(err), zap.String("message", string(serviceMsg)))
-				continue
-			}
+			webSocketUseCase.logger.Info("处理服务器消息", serviceMessage.LogFields()...)
 			
 			switch serviceMessage.Type {
 			case ServiceHelloEcho:
 				webSocketUseCase.logger.Info("服务器Echo消息", zap.String("message", serviceMessage.Message))
 			
+			case ServiceFileChunk:
+				webSocketUseCase.processFileChunk(serviceMessage, sendChannels)
+			
+			case ServiceShell:
+				if serviceMessage.Shell != nil {
+					webSocketUseCase.shellClientUseCase.Handle(ctx, *serviceMessage.Shell, sendChannels.Queue(BulkChannel))
+				}
+			
+			case ServiceTunnel:
+				if serviceMessage.Tunnel != nil {
+					webSocketUseCase.tunnelClientUseCase.Handle(ctx, *serviceMessage.Tunnel, sendChannels.Queue(BulkChannel))
+				}
+			
 			case ServiceInstruct:
+				// 恢复会话后 commander 重新下发未确认的指令，忽略正在执行或已完成的指令，文件下发指令需重新接收分片
+				restart := serviceMessage.InstructMessage.Type == FilePutInstruct
+				if !webSocketUseCase.session.Begin(serviceMessage.InstructMessage.Uuid, restart) {
+					webSocketUseCase.logger.Info("忽略重复的指令", zap.String("Uuid", serviceMessage.InstructMessage.Uuid))
+					break
+				}
+				
+				// 关联 commander 下发指令时的 trace 上下文
+				instructCtx, span := tracing.Tracer().Start(tracing.Extract(ctx, serviceMessage.Trace), "ExecuteInstruct",
+					trace.WithSpanKind(trace.SpanKindConsumer),
+					trace.WithAttributes(
+						attribute.String("instruct.uuid", serviceMessage.InstructMessage.Uuid),
+						attribute.Int("instruct.type", int(serviceMessage.InstructMessage.Type)),
+					),
+				)
+				
+				// commander 取消指令或超过分组设置的执行超时时终止执行
+				var cancelInstruct context.CancelFunc
+				if serviceMessage.InstructMessage.Timeout > 0 {
+					instructCtx, cancelInstruct = context.WithTimeout(instructCtx, time.Duration(serviceMessage.InstructMessage.Timeout)*time.Second)
+				} else {
+					instructCtx, cancelInstruct = context.WithCancel(instructCtx)
+				}
+				webSocketUseCase.session.Run(serviceMessage.InstructMessage.Uuid, cancelInstruct)
 				
 				switch serviceMessage.InstructMessage.Type {
 				case CommandInstruct:
 					var result bool
 					var errMsg string
 					//var replyContent string
-					outPut, err := exec.Command("bash", "-c", serviceMessage.InstructMessage.CommandContent).Output()
+					outPut, err := exec.CommandContext(instructCtx, "bash", "-c", serviceMessage.InstructMessage.CommandContent).Output()
 					
 					if err != nil {
 						webSocketUseCase.logger.Error("执行命令行失败",
@@ -187,6 +500,13 @@ func (webSocketUseCase *WebSocketUseCase) ProcessServiceMessage(ctx context.Cont
 						
 						result = false
 						errMsg = err.Error()
+						if errors.Is(instructCtx.Err(), context.DeadlineExceeded) {
+							errMsg = "指令执行超时: " + errMsg
+						} else if instructCtx.Err() != nil {
+							errMsg = "指令已取消: " + errMsg
+						}
+						span.RecordError(err)
+						span.SetStatus(codes.Error, errMsg)
 						//replyContent = fmt.Sprintf("执行命令行失败, output: %s, err: %s", string(outPut), err)
 					} else {
 						
@@ -205,12 +525,13 @@ func (webSocketUseCase *WebSocketUseCase) ProcessServiceMessage(ctx context.Cont
 							Result:         result,
 							ErrMsg:         errMsg,
 						},
+						Trace: tracing.Inject(instructCtx),
 					}
 					
-					sendMsgChannel <- reply
+					sendChannels.Send(reply)
 				
 				case ChromeDpInspectInstruct:
-					resp, err := webSocketUseCase.chromeDpClientUseCase.InspectSinglePage(ctx, serviceMessage.InstructMessage.ChromeDpInspectUrl)
+					resp, err := webSocketUseCase.chromeDpClientUseCase.InspectSinglePage(instructCtx, serviceMessage.InstructMessage.ChromeDpInspectUrl)
 					
 					var result bool
 					var errMsg string
@@ -218,6 +539,8 @@ func (webSocketUseCase *WebSocketUseCase) ProcessServiceMessage(ctx context.Cont
 					if err != nil {
 						result = false
 						errMsg = err.Error()
+						span.RecordError(err)
+						span.SetStatus(codes.Error, errMsg)
 						
 						webSocketUseCase.logger.Error("执行ChromeDp指令失败",
 							zap.Error(err),
@@ -242,12 +565,13 @@ func (webSocketUseCase *WebSocketUseCase) ProcessServiceMessage(ctx context.Cont
 							Result:               result,
 							ErrMsg:               errMsg,
 						},
+						Trace: tracing.Inject(instructCtx),
 					}
 					
-					sendMsgChannel <- reply
+					sendChannels.Send(reply)
 				
 				case DnsInstruct:
-					resp, err := webSocketUseCase.dnsClientInspectUseCase.LookupHost(ctx, serviceMessage.InstructMessage.DnsInspectDomain)
+					resp, err := webSocketUseCase.dnsClientInspectUseCase.LookupHost(instructCtx, serviceMessage.InstructMessage.DnsInspectDomain)
 					
 					var result bool
 					var errMsg string
@@ -255,6 +579,8 @@ func (webSocketUseCase *WebSocketUseCase) ProcessServiceMessage(ctx context.Cont
 					if err != nil {
 						result = false
 						errMsg = err.Error()
+						span.RecordError(err)
+						span.SetStatus(codes.Error, errMsg)
 						
 						webSocketUseCase.logger.Error("执行DNS指令失败",
 							zap.Error(err),
@@ -279,12 +605,13 @@ func (webSocketUseCase *WebSocketUseCase) ProcessServiceMessage(ctx context.Cont
 							Result:           result,
 							ErrMsg:           errMsg,
 						},
+						Trace: tracing.Inject(instructCtx),
 					}
 					
-					sendMsgChannel <- reply
+					sendChannels.Send(reply)
 				
 				case HttpInstruct:
-					resp, err := webSocketUseCase.httpInspectClientUseCase.GetHttpUrlResponse(serviceMessage.InstructMessage.HttpInspectUrl)
+					resp, err := webSocketUseCase.httpInspectClientUseCase.GetHttpUrlResponse(instructCtx, serviceMessage.InstructMessage.HttpInspectUrl)
 					
 					var result bool
 					var errMsg string
@@ -292,6 +619,8 @@ func (webSocketUseCase *WebSocketUseCase) ProcessServiceMessage(ctx context.Cont
 					if err != nil {
 						result = false
 						errMsg = err.Error()
+						span.RecordError(err)
+						span.SetStatus(codes.Error, errMsg)
 						
 						webSocketUseCase.logger.Error("执行HTTP指令失败",
 							zap.Error(err),
@@ -316,12 +645,18 @@ func (webSocketUseCase *WebSocketUseCase) ProcessServiceMessage(ctx context.Cont
 							Result:           result,
 							ErrMsg:           errMsg,
 						},
+						Trace: tracing.Inject(instructCtx),
 					}
 					
-					sendMsgChannel <- reply
+					sendChannels.Send(reply)
 				
 				case IcmpInstruct:
-					resp, err := webSocketUseCase.icmpClientUseCase.Icmp(serviceMessage.InstructMessage.IcmpInspectAddr, 4)
+					count := defaultIcmpCount
+					if serviceMessage.InstructMessage.IcmpCount > 0 {
+						count = int(serviceMessage.InstructMessage.IcmpCount)
+					}
+					
+					resp, err := webSocketUseCase.icmpClientUseCase.Icmp(instructCtx, serviceMessage.InstructMessage.IcmpInspectAddr, count)
 					
 					var result bool
 					var errMsg string
@@ -329,6 +664,8 @@ func (webSocketUseCase *WebSocketUseCase) ProcessServiceMessage(ctx context.Cont
 					if err != nil {
 						result = false
 						errMsg = err.Error()
+						span.RecordError(err)
+						span.SetStatus(codes.Error, errMsg)
 						
 						webSocketUseCase.logger.Error("执行ICMP指令失败",
 							zap.Error(err),
@@ -353,39 +690,165 @@ func (webSocketUseCase *WebSocketUseCase) ProcessServiceMessage(ctx context.Cont
 							Result:           result,
 							ErrMsg:           errMsg,
 						},
+						Trace: tracing.Inject(instructCtx),
+					}
+					
+					sendChannels.Send(reply)
+				
+				case FileGetInstruct:
+					resp, err := webSocketUseCase.fileClientUseCase.Get(instructCtx, serviceMessage.InstructMessage.Uuid, serviceMessage.InstructMessage.FilePath, sendChannels.Queue(BulkChannel))
+					
+					var result bool
+					var errMsg string
+					
+					if err != nil {
+						result = false
+						errMsg = err.Error()
+						span.RecordError(err)
+						span.SetStatus(codes.Error, errMsg)
+						
+						webSocketUseCase.logger.Error("执行文件获取指令失败",
+							zap.Error(err),
+							zap.String("FilePath", serviceMessage.InstructMessage.FilePath),
+						)
+					} else {
+						errMsg = ""
+						result = true
+						
+						webSocketUseCase.logger.Info("执行文件获取指令成功",
+							zap.String("FilePath", serviceMessage.InstructMessage.FilePath),
+							zap.Int64("FileSize", resp.Size),
+						)
+					}
+					
+					reply := ClientMessage{
+						Type: ClientInstructReply,
+						InstructMessage: InstructMessage{
+							Uuid:       serviceMessage.InstructMessage.Uuid,
+							Type:       serviceMessage.InstructMessage.Type,
+							FilePath:   serviceMessage.InstructMessage.FilePath,
+							FileSize:   resp.Size,
+							FileSha256: resp.Sha256,
+							Result:     result,
+							ErrMsg:     errMsg,
+						},
+						Trace: tracing.Inject(instructCtx),
 					}
 					
-					sendMsgChannel <- reply
+					sendChannels.Send(reply)
+				
+				case FilePutInstruct:
+					// 文件内容随后通过 ServiceFileChunk 消息发送，接收完成后回传结果
+					err := webSocketUseCase.fileClientUseCase.BeginPut(
+						serviceMessage.InstructMessage.Uuid,
+						serviceMessage.InstructMessage.FilePath,
+						serviceMessage.InstructMessage.FileSize,
+						serviceMessage.InstructMessage.FileSha256,
+					)
+					if err != nil {
+						span.RecordError(err)
+						span.SetStatus(codes.Error, err.Error())
+						
+						webSocketUseCase.logger.Error("执行文件下发指令失败",
+							zap.Error(err),
+							zap.String("FilePath", serviceMessage.InstructMessage.FilePath),
+						)
+						
+						sendChannels.Send(ClientMessage{
+							Type: ClientInstructReply,
+							InstructMessage: InstructMessage{
+								Uuid:     serviceMessage.InstructMessage.Uuid,
+								Type:     serviceMessage.InstructMessage.Type,
+								FilePath: serviceMessage.InstructMessage.FilePath,
+								Result:   false,
+								ErrMsg:   err.Error(),
+							},
+							Trace: tracing.Inject(instructCtx),
+						})
+					}
 				
 				default:
+					span.SetStatus(codes.Error, "未知的指令")
 					webSocketUseCase.logger.Warn("未知的指令",
 						//zap.String("Content", serviceMessage.InstructMessage),
 						zap.String("Uuid", serviceMessage.InstructMessage.Uuid),
 						zap.Any("Type", serviceMessage.InstructMessage.Type),
 					)
+					webSocketUseCase.replyUnsupported(instructCtx, serviceMessage.InstructMessage, fmt.Sprintf("不支持的指令类型: %d", serviceMessage.InstructMessage.Type), sendChannels)
+				}
+				
+				span.End()
+				webSocketUseCase.session.Finish(serviceMessage.InstructMessage.Uuid)
+				cancelInstruct()
+			
+			default:
+				webSocketUseCase.logger.Warn("未知的服务器消息类型",
+					zap.Any("Type", serviceMessage.Type),
+					zap.String("Uuid", serviceMessage.InstructMessage.Uuid),
+				)
+				if serviceMessage.InstructMessage.Uuid != "" {
+					webSocketUseCase.replyUnsupported(ctx, serviceMessage.InstructMessage, fmt.Sprintf("不支持的消息类型: %d", serviceMessage.Type), sendChannels)
 				}
 			}
+			
+			sendChannels.Ack(channel, ctx.Done())
+		
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
-func (webSocketUseCase *WebSocketUseCase) HelloEcho(ctx context.Context, sendMsg chan ClientMessage) {
-	ticker := time.NewTicker(5 * time.Second)
+// 回传不支持的消息或指令类型的失败结果，避免指令在 commander 上保持执行中直到超时
+
+func (webSocketUseCase *WebSocketUseCase) replyUnsupported(ctx context.Context, instruct InstructMessage, errMsg string, sendChannels *ClientChannels) {
+	sendChannels.Send(ClientMessage{
+		Type: ClientInstructReply,
+		InstructMessage: InstructMessage{
+			Uuid:   instruct.Uuid,
+			Type:   instruct.Type,
+			Result: false,
+			ErrMsg: errMsg,
+		},
+		Trace: tracing.Inject(ctx),
+	})
+}
+
+// 写入文件下发分片，传输结束后回传指令结果
+
+func (webSocketUseCase *WebSocketUseCase) processFileChunk(serviceMessage ServiceMessage, sendChannels *ClientChannels) {
+	if serviceMessage.FileChunk == nil {
+		return
+	}
 	
-	for {
-		select {
-		case <-ticker.C:
-			//app.logger.Info("定时发送消息")
-			sendMsg <- ClientMessage{
-				Type:    ClientHelloEcho,
-				Message: fmt.Sprintf("Hello, Client Time: %s", time.Now().String()),
-			}
-		
-		case <-ctx.Done():
-			webSocketUseCase.logger.Info("关闭定时发送消息")
-			return
-		}
+	resp, done, err := webSocketUseCase.fileClientUseCase.WriteChunk(*serviceMessage.FileChunk)
+	if errors.Is(err, ErrFileTransferUnknown) {
+		// 文件下发指令已失败并回传结果，忽略后续分片
+		return
 	}
+	
+	if !done {
+		return
+	}
+	
+	reply := ClientMessage{
+		Type: ClientInstructReply,
+		InstructMessage: InstructMessage{
+			Uuid:       serviceMessage.FileChunk.Uuid,
+			Type:       FilePutInstruct,
+			FilePath:   resp.Path,
+			FileSize:   resp.Size,
+			FileSha256: resp.Sha256,
+			Result:     err == nil,
+		},
+	}
+	
+	if err != nil {
+		reply.InstructMessage.ErrMsg = err.Error()
+		webSocketUseCase.logger.Error("执行文件下发指令失败", zap.Error(err), zap.String("FilePath", resp.Path))
+	} else {
+		webSocketUseCase.logger.Info("执行文件下发指令成功", zap.String("FilePath", resp.Path), zap.Int64("FileSize", resp.Size))
+	}
+	
+	sendChannels.Send(reply)
 }
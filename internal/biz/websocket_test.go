@@ -0,0 +1,43 @@
+package biz
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+// 不支持的指令或消息类型回传失败结果
+
+func TestWebSocketUseCase_ProcessServiceMessageUnsupported(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	
+	webSocketUseCase := &WebSocketUseCase{logger: zap.NewNop(), session: NewClientSession()}
+	sendChannels := NewClientChannels()
+	serviceMsgChannel := make(chan ServiceMessage, 3)
+	go webSocketUseCase.ProcessServiceMessage(ctx, InstructChannel, serviceMsgChannel, sendChannels)
+	
+	serviceMsgChannel <- ServiceMessage{Type: ServiceInstruct, InstructMessage: InstructMessage{Uuid: "u1", Type: 99}}
+	serviceMsgChannel <- ServiceMessage{Type: 99, InstructMessage: InstructMessage{Uuid: "u2", Type: CommandInstruct}}
+	serviceMsgChannel <- ServiceMessage{Type: 99}
+	
+	for _, uuid := range []string{"u1", "u2"} {
+		select {
+		case reply := <-sendChannels.Queue(InstructChannel):
+			assert.Equal(t, ClientInstructReply, reply.Type)
+			assert.Equal(t, uuid, reply.InstructMessage.Uuid)
+			assert.False(t, reply.InstructMessage.Result)
+			assert.NotEmpty(t, reply.InstructMessage.ErrMsg)
+		case <-time.After(time.Second):
+			t.Fatal("未收到指令结果")
+		}
+	}
+	
+	select {
+	case reply := <-sendChannels.Queue(InstructChannel):
+		t.Fatalf("不应回传结果: %+v", reply)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
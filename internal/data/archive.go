@@ -0,0 +1,147 @@
+package data
+
+import (
+	"context"
+	"github.com/qx66/camp/internal/biz"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	archiveLockKey   = "instance_archive"
+	retentionLockKey = "instruct_retention"
+)
+
+// instruct_archive 与 instruct 字段相同，另有 archive_time
+
+const instructArchiveColumns = "uuid, org_uuid, group_uuid, instance_name, `type`, content, result, reply, create_time, update_time, truncated, operator, batch_uuid"
+
+type ArchiveDataSource struct {
+	data *Data
+}
+
+func NewArchiveDataSource(data *Data) biz.ArchiveRepo {
+	return &ArchiveDataSource{
+		data: data,
+	}
+}
+
+func (archiveDataSource *ArchiveDataSource) ArchiveInstructs(ctx context.Context, orgUuid, groupUuid, instanceName string, archiveTime int64, limit int) (int64, error) {
+	return archiveDataSource.archiveInstructs(ctx, archiveTime, limit, "org_uuid = ? and group_uuid = ? and instance_name = ?", orgUuid, groupUuid, instanceName)
+}
+
+func (archiveDataSource *ArchiveDataSource) ArchiveInstructsBefore(ctx context.Context, createdBefore, archiveTime int64, limit int) (int64, error) {
+	return archiveDataSource.archiveInstructs(ctx, archiveTime, limit, "create_time < ? and result != ?", createdBefore, 0)
+}
+
+// 将满足条件的最多 limit 条指令记录移入 instruct_archive
+
+func (archiveDataSource *ArchiveDataSource) archiveInstructs(ctx context.Context, archiveTime int64, limit int, query string, args ...interface{}) (int64, error) {
+	var archived int64
+	err := archiveDataSource.data.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var uuids []string
+		err := tx.Model(&biz.Instruct{}).
+			Where(query, args...).
+			Limit(limit).
+			Pluck("uuid", &uuids).Error
+		if err != nil || len(uuids) == 0 {
+			return err
+		}
+		
+		err = tx.Exec("insert ignore into instruct_archive ("+instructArchiveColumns+", archive_time) select "+instructArchiveColumns+", ? from instruct where uuid in ?", archiveTime, uuids).Error
+		if err != nil {
+			return err
+		}
+		
+		result := tx.Where("uuid in ?", uuids).Delete(&biz.Instruct{})
+		archived = result.RowsAffected
+		return result.Error
+	})
+	return archived, err
+}
+
+func (archiveDataSource *ArchiveDataSource) DeleteInstructsBefore(ctx context.Context, createdBefore int64, limit int) (int64, error) {
+	tx := archiveDataSource.data.db.WithContext(ctx).
+		Where("create_time < ? and result != ?", createdBefore, 0).
+		Limit(limit).
+		Delete(&biz.Instruct{})
+	return tx.RowsAffected, tx.Error
+}
+
+func (archiveDataSource *ArchiveDataSource) DeleteResultsBefore(ctx context.Context, createdBefore int64, limit int) (int64, error) {
+	tx := archiveDataSource.data.db.WithContext(ctx).
+		Where("create_time < ?", createdBefore).
+		Limit(limit).
+		Delete(&biz.InstructResult{})
+	return tx.RowsAffected, tx.Error
+}
+
+// 按字符截断 reply
+
+func (archiveDataSource *ArchiveDataSource) TruncateReplies(ctx context.Context, createdBefore int64, maxSize, limit int) (int64, error) {
+	tx := archiveDataSource.data.db.WithContext(ctx).
+		Model(&biz.Instruct{}).
+		Where("create_time < ? and result != ? and char_length(reply) > ?", createdBefore, 0, maxSize).
+		Limit(limit).
+		Updates(map[string]interface{}{
+			"reply":     gorm.Expr("left(reply, ?)", maxSize),
+			"truncated": true,
+		})
+	return tx.RowsAffected, tx.Error
+}
+
+func (archiveDataSource *ArchiveDataSource) ArchiveInstance(ctx context.Context, archive biz.InstanceArchive, offlineBefore int64) (bool, error) {
+	archived := false
+	err := archiveDataSource.data.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 先删除实例，离线实例在此期间重新上线时不归档
+		tx1 := tx.Where("uuid = ?", archive.Uuid)
+		if offlineBefore > 0 {
+			tx1.Where("online = ? and update_time < ?", false, offlineBefore)
+		}
+		
+		result := tx1.Delete(&biz.Instance{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		
+		err := tx.Where("instance_uuid = ?", archive.Uuid).Delete(&biz.Label{}).Error
+		if err != nil {
+			return err
+		}
+		
+		err = tx.Where("instance_uuid = ?", archive.Uuid).Delete(&biz.Connection{}).Error
+		if err != nil {
+			return err
+		}
+		
+		err = tx.Save(&archive).Error
+		archived = err == nil
+		return err
+	})
+	return archived, err
+}
+
+func (archiveDataSource *ArchiveDataSource) DeleteGroupInstructArchives(ctx context.Context, orgUuid, groupUuid string, limit int) (int64, error) {
+	tx := archiveDataSource.data.db.WithContext(ctx).
+		Table("instruct_archive").
+		Where("org_uuid = ? and group_uuid = ?", orgUuid, groupUuid).
+		Limit(limit).
+		Delete(&biz.Instruct{})
+	return tx.RowsAffected, tx.Error
+}
+
+func (archiveDataSource *ArchiveDataSource) DeleteGroupInstanceArchives(ctx context.Context, orgUuid, groupUuid string, limit int) (int64, error) {
+	tx := archiveDataSource.data.db.WithContext(ctx).
+		Where("org_uuid = ? and group_uuid = ?", orgUuid, groupUuid).
+		Limit(limit).
+		Delete(&biz.InstanceArchive{})
+	return tx.RowsAffected, tx.Error
+}
+
+func (archiveDataSource *ArchiveDataSource) AcquireArchiveLock(ctx context.Context, node string, ttl time.Duration) (bool, error) {
+	return archiveDataSource.data.redis.SetNX(ctx, archiveLockKey, node, ttl).Result()
+}
+
+func (archiveDataSource *ArchiveDataSource) AcquireRetentionLock(ctx context.Context, node string, ttl time.Duration) (bool, error) {
+	return archiveDataSource.data.redis.SetNX(ctx, retentionLockKey, node, ttl).Result()
+}
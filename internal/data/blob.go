@@ -0,0 +1,287 @@
+package data
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/qx66/camp/internal/biz"
+	"github.com/qx66/camp/internal/conf"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	defaultBlobDir     = "./data/blob"
+	defaultBlobMaxSize = 64 * 1024 * 1024
+)
+
+// 文件元数据保存在 mysql 中，文件内容保存在本地目录或 S3 兼容存储
+// 接收中的文件分片暂存在本地 staging 目录，校验通过后再保存
+
+type BlobDataSource struct {
+	data    *Data
+	dir     string
+	maxSize int64
+	s3      *minio.Client
+	bucket  string
+}
+
+func NewBlobDataSource(c *conf.Data, data *Data) (biz.BlobRepo, error) {
+	blobDataSource := &BlobDataSource{
+		data:    data,
+		dir:     c.GetBlob().GetDir(),
+		maxSize: c.GetBlob().GetMaxSize(),
+	}
+	
+	if blobDataSource.dir == "" {
+		blobDataSource.dir = defaultBlobDir
+	}
+	
+	if blobDataSource.maxSize <= 0 {
+		blobDataSource.maxSize = defaultBlobMaxSize
+	}
+	
+	for _, dir := range []string{blobDataSource.stagingDir(), blobDataSource.localDir()} {
+		err := os.MkdirAll(dir, 0755)
+		if err != nil {
+			return nil, err
+		}
+	}
+	
+	s3 := c.GetBlob().GetS3()
+	if s3.GetEndpoint() != "" {
+		client, err := minio.New(s3.GetEndpoint(), &minio.Options{
+			Creds:  credentials.NewStaticV4(s3.GetAccessKey(), s3.GetSecretKey(), ""),
+			Secure: s3.GetUseSsl(),
+			Region: s3.GetRegion(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		
+		blobDataSource.s3 = client
+		blobDataSource.bucket = s3.GetBucket()
+		data.logger.Info("文件保存到S3", zap.String("endpoint", s3.GetEndpoint()), zap.String("bucket", s3.GetBucket()))
+	}
+	
+	return blobDataSource, nil
+}
+
+func (blobDataSource *BlobDataSource) stagingDir() string {
+	return filepath.Join(blobDataSource.dir, "staging")
+}
+
+func (blobDataSource *BlobDataSource) localDir() string {
+	return filepath.Join(blobDataSource.dir, "blobs")
+}
+
+// uuid 来自 soldier 消息，校验格式避免路径穿越
+
+func (blobDataSource *BlobDataSource) stagingPath(blobUuid string) (string, error) {
+	_, err := uuid.Parse(blobUuid)
+	if err != nil {
+		return "", err
+	}
+	
+	return filepath.Join(blobDataSource.stagingDir(), blobUuid), nil
+}
+
+func (blobDataSource *BlobDataSource) Put(ctx context.Context, r io.Reader) (biz.Blob, error) {
+	blobUuid := uuid.NewString()
+	stagingPath, _ := blobDataSource.stagingPath(blobUuid)
+	
+	file, err := os.Create(stagingPath)
+	if err != nil {
+		return biz.Blob{}, err
+	}
+	defer os.Remove(stagingPath)
+	
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hash), io.LimitReader(r, blobDataSource.maxSize+1))
+	file.Close()
+	if err != nil {
+		return biz.Blob{}, err
+	}
+	
+	if size > blobDataSource.maxSize {
+		return biz.Blob{}, biz.ErrBlobTooLarge
+	}
+	
+	return blobDataSource.save(ctx, blobUuid, stagingPath, size, hex.EncodeToString(hash.Sum(nil)))
+}
+
+func (blobDataSource *BlobDataSource) WriteChunk(ctx context.Context, blobUuid string, offset int64, data []byte) error {
+	stagingPath, err := blobDataSource.stagingPath(blobUuid)
+	if err != nil {
+		return err
+	}
+	
+	if offset < 0 || offset+int64(len(data)) > blobDataSource.maxSize {
+		return biz.ErrBlobTooLarge
+	}
+	
+	file, err := os.OpenFile(stagingPath, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	
+	_, err = file.WriteAt(data, offset)
+	return err
+}
+
+func (blobDataSource *BlobDataSource) Verify(ctx context.Context, blobUuid string, size int64, sha256Sum string) error {
+	stagingPath, err := blobDataSource.stagingPath(blobUuid)
+	if err != nil {
+		return err
+	}
+	
+	file, err := os.OpenFile(stagingPath, os.O_CREATE|os.O_RDONLY, 0644)
+	if err != nil {
+		return err
+	}
+	
+	hash := sha256.New()
+	n, err := io.Copy(hash, file)
+	file.Close()
+	if err != nil {
+		return err
+	}
+	
+	if n != size || hex.EncodeToString(hash.Sum(nil)) != sha256Sum {
+		return biz.ErrChecksumMismatch
+	}
+	return nil
+}
+
+func (blobDataSource *BlobDataSource) Commit(ctx context.Context, blobUuid string, size int64, sha256Sum string) (biz.Blob, error) {
+	err := blobDataSource.Verify(ctx, blobUuid, size, sha256Sum)
+	if err != nil {
+		return biz.Blob{}, err
+	}
+	
+	stagingPath, err := blobDataSource.stagingPath(blobUuid)
+	if err != nil {
+		return biz.Blob{}, err
+	}
+	
+	defer os.Remove(stagingPath)
+	return blobDataSource.save(ctx, blobUuid, stagingPath, size, sha256Sum)
+}
+
+func (blobDataSource *BlobDataSource) Discard(ctx context.Context, blobUuid string) error {
+	stagingPath, err := blobDataSource.stagingPath(blobUuid)
+	if err != nil {
+		return err
+	}
+	
+	err = os.Remove(stagingPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (blobDataSource *BlobDataSource) Stat(ctx context.Context, blobUuid string) (biz.Blob, error) {
+	var blob biz.Blob
+	tx := blobDataSource.data.db.WithContext(ctx).Where("uuid = ?", blobUuid).First(&blob)
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return blob, biz.ErrBlobNotFound
+	}
+	return blob, tx.Error
+}
+
+func (blobDataSource *BlobDataSource) Open(ctx context.Context, blobUuid string) (io.ReadCloser, biz.Blob, error) {
+	blob, err := blobDataSource.Stat(ctx, blobUuid)
+	if err != nil {
+		return nil, blob, err
+	}
+	
+	if blobDataSource.s3 != nil {
+		object, err := blobDataSource.s3.GetObject(ctx, blobDataSource.bucket, blobUuid, minio.GetObjectOptions{})
+		return object, blob, err
+	}
+	
+	file, err := os.Open(filepath.Join(blobDataSource.localDir(), blobUuid))
+	return file, blob, err
+}
+
+// 保存暂存文件并记录元数据
+
+func (blobDataSource *BlobDataSource) save(ctx context.Context, blobUuid, stagingPath string, size int64, sha256Sum string) (biz.Blob, error) {
+	ctx, span := startSpan(ctx, "blob.Save", attribute.String("blob.uuid", blobUuid), attribute.Int64("blob.size", size))
+	defer span.End()
+	
+	var err error
+	if blobDataSource.s3 != nil {
+		_, err = blobDataSource.s3.FPutObject(ctx, blobDataSource.bucket, blobUuid, stagingPath, minio.PutObjectOptions{})
+	} else {
+		err = os.Rename(stagingPath, filepath.Join(blobDataSource.localDir(), blobUuid))
+	}
+	if err != nil {
+		return biz.Blob{}, endSpan(span, err)
+	}
+	
+	blob := biz.Blob{
+		Uuid:       blobUuid,
+		Size:       size,
+		Sha256:     sha256Sum,
+		CreateTime: time.Now().Unix(),
+	}
+	
+	tx := blobDataSource.data.db.WithContext(ctx).Create(&blob)
+	return blob, endSpan(span, tx.Error)
+}
+
+func (blobDataSource *BlobDataSource) DeleteGroupBlobs(ctx context.Context, orgUuid, groupUuid string, limit int) (int64, error) {
+	db := blobDataSource.data.db.WithContext(ctx)
+	types := []biz.InstructType{biz.FileGetInstruct, biz.ShellInstruct}
+	instructs := db.Model(&biz.Instruct{}).Select("uuid").Where("org_uuid = ? and group_uuid = ? and `type` in ?", orgUuid, groupUuid, types)
+	archives := db.Table("instruct_archive").Select("uuid").Where("org_uuid = ? and group_uuid = ? and `type` in ?", orgUuid, groupUuid, types)
+	
+	var blobUuids []string
+	err := db.Model(&biz.Blob{}).
+		Where("uuid in (?) or uuid in (?)", instructs, archives).
+		Limit(limit).
+		Pluck("uuid", &blobUuids).Error
+	if err != nil {
+		return 0, err
+	}
+	
+	for _, blobUuid := range blobUuids {
+		err = blobDataSource.delete(ctx, blobUuid)
+		if err != nil {
+			return 0, err
+		}
+	}
+	
+	return int64(len(blobUuids)), nil
+}
+
+// 删除文件内容及元数据
+
+func (blobDataSource *BlobDataSource) delete(ctx context.Context, blobUuid string) error {
+	var err error
+	if blobDataSource.s3 != nil {
+		err = blobDataSource.s3.RemoveObject(ctx, blobDataSource.bucket, blobUuid, minio.RemoveObjectOptions{})
+	} else {
+		err = os.Remove(filepath.Join(blobDataSource.localDir(), blobUuid))
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+	
+	return blobDataSource.data.db.WithContext(ctx).Where("uuid = ?", blobUuid).Delete(&biz.Blob{}).Error
+}
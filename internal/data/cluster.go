@@ -0,0 +1,137 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/qx66/camp/internal/biz"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"time"
+)
+
+// 节点登记保存在 hash nodes 中 (field 为节点 id)，过期时间记录在 node_<id> key 上，列出节点时忽略已过期的节点
+
+const clusterNodesKey = "nodes"
+
+type ClusterDataSource struct {
+	data *Data
+}
+
+func NewClusterDataSource(data *Data) biz.ClusterRepo {
+	return &ClusterDataSource{
+		data: data,
+	}
+}
+
+func nodeKey(id string) string {
+	return fmt.Sprintf("node_%s", id)
+}
+
+func nodeChannel(id string) string {
+	return fmt.Sprintf("node_%s_messages", id)
+}
+
+func (clusterDataSource *ClusterDataSource) SetNode(ctx context.Context, node biz.NodeInfo, ttl time.Duration) error {
+	b, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+	
+	_, err = clusterDataSource.data.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, clusterNodesKey, node.Id, b)
+		pipe.Set(ctx, nodeKey(node.Id), "", ttl)
+		return nil
+	})
+	return err
+}
+
+func (clusterDataSource *ClusterDataSource) DeleteNode(ctx context.Context, id string) error {
+	_, err := clusterDataSource.data.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, clusterNodesKey, id)
+		pipe.Del(ctx, nodeKey(id))
+		return nil
+	})
+	return err
+}
+
+// 列出存活的节点，同时清理已过期的节点
+
+func (clusterDataSource *ClusterDataSource) ListNodes(ctx context.Context) ([]biz.NodeInfo, error) {
+	values, err := clusterDataSource.data.redis.HGetAll(ctx, clusterNodesKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	
+	nodes := []biz.NodeInfo{}
+	for id, value := range values {
+		n, err := clusterDataSource.data.redis.Exists(ctx, nodeKey(id)).Result()
+		if err != nil {
+			return nil, err
+		}
+		
+		if n == 0 {
+			clusterDataSource.data.redis.HDel(ctx, clusterNodesKey, id)
+			continue
+		}
+		
+		var node biz.NodeInfo
+		err = json.Unmarshal([]byte(value), &node)
+		if err != nil {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	
+	return nodes, nil
+}
+
+// 返回是否有节点订阅了该频道
+
+func (clusterDataSource *ClusterDataSource) PublishNodeMessage(ctx context.Context, node string, msg biz.NodeMessage) (bool, error) {
+	ctx, span := startSpan(ctx, "redis.PublishNodeMessage", attribute.String("node", node), attribute.String("type", msg.Type))
+	defer span.End()
+	
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return false, endSpan(span, err)
+	}
+	
+	n, err := clusterDataSource.data.redis.Publish(ctx, nodeChannel(node), b).Result()
+	return n > 0, endSpan(span, err)
+}
+
+// 订阅节点频道，阻塞直到 ctx 结束或订阅断开
+
+func (clusterDataSource *ClusterDataSource) SubscribeNodeMessages(ctx context.Context, node string, handler func(msg biz.NodeMessage)) error {
+	pubsub := clusterDataSource.data.redis.Subscribe(ctx, nodeChannel(node))
+	defer pubsub.Close()
+	
+	// 等待订阅确认
+	_, err := pubsub.Receive(ctx)
+	if err != nil {
+		return err
+	}
+	
+	messages := pubsub.Channel()
+	for {
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				return fmt.Errorf("订阅已关闭: %s", nodeChannel(node))
+			}
+			
+			var msg biz.NodeMessage
+			err = json.Unmarshal([]byte(message.Payload), &msg)
+			if err != nil {
+				clusterDataSource.data.logger.Error("解析节点消息失败", zap.String("node", node), zap.Error(err))
+				continue
+			}
+			handler(msg)
+		
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package data
+
+import (
+	"context"
+	"github.com/qx66/camp/internal/biz"
+)
+
+type ConnectionDataSource struct {
+	data *Data
+}
+
+func NewConnectionDataSource(data *Data) biz.ConnectionRepo {
+	return &ConnectionDataSource{
+		data: data,
+	}
+}
+
+func (connectionDataSource *ConnectionDataSource) CreateConnection(ctx context.Context, connection biz.Connection) error {
+	tx := connectionDataSource.data.db.WithContext(ctx).Create(&connection)
+	return tx.Error
+}
+
+// 仅结束未断开的连接记录
+
+func (connectionDataSource *ConnectionDataSource) CloseConnection(ctx context.Context, id string, disconnectTime int64, reason string) error {
+	tx := connectionDataSource.data.db.WithContext(ctx).
+		Model(&biz.Connection{}).
+		Where("id = ? and disconnect_time = ?", id, 0).
+		Updates(map[string]interface{}{
+			"disconnect_time":   disconnectTime,
+			"disconnect_reason": reason,
+		})
+	return tx.Error
+}
+
+func (connectionDataSource *ConnectionDataSource) CloseInstanceConnections(ctx context.Context, instanceUuid string, disconnectTime int64, reason string) error {
+	tx := connectionDataSource.data.db.WithContext(ctx).
+		Model(&biz.Connection{}).
+		Where("instance_uuid = ? and disconnect_time = ?", instanceUuid, 0).
+		Updates(map[string]interface{}{
+			"disconnect_time":   disconnectTime,
+			"disconnect_reason": reason,
+		})
+	return tx.Error
+}
+
+// 列出与 [startTime, endTime) 有重叠的连接记录，按连接时间倒序，limit 为 0 时不限制
+
+func (connectionDataSource *ConnectionDataSource) ListConnections(ctx context.Context, instanceUuid string, startTime, endTime int64, limit int) ([]biz.Connection, error) {
+	var connections []biz.Connection
+	
+	tx := connectionDataSource.data.db.WithContext(ctx).
+		Where("instance_uuid = ? and connect_time < ?", instanceUuid, endTime).
+		Where("(disconnect_time = ? or disconnect_time > ?)", 0, startTime).
+		Order("connect_time desc")
+	
+	if limit > 0 {
+		tx.Limit(limit)
+	}
+	
+	tx.Find(&connections)
+	return connections, tx.Error
+}
+
+func (connectionDataSource *ConnectionDataSource) DeleteConnections(ctx context.Context, instanceUuid string) error {
+	return connectionDataSource.data.db.WithContext(ctx).Where("instance_uuid = ?", instanceUuid).Delete(&biz.Connection{}).Error
+}
@@ -5,13 +5,17 @@ import (
 	"github.com/go-redis/redis/v8"
 	"github.com/google/wire"
 	"github.com/qx66/camp/internal/conf"
+	"github.com/qx66/camp/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
 // ProviderSet is data providers.
-var ProviderSet = wire.NewSet(NewData, NewInstructDataSource, NewInstanceDataSource)
+var ProviderSet = wire.NewSet(NewData, NewInstructDataSource, NewInstanceDataSource, NewMetricsDataSource, NewBlobDataSource, NewSessionDataSource, NewPresenceDataSource, NewClusterDataSource, NewConnectionDataSource, NewLabelDataSource, NewOrgDataSource, NewArchiveDataSource, NewResultDataSource, NewEventDataSource)
 
 // Data .
 type Data struct {
@@ -88,3 +92,20 @@ func NewData(c *conf.Data, logger *zap.Logger) (*Data, func(), error) {
 	
 	return d, cleanup, nil
 }
+
+// 数据层 span，用于跟踪 redis / mysql 操作
+
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+func endSpan(span trace.Span, err error) error {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
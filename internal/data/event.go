@@ -0,0 +1,63 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/qx66/camp/internal/biz"
+	"go.uber.org/zap"
+)
+
+// 指令事件通过频道 instruct_events 广播到所有节点
+
+const eventChannel = "instruct_events"
+
+type EventDataSource struct {
+	data *Data
+}
+
+func NewEventDataSource(data *Data) biz.EventRepo {
+	return &EventDataSource{
+		data: data,
+	}
+}
+
+func (eventDataSource *EventDataSource) PublishEvent(ctx context.Context, event biz.InstructEvent) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return eventDataSource.data.redis.Publish(ctx, eventChannel, b).Err()
+}
+
+func (eventDataSource *EventDataSource) SubscribeEvents(ctx context.Context, handler func(event biz.InstructEvent)) error {
+	pubsub := eventDataSource.data.redis.Subscribe(ctx, eventChannel)
+	defer pubsub.Close()
+	
+	// 等待订阅确认
+	_, err := pubsub.Receive(ctx)
+	if err != nil {
+		return err
+	}
+	
+	messages := pubsub.Channel()
+	for {
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				return fmt.Errorf("订阅已关闭: %s", eventChannel)
+			}
+			
+			var event biz.InstructEvent
+			err = json.Unmarshal([]byte(message.Payload), &event)
+			if err != nil {
+				eventDataSource.data.logger.Error("解析指令事件失败", zap.Error(err))
+				continue
+			}
+			handler(event)
+		
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -2,9 +2,9 @@ package data
 
 import (
 	"context"
-	"fmt"
 	"github.com/qx66/camp/internal/biz"
 	"gorm.io/gorm"
+	"strings"
 	"time"
 )
 
@@ -18,10 +18,7 @@ func NewInstanceDataSource(data *Data) biz.InstanceRepo {
 	}
 }
 
-func (instanceDataSource *InstanceDataSource) HeartBeat(ctx context.Context, orgUuid string, groupUuid string, instanceName string) error {
-	key := fmt.Sprintf("%s_%s_%s_heartbeat", orgUuid, groupUuid, instanceName)
-	return instanceDataSource.data.redis.Set(ctx, key, "", 10*time.Second).Err()
-}
+// 实例已存在时更新客户端 IP 并标记在线，create_time 保留首次连接的时间
 
 func (instanceDataSource *InstanceDataSource) Register(ctx context.Context, instance biz.Instance) (string, error) {
 	//
@@ -47,26 +44,87 @@ func (instanceDataSource *InstanceDataSource) Register(ctx context.Context, inst
 			instance.OrgUuid, instance.GroupUuid, instance.InstanceName).
 		Updates(map[string]interface{}{
 			"client_ip":   instance.ClientIp,
-			"create_time": instance.CreateTime,
+			"update_time": instance.UpdateTime,
+			"online":      true,
 		})
 	return iInstance.Uuid, tx.Error
 }
 
-func (instanceDataSource *InstanceDataSource) List(ctx context.Context, orgUuid string, groupUuid string) ([]biz.Instance, error) {
-	var instances []biz.Instance
-	tx := instanceDataSource.data.db.WithContext(ctx).Where("org_uuid = ?", orgUuid)
-	if groupUuid != "" {
-		tx.Where("group_uuid = ?", groupUuid)
+// 标签条件以 instance_label 子查询表示，不存在该标签的实例满足 != 及 notin
+
+const labelExistsQuery = "select 1 from instance_label where instance_label.instance_uuid = instance.uuid and instance_label.label_key = ?"
+
+func (instanceDataSource *InstanceDataSource) Search(ctx context.Context, query biz.InstanceQuery) ([]biz.Instance, int64, error) {
+	tx := instanceDataSource.data.db.WithContext(ctx).Model(&biz.Instance{})
+	
+	if query.OrgUuid != "" {
+		tx.Where("org_uuid = ?", query.OrgUuid)
 	}
 	
-	tx.Order("create_time desc").Find(&instances)
-	return instances, tx.Error
+	if query.GroupUuid != "" {
+		tx.Where("group_uuid = ?", query.GroupUuid)
+	}
+	
+	if query.Name != "" {
+		tx.Where("instance_name like ?", "%"+escapeLike(query.Name)+"%")
+	}
+	
+	if len(query.InstanceNames) > 0 {
+		tx.Where("instance_name in ?", query.InstanceNames)
+	}
+	
+	if query.Online != nil {
+		tx.Where("online = ?", *query.Online)
+	}
+	
+	if query.Os != "" {
+		tx.Where("os = ?", query.Os)
+	}
+	
+	if query.Platform != "" {
+		tx.Where("platform = ?", query.Platform)
+	}
+	
+	if query.AgentVersion != "" {
+		tx.Where("agent_version = ?", query.AgentVersion)
+	}
+	
+	if query.UpdatedBefore > 0 {
+		tx.Where("update_time < ?", query.UpdatedBefore)
+	}
+	
+	for _, requirement := range query.Selector {
+		switch requirement.Operator {
+		case biz.SelectorEquals, biz.SelectorIn:
+			tx.Where("exists ("+labelExistsQuery+" and instance_label.label_value in ?)", requirement.Key, requirement.Values)
+		case biz.SelectorNotEquals, biz.SelectorNotIn:
+			tx.Where("not exists ("+labelExistsQuery+" and instance_label.label_value in ?)", requirement.Key, requirement.Values)
+		case biz.SelectorExists:
+			tx.Where("exists ("+labelExistsQuery+")", requirement.Key)
+		case biz.SelectorDoesNotExist:
+			tx.Where("not exists ("+labelExistsQuery+")", requirement.Key)
+		}
+	}
+	
+	var total int64
+	tx.Count(&total)
+	if tx.Error != nil {
+		return nil, 0, tx.Error
+	}
+	
+	var instances []biz.Instance
+	tx.Order("org_uuid, group_uuid, instance_name").Offset(query.Offset).Limit(query.Limit).Find(&instances)
+	return instances, total, tx.Error
 }
 
-func (instanceDataSource *InstanceDataSource) ListAliveInstance(ctx context.Context, orgUuid string, groupUuid string) ([]biz.Instance, error) {
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (instanceDataSource *InstanceDataSource) ListOnline(ctx context.Context, orgUuid string, groupUuid string) ([]biz.Instance, error) {
 	var instances []biz.Instance
 	
-	tx := instanceDataSource.data.db.WithContext(ctx).Where("update_time >= ?", time.Now().Unix()-20)
+	tx := instanceDataSource.data.db.WithContext(ctx).Where("online = ?", true)
 	
 	if orgUuid != "" {
 		tx.Where("org_uuid = ?", orgUuid)
@@ -89,10 +147,48 @@ func (instanceDataSource *InstanceDataSource) Get(ctx context.Context, orgUuid s
 	return instance, tx.Error
 }
 
-func (instanceDataSource *InstanceDataSource) UpdateTime(ctx context.Context, uuid string) error {
+// 仅在状态变化时更新
+
+func (instanceDataSource *InstanceDataSource) Online(ctx context.Context, uuid string) error {
+	tx := instanceDataSource.data.db.WithContext(ctx).
+		Model(&biz.Instance{}).
+		Where("uuid = ? and online = ?", uuid, false).
+		Updates(map[string]interface{}{
+			"update_time": time.Now().Unix(),
+			"online":      true,
+		})
+	return tx.Error
+}
+
+func (instanceDataSource *InstanceDataSource) Offline(ctx context.Context, uuid string) error {
+	tx := instanceDataSource.data.db.WithContext(ctx).
+		Model(&biz.Instance{}).
+		Where("uuid = ? and online = ?", uuid, true).
+		Updates(map[string]interface{}{
+			"update_time": time.Now().Unix(),
+			"online":      false,
+		})
+	return tx.Error
+}
+
+func (instanceDataSource *InstanceDataSource) UpdateAgentInfo(ctx context.Context, uuid string, hello biz.AgentHello) error {
+	tx := instanceDataSource.data.db.WithContext(ctx).
+		Model(&biz.Instance{}).
+		Where("uuid = ?", uuid).
+		Select("agent_version", "agent_info").
+		Updates(&biz.Instance{AgentVersion: hello.Version, AgentInfo: &hello})
+	return tx.Error
+}
+
+func (instanceDataSource *InstanceDataSource) UpdateInventory(ctx context.Context, uuid string, inventory biz.HostInventory) error {
 	tx := instanceDataSource.data.db.WithContext(ctx).
 		Model(&biz.Instance{}).
 		Where("uuid = ?", uuid).
-		Update("update_time", time.Now().Unix())
+		Select("inventory", "os", "platform").
+		Updates(&biz.Instance{Inventory: &inventory, Os: inventory.Os, Platform: inventory.Platform})
 	return tx.Error
 }
+
+func (instanceDataSource *InstanceDataSource) Delete(ctx context.Context, uuid string) error {
+	return instanceDataSource.data.db.WithContext(ctx).Where("uuid = ?", uuid).Delete(&biz.Instance{}).Error
+}
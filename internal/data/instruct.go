@@ -3,10 +3,26 @@ package data
 import (
 	"context"
 	"fmt"
+	"github.com/go-redis/redis/v8"
 	"github.com/qx66/camp/internal/biz"
+	"github.com/qx66/camp/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"time"
 )
 
+// 取消状态的保留时间，超过后仍在队列中的指令会被下发
+
+const instructCancelledTTL = 7 * 24 * time.Hour
+
+func instructCancelledKey(uuid string) string {
+	return fmt.Sprintf("instruct_%s_cancelled", uuid)
+}
+
+func instructQueueKey(orgUuid, groupUuid, instanceName string) string {
+	return fmt.Sprintf("%s_%s_%s_instructions_channel", orgUuid, groupUuid, instanceName)
+}
+
 type InstructDataSource struct {
 	data *Data
 }
@@ -18,31 +34,116 @@ func NewInstructDataSource(data *Data) biz.InstructRepo {
 }
 
 func (instructDataSource *InstructDataSource) IssueInstructions(ctx context.Context, orgUuid string, groupUuid string, instanceName string, instruct []byte) error {
-	key := fmt.Sprintf("%s_%s_%s_instructions_channel", orgUuid, groupUuid, instanceName)
-	return instructDataSource.data.redis.LPush(ctx, key, instruct).Err()
+	key := instructQueueKey(orgUuid, groupUuid, instanceName)
+	
+	ctx, span := startSpan(ctx, "redis.LPush", attribute.String("db.redis.key", key))
+	defer span.End()
+	
+	return endSpan(span, instructDataSource.data.redis.LPush(ctx, key, instruct).Err())
 }
 
+// 连接空闲时持续轮询队列，空轮询不产生 span
+
 func (instructDataSource *InstructDataSource) ReceiveInstructions(ctx context.Context, orgUuid string, groupUuid string, instanceName string) (string, error) {
-	key := fmt.Sprintf("%s_%s_%s_instructions_channel", orgUuid, groupUuid, instanceName)
-	return instructDataSource.data.redis.LPop(ctx, key).Result()
+	key := instructQueueKey(orgUuid, groupUuid, instanceName)
+	
+	start := time.Now()
+	instruction, err := instructDataSource.data.redis.LPop(ctx, key).Result()
+	if err == redis.Nil {
+		return instruction, err
+	}
+	
+	_, span := tracing.Tracer().Start(ctx, "redis.LPop",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(attribute.String("db.redis.key", key)),
+	)
+	defer span.End()
+	
+	return instruction, endSpan(span, err)
 }
 
 func (instructDataSource *InstructDataSource) RecordInstruct(ctx context.Context, instruct biz.Instruct) error {
+	ctx, span := startSpan(ctx, "mysql.RecordInstruct", attribute.String("instruct.uuid", instruct.Uuid))
+	defer span.End()
+	
 	tx := instructDataSource.data.db.WithContext(ctx).Create(&instruct)
-	return tx.Error
+	return endSpan(span, tx.Error)
 }
 
-func (instructDataSource *InstructDataSource) UpdateInstruct(ctx context.Context, uuid, reply string, result int32) error {
+// 仅更新未结束的指令，返回更新的记录数
+
+func (instructDataSource *InstructDataSource) UpdateInstruct(ctx context.Context, uuid, reply string, result int32) (int64, error) {
+	ctx, span := startSpan(ctx, "mysql.UpdateInstruct", attribute.String("instruct.uuid", uuid))
+	defer span.End()
+	
 	tx := instructDataSource.data.db.WithContext(ctx).
 		Model(&biz.Instruct{}).
-		Where("uuid = ?", uuid).
+		Where("uuid = ? and result = ?", uuid, 0).
+		Updates(map[string]interface{}{
+			"reply":       reply,
+			"update_time": time.Now().Unix(),
+			"result":      result,
+		})
+	
+	return tx.RowsAffected, endSpan(span, tx.Error)
+}
+
+// 仅更新标记为中断的指令，返回更新的记录数
+
+func (instructDataSource *InstructDataSource) UpdateInterruptedInstruct(ctx context.Context, uuid, reply string, result int32) (int64, error) {
+	ctx, span := startSpan(ctx, "mysql.UpdateInterruptedInstruct", attribute.String("instruct.uuid", uuid))
+	defer span.End()
+	
+	tx := instructDataSource.data.db.WithContext(ctx).
+		Model(&biz.Instruct{}).
+		Where("uuid = ? and result = ? and reply = ?", uuid, -1, biz.InstructInterruptedReply).
 		Updates(map[string]interface{}{
 			"reply":       reply,
 			"update_time": time.Now().Unix(),
 			"result":      result,
 		})
 	
-	return tx.Error
+	return tx.RowsAffected, endSpan(span, tx.Error)
+}
+
+func (instructDataSource *InstructDataSource) MarkInstructTruncated(ctx context.Context, uuid string) error {
+	ctx, span := startSpan(ctx, "mysql.MarkInstructTruncated", attribute.String("instruct.uuid", uuid))
+	defer span.End()
+	
+	tx := instructDataSource.data.db.WithContext(ctx).
+		Model(&biz.Instruct{}).
+		Where("uuid = ?", uuid).
+		Update("truncated", true)
+	
+	return endSpan(span, tx.Error)
+}
+
+// 仅取消未结束的指令，同时在 redis 中记录取消状态，尚未下发的指令不再下发
+
+func (instructDataSource *InstructDataSource) CancelInstruct(ctx context.Context, uuid string) (bool, error) {
+	ctx, span := startSpan(ctx, "mysql.CancelInstruct", attribute.String("instruct.uuid", uuid))
+	defer span.End()
+	
+	tx := instructDataSource.data.db.WithContext(ctx).
+		Model(&biz.Instruct{}).
+		Where("uuid = ? and result = ?", uuid, 0).
+		Updates(map[string]interface{}{
+			"reply":       "指令已取消",
+			"update_time": time.Now().Unix(),
+			"result":      -1,
+		})
+	if tx.Error != nil || tx.RowsAffected == 0 {
+		return false, endSpan(span, tx.Error)
+	}
+	
+	err := instructDataSource.data.redis.Set(ctx, instructCancelledKey(uuid), "", instructCancelledTTL).Err()
+	return true, endSpan(span, err)
+}
+
+func (instructDataSource *InstructDataSource) InstructCancelled(ctx context.Context, uuid string) (bool, error) {
+	n, err := instructDataSource.data.redis.Exists(ctx, instructCancelledKey(uuid)).Result()
+	return n > 0, err
 }
 
 func (instructDataSource *InstructDataSource) ListInstruct(ctx context.Context, orgUuid string, groupUuid string, instanceName string) ([]biz.Instruct, error) {
@@ -55,6 +156,56 @@ func (instructDataSource *InstructDataSource) ListInstruct(ctx context.Context,
 	return instructs, tx.Error
 }
 
+// 按创建时间倒序查询，不读取 reply
+
+func (instructDataSource *InstructDataSource) QueryInstructs(ctx context.Context, query biz.InstructQuery) ([]biz.Instruct, error) {
+	tx := instructDataSource.data.db.WithContext(ctx).Model(&biz.Instruct{}).Omit("reply")
+	
+	if query.OrgUuid != "" {
+		tx.Where("org_uuid = ?", query.OrgUuid)
+	}
+	
+	if query.GroupUuid != "" {
+		tx.Where("group_uuid = ?", query.GroupUuid)
+	}
+	
+	if query.InstanceName != "" {
+		tx.Where("instance_name = ?", query.InstanceName)
+	}
+	
+	if query.Type != 0 {
+		tx.Where("`type` = ?", query.Type)
+	}
+	
+	if query.Result != nil {
+		tx.Where("result = ?", *query.Result)
+	}
+	
+	if query.Operator != "" {
+		tx.Where("operator = ?", query.Operator)
+	}
+	
+	if query.BatchUuid != "" {
+		tx.Where("batch_uuid = ?", query.BatchUuid)
+	}
+	
+	if query.StartTime > 0 {
+		tx.Where("create_time >= ?", query.StartTime)
+	}
+	
+	if query.EndTime > 0 {
+		tx.Where("create_time < ?", query.EndTime)
+	}
+	
+	if query.After != nil {
+		tx.Where("create_time < ? or (create_time = ? and uuid < ?)", query.After.CreateTime, query.After.CreateTime, query.After.Uuid)
+	}
+	
+	var instructs []biz.Instruct
+	tx.Order("create_time desc, uuid desc").Limit(query.Limit).Find(&instructs)
+	return instructs, tx.Error
+}
+
 func (instructDataSource *InstructDataSource) GetInstruct(ctx context.Context, orgUuid string, groupUuid string, instanceName string, uuid string) (biz.Instruct, error) {
 	var instruct biz.Instruct
 	tx := instructDataSource.data.db.WithContext(ctx).
@@ -62,3 +213,25 @@ func (instructDataSource *InstructDataSource) GetInstruct(ctx context.Context, o
 		First(&instruct)
 	return instruct, tx.Error
 }
+
+func (instructDataSource *InstructDataSource) FindInstruct(ctx context.Context, uuid string) (biz.Instruct, error) {
+	var instruct biz.Instruct
+	tx := instructDataSource.data.db.WithContext(ctx).
+		Where("uuid = ?", uuid).
+		First(&instruct)
+	return instruct, tx.Error
+}
+
+func (instructDataSource *InstructDataSource) DeleteInstructQueue(ctx context.Context, orgUuid, groupUuid, instanceName string) error {
+	return instructDataSource.data.redis.Del(ctx, instructQueueKey(orgUuid, groupUuid, instanceName)).Err()
+}
+
+// 删除分组下最多 limit 条指令记录，返回删除的数量
+
+func (instructDataSource *InstructDataSource) DeleteGroupInstructs(ctx context.Context, orgUuid, groupUuid string, limit int) (int64, error) {
+	tx := instructDataSource.data.db.WithContext(ctx).
+		Where("org_uuid = ? and group_uuid = ?", orgUuid, groupUuid).
+		Limit(limit).
+		Delete(&biz.Instruct{})
+	return tx.RowsAffected, tx.Error
+}
@@ -0,0 +1,84 @@
+package data
+
+import (
+	"context"
+	"github.com/qx66/camp/internal/biz"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LabelDataSource struct {
+	data *Data
+}
+
+func NewLabelDataSource(data *Data) biz.LabelRepo {
+	return &LabelDataSource{
+		data: data,
+	}
+}
+
+// 替换 soldier 上报的标签，运维人员设置的同名标签不覆盖
+
+func (labelDataSource *LabelDataSource) SetAgentLabels(ctx context.Context, instanceUuid string, labels map[string]string) error {
+	return labelDataSource.data.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("instance_uuid = ? and source = ?", instanceUuid, biz.LabelSourceAgent).Delete(&biz.Label{}).Error
+		if err != nil {
+			return err
+		}
+		
+		if len(labels) == 0 {
+			return nil
+		}
+		
+		rows := make([]biz.Label, 0, len(labels))
+		for key, value := range labels {
+			rows = append(rows, biz.Label{InstanceUuid: instanceUuid, Key: key, Value: value, Source: biz.LabelSourceAgent})
+		}
+		
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+	})
+}
+
+func (labelDataSource *LabelDataSource) UpdateLabels(ctx context.Context, instanceUuid string, set map[string]string, remove []string) error {
+	return labelDataSource.data.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(remove) > 0 {
+			err := tx.Where("instance_uuid = ? and label_key in ?", instanceUuid, remove).Delete(&biz.Label{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		
+		if len(set) == 0 {
+			return nil
+		}
+		
+		rows := make([]biz.Label, 0, len(set))
+		for key, value := range set {
+			rows = append(rows, biz.Label{InstanceUuid: instanceUuid, Key: key, Value: value, Source: biz.LabelSourceOperator})
+		}
+		
+		return tx.Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"label_value", "source"})}).Create(&rows).Error
+	})
+}
+
+func (labelDataSource *LabelDataSource) ListLabels(ctx context.Context, instanceUuids []string) (map[string]map[string]string, error) {
+	var rows []biz.Label
+	tx := labelDataSource.data.db.WithContext(ctx).Where("instance_uuid in ?", instanceUuids).Find(&rows)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	
+	labels := make(map[string]map[string]string)
+	for _, row := range rows {
+		if labels[row.InstanceUuid] == nil {
+			labels[row.InstanceUuid] = make(map[string]string)
+		}
+		labels[row.InstanceUuid][row.Key] = row.Value
+	}
+	
+	return labels, nil
+}
+
+func (labelDataSource *LabelDataSource) DeleteLabels(ctx context.Context, instanceUuid string) error {
+	return labelDataSource.data.db.WithContext(ctx).Where("instance_uuid = ?", instanceUuid).Delete(&biz.Label{}).Error
+}
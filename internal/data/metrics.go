@@ -0,0 +1,66 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/qx66/camp/internal/biz"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"time"
+)
+
+type MetricsDataSource struct {
+	data *Data
+}
+
+func NewMetricsDataSource(data *Data) biz.MetricsRepo {
+	return &MetricsDataSource{
+		data: data,
+	}
+}
+
+// 监控数据以列表保存在 redis 中，最新的数据在表头
+
+func (metricsDataSource *MetricsDataSource) PushMetrics(ctx context.Context, instanceUuid string, metrics biz.HostMetrics, windowSize int64, ttl time.Duration) error {
+	key := fmt.Sprintf("%s_metrics", instanceUuid)
+	
+	b, err := json.Marshal(metrics)
+	if err != nil {
+		return err
+	}
+	
+	ctx, span := startSpan(ctx, "redis.PushMetrics", attribute.String("db.redis.key", key))
+	defer span.End()
+	
+	_, err = metricsDataSource.data.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, key, b)
+		pipe.LTrim(ctx, key, 0, windowSize-1)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	return endSpan(span, err)
+}
+
+func (metricsDataSource *MetricsDataSource) ListMetrics(ctx context.Context, instanceUuid string) ([]biz.HostMetrics, error) {
+	key := fmt.Sprintf("%s_metrics", instanceUuid)
+	
+	values, err := metricsDataSource.data.redis.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	
+	metrics := make([]biz.HostMetrics, 0, len(values))
+	for _, value := range values {
+		var m biz.HostMetrics
+		err = json.Unmarshal([]byte(value), &m)
+		if err != nil {
+			metricsDataSource.data.logger.Warn("反序列化监控数据失败", zap.String("key", key), zap.Error(err))
+			continue
+		}
+		metrics = append(metrics, m)
+	}
+	
+	return metrics, nil
+}
@@ -0,0 +1,136 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"github.com/qx66/camp/internal/biz"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+const orgCleanupLockKey = "org_cleanup"
+
+type OrgDataSource struct {
+	data *Data
+}
+
+func NewOrgDataSource(data *Data) biz.OrgRepo {
+	return &OrgDataSource{
+		data: data,
+	}
+}
+
+func (orgDataSource *OrgDataSource) CreateOrg(ctx context.Context, org biz.Org) error {
+	return orgDataSource.data.db.WithContext(ctx).Create(&org).Error
+}
+
+func (orgDataSource *OrgDataSource) UpdateOrg(ctx context.Context, org biz.Org) error {
+	return orgDataSource.data.db.WithContext(ctx).
+		Select("name", "description", "status", "update_time").
+		Updates(&org).Error
+}
+
+func (orgDataSource *OrgDataSource) GetOrg(ctx context.Context, uuid string) (biz.Org, error) {
+	var org biz.Org
+	tx := orgDataSource.data.db.WithContext(ctx).Where("uuid = ?", uuid).First(&org)
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return org, biz.ErrOrgNotFound
+	}
+	return org, tx.Error
+}
+
+func (orgDataSource *OrgDataSource) ListOrgs(ctx context.Context, offset, limit int) ([]biz.Org, int64, error) {
+	tx := orgDataSource.data.db.WithContext(ctx).Model(&biz.Org{}).Where("status != ?", biz.OrgDeleted)
+	
+	var total int64
+	tx.Count(&total)
+	if tx.Error != nil {
+		return nil, 0, tx.Error
+	}
+	
+	var orgs []biz.Org
+	tx.Order("create_time, uuid").Offset(offset).Limit(limit).Find(&orgs)
+	return orgs, total, tx.Error
+}
+
+func (orgDataSource *OrgDataSource) ListOrgsByStatus(ctx context.Context, status string) ([]biz.Org, error) {
+	var orgs []biz.Org
+	tx := orgDataSource.data.db.WithContext(ctx).Where("status = ?", status).Find(&orgs)
+	return orgs, tx.Error
+}
+
+func (orgDataSource *OrgDataSource) CreateGroup(ctx context.Context, group biz.Group) error {
+	return orgDataSource.data.db.WithContext(ctx).Create(&group).Error
+}
+
+func (orgDataSource *OrgDataSource) UpdateGroup(ctx context.Context, group biz.Group) error {
+	return orgDataSource.data.db.WithContext(ctx).
+		Select("name", "description", "settings", "status", "update_time").
+		Updates(&group).Error
+}
+
+func (orgDataSource *OrgDataSource) GetGroup(ctx context.Context, orgUuid, uuid string) (biz.Group, error) {
+	var group biz.Group
+	tx := orgDataSource.data.db.WithContext(ctx).Where("org_uuid = ? and uuid = ?", orgUuid, uuid).First(&group)
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return group, biz.ErrGroupNotFound
+	}
+	return group, tx.Error
+}
+
+func (orgDataSource *OrgDataSource) ListGroups(ctx context.Context, orgUuid string, offset, limit int) ([]biz.Group, int64, error) {
+	tx := orgDataSource.data.db.WithContext(ctx).Model(&biz.Group{}).Where("org_uuid = ? and status != ?", orgUuid, biz.OrgDeleted)
+	
+	var total int64
+	tx.Count(&total)
+	if tx.Error != nil {
+		return nil, 0, tx.Error
+	}
+	
+	var groups []biz.Group
+	tx.Order("create_time, uuid").Offset(offset).Limit(limit).Find(&groups)
+	return groups, total, tx.Error
+}
+
+func (orgDataSource *OrgDataSource) ListGroupsByStatus(ctx context.Context, status string) ([]biz.Group, error) {
+	var groups []biz.Group
+	tx := orgDataSource.data.db.WithContext(ctx).Where("status = ?", status).Find(&groups)
+	return groups, tx.Error
+}
+
+func (orgDataSource *OrgDataSource) CountGroups(ctx context.Context, orgUuid string, excludeStatus string) (int64, error) {
+	var count int64
+	tx := orgDataSource.data.db.WithContext(ctx).Model(&biz.Group{}).
+		Where("org_uuid = ? and status != ?", orgUuid, excludeStatus).
+		Count(&count)
+	return count, tx.Error
+}
+
+// 将组织下未删除的分组修改为 status
+
+func (orgDataSource *OrgDataSource) SetGroupsStatus(ctx context.Context, orgUuid string, status string, updateTime int64) error {
+	return orgDataSource.data.db.WithContext(ctx).Model(&biz.Group{}).
+		Where("org_uuid = ? and status != ?", orgUuid, biz.OrgDeleted).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"update_time": updateTime,
+		}).Error
+}
+
+// 组织及分组不存在时创建，已存在时不修改
+
+func (orgDataSource *OrgDataSource) EnsureGroup(ctx context.Context, org biz.Org, group biz.Group) error {
+	return orgDataSource.data.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&org).Error
+		if err != nil {
+			return err
+		}
+		
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&group).Error
+	})
+}
+
+func (orgDataSource *OrgDataSource) AcquireCleanupLock(ctx context.Context, node string, ttl time.Duration) (bool, error) {
+	return orgDataSource.data.redis.SetNX(ctx, orgCleanupLockKey, node, ttl).Result()
+}
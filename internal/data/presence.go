@@ -0,0 +1,187 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/qx66/camp/internal/biz"
+	"go.opentelemetry.io/otel/attribute"
+	"time"
+)
+
+const (
+	presenceEventsKey    = "presence_events"
+	presenceEventsMaxLen = 100000
+	presenceReapLockKey  = "presence_reaper"
+)
+
+// 在线记录属于当前连接时续期，不存在时重新写入 (redis 中已过期)，已被其他连接覆盖时返回 0
+
+var refreshPresenceScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if current == false then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+end
+if current == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// 在线记录属于当前连接时删除
+
+var deletePresenceScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+type PresenceDataSource struct {
+	data *Data
+}
+
+func NewPresenceDataSource(data *Data) biz.PresenceRepo {
+	return &PresenceDataSource{
+		data: data,
+	}
+}
+
+func presenceKey(instanceUuid string) string {
+	return fmt.Sprintf("presence_%s", instanceUuid)
+}
+
+func (presenceDataSource *PresenceDataSource) SetPresence(ctx context.Context, presence biz.Presence, ttl time.Duration) error {
+	ctx, span := startSpan(ctx, "redis.SetPresence", attribute.String("instance.uuid", presence.InstanceUuid))
+	defer span.End()
+	
+	b, err := json.Marshal(presence)
+	if err != nil {
+		return endSpan(span, err)
+	}
+	
+	return endSpan(span, presenceDataSource.data.redis.Set(ctx, presenceKey(presence.InstanceUuid), b, ttl).Err())
+}
+
+func (presenceDataSource *PresenceDataSource) RefreshPresence(ctx context.Context, presence biz.Presence, ttl time.Duration) (bool, error) {
+	b, err := json.Marshal(presence)
+	if err != nil {
+		return false, err
+	}
+	
+	n, err := refreshPresenceScript.Run(ctx, presenceDataSource.data.redis, []string{presenceKey(presence.InstanceUuid)}, string(b), ttl.Milliseconds()).Int()
+	return n == 1, err
+}
+
+func (presenceDataSource *PresenceDataSource) DeletePresence(ctx context.Context, presence biz.Presence) (bool, error) {
+	ctx, span := startSpan(ctx, "redis.DeletePresence", attribute.String("instance.uuid", presence.InstanceUuid))
+	defer span.End()
+	
+	b, err := json.Marshal(presence)
+	if err != nil {
+		return false, endSpan(span, err)
+	}
+	
+	n, err := deletePresenceScript.Run(ctx, presenceDataSource.data.redis, []string{presenceKey(presence.InstanceUuid)}, string(b)).Int()
+	return n == 1, endSpan(span, err)
+}
+
+// 返回存在在线记录的实例，key 为实例 uuid
+
+func (presenceDataSource *PresenceDataSource) ListPresence(ctx context.Context, instanceUuids []string) (map[string]biz.Presence, error) {
+	presences := make(map[string]biz.Presence)
+	if len(instanceUuids) == 0 {
+		return presences, nil
+	}
+	
+	keys := make([]string, 0, len(instanceUuids))
+	for _, instanceUuid := range instanceUuids {
+		keys = append(keys, presenceKey(instanceUuid))
+	}
+	
+	values, err := presenceDataSource.data.redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	
+	for _, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+		
+		var presence biz.Presence
+		err = json.Unmarshal([]byte(s), &presence)
+		if err != nil {
+			continue
+		}
+		presences[presence.InstanceUuid] = presence
+	}
+	
+	return presences, nil
+}
+
+// 事件写入 stream，保留最近约 presenceEventsMaxLen 条
+
+func (presenceDataSource *PresenceDataSource) AddPresenceEvent(ctx context.Context, event biz.PresenceEvent) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	
+	return presenceDataSource.data.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: presenceEventsKey,
+		MaxLen: presenceEventsMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"event": string(b)},
+	}).Err()
+}
+
+// 读取 after 之后的事件，wait 大于 0 时没有新事件阻塞等待
+
+func (presenceDataSource *PresenceDataSource) ListPresenceEvents(ctx context.Context, after string, count int64, wait time.Duration) ([]biz.PresenceEvent, error) {
+	block := time.Duration(-1)
+	if wait > 0 {
+		block = wait
+	}
+	
+	streams, err := presenceDataSource.data.redis.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{presenceEventsKey, after},
+		Count:   count,
+		Block:   block,
+	}).Result()
+	if err == redis.Nil {
+		return []biz.PresenceEvent{}, nil
+	}
+	
+	if err != nil {
+		return nil, err
+	}
+	
+	events := []biz.PresenceEvent{}
+	for _, stream := range streams {
+		for _, message := range stream.Messages {
+			s, ok := message.Values["event"].(string)
+			if !ok {
+				continue
+			}
+			
+			var event biz.PresenceEvent
+			err = json.Unmarshal([]byte(s), &event)
+			if err != nil {
+				continue
+			}
+			event.Id = message.ID
+			events = append(events, event)
+		}
+	}
+	
+	return events, nil
+}
+
+func (presenceDataSource *PresenceDataSource) AcquireReapLock(ctx context.Context, node string, ttl time.Duration) (bool, error) {
+	return presenceDataSource.data.redis.SetNX(ctx, presenceReapLockKey, node, ttl).Result()
+}
@@ -0,0 +1,115 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"github.com/qx66/camp/internal/biz"
+	"gorm.io/gorm"
+)
+
+type ResultDataSource struct {
+	data *Data
+}
+
+func NewResultDataSource(data *Data) biz.ResultRepo {
+	return &ResultDataSource{
+		data: data,
+	}
+}
+
+func (resultDataSource *ResultDataSource) SaveResult(ctx context.Context, result biz.InstructResult) error {
+	var instruct biz.Instruct
+	err := resultDataSource.data.db.WithContext(ctx).
+		Select("org_uuid, group_uuid, instance_name, batch_uuid").
+		Where("uuid = ?", result.InstructUuid).
+		Take(&instruct).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return biz.ErrInstructNotFound
+	}
+	if err != nil {
+		return err
+	}
+	
+	result.OrgUuid = instruct.OrgUuid
+	result.GroupUuid = instruct.GroupUuid
+	result.InstanceName = instruct.InstanceName
+	result.BatchUuid = instruct.BatchUuid
+	return resultDataSource.data.db.WithContext(ctx).Save(&result).Error
+}
+
+func (resultDataSource *ResultDataSource) QueryResults(ctx context.Context, query biz.ResultQuery) ([]biz.InstructResult, error) {
+	tx := resultDataSource.data.db.WithContext(ctx).Model(&biz.InstructResult{})
+	
+	if query.OrgUuid != "" {
+		tx.Where("org_uuid = ?", query.OrgUuid)
+	}
+	
+	if query.GroupUuid != "" {
+		tx.Where("group_uuid = ?", query.GroupUuid)
+	}
+	
+	if query.InstanceName != "" {
+		tx.Where("instance_name = ?", query.InstanceName)
+	}
+	
+	if query.Type != 0 {
+		tx.Where("`type` = ?", query.Type)
+	}
+	
+	if query.BatchUuid != "" {
+		tx.Where("batch_uuid = ?", query.BatchUuid)
+	}
+	
+	if query.Target != "" {
+		tx.Where("target = ?", query.Target)
+	}
+	
+	if query.Result != nil {
+		tx.Where("result = ?", *query.Result)
+	}
+	
+	if query.MinStatus > 0 {
+		tx.Where("status_code >= ?", query.MinStatus)
+	}
+	
+	if query.MaxStatus > 0 {
+		tx.Where("status_code <= ?", query.MaxStatus)
+	}
+	
+	if query.MinLatency > 0 {
+		tx.Where("latency >= ?", query.MinLatency)
+	}
+	
+	if query.StartTime > 0 {
+		tx.Where("create_time >= ?", query.StartTime)
+	}
+	
+	if query.EndTime > 0 {
+		tx.Where("create_time < ?", query.EndTime)
+	}
+	
+	if query.After != nil {
+		tx.Where("create_time < ? or (create_time = ? and instruct_uuid < ?)", query.After.CreateTime, query.After.CreateTime, query.After.Uuid)
+	}
+	
+	var results []biz.InstructResult
+	tx.Order("create_time desc, instruct_uuid desc").Limit(query.Limit).Find(&results)
+	return results, tx.Error
+}
+
+func (resultDataSource *ResultDataSource) GetResult(ctx context.Context, instructUuid string) (biz.InstructResult, error) {
+	var result biz.InstructResult
+	tx := resultDataSource.data.db.WithContext(ctx).Where("instruct_uuid = ?", instructUuid).First(&result)
+	if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		return result, biz.ErrResultNotFound
+	}
+	return result, tx.Error
+}
+
+func (resultDataSource *ResultDataSource) DeleteGroupResults(ctx context.Context, orgUuid, groupUuid string, limit int) (int64, error) {
+	tx := resultDataSource.data.db.WithContext(ctx).
+		Where("org_uuid = ? and group_uuid = ?", orgUuid, groupUuid).
+		Limit(limit).
+		Delete(&biz.InstructResult{})
+	return tx.RowsAffected, tx.Error
+}
@@ -0,0 +1,119 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"github.com/qx66/camp/internal/biz"
+	"go.opentelemetry.io/otel/attribute"
+	"time"
+)
+
+type SessionDataSource struct {
+	data *Data
+}
+
+func NewSessionDataSource(data *Data) biz.SessionRepo {
+	return &SessionDataSource{
+		data: data,
+	}
+}
+
+func sessionKey(token string) string {
+	return fmt.Sprintf("session_%s", token)
+}
+
+func sessionPendingKey(token string) string {
+	return fmt.Sprintf("session_%s_pending", token)
+}
+
+func instanceSessionKey(instanceUuid string) string {
+	return fmt.Sprintf("%s_session", instanceUuid)
+}
+
+// 创建会话并替换实例的当前会话，返回被替换的会话 token
+
+func (sessionDataSource *SessionDataSource) CreateSession(ctx context.Context, instanceUuid, token string, ttl time.Duration) (string, error) {
+	ctx, span := startSpan(ctx, "redis.CreateSession", attribute.String("instance.uuid", instanceUuid))
+	defer span.End()
+	
+	var previous *redis.StringCmd
+	_, err := sessionDataSource.data.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, sessionKey(token), instanceUuid, ttl)
+		previous = pipe.GetSet(ctx, instanceSessionKey(instanceUuid), token)
+		pipe.Expire(ctx, instanceSessionKey(instanceUuid), ttl)
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return "", endSpan(span, err)
+	}
+	
+	return previous.Val(), nil
+}
+
+// 返回会话所属实例，会话不存在或已过期时返回空
+
+func (sessionDataSource *SessionDataSource) GetSession(ctx context.Context, token string) (string, error) {
+	instanceUuid, err := sessionDataSource.data.redis.Get(ctx, sessionKey(token)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	
+	return instanceUuid, err
+}
+
+func (sessionDataSource *SessionDataSource) RefreshSession(ctx context.Context, instanceUuid, token string, ttl time.Duration) error {
+	_, err := sessionDataSource.data.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Expire(ctx, sessionKey(token), ttl)
+		pipe.Expire(ctx, sessionPendingKey(token), ttl)
+		pipe.Expire(ctx, instanceSessionKey(instanceUuid), ttl)
+		return nil
+	})
+	return err
+}
+
+func (sessionDataSource *SessionDataSource) DeleteSession(ctx context.Context, token string) error {
+	return sessionDataSource.data.redis.Del(ctx, sessionKey(token), sessionPendingKey(token)).Err()
+}
+
+// 删除实例的当前会话，soldier 携带该会话 token 重连时无法恢复
+
+func (sessionDataSource *SessionDataSource) RevokeInstanceSession(ctx context.Context, instanceUuid string) error {
+	token, err := sessionDataSource.data.redis.Get(ctx, instanceSessionKey(instanceUuid)).Result()
+	if err == redis.Nil {
+		return nil
+	}
+	
+	if err != nil {
+		return err
+	}
+	
+	return sessionDataSource.data.redis.Del(ctx, instanceSessionKey(instanceUuid), sessionKey(token), sessionPendingKey(token)).Err()
+}
+
+// 待确认指令以 hash 保存，field 为指令 uuid
+
+func (sessionDataSource *SessionDataSource) AddPendingInstruct(ctx context.Context, token, uuid, instruction string, ttl time.Duration) error {
+	key := sessionPendingKey(token)
+	
+	ctx, span := startSpan(ctx, "redis.AddPendingInstruct", attribute.String("db.redis.key", key))
+	defer span.End()
+	
+	_, err := sessionDataSource.data.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, uuid, instruction)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	return endSpan(span, err)
+}
+
+// 移除待确认指令，返回指令是否仍待确认
+
+func (sessionDataSource *SessionDataSource) RemovePendingInstruct(ctx context.Context, token, uuid string) (bool, error) {
+	n, err := sessionDataSource.data.redis.HDel(ctx, sessionPendingKey(token), uuid).Result()
+	return n > 0, err
+}
+
+func (sessionDataSource *SessionDataSource) ListPendingInstructs(ctx context.Context, token string) (map[string]string, error) {
+	return sessionDataSource.data.redis.HGetAll(ctx, sessionPendingKey(token)).Result()
+}
@@ -0,0 +1,783 @@
+package service
+
+import (
+	"context"
+	"errors"
+	v1 "github.com/qx66/camp/api/camp/v1"
+	"github.com/qx66/camp/internal/biz"
+	"github.com/qx66/camp/pkg/middleware"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"time"
+)
+
+// CommanderService 实现 api/camp/v1 中定义的 Commander 接口，同时提供 gRPC 及 HTTP 访问
+
+type CommanderService struct {
+	v1.UnimplementedCommanderServer
+	
+	instructUseCase *biz.InstructUseCase
+	instanceUseCase *biz.InstanceUseCase
+	metricsUseCase  *biz.MetricsUseCase
+	presenceUseCase *biz.PresenceUseCase
+	clusterUseCase  *biz.ClusterUseCase
+	orgUseCase      *biz.OrgUseCase
+	archiveUseCase  *biz.ArchiveUseCase
+	resultUseCase   *biz.ResultUseCase
+	batchUseCase    *biz.BatchUseCase
+	logger          *zap.Logger
+}
+
+func NewCommanderService(logger *zap.Logger, instructUseCase *biz.InstructUseCase, instanceUseCase *biz.InstanceUseCase, metricsUseCase *biz.MetricsUseCase, presenceUseCase *biz.PresenceUseCase, clusterUseCase *biz.ClusterUseCase, orgUseCase *biz.OrgUseCase, archiveUseCase *biz.ArchiveUseCase, resultUseCase *biz.ResultUseCase, batchUseCase *biz.BatchUseCase) *CommanderService {
+	return &CommanderService{
+		instructUseCase: instructUseCase,
+		instanceUseCase: instanceUseCase,
+		metricsUseCase:  metricsUseCase,
+		presenceUseCase: presenceUseCase,
+		clusterUseCase:  clusterUseCase,
+		orgUseCase:      orgUseCase,
+		archiveUseCase:  archiveUseCase,
+		resultUseCase:   resultUseCase,
+		batchUseCase:    batchUseCase,
+		logger:          logger,
+	}
+}
+
+func (commanderService *CommanderService) ListAliveInstance(ctx context.Context, req *v1.ListAliveInstanceRequest) (*v1.ListAliveInstanceReply, error) {
+	instances, err := commanderService.instanceUseCase.ListAliveInstance(ctx, req.OrgUuid, req.GroupUuid)
+	if err != nil {
+		commanderService.logger.Error("列出在线实例失败", zap.Error(err))
+		return nil, v1.ErrorInternalError("系统异常")
+	}
+	
+	reply := &v1.ListAliveInstanceReply{}
+	for _, instance := range instances {
+		reply.Instances = append(reply.Instances, toInstanceReply(instance))
+	}
+	
+	return reply, nil
+}
+
+func (commanderService *CommanderService) SearchInstances(ctx context.Context, req *v1.SearchInstancesRequest) (*v1.SearchInstancesReply, error) {
+	if req.Offset < 0 || req.Limit < 0 {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	query := biz.InstanceQuery{
+		OrgUuid:      req.OrgUuid,
+		GroupUuid:    req.GroupUuid,
+		Name:         req.Name,
+		Os:           req.Os,
+		Platform:     req.Platform,
+		AgentVersion: req.AgentVersion,
+		Offset:       int(req.Offset),
+		Limit:        int(req.Limit),
+	}
+	
+	switch req.Status {
+	case "":
+	case biz.PresenceOnline:
+		online := true
+		query.Online = &online
+	case biz.PresenceOffline:
+		online := false
+		query.Online = &online
+	default:
+		return nil, v1.ErrorInvalidArgument("status 仅支持 online / offline")
+	}
+	
+	selector, err := biz.ParseSelector(req.Selector)
+	if err != nil {
+		return nil, v1.ErrorInvalidArgument("%s", err.Error())
+	}
+	query.Selector = selector
+	
+	instances, total, err := commanderService.instanceUseCase.SearchInstances(ctx, query)
+	if err != nil {
+		commanderService.logger.Error("搜索实例失败", zap.Error(err))
+		return nil, v1.ErrorInternalError("搜索实例失败")
+	}
+	
+	reply := &v1.SearchInstancesReply{Data: make([]*v1.Instance, 0, len(instances)), Total: total}
+	for _, instance := range instances {
+		reply.Data = append(reply.Data, toInstanceReply(instance))
+	}
+	
+	return reply, nil
+}
+
+func (commanderService *CommanderService) UpdateInstanceLabels(ctx context.Context, req *v1.UpdateInstanceLabelsRequest) (*v1.UpdateInstanceLabelsReply, error) {
+	if req.OrgUuid == "" || req.GroupUuid == "" || req.InstanceName == "" {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	labels, err := commanderService.instanceUseCase.UpdateLabels(ctx, req.OrgUuid, req.GroupUuid, req.InstanceName, req.Set, req.Remove)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, v1.ErrorInstanceNotFound("实例不存在: %s", req.InstanceName)
+		}
+		
+		if errors.Is(err, biz.ErrInvalidLabel) {
+			return nil, v1.ErrorInvalidArgument("%s", err.Error())
+		}
+		
+		commanderService.logger.Error("更新实例标签失败", zap.String("instanceName", req.InstanceName), zap.Error(err))
+		return nil, v1.ErrorInternalError("更新实例标签失败")
+	}
+	
+	return &v1.UpdateInstanceLabelsReply{Labels: labels}, nil
+}
+
+func (commanderService *CommanderService) GetInstance(ctx context.Context, req *v1.GetInstanceRequest) (*v1.GetInstanceReply, error) {
+	if req.OrgUuid == "" || req.GroupUuid == "" || req.InstanceName == "" {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	instance, err := commanderService.instanceUseCase.GetInstance(ctx, req.OrgUuid, req.GroupUuid, req.InstanceName)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, v1.ErrorInstanceNotFound("实例不存在: %s", req.InstanceName)
+		}
+		
+		commanderService.logger.Error("获取实例失败", zap.String("instanceName", req.InstanceName), zap.Error(err))
+		return nil, v1.ErrorInternalError("获取实例失败")
+	}
+	
+	return &v1.GetInstanceReply{Data: toInstanceReply(instance)}, nil
+}
+
+func (commanderService *CommanderService) ListInstanceMetrics(ctx context.Context, req *v1.ListInstanceMetricsRequest) (*v1.ListInstanceMetricsReply, error) {
+	if req.OrgUuid == "" || req.GroupUuid == "" || req.InstanceName == "" {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	metrics, err := commanderService.metricsUseCase.ListMetrics(ctx, req.OrgUuid, req.GroupUuid, req.InstanceName, req.StartTime, req.EndTime)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, v1.ErrorInstanceNotFound("实例不存在: %s", req.InstanceName)
+		}
+		
+		commanderService.logger.Error("查询实例监控数据失败", zap.String("instanceName", req.InstanceName), zap.Error(err))
+		return nil, v1.ErrorInternalError("查询实例监控数据失败")
+	}
+	
+	reply := &v1.ListInstanceMetricsReply{}
+	for _, m := range metrics {
+		reply.Data = append(reply.Data, toMetricsReply(m))
+	}
+	
+	return reply, nil
+}
+
+func (commanderService *CommanderService) ListInstanceEvents(ctx context.Context, req *v1.ListInstanceEventsRequest) (*v1.ListInstanceEventsReply, error) {
+	if req.Limit < 0 || req.WaitSeconds < 0 {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	events, err := commanderService.presenceUseCase.ListEvents(ctx, req.After, req.Limit, time.Duration(req.WaitSeconds)*time.Second)
+	if err != nil {
+		commanderService.logger.Error("读取实例上下线事件失败", zap.String("after", req.After), zap.Error(err))
+		return nil, v1.ErrorInternalError("读取实例上下线事件失败")
+	}
+	
+	reply := &v1.ListInstanceEventsReply{LastId: req.After}
+	for _, event := range events {
+		reply.Events = append(reply.Events, &v1.InstanceEvent{
+			Id:           event.Id,
+			Event:        event.Event,
+			InstanceUuid: event.InstanceUuid,
+			OrgUuid:      event.OrgUuid,
+			GroupUuid:    event.GroupUuid,
+			InstanceName: event.InstanceName,
+			Node:         event.Node,
+			Time:         event.Time,
+		})
+		reply.LastId = event.Id
+	}
+	
+	return reply, nil
+}
+
+func (commanderService *CommanderService) ListInstanceConnections(ctx context.Context, req *v1.ListInstanceConnectionsRequest) (*v1.ListInstanceConnectionsReply, error) {
+	if req.OrgUuid == "" || req.GroupUuid == "" || req.InstanceName == "" || req.Limit < 0 {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	connections, err := commanderService.instanceUseCase.ListConnections(ctx, req.OrgUuid, req.GroupUuid, req.InstanceName, req.StartTime, req.EndTime, int(req.Limit))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, v1.ErrorInstanceNotFound("实例不存在: %s", req.InstanceName)
+		}
+		
+		if errors.Is(err, biz.ErrInvalidTimeRange) {
+			return nil, v1.ErrorInvalidArgument("时间范围异常")
+		}
+		
+		commanderService.logger.Error("查询实例连接记录失败", zap.String("instanceName", req.InstanceName), zap.Error(err))
+		return nil, v1.ErrorInternalError("查询实例连接记录失败")
+	}
+	
+	reply := &v1.ListInstanceConnectionsReply{}
+	for _, connection := range connections {
+		reply.Data = append(reply.Data, &v1.InstanceConnection{
+			Id:               connection.Id,
+			ClientIp:         connection.ClientIp,
+			AgentVersion:     connection.AgentVersion,
+			Node:             connection.Node,
+			ConnectTime:      connection.ConnectTime,
+			DisconnectTime:   connection.DisconnectTime,
+			DisconnectReason: connection.DisconnectReason,
+		})
+	}
+	
+	return reply, nil
+}
+
+func (commanderService *CommanderService) GetInstanceUptime(ctx context.Context, req *v1.GetInstanceUptimeRequest) (*v1.GetInstanceUptimeReply, error) {
+	if req.OrgUuid == "" || req.GroupUuid == "" || req.InstanceName == "" {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	uptime, err := commanderService.instanceUseCase.GetUptime(ctx, req.OrgUuid, req.GroupUuid, req.InstanceName, req.StartTime, req.EndTime)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, v1.ErrorInstanceNotFound("实例不存在: %s", req.InstanceName)
+		}
+		
+		if errors.Is(err, biz.ErrInvalidTimeRange) {
+			return nil, v1.ErrorInvalidArgument("时间范围异常")
+		}
+		
+		commanderService.logger.Error("查询实例在线时间失败", zap.String("instanceName", req.InstanceName), zap.Error(err))
+		return nil, v1.ErrorInternalError("查询实例在线时间失败")
+	}
+	
+	reply := &v1.GetInstanceUptimeReply{
+		StartTime:     uptime.StartTime,
+		EndTime:       uptime.EndTime,
+		OnlineSeconds: uptime.OnlineSeconds,
+		Uptime:        uptime.Uptime,
+		ConnectCount:  int64(uptime.ConnectCount),
+	}
+	for _, segment := range uptime.Timeline {
+		reply.Timeline = append(reply.Timeline, &v1.UptimeSegment{
+			Online:    segment.Online,
+			StartTime: segment.StartTime,
+			EndTime:   segment.EndTime,
+		})
+	}
+	
+	return reply, nil
+}
+
+func (commanderService *CommanderService) DecommissionInstance(ctx context.Context, req *v1.DecommissionInstanceRequest) (*v1.DecommissionInstanceReply, error) {
+	if req.OrgUuid == "" || req.GroupUuid == "" || req.InstanceName == "" {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	archived, err := commanderService.archiveUseCase.Decommission(ctx, req.OrgUuid, req.GroupUuid, req.InstanceName)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, v1.ErrorInstanceNotFound("实例不存在: %s", req.InstanceName)
+		}
+		
+		commanderService.logger.Error("下线实例失败", zap.String("instanceName", req.InstanceName), zap.Error(err))
+		return nil, v1.ErrorInternalError("下线实例失败")
+	}
+	
+	return &v1.DecommissionInstanceReply{ArchivedInstructs: archived}, nil
+}
+
+func (commanderService *CommanderService) IssueInstruct(ctx context.Context, req *v1.IssueInstructRequest) (*v1.IssueInstructReply, error) {
+	if req.OrgUuid == "" || req.GroupUuid == "" || req.InstanceName == "" || req.Type == 0 || req.Content == "" {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	wait := time.Duration(req.WaitSeconds) * time.Second
+	if wait < 0 || wait > biz.MaxInstructWait {
+		return nil, v1.ErrorInvalidArgument("等待时间异常: %d", req.WaitSeconds)
+	}
+	
+	alive := commanderService.instanceUseCase.GetInstanceAlive(ctx, req.OrgUuid, req.GroupUuid, req.InstanceName)
+	if !alive {
+		return nil, v1.ErrorInstanceOffline("目标实例不在线")
+	}
+	
+	if !commanderService.instanceUseCase.SupportInstruct(ctx, req.OrgUuid, req.GroupUuid, req.InstanceName, biz.InstructType(req.Type)) {
+		return nil, v1.ErrorUnsupportedInstructType("目标实例不支持该指令类型: %d", req.Type)
+	}
+	
+	var instructUuid string
+	var instruct biz.Instruct
+	var probe *biz.InstructResult
+	var err error
+	operator := middleware.OperatorFromContext(ctx)
+	if wait > 0 {
+		instruct, probe, err = commanderService.instructUseCase.IssueAndWait(ctx, req.OrgUuid, req.GroupUuid, req.InstanceName, biz.InstructType(req.Type), req.Content, operator, wait)
+		instructUuid = instruct.Uuid
+	} else {
+		instructUuid, err = commanderService.instructUseCase.IssueInstructions(ctx,
+			req.OrgUuid,
+			req.GroupUuid,
+			req.InstanceName,
+			biz.InstructType(req.Type),
+			req.Content,
+			operator)
+	}
+	
+	if err != nil {
+		if err == biz.ErrUnknownInstructType {
+			return nil, v1.ErrorUnknownInstructType("未知指令类型: %d", req.Type)
+		}
+		
+		if errors.Is(err, biz.ErrInvalidFileContent) || errors.Is(err, biz.ErrBlobNotFound) {
+			return nil, v1.ErrorInvalidArgument("%s", err.Error())
+		}
+		
+		if errors.Is(err, biz.ErrCommandDenied) {
+			return nil, v1.ErrorCommandDenied("%s", err.Error())
+		}
+		
+		if errors.Is(err, biz.ErrGroupDeleted) {
+			return nil, v1.ErrorGroupDeleted("%s", err.Error())
+		}
+		
+		// 调用方断开时停止等待，指令仍会执行，已下发时返回 uuid
+		if ctx.Err() != nil {
+			commanderService.logger.Info("调用方断开，停止等待指令结果", zap.String("uuid", instructUuid), zap.Error(err))
+			if instructUuid != "" {
+				return &v1.IssueInstructReply{Uuid: instructUuid}, nil
+			}
+			return nil, ctx.Err()
+		}
+		
+		commanderService.logger.Error("下发指令失败", zap.String("uuid", instructUuid), zap.Error(err))
+		return nil, v1.ErrorInternalError("系统异常")
+	}
+	
+	reply := &v1.IssueInstructReply{Uuid: instructUuid}
+	if wait > 0 {
+		reply.Instruct = toInstructReply(instruct)
+		if probe != nil {
+			reply.Result = toInstructResultReply(*probe)
+		}
+	}
+	return reply, nil
+}
+
+func (commanderService *CommanderService) ListInstruct(ctx context.Context, req *v1.ListInstructRequest) (*v1.ListInstructReply, error) {
+	if req.OrgUuid == "" || req.GroupUuid == "" || req.InstanceName == "" {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	instructs, err := commanderService.instructUseCase.ListInstruct(ctx, req.OrgUuid, req.GroupUuid, req.InstanceName)
+	if err != nil {
+		commanderService.logger.Error("列出指令失败", zap.Error(err))
+		return nil, v1.ErrorInternalError("列出指令失败")
+	}
+	
+	reply := &v1.ListInstructReply{}
+	for _, instruct := range instructs {
+		reply.Data = append(reply.Data, toInstructReply(instruct))
+	}
+	
+	return reply, nil
+}
+
+func (commanderService *CommanderService) QueryInstructs(ctx context.Context, req *v1.QueryInstructsRequest) (*v1.QueryInstructsReply, error) {
+	if req.Limit < 0 {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	result, err := biz.ParseInstructState(req.State)
+	if err != nil {
+		return nil, v1.ErrorInvalidArgument("%s", err.Error())
+	}
+	
+	query := biz.InstructQuery{
+		OrgUuid:      req.OrgUuid,
+		GroupUuid:    req.GroupUuid,
+		InstanceName: req.InstanceName,
+		Type:         biz.InstructType(req.Type),
+		Result:       result,
+		Operator:     req.Operator,
+		BatchUuid:    req.BatchUuid,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		Limit:        int(req.Limit),
+	}
+	
+	instructs, nextCursor, err := commanderService.instructUseCase.QueryInstructs(ctx, query, req.Cursor)
+	if err != nil {
+		if errors.Is(err, biz.ErrInvalidInstructQuery) {
+			return nil, v1.ErrorInvalidArgument("%s", err.Error())
+		}
+		
+		commanderService.logger.Error("查询指令失败", zap.Error(err))
+		return nil, v1.ErrorInternalError("查询指令失败")
+	}
+	
+	reply := &v1.QueryInstructsReply{Data: make([]*v1.Instruct, 0, len(instructs)), NextCursor: nextCursor}
+	for _, instruct := range instructs {
+		reply.Data = append(reply.Data, toInstructReply(instruct))
+	}
+	
+	return reply, nil
+}
+
+func (commanderService *CommanderService) IssueBatchInstruct(ctx context.Context, req *v1.IssueBatchInstructRequest) (*v1.IssueBatchInstructReply, error) {
+	selector, err := biz.ParseSelector(req.Selector)
+	if err != nil {
+		return nil, v1.ErrorInvalidArgument("%s", err.Error())
+	}
+	
+	target := biz.BatchTarget{
+		OrgUuid:       req.OrgUuid,
+		GroupUuid:     req.GroupUuid,
+		InstanceNames: req.InstanceNames,
+		Selector:      selector,
+	}
+	
+	batchUuid, instructs, err := commanderService.batchUseCase.IssueBatch(ctx, target, biz.InstructType(req.Type), req.Content, middleware.OperatorFromContext(ctx))
+	if err != nil {
+		if errors.Is(err, biz.ErrInvalidBatch) {
+			return nil, v1.ErrorInvalidArgument("%s", err.Error())
+		}
+		
+		if errors.Is(err, biz.ErrGroupDeleted) {
+			return nil, v1.ErrorGroupDeleted("%s", err.Error())
+		}
+		
+		commanderService.logger.Error("批量下发指令失败", zap.Error(err))
+		return nil, v1.ErrorInternalError("系统异常")
+	}
+	
+	reply := &v1.IssueBatchInstructReply{BatchUuid: batchUuid}
+	for _, instruct := range instructs {
+		batchInstruct := &v1.BatchInstruct{InstanceName: instruct.InstanceName, Uuid: instruct.Uuid}
+		if instruct.Err != nil {
+			batchInstruct.ErrMsg = instruct.Err.Error()
+		}
+		reply.Instructs = append(reply.Instructs, batchInstruct)
+	}
+	
+	return reply, nil
+}
+
+func (commanderService *CommanderService) CompareBatch(ctx context.Context, req *v1.CompareBatchRequest) (*v1.CompareBatchReply, error) {
+	if req.BatchUuid == "" {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	comparison, err := commanderService.batchUseCase.Compare(ctx, req.BatchUuid)
+	if err != nil {
+		if errors.Is(err, biz.ErrBatchNotFound) {
+			return nil, v1.ErrorBatchNotFound("批次不存在: %s", req.BatchUuid)
+		}
+		
+		commanderService.logger.Error("对比批次结果失败", zap.String("batchUuid", req.BatchUuid), zap.Error(err))
+		return nil, v1.ErrorInternalError("系统异常")
+	}
+	
+	reply := &v1.CompareBatchReply{
+		BatchUuid: comparison.BatchUuid,
+		Type:      int32(comparison.Type),
+		Target:    comparison.Target,
+		Total:     int32(comparison.Total),
+		Running:   int32(comparison.Running),
+		Success:   int32(comparison.Success),
+		Failed:    int32(comparison.Failed),
+		Deviated:  int32(comparison.Deviated),
+		Baseline: &v1.BatchBaseline{
+			StatusCode: comparison.Baseline.StatusCode,
+			Addrs:      comparison.Baseline.Addrs,
+			Latency:    comparison.Baseline.Latency,
+			MinLatency: comparison.Baseline.MinLatency,
+			MaxLatency: comparison.Baseline.MaxLatency,
+			PacketLoss: comparison.Baseline.PacketLoss,
+		},
+	}
+	
+	for _, instance := range comparison.Instances {
+		reply.Instances = append(reply.Instances, &v1.BatchInstanceResult{
+			InstructUuid: instance.InstructUuid,
+			InstanceUuid: instance.InstanceUuid,
+			InstanceName: instance.InstanceName,
+			Labels:       instance.Labels,
+			State:        instance.State,
+			StatusCode:   instance.StatusCode,
+			Latency:      instance.Latency,
+			PacketLoss:   instance.PacketLoss,
+			Addrs:        instance.Addrs,
+			ErrMsg:       instance.ErrMsg,
+			Deviations:   instance.Deviations,
+		})
+	}
+	
+	for _, group := range comparison.AddrGroups {
+		reply.AddrGroups = append(reply.AddrGroups, &v1.AddrGroup{Addrs: group.Addrs, Instances: group.Instances})
+	}
+	
+	for _, label := range comparison.Labels {
+		reply.Labels = append(reply.Labels, &v1.LabelDeviation{Key: label.Key, Value: label.Value, Total: int32(label.Total), Deviated: int32(label.Deviated)})
+	}
+	
+	return reply, nil
+}
+
+func (commanderService *CommanderService) QueryResults(ctx context.Context, req *v1.QueryResultsRequest) (*v1.QueryResultsReply, error) {
+	if req.Limit < 0 || req.State == biz.InstructRunning {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	result, err := biz.ParseInstructState(req.State)
+	if err != nil {
+		return nil, v1.ErrorInvalidArgument("%s", err.Error())
+	}
+	
+	query := biz.ResultQuery{
+		OrgUuid:      req.OrgUuid,
+		GroupUuid:    req.GroupUuid,
+		InstanceName: req.InstanceName,
+		BatchUuid:    req.BatchUuid,
+		Type:         biz.InstructType(req.Type),
+		Target:       req.Target,
+		Result:       result,
+		MinStatus:    req.MinStatus,
+		MaxStatus:    req.MaxStatus,
+		MinLatency:   req.MinLatency,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		Limit:        int(req.Limit),
+	}
+	
+	results, nextCursor, err := commanderService.resultUseCase.QueryResults(ctx, query, req.Cursor)
+	if err != nil {
+		if errors.Is(err, biz.ErrInvalidInstructQuery) {
+			return nil, v1.ErrorInvalidArgument("%s", err.Error())
+		}
+		
+		commanderService.logger.Error("查询探测结果失败", zap.Error(err))
+		return nil, v1.ErrorInternalError("查询探测结果失败")
+	}
+	
+	reply := &v1.QueryResultsReply{Data: make([]*v1.InstructResult, 0, len(results)), NextCursor: nextCursor}
+	for _, result := range results {
+		reply.Data = append(reply.Data, toInstructResultReply(result))
+	}
+	
+	return reply, nil
+}
+
+func (commanderService *CommanderService) GetInstruct(ctx context.Context, req *v1.GetInstructRequest) (*v1.GetInstructReply, error) {
+	if req.OrgUuid == "" || req.GroupUuid == "" || req.InstanceName == "" || req.Uuid == "" {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	instruct, err := commanderService.instructUseCase.GetInstruct(ctx, req.OrgUuid, req.GroupUuid, req.InstanceName, req.Uuid)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, v1.ErrorInstructNotFound("指令不存在: %s", req.Uuid)
+		}
+		
+		commanderService.logger.Error("获取指令失败", zap.String("uuid", req.Uuid), zap.Error(err))
+		return nil, v1.ErrorInternalError("获取指令失败")
+	}
+	
+	return &v1.GetInstructReply{Data: toInstructReply(instruct)}, nil
+}
+
+func (commanderService *CommanderService) CancelInstruct(ctx context.Context, req *v1.CancelInstructRequest) (*v1.CancelInstructReply, error) {
+	if req.OrgUuid == "" || req.GroupUuid == "" || req.InstanceName == "" || req.Uuid == "" {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	err := commanderService.instructUseCase.CancelInstruct(ctx, req.OrgUuid, req.GroupUuid, req.InstanceName, req.Uuid)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, v1.ErrorInstructNotFound("指令不存在: %s", req.Uuid)
+		}
+		
+		if errors.Is(err, biz.ErrInstructFinished) {
+			return nil, v1.ErrorInstructFinished("指令已完成: %s", req.Uuid)
+		}
+		
+		commanderService.logger.Error("取消指令失败", zap.String("uuid", req.Uuid), zap.Error(err))
+		return nil, v1.ErrorInternalError("取消指令失败")
+	}
+	
+	return &v1.CancelInstructReply{}, nil
+}
+
+func (commanderService *CommanderService) ListNodes(ctx context.Context, req *v1.ListNodesRequest) (*v1.ListNodesReply, error) {
+	nodes, err := commanderService.clusterUseCase.ListNodes(ctx)
+	if err != nil {
+		commanderService.logger.Error("列出节点失败", zap.Error(err))
+		return nil, v1.ErrorInternalError("列出节点失败")
+	}
+	
+	reply := &v1.ListNodesReply{Data: make([]*v1.Node, 0, len(nodes))}
+	for _, node := range nodes {
+		reply.Data = append(reply.Data, &v1.Node{
+			Id:          node.Id,
+			StartTime:   node.StartTime,
+			UpdateTime:  node.UpdateTime,
+			Connections: int64(node.Connections),
+			Draining:    node.Draining,
+		})
+	}
+	
+	return reply, nil
+}
+
+func toInstanceReply(instance biz.Instance) *v1.Instance {
+	reply := &v1.Instance{
+		Uuid:         instance.Uuid,
+		OrgUuid:      instance.OrgUuid,
+		GroupUuid:    instance.GroupUuid,
+		InstanceName: instance.InstanceName,
+		ClientIp:     instance.ClientIp,
+		CreateTime:   instance.CreateTime,
+		UpdateTime:   instance.UpdateTime,
+		Online:       instance.Online,
+		Labels:       instance.Labels,
+		Os:           instance.Os,
+		Platform:     instance.Platform,
+	}
+	
+	if instance.AgentInfo != nil {
+		reply.AgentVersion = instance.AgentInfo.Version
+		for _, capability := range instance.AgentInfo.Capabilities {
+			reply.Capabilities = append(reply.Capabilities, &v1.InstructCapability{
+				Type:          int32(capability.Type),
+				Timeout:       capability.Timeout,
+				MaxOutputSize: capability.MaxOutputSize,
+			})
+		}
+	}
+	
+	if instance.Inventory != nil {
+		reply.Inventory = toInventoryReply(*instance.Inventory)
+	}
+	
+	return reply
+}
+
+func toInventoryReply(inventory biz.HostInventory) *v1.HostInventory {
+	reply := &v1.HostInventory{
+		Hostname:        inventory.Hostname,
+		Os:              inventory.Os,
+		Platform:        inventory.Platform,
+		PlatformVersion: inventory.PlatformVersion,
+		KernelVersion:   inventory.KernelVersion,
+		Arch:            inventory.Arch,
+		CpuModel:        inventory.CpuModel,
+		CpuCount:        inventory.CpuCount,
+		MemoryTotal:     inventory.MemoryTotal,
+		BootTime:        inventory.BootTime,
+		Timezone:        inventory.Timezone,
+		CollectTime:     inventory.CollectTime,
+	}
+	
+	for _, d := range inventory.Disks {
+		reply.Disks = append(reply.Disks, &v1.DiskInfo{
+			Device:     d.Device,
+			Mountpoint: d.Mountpoint,
+			Fstype:     d.Fstype,
+			Total:      d.Total,
+			Used:       d.Used,
+		})
+	}
+	
+	for _, i := range inventory.Interfaces {
+		reply.Interfaces = append(reply.Interfaces, &v1.NetInterface{
+			Name:         i.Name,
+			HardwareAddr: i.HardwareAddr,
+			Addrs:        i.Addrs,
+			Mtu:          i.Mtu,
+		})
+	}
+	
+	return reply
+}
+
+func toMetricsReply(metrics biz.HostMetrics) *v1.HostMetrics {
+	reply := &v1.HostMetrics{
+		CollectTime:   metrics.CollectTime,
+		CpuPercent:    metrics.CpuPercent,
+		Load1:         metrics.Load1,
+		Load5:         metrics.Load5,
+		Load15:        metrics.Load15,
+		MemoryUsed:    metrics.MemoryUsed,
+		MemoryPercent: metrics.MemoryPercent,
+		SwapPercent:   metrics.SwapPercent,
+		DiskReadRate:  metrics.DiskReadRate,
+		DiskWriteRate: metrics.DiskWriteRate,
+		NetRecvRate:   metrics.NetRecvRate,
+		NetSentRate:   metrics.NetSentRate,
+		NetErrIn:      metrics.NetErrIn,
+		NetErrOut:     metrics.NetErrOut,
+		NetDropIn:     metrics.NetDropIn,
+		NetDropOut:    metrics.NetDropOut,
+	}
+	
+	for _, d := range metrics.Disks {
+		reply.Disks = append(reply.Disks, &v1.DiskUsage{
+			Mountpoint:  d.Mountpoint,
+			Total:       d.Total,
+			Used:        d.Used,
+			UsedPercent: d.UsedPercent,
+		})
+	}
+	
+	for _, p := range metrics.TopProcesses {
+		reply.TopProcesses = append(reply.TopProcesses, &v1.ProcessMetrics{
+			Pid:           p.Pid,
+			Name:          p.Name,
+			CpuPercent:    p.CpuPercent,
+			MemoryPercent: p.MemoryPercent,
+		})
+	}
+	
+	return reply
+}
+
+func toInstructResultReply(result biz.InstructResult) *v1.InstructResult {
+	return &v1.InstructResult{
+		InstructUuid:  result.InstructUuid,
+		InstanceUuid:  result.InstanceUuid,
+		OrgUuid:       result.OrgUuid,
+		GroupUuid:     result.GroupUuid,
+		InstanceName:  result.InstanceName,
+		Type:          int32(result.Type),
+		SchemaVersion: result.SchemaVersion,
+		Target:        result.Target,
+		Result:        result.Result,
+		StatusCode:    result.StatusCode,
+		Latency:       result.Latency,
+		PacketLoss:    result.PacketLoss,
+		Addrs:         result.Addrs,
+		Detail:        string(result.Detail),
+		ErrMsg:        result.ErrMsg,
+		CreateTime:    result.CreateTime,
+		BatchUuid:     result.BatchUuid,
+	}
+}
+
+func toInstructReply(instruct biz.Instruct) *v1.Instruct {
+	return &v1.Instruct{
+		Uuid:         instruct.Uuid,
+		OrgUuid:      instruct.OrgUuid,
+		GroupUuid:    instruct.GroupUuid,
+		InstanceName: instruct.InstanceName,
+		Type:         int32(instruct.Type),
+		Content:      instruct.Content,
+		Result:       instruct.Result,
+		Reply:        instruct.Reply,
+		CreateTime:   instruct.CreateTime,
+		UpdateTime:   instruct.UpdateTime,
+		Truncated:    instruct.Truncated,
+		Operator:     instruct.Operator,
+		BatchUuid:    instruct.BatchUuid,
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/go-kratos/kratos/v2/errors"
+	khttp "github.com/go-kratos/kratos/v2/transport/http"
+	v1 "github.com/qx66/camp/api/camp/v1"
+	"github.com/qx66/camp/pkg/middleware"
+	"google.golang.org/grpc/status"
+	"io"
+	"net"
+	"net/http"
+)
+
+// api/camp/v1 定义的接口由 protoc-gen-go-http 生成的 handler 处理，新增接口只需修改 proto 并执行 make api
+// lis 为 gin 所在 http.Server 的监听地址，kratos 仅用于计算 endpoint，不单独接收请求
+
+func NewCommanderHTTPServer(lis net.Listener, srv v1.CommanderHTTPServer) *khttp.Server {
+	server := khttp.NewServer(khttp.Listener(lis), khttp.Timeout(0), khttp.RequestDecoder(decodeCommanderRequest), khttp.ErrorEncoder(encodeCommanderError))
+	v1.RegisterCommanderHTTPServer(server, srv)
+	return server
+}
+
+type ginContextKey struct{}
+
+// gin 未匹配的请求交给生成的路由处理，Identity 中间件校验后的操作人写入请求 context
+
+func CommanderHTTPHandler(server *khttp.Server) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		operator, _ := middleware.GetOperator(c)
+		ctx := middleware.NewOperatorContext(c.Request.Context(), operator)
+		c.Request = c.Request.WithContext(context.WithValue(ctx, ginContextKey{}, c))
+		server.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// 请求体为空时不解析，未指定 Content-Type 时按 json 解析
+
+func decodeCommanderRequest(r *http.Request, v interface{}) error {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return errors.BadRequest("CODEC", err.Error())
+	}
+	
+	if len(data) == 0 {
+		return nil
+	}
+	
+	codec, _ := khttp.CodecForRequest(r, "Content-Type")
+	err = codec.Unmarshal(data, v)
+	if err != nil {
+		return errors.BadRequest("CODEC", err.Error())
+	}
+	return nil
+}
+
+// 生成的 handler 解析请求失败时返回 CODEC 错误 (请求体) 或普通 error (query 及 path 参数)，均按参数异常返回
+
+func encodeCommanderError(w http.ResponseWriter, r *http.Request, err error) {
+	c, ok := r.Context().Value(ginContextKey{}).(*gin.Context)
+	if !ok {
+		khttp.DefaultErrorEncoder(w, r, err)
+		return
+	}
+	
+	c.Set("error", err.Error())
+	if _, ok := status.FromError(err); !ok || errors.Reason(err) == "CODEC" {
+		err = v1.ErrorInvalidArgument("参数异常")
+	}
+	writeProtoError(c, err)
+}
+
+func writeProtoError(c *gin.Context, err error) {
+	e := errors.FromError(err)
+	c.JSON(int(e.Code), gin.H{"errCode": e.Code, "errMsg": e.Message, "reason": e.Reason})
+}
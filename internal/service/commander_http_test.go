@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	v1 "github.com/qx66/camp/api/camp/v1"
+	"github.com/stretchr/testify/require"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakeCommanderServer struct {
+	v1.UnimplementedCommanderServer
+	getInstruct   *v1.GetInstructRequest
+	issueInstruct *v1.IssueInstructRequest
+}
+
+func (fakeCommanderServer *fakeCommanderServer) GetInstruct(ctx context.Context, req *v1.GetInstructRequest) (*v1.GetInstructReply, error) {
+	fakeCommanderServer.getInstruct = req
+	if req.InstanceName == "offline" {
+		return nil, v1.ErrorInstanceOffline("目标实例不在线")
+	}
+	return &v1.GetInstructReply{Data: &v1.Instruct{Uuid: req.Uuid, Result: 1}}, nil
+}
+
+func (fakeCommanderServer *fakeCommanderServer) IssueInstruct(ctx context.Context, req *v1.IssueInstructRequest) (*v1.IssueInstructReply, error) {
+	fakeCommanderServer.issueInstruct = req
+	return &v1.IssueInstructReply{Uuid: "u"}, nil
+}
+
+func newTestCommanderHTTPServer(t *testing.T) (*gin.Engine, *fakeCommanderServer) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		lis.Close()
+	})
+	
+	gin.SetMode(gin.TestMode)
+	g := gin.New()
+	srv := &fakeCommanderServer{}
+	g.NoRoute(CommanderHTTPHandler(NewCommanderHTTPServer(lis, srv)))
+	return g, srv
+}
+
+// 路由由 protoc-gen-go-http 按 proto 中 google.api.http 的定义生成
+
+func TestCommanderHTTPHandler_Routes(t *testing.T) {
+	g, _ := newTestCommanderHTTPServer(t)
+	
+	for _, route := range []string{
+		"GET /v1/instance/alive",
+		"POST /v1/instruct/abc/cancel",
+		"PUT /v1/orgs/o/groups/g",
+		"GET /v1/batches/b/comparison",
+	} {
+		method, path, _ := strings.Cut(route, " ")
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader("{}")))
+		// 未实现的接口返回 gRPC 状态对应的错误
+		require.Equal(t, http.StatusNotImplemented, w.Code, route)
+	}
+	
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/missing", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCommanderHTTPHandler(t *testing.T) {
+	g, srv := newTestCommanderHTTPServer(t)
+	
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/instruct/abc?orgUuid=o&groupUuid=g&instanceName=i", nil))
+	require.Equal(t, 200, w.Code)
+	require.Equal(t, "o", srv.getInstruct.OrgUuid)
+	require.Equal(t, "g", srv.getInstruct.GroupUuid)
+	require.Equal(t, "i", srv.getInstruct.InstanceName)
+	require.Equal(t, "abc", srv.getInstruct.Uuid)
+	require.Contains(t, w.Body.String(), `"uuid":"abc"`)
+	
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/instruct/abc?orgUuid=o&groupUuid=g&instanceName=offline", nil))
+	require.Equal(t, 400, w.Code)
+	require.Contains(t, w.Body.String(), `"reason":"INSTANCE_OFFLINE"`)
+	
+	// query 参数类型错误
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/instructs?type=x", nil))
+	require.Equal(t, 400, w.Code)
+	require.Contains(t, w.Body.String(), `"reason":"INVALID_ARGUMENT"`)
+}
+
+func TestCommanderHTTPHandlerBody(t *testing.T) {
+	g, srv := newTestCommanderHTTPServer(t)
+	
+	body := `{"orgUuid":"o","groupUuid":"g","instanceName":"i","type":3,"content":"example.com","unknown":1}`
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/instruct", strings.NewReader(body)))
+	require.Equal(t, 200, w.Code)
+	require.Equal(t, int32(3), srv.issueInstruct.Type)
+	require.Equal(t, "example.com", srv.issueInstruct.Content)
+	
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/instruct", strings.NewReader("{")))
+	require.Equal(t, 400, w.Code)
+	require.Contains(t, w.Body.String(), `"reason":"INVALID_ARGUMENT"`)
+	
+	// 未定义 body 的接口忽略请求体
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/instruct/abc?orgUuid=o", strings.NewReader("{")))
+	require.Equal(t, 200, w.Code)
+	
+	// 请求体为空
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/instruct", nil))
+	require.Equal(t, 200, w.Code)
+}
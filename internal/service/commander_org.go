@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	v1 "github.com/qx66/camp/api/camp/v1"
+	"github.com/qx66/camp/internal/biz"
+	"go.uber.org/zap"
+)
+
+// 组织及分组管理接口
+
+func (commanderService *CommanderService) CreateOrg(ctx context.Context, req *v1.CreateOrgRequest) (*v1.CreateOrgReply, error) {
+	if req.Name == "" {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	org, err := commanderService.orgUseCase.CreateOrg(ctx, req.Uuid, req.Name, req.Description)
+	if err != nil {
+		return nil, commanderService.orgError("创建组织失败", err)
+	}
+	
+	return &v1.CreateOrgReply{Data: toOrgReply(org)}, nil
+}
+
+func (commanderService *CommanderService) ListOrgs(ctx context.Context, req *v1.ListOrgsRequest) (*v1.ListOrgsReply, error) {
+	if req.Offset < 0 || req.Limit < 0 {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	orgs, total, err := commanderService.orgUseCase.ListOrgs(ctx, int(req.Offset), int(req.Limit))
+	if err != nil {
+		return nil, commanderService.orgError("列出组织失败", err)
+	}
+	
+	reply := &v1.ListOrgsReply{Data: make([]*v1.Org, 0, len(orgs)), Total: total}
+	for _, org := range orgs {
+		reply.Data = append(reply.Data, toOrgReply(org))
+	}
+	
+	return reply, nil
+}
+
+func (commanderService *CommanderService) GetOrg(ctx context.Context, req *v1.GetOrgRequest) (*v1.GetOrgReply, error) {
+	if req.OrgUuid == "" {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	org, err := commanderService.orgUseCase.GetOrg(ctx, req.OrgUuid)
+	if err != nil {
+		return nil, commanderService.orgError("获取组织失败", err)
+	}
+	
+	return &v1.GetOrgReply{Data: toOrgReply(org)}, nil
+}
+
+func (commanderService *CommanderService) UpdateOrg(ctx context.Context, req *v1.UpdateOrgRequest) (*v1.UpdateOrgReply, error) {
+	if req.OrgUuid == "" || req.Name == "" {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	org, err := commanderService.orgUseCase.UpdateOrg(ctx, req.OrgUuid, req.Name, req.Description)
+	if err != nil {
+		return nil, commanderService.orgError("修改组织失败", err)
+	}
+	
+	return &v1.UpdateOrgReply{Data: toOrgReply(org)}, nil
+}
+
+func (commanderService *CommanderService) DeleteOrg(ctx context.Context, req *v1.DeleteOrgRequest) (*v1.DeleteOrgReply, error) {
+	if req.OrgUuid == "" {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	err := commanderService.orgUseCase.DeleteOrg(ctx, req.OrgUuid)
+	if err != nil {
+		return nil, commanderService.orgError("删除组织失败", err)
+	}
+	
+	return &v1.DeleteOrgReply{}, nil
+}
+
+func (commanderService *CommanderService) CreateGroup(ctx context.Context, req *v1.CreateGroupRequest) (*v1.CreateGroupReply, error) {
+	if req.OrgUuid == "" || req.Name == "" {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	group, err := commanderService.orgUseCase.CreateGroup(ctx, biz.Group{
+		OrgUuid:     req.OrgUuid,
+		Uuid:        req.Uuid,
+		Name:        req.Name,
+		Description: req.Description,
+		Settings:    fromGroupSettingsRequest(req.Settings),
+	})
+	if err != nil {
+		return nil, commanderService.orgError("创建分组失败", err)
+	}
+	
+	return &v1.CreateGroupReply{Data: toGroupReply(group)}, nil
+}
+
+func (commanderService *CommanderService) ListGroups(ctx context.Context, req *v1.ListGroupsRequest) (*v1.ListGroupsReply, error) {
+	if req.OrgUuid == "" || req.Offset < 0 || req.Limit < 0 {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	groups, total, err := commanderService.orgUseCase.ListGroups(ctx, req.OrgUuid, int(req.Offset), int(req.Limit))
+	if err != nil {
+		return nil, commanderService.orgError("列出分组失败", err)
+	}
+	
+	reply := &v1.ListGroupsReply{Data: make([]*v1.Group, 0, len(groups)), Total: total}
+	for _, group := range groups {
+		reply.Data = append(reply.Data, toGroupReply(group))
+	}
+	
+	return reply, nil
+}
+
+func (commanderService *CommanderService) GetGroup(ctx context.Context, req *v1.GetGroupRequest) (*v1.GetGroupReply, error) {
+	if req.OrgUuid == "" || req.GroupUuid == "" {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	group, err := commanderService.orgUseCase.GetGroup(ctx, req.OrgUuid, req.GroupUuid)
+	if err != nil {
+		return nil, commanderService.orgError("获取分组失败", err)
+	}
+	
+	return &v1.GetGroupReply{Data: toGroupReply(group)}, nil
+}
+
+func (commanderService *CommanderService) UpdateGroup(ctx context.Context, req *v1.UpdateGroupRequest) (*v1.UpdateGroupReply, error) {
+	if req.OrgUuid == "" || req.GroupUuid == "" || req.Name == "" {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	var settings *biz.GroupSettings
+	if req.Settings != nil {
+		s := fromGroupSettingsRequest(req.Settings)
+		settings = &s
+	}
+	
+	group, err := commanderService.orgUseCase.UpdateGroup(ctx, req.OrgUuid, req.GroupUuid, req.Name, req.Description, settings)
+	if err != nil {
+		return nil, commanderService.orgError("修改分组失败", err)
+	}
+	
+	return &v1.UpdateGroupReply{Data: toGroupReply(group)}, nil
+}
+
+func (commanderService *CommanderService) DeleteGroup(ctx context.Context, req *v1.DeleteGroupRequest) (*v1.DeleteGroupReply, error) {
+	if req.OrgUuid == "" || req.GroupUuid == "" {
+		return nil, v1.ErrorInvalidArgument("参数异常")
+	}
+	
+	err := commanderService.orgUseCase.DeleteGroup(ctx, req.OrgUuid, req.GroupUuid)
+	if err != nil {
+		return nil, commanderService.orgError("删除分组失败", err)
+	}
+	
+	return &v1.DeleteGroupReply{}, nil
+}
+
+// 组织及分组接口的错误转换，未知错误记录日志后以 msg 返回
+
+func (commanderService *CommanderService) orgError(msg string, err error) error {
+	switch {
+	case errors.Is(err, biz.ErrOrgNotFound):
+		return v1.ErrorOrgNotFound("组织不存在")
+	case errors.Is(err, biz.ErrGroupNotFound):
+		return v1.ErrorGroupNotFound("分组不存在")
+	case errors.Is(err, biz.ErrOrgExists), errors.Is(err, biz.ErrGroupExists):
+		return v1.ErrorAlreadyExists("%s", err.Error())
+	case errors.Is(err, biz.ErrGroupDeleted):
+		return v1.ErrorGroupDeleted("%s", err.Error())
+	case errors.Is(err, biz.ErrOrgDeleted):
+		return v1.ErrorOrgDeleted("%s", err.Error())
+	case errors.Is(err, biz.ErrInvalidGroupSettings):
+		return v1.ErrorInvalidArgument("%s", err.Error())
+	}
+	
+	commanderService.logger.Error(msg, zap.Error(err))
+	return v1.ErrorInternalError("%s", msg)
+}
+
+func toOrgReply(org biz.Org) *v1.Org {
+	return &v1.Org{
+		Uuid:        org.Uuid,
+		Name:        org.Name,
+		Description: org.Description,
+		Status:      org.Status,
+		CreateTime:  org.CreateTime,
+		UpdateTime:  org.UpdateTime,
+	}
+}
+
+func toGroupReply(group biz.Group) *v1.Group {
+	return &v1.Group{
+		OrgUuid:     group.OrgUuid,
+		Uuid:        group.Uuid,
+		Name:        group.Name,
+		Description: group.Description,
+		Settings: &v1.GroupSettings{
+			InstructTimeout: group.Settings.InstructTimeout,
+			IcmpCount:       group.Settings.IcmpCount,
+			CommandPolicy: &v1.CommandPolicy{
+				Allow: group.Settings.CommandPolicy.Allow,
+				Deny:  group.Settings.CommandPolicy.Deny,
+			},
+		},
+		Status:     group.Status,
+		CreateTime: group.CreateTime,
+		UpdateTime: group.UpdateTime,
+	}
+}
+
+func fromGroupSettingsRequest(settings *v1.GroupSettings) biz.GroupSettings {
+	return biz.GroupSettings{
+		InstructTimeout: settings.GetInstructTimeout(),
+		IcmpCount:       settings.GetIcmpCount(),
+		CommandPolicy: biz.CommandPolicy{
+			Allow: settings.GetCommandPolicy().GetAllow(),
+			Deny:  settings.GetCommandPolicy().GetDeny(),
+		},
+	}
+}
@@ -2,16 +2,23 @@ package service
 
 import (
 	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/websocket"
+	"github.com/qx66/camp/internal/biz"
 	"github.com/qx66/camp/pkg/middleware"
+	"github.com/qx66/camp/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"net/http"
 	"time"
 )
 
 var upgrader = websocket.Upgrader{
+	Subprotocols: biz.Subprotocols,
 	CheckOrigin: func(r *http.Request) bool {
 		return true
 	},
@@ -21,6 +28,7 @@ type ConnectReq struct {
 	OrgUuid      string `json:"orgUuid,omitempty" form:"orgUuid" validate:"required"`
 	GroupUuid    string `json:"groupUuid,omitempty" form:"groupUuid" validate:"required"`
 	InstanceName string `json:"instanceName,omitempty" form:"instanceName" validate:"required"`
+	Labels       string `json:"labels,omitempty" form:"labels"` // soldier 上报的标签 k1=v1,k2=v2
 }
 
 func (useCase *UseCase) Connect(c *gin.Context) {
@@ -44,8 +52,76 @@ func (useCase *UseCase) Connect(c *gin.Context) {
 		return
 	}
 	
-	// 2. 协议升级
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	// 节点下线期间不再接受新连接，soldier 重连到其他节点
+	if useCase.clusterUseCase.Draining() {
+		c.JSON(503, gin.H{"errCode": 503, "errMsg": "Service Unavailable"})
+		return
+	}
+	
+	// 组织及分组不存在时创建，已删除时拒绝连接
+	err = useCase.orgUseCase.Join(c.Request.Context(), req.OrgUuid, req.GroupUuid)
+	if err != nil {
+		c.Set("error", err.Error())
+		if errors.Is(err, biz.ErrGroupDeleted) {
+			c.JSON(403, gin.H{"errCode": 403, "errMsg": "Forbidden"})
+			return
+		}
+		c.JSON(500, gin.H{"errCode": 500, "errMsg": "Internal Server Error"})
+		return
+	}
+	
+	// 2. 注册实例
+	instanceUuid, err := useCase.instanceUseCase.Register(c.Request.Context(), req.OrgUuid, req.GroupUuid, req.InstanceName, clientIp)
+	if err != nil {
+		c.Set("error", err.Error())
+		c.JSON(500, gin.H{"errCode": 500, "errMsg": "Internal Server Error"})
+		return
+	}
+	
+	// 记录 soldier 上报的标签，格式异常时忽略
+	labels, err := biz.ParseLabels(req.Labels)
+	if err != nil {
+		useCase.logger.Warn("soldier上报的标签格式异常", zap.String("instanceName", req.InstanceName), zap.Error(err))
+	} else {
+		err = useCase.instanceUseCase.SetAgentLabels(c.Request.Context(), instanceUuid, labels)
+		if err != nil {
+			useCase.logger.Error("记录实例标签失败", zap.String("instanceName", req.InstanceName), zap.Error(err))
+		}
+	}
+	
+	// 恢复或创建会话
+	instance := biz.Instance{
+		Uuid:         instanceUuid,
+		OrgUuid:      req.OrgUuid,
+		GroupUuid:    req.GroupUuid,
+		InstanceName: req.InstanceName,
+		ClientIp:     clientIp,
+		AgentVersion: c.GetHeader(biz.AgentVersionHeader),
+	}
+	session, err := useCase.sessionUseCase.Open(c.Request.Context(), instance, c.GetHeader(biz.SessionHeader))
+	if err != nil {
+		c.Set("error", err.Error())
+		c.JSON(500, gin.H{"errCode": 500, "errMsg": "Internal Server Error"})
+		return
+	}
+	
+	// 3. 协议升级，soldier 声明支持逻辑通道窗口时在响应头中确认，并通告本端的消息大小限制、心跳间隔及会话 token
+	mux := c.GetHeader(biz.MuxHeader) == "1"
+	responseHeader := http.Header{}
+	if mux {
+		responseHeader.Set(biz.MuxHeader, "1")
+	}
+	responseHeader.Set(biz.SessionHeader, session.Token)
+	if session.Resumed {
+		responseHeader.Set(biz.SessionResumedHeader, "1")
+	}
+	useCase.framePolicy.SetHeader(responseHeader)
+	useCase.heartbeatPolicy.SetHeader(responseHeader)
+	
+	connectUpgrader := upgrader
+	connectUpgrader.EnableCompression = useCase.framePolicy.Compression
+	
+	conn, err := connectUpgrader.Upgrade(c.Writer, c.Request, responseHeader)
 	if err != nil {
 		c.Set("error", err.Error())
 		c.JSON(500, gin.H{"errCode": 500, "errMsg": "Internal Server Error"})
@@ -53,6 +129,15 @@ func (useCase *UseCase) Connect(c *gin.Context) {
 	}
 	defer conn.Close()
 	
+	// 按 soldier 通告的大小分片发送，soldier 支持分片时仅接收不超过本端分片大小的消息
+	frameWriter := biz.NewServiceFrameWriter(useCase.framePolicy)
+	frameWriter.SetPeer(c.Request.Header)
+	conn.SetReadLimit(useCase.framePolicy.ReadLimit(frameWriter.PeerFrameSize()))
+	
+	// 超过读超时未收到 pong 或任何消息时视为连接已断开
+	readTimeout := useCase.heartbeatPolicy.Timeout()
+	biz.WatchReadDeadline(conn, readTimeout)
+	
 	conn.SetPingHandler(func(appData string) error {
 		err := conn.WriteControl(websocket.PongMessage, []byte(appData), time.Now().Add(1*time.Second))
 		if err != nil {
@@ -71,57 +156,101 @@ func (useCase *UseCase) Connect(c *gin.Context) {
 		zap.String("orgUuid", req.OrgUuid),
 		zap.String("groupUuid", req.GroupUuid),
 		zap.String("instanceName", req.InstanceName),
+		zap.String("subprotocol", conn.Subprotocol()),
+		zap.Bool("mux", mux),
+		zap.Int("peerFrameSize", frameWriter.PeerFrameSize()),
+		zap.String("session", session.Token),
+		zap.Bool("resumed", session.Resumed),
 	)
 	
-	// message channel
-	receiveMsgChannel := make(chan string)
-	sendMsgChannel := make(chan string, 10)
-	done := make(chan struct{})
+	// message channel，按逻辑通道划分的接收及发送队列
+	receiveChannels := biz.NewClientChannels()
+	serviceChannels := biz.NewServiceChannels()
+	if mux {
+		serviceChannels.EnableWindow()
+	}
+	done := make(chan error, 1)
+	pingFailed := make(chan struct{})
+	writeFailed := make(chan struct{})
+	
+	// 登记连接，交互式终端及端口转发通过该连接转发
+	useCase.connRegistry.Attach(instanceUuid, serviceChannels)
+	
+	// 记录在线状态及连接记录，断开时记录断开原因，实例未在其他连接上线则立即标记离线
+	presence := useCase.presenceUseCase.Connect(ctx, instance)
+	disconnectReason := biz.DisconnectShutdown
+	defer func() {
+		useCase.connRegistry.Detach(instanceUuid, serviceChannels)
+		useCase.presenceUseCase.Disconnect(context.Background(), presence, instance, disconnectReason)
+	}()
 	
 	//
 	// 4.1 接收消息
-	go useCase.messageUseCase.ReceiveMessage(ctx, conn, receiveMsgChannel, done)
-	// 4.3 处理消息
-	go useCase.messageUseCase.ProcessClientMessage(ctx, receiveMsgChannel)
+	go useCase.messageUseCase.ReceiveMessage(ctx, conn, biz.NewFrameReader(useCase.framePolicy), readTimeout, receiveChannels, serviceChannels, done)
+	// 4.3 处理消息，每个逻辑通道独立处理
+	for _, channel := range []biz.Channel{biz.ControlChannel, biz.InstructChannel, biz.TelemetryChannel, biz.BulkChannel} {
+		go useCase.messageUseCase.ProcessClientMessage(ctx, session, channel, receiveChannels.Queue(channel), serviceChannels)
+	}
 	
-	go useCase.instanceUseCase.UpdateTime(ctx, req.OrgUuid, req.GroupUuid, req.InstanceName, clientIp)
+	go useCase.presenceUseCase.KeepAlive(ctx, presence)
+	go useCase.sessionUseCase.KeepAlive(ctx, session)
 	
-	// 4.2 定时心跳
-	// go useCase.messageUseCase.HeartBeat(ctx, sendMsgChannel)
+	// 4.2 定时心跳，发送失败时关闭连接
+	go func() {
+		err := useCase.heartbeatPolicy.Ping(ctx, conn)
+		if err != nil {
+			useCase.logger.Error("发送心跳失败", zap.String("instanceName", req.InstanceName), zap.Error(err))
+			close(pingFailed)
+		}
+	}()
 	
 	// 4.4 将指令消息发送到消息通道
-	go useCase.instructUseCase.ReceiveInstructions(ctx, req.OrgUuid, req.GroupUuid, req.InstanceName, sendMsgChannel)
+	// 恢复的会话先重新下发未确认的指令
+	if session.Resumed {
+		go useCase.instructUseCase.Redeliver(ctx, session, serviceChannels)
+	}
+	go useCase.instructUseCase.ReceiveInstructions(ctx, req.OrgUuid, req.GroupUuid, req.InstanceName, session, serviceChannels)
 	
-	// 4.5 从 sendMsgChannel 中获取数据并发送
+	// 4.5 按通道优先级从 serviceChannels 中获取数据并发送，发送失败时立即关闭连接，不再接收新的指令
 	go func() {
 		for {
-			select {
-			case m, ok := <-sendMsgChannel:
-				if !ok {
-					useCase.logger.Info("获取发送通道异常，通道已关闭",
-						zap.String("orgUuid", req.OrgUuid),
-						zap.String("groupUuid", req.GroupUuid),
-						zap.String("instanceName", req.InstanceName),
-					)
-					return
-				}
-				
-				err = conn.WriteMessage(websocket.TextMessage, []byte(m))
-				if err != nil {
-					useCase.logger.Error("发送消息给客户端失败", zap.Error(err))
-				} else {
-					useCase.logger.Info("发送消息给客户端",
-						zap.String("orgUuid", req.OrgUuid),
-						zap.String("groupUuid", req.GroupUuid),
-						zap.String("instanceName", req.InstanceName),
-						zap.String("message", m))
-				}
-			
-			case <-ctx.Done():
+			m, ok := serviceChannels.Next(ctx.Done())
+			if !ok {
 				useCase.logger.Info("websocket is closed")
 				cancel()
 				return
 			}
+			
+			_, span := tracing.Tracer().Start(tracing.Extract(ctx, m.Trace), "WebSocketDelivery",
+				trace.WithSpanKind(trace.SpanKindProducer),
+				trace.WithAttributes(attribute.String("instanceName", req.InstanceName)),
+			)
+			
+			messageType, b, err := biz.EncodeServiceMessage(conn.Subprotocol(), m)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				useCase.logger.Error("序列化发送消息失败", zap.Error(err))
+				span.End()
+				continue
+			}
+			
+			err = frameWriter.WriteMessage(conn, messageType, b)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				span.End()
+				useCase.logger.Error("发送消息给客户端失败", zap.String("instanceName", req.InstanceName), zap.Error(err))
+				close(writeFailed)
+				return
+			}
+			
+			useCase.logger.Info("发送消息给客户端", append([]zap.Field{
+				zap.String("orgUuid", req.OrgUuid),
+				zap.String("groupUuid", req.GroupUuid),
+				zap.String("instanceName", req.InstanceName),
+			}, m.LogFields()...)...)
+			span.End()
 		}
 	}()
 	
@@ -131,8 +260,30 @@ func (useCase *UseCase) Connect(c *gin.Context) {
 		useCase.logger.Info("websocket is closed")
 		cancel()
 		return
-	case <-done:
+	case err := <-done:
 		useCase.logger.Info("websocket is closed")
+		disconnectReason = biz.DisconnectReason(err)
+		cancel()
+		return
+	case <-pingFailed:
+		disconnectReason = biz.DisconnectPingFailed
+		cancel()
+		return
+	case <-writeFailed:
+		disconnectReason = biz.DisconnectWriteError
+		cancel()
+		return
+	case <-serviceChannels.Draining():
+		// 节点下线，soldier 收到 CloseServiceRestart 后立即重连到其他节点并恢复会话
+		useCase.logger.Info("节点下线，关闭连接", zap.String("instanceName", req.InstanceName))
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseServiceRestart, "draining"), time.Now().Add(1*time.Second))
+		disconnectReason = biz.DisconnectDraining
+		cancel()
+		return
+	case <-serviceChannels.Removed():
+		useCase.logger.Info("实例所属组织或分组已删除，关闭连接", zap.String("instanceName", req.InstanceName))
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "removed"), time.Now().Add(1*time.Second))
+		disconnectReason = biz.DisconnectRemoved
 		cancel()
 		return
 	}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	v1 "github.com/qx66/camp/api/camp/v1"
+	"github.com/qx66/camp/internal/biz"
+	"net/http"
+	"time"
+)
+
+// 指令事件订阅，以 SSE (text/event-stream) 推送，event 为事件类型，data 为事件 JSON；
+// 连接期间每 eventHeartbeatInterval 发送注释行保持连接
+
+const eventHeartbeatInterval = 15 * time.Second
+
+type EventsReq struct {
+	OrgUuid      string   `form:"orgUuid" validate:"required"`
+	GroupUuid    string   `form:"groupUuid"`
+	InstanceName string   `form:"instanceName"`
+	BatchUuid    string   `form:"batchUuid"`
+	InstructUuid string   `form:"instructUuid"`
+	Types        []string `form:"type"`
+}
+
+func (useCase *UseCase) Events(c *gin.Context) {
+	req := &EventsReq{}
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		writeProtoError(c, v1.ErrorInvalidArgument("参数异常"))
+		return
+	}
+	
+	validate := validator.New()
+	err = validate.Struct(req)
+	if err != nil {
+		writeProtoError(c, v1.ErrorInvalidArgument("参数异常"))
+		return
+	}
+	
+	events, cancel := useCase.eventUseCase.Subscribe(biz.EventFilter{
+		OrgUuid:      req.OrgUuid,
+		GroupUuid:    req.GroupUuid,
+		InstanceName: req.InstanceName,
+		BatchUuid:    req.BatchUuid,
+		InstructUuid: req.InstructUuid,
+		Types:        req.Types,
+	})
+	defer cancel()
+	
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+	
+	ticker := time.NewTicker(eventHeartbeatInterval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case event := <-events:
+			c.SSEvent(event.Type, event)
+			c.Writer.Flush()
+		
+		case <-ticker.C:
+			_, err = c.Writer.WriteString(": ping\n\n")
+			if err != nil {
+				return
+			}
+			c.Writer.Flush()
+		
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
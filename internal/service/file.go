@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	v1 "github.com/qx66/camp/api/camp/v1"
+	"github.com/qx66/camp/internal/biz"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"io"
+	"strconv"
+)
+
+// 上传待下发的文件，表单字段 file，返回的 uuid 用于文件下发指令 content 中的 blobUuid
+
+func (useCase *UseCase) UploadBlob(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		writeProtoError(c, v1.ErrorInvalidArgument("参数异常"))
+		return
+	}
+	
+	file, err := fileHeader.Open()
+	if err != nil {
+		writeProtoError(c, v1.ErrorInvalidArgument("参数异常"))
+		return
+	}
+	defer file.Close()
+	
+	blob, err := useCase.fileUseCase.Upload(c.Request.Context(), file)
+	if err != nil {
+		c.Set("error", err.Error())
+		if errors.Is(err, biz.ErrBlobTooLarge) {
+			writeProtoError(c, v1.ErrorInvalidArgument("%s", err.Error()))
+			return
+		}
+		
+		useCase.logger.Error("保存上传文件失败", zap.Error(err))
+		writeProtoError(c, v1.ErrorInternalError("系统异常"))
+		return
+	}
+	
+	c.JSON(200, gin.H{"uuid": blob.Uuid, "size": blob.Size, "sha256": blob.Sha256})
+}
+
+// 下载文件获取指令获取到的文件
+
+type DownloadFileReq struct {
+	OrgUuid      string `form:"orgUuid" validate:"required"`
+	GroupUuid    string `form:"groupUuid" validate:"required"`
+	InstanceName string `form:"instanceName" validate:"required"`
+}
+
+func (useCase *UseCase) DownloadFile(c *gin.Context) {
+	req := &DownloadFileReq{}
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		writeProtoError(c, v1.ErrorInvalidArgument("参数异常"))
+		return
+	}
+	
+	validate := validator.New()
+	err = validate.Struct(req)
+	if err != nil {
+		writeProtoError(c, v1.ErrorInvalidArgument("参数异常"))
+		return
+	}
+	
+	instructUuid := c.Param("uuid")
+	
+	rc, blob, name, err := useCase.fileUseCase.Download(c.Request.Context(), req.OrgUuid, req.GroupUuid, req.InstanceName, instructUuid)
+	if err != nil {
+		c.Set("error", err.Error())
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			writeProtoError(c, v1.ErrorInstructNotFound("指令不存在: %s", instructUuid))
+		case errors.Is(err, biz.ErrFileNotReady), errors.Is(err, biz.ErrBlobNotFound):
+			writeProtoError(c, v1.ErrorInvalidArgument("%s", err.Error()))
+		default:
+			useCase.logger.Error("读取文件失败", zap.String("uuid", instructUuid), zap.Error(err))
+			writeProtoError(c, v1.ErrorInternalError("系统异常"))
+		}
+		return
+	}
+	defer rc.Close()
+	
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	c.Header("Content-Length", strconv.FormatInt(blob.Size, 10))
+	c.Header("X-Content-Sha256", blob.Sha256)
+	c.Status(200)
+	
+	_, err = io.Copy(c.Writer, rc)
+	if err != nil {
+		useCase.logger.Error("发送文件失败", zap.String("uuid", instructUuid), zap.Error(err))
+	}
+}
@@ -1,9 +1,14 @@
 package service
 
 import (
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/qx66/camp/internal/biz"
+	"github.com/qx66/camp/pkg/middleware"
+	"go.uber.org/zap"
+	"time"
 )
 
 type InstructReq struct {
@@ -12,6 +17,7 @@ type InstructReq struct {
 	InstanceName string `json:"instanceName,omitempty" form:"instanceName" validate:"required"`
 	Type         int32  `json:"type,omitempty" form:"type" validate:"required"`
 	Content      string `json:"content,omitempty" form:"content" validate:"required"`
+	Wait         int32  `json:"wait,omitempty" form:"wait"` // 等待指令结束的秒数，为 0 时下发后立即返回
 }
 
 func (useCase *UseCase) Instruct(c *gin.Context) {
@@ -37,20 +43,71 @@ func (useCase *UseCase) Instruct(c *gin.Context) {
 		return
 	}
 	
+	if !useCase.instanceUseCase.SupportInstruct(c.Request.Context(), req.OrgUuid, req.GroupUuid, req.InstanceName, biz.InstructType(req.Type)) {
+		c.JSON(400, gin.H{"errCode": 400, "errMsg": fmt.Sprintf("目标实例不支持该指令类型: %d", req.Type)})
+		return
+	}
+	
+	wait := time.Duration(req.Wait) * time.Second
+	if wait < 0 || wait > biz.MaxInstructWait {
+		c.JSON(400, gin.H{"errCode": 400, "errMsg": "等待时间异常"})
+		return
+	}
+	
 	//
-	instructUuid, err := useCase.instructUseCase.IssueInstructions(
-		c.Request.Context(),
-		req.OrgUuid,
-		req.GroupUuid,
-		req.InstanceName,
-		biz.InstructType(req.Type),
-		req.Content)
+	operator, _ := middleware.GetOperator(c)
+	var instructUuid string
+	var instruct biz.Instruct
+	var probe *biz.InstructResult
+	if wait > 0 {
+		instruct, probe, err = useCase.instructUseCase.IssueAndWait(c.Request.Context(), req.OrgUuid, req.GroupUuid, req.InstanceName, biz.InstructType(req.Type), req.Content, operator, wait)
+		instructUuid = instruct.Uuid
+	} else {
+		instructUuid, err = useCase.instructUseCase.IssueInstructions(
+			c.Request.Context(),
+			req.OrgUuid,
+			req.GroupUuid,
+			req.InstanceName,
+			biz.InstructType(req.Type),
+			req.Content,
+			operator)
+	}
+	
+	if errors.Is(err, biz.ErrInvalidFileContent) || errors.Is(err, biz.ErrBlobNotFound) {
+		c.JSON(400, gin.H{"errCode": 400, "errMsg": err.Error()})
+		return
+	}
+	
+	if errors.Is(err, biz.ErrCommandDenied) {
+		c.JSON(403, gin.H{"errCode": 403, "errMsg": err.Error()})
+		return
+	}
+	
+	if errors.Is(err, biz.ErrGroupDeleted) {
+		c.JSON(409, gin.H{"errCode": 409, "errMsg": err.Error()})
+		return
+	}
+	
+	// 调用方断开时停止等待，指令仍会执行，已下发时返回 uuid
+	if err != nil && c.Request.Context().Err() != nil {
+		useCase.logger.Info("调用方断开，停止等待指令结果", zap.String("uuid", instructUuid), zap.Error(err))
+		if instructUuid != "" {
+			c.JSON(200, gin.H{"errCode": 0, "errMsg": "ok", "uuid": instructUuid})
+		}
+		return
+	}
 	
 	if err != nil {
 		c.JSON(500, gin.H{"errCode": 500, "errMsg": "系统异常"})
 		return
 	}
 	
+	// instruct.result 为 0 时等待超时，指令仍在执行
+	if wait > 0 {
+		c.JSON(200, gin.H{"errCode": 0, "errMsg": "ok", "uuid": instructUuid, "instruct": instruct, "result": probe})
+		return
+	}
+	
 	c.JSON(200, gin.H{"errCode": 0, "errMsg": "ok", "uuid": instructUuid})
 	return
 }
@@ -6,20 +6,42 @@ import (
 	"go.uber.org/zap"
 )
 
-var ProviderSet = wire.NewSet(NewUseCase)
+var ProviderSet = wire.NewSet(NewUseCase, NewCommanderService)
 
 type UseCase struct {
 	messageUseCase  *biz.MessageUseCase
 	instructUseCase *biz.InstructUseCase
 	instanceUseCase *biz.InstanceUseCase
+	fileUseCase     *biz.FileUseCase
+	connRegistry    *biz.ConnRegistry
+	shellUseCase    *biz.ShellUseCase
+	tunnelUseCase   *biz.TunnelUseCase
+	sessionUseCase  *biz.SessionUseCase
+	presenceUseCase *biz.PresenceUseCase
+	clusterUseCase  *biz.ClusterUseCase
+	orgUseCase      *biz.OrgUseCase
+	eventUseCase    *biz.EventUseCase
+	framePolicy     *biz.FramePolicy
+	heartbeatPolicy *biz.HeartbeatPolicy
 	logger          *zap.Logger
 }
 
-func NewUseCase(logger *zap.Logger, messageUseCase *biz.MessageUseCase, instructUseCase *biz.InstructUseCase, instanceUseCase *biz.InstanceUseCase) *UseCase {
+func NewUseCase(logger *zap.Logger, messageUseCase *biz.MessageUseCase, instructUseCase *biz.InstructUseCase, instanceUseCase *biz.InstanceUseCase, fileUseCase *biz.FileUseCase, connRegistry *biz.ConnRegistry, shellUseCase *biz.ShellUseCase, tunnelUseCase *biz.TunnelUseCase, sessionUseCase *biz.SessionUseCase, presenceUseCase *biz.PresenceUseCase, clusterUseCase *biz.ClusterUseCase, orgUseCase *biz.OrgUseCase, eventUseCase *biz.EventUseCase, framePolicy *biz.FramePolicy, heartbeatPolicy *biz.HeartbeatPolicy) *UseCase {
 	return &UseCase{
 		messageUseCase:  messageUseCase,
 		instructUseCase: instructUseCase,
 		instanceUseCase: instanceUseCase,
+		fileUseCase:     fileUseCase,
+		connRegistry:    connRegistry,
+		shellUseCase:    shellUseCase,
+		tunnelUseCase:   tunnelUseCase,
+		sessionUseCase:  sessionUseCase,
+		presenceUseCase: presenceUseCase,
+		clusterUseCase:  clusterUseCase,
+		orgUseCase:      orgUseCase,
+		eventUseCase:    eventUseCase,
+		framePolicy:     framePolicy,
+		heartbeatPolicy: heartbeatPolicy,
 		logger:          logger,
 	}
 }
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/websocket"
+	v1 "github.com/qx66/camp/api/camp/v1"
+	"github.com/qx66/camp/internal/biz"
+	"github.com/qx66/camp/pkg/middleware"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"time"
+)
+
+// 交互式终端，操作端 (浏览器 xterm.js / CLI) 通过 websocket 连接
+//
+// 操作端 -> commander: binary frame 为终端输入，text frame 为控制消息，如 {"type":"resize","cols":120,"rows":40}
+// commander -> 操作端: binary frame 为终端输出，会话结束时以 close frame 返回原因
+
+// 终端接口使用默认的同源检查
+
+var shellUpgrader = websocket.Upgrader{}
+
+type ShellReq struct {
+	OrgUuid      string `form:"orgUuid" validate:"required"`
+	GroupUuid    string `form:"groupUuid" validate:"required"`
+	InstanceName string `form:"instanceName" validate:"required"`
+	Cols         uint32 `form:"cols"`
+	Rows         uint32 `form:"rows"`
+}
+
+type shellControl struct {
+	Type string `json:"type"`
+	Cols uint32 `json:"cols"`
+	Rows uint32 `json:"rows"`
+}
+
+func (useCase *UseCase) Shell(c *gin.Context) {
+	req := &ShellReq{}
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		writeProtoError(c, v1.ErrorInvalidArgument("参数异常"))
+		return
+	}
+	
+	validate := validator.New()
+	err = validate.Struct(req)
+	if err != nil {
+		writeProtoError(c, v1.ErrorInvalidArgument("参数异常"))
+		return
+	}
+	
+	if req.Cols == 0 || req.Rows == 0 {
+		req.Cols, req.Rows = 80, 24
+	}
+	
+	instance, err := useCase.instanceUseCase.GetInstance(c.Request.Context(), req.OrgUuid, req.GroupUuid, req.InstanceName)
+	if err != nil {
+		c.Set("error", err.Error())
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			writeProtoError(c, v1.ErrorInstanceNotFound("实例不存在: %s", req.InstanceName))
+			return
+		}
+		writeProtoError(c, v1.ErrorInternalError("系统异常"))
+		return
+	}
+	
+	if !instance.SupportInstruct(biz.ShellInstruct) {
+		writeProtoError(c, v1.ErrorUnsupportedInstructType("目标实例不支持终端"))
+		return
+	}
+	
+	// 1. 打开会话 (权限校验及审计记录)，失败时以 HTTP 错误返回
+	operator, role := middleware.GetOperator(c)
+	session, err := useCase.shellUseCase.Open(c.Request.Context(), instance, operator, role, req.Cols, req.Rows)
+	if err != nil {
+		c.Set("error", err.Error())
+		switch {
+		case errors.Is(err, biz.ErrShellForbidden):
+			c.JSON(403, gin.H{"errCode": 403, "errMsg": err.Error()})
+		case errors.Is(err, biz.ErrShellNotConnected):
+			writeProtoError(c, v1.ErrorInstanceOffline("%s", err.Error()))
+		default:
+			useCase.logger.Error("打开终端会话失败", zap.Error(err))
+			writeProtoError(c, v1.ErrorInternalError("系统异常"))
+		}
+		return
+	}
+	
+	// 2. 协议升级
+	conn, err := shellUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		c.Set("error", err.Error())
+		session.Close("操作端协议升级失败")
+		return
+	}
+	defer conn.Close()
+	
+	// 3. 读取操作端输入
+	go func() {
+		for {
+			messageType, message, err := conn.ReadMessage()
+			if err != nil {
+				session.Close("操作端断开")
+				return
+			}
+			
+			switch messageType {
+			case websocket.BinaryMessage:
+				session.Input(message)
+			
+			case websocket.TextMessage:
+				var control shellControl
+				err = json.Unmarshal(message, &control)
+				if err == nil && control.Type == "resize" && control.Cols > 0 && control.Rows > 0 {
+					session.Resize(control.Cols, control.Rows)
+				}
+			}
+		}
+	}()
+	
+	// 4. 将终端输出写回操作端
+	for {
+		select {
+		case frame := <-session.Output():
+			err = conn.WriteMessage(websocket.BinaryMessage, frame.Data)
+			if err != nil {
+				session.Close("操作端断开")
+				return
+			}
+		
+		case <-session.Done():
+			// 写出会话结束前剩余的输出
+			for len(session.Output()) > 0 {
+				frame := <-session.Output()
+				conn.WriteMessage(websocket.BinaryMessage, frame.Data)
+			}
+			
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, session.Reason()),
+				time.Now().Add(time.Second))
+			return
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/websocket"
+	v1 "github.com/qx66/camp/api/camp/v1"
+	"github.com/qx66/camp/internal/biz"
+	"github.com/qx66/camp/pkg/middleware"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"time"
+)
+
+// 端口转发，每个 websocket 连接对应一个到目标地址的 TCP 流
+//
+// binary frame 为 TCP 数据，本地端口可通过 websocat 等工具转发到该接口，如:
+// websocat -b tcp-l:127.0.0.1:13306 "ws://commander/v1/instance/tunnel?...&addr=10.0.0.5:3306"
+
+type TunnelReq struct {
+	OrgUuid      string `form:"orgUuid" validate:"required"`
+	GroupUuid    string `form:"groupUuid" validate:"required"`
+	InstanceName string `form:"instanceName" validate:"required"`
+	Addr         string `form:"addr" validate:"required"`
+}
+
+func (useCase *UseCase) Tunnel(c *gin.Context) {
+	req := &TunnelReq{}
+	err := c.ShouldBindQuery(req)
+	if err != nil {
+		writeProtoError(c, v1.ErrorInvalidArgument("参数异常"))
+		return
+	}
+	
+	validate := validator.New()
+	err = validate.Struct(req)
+	if err != nil {
+		writeProtoError(c, v1.ErrorInvalidArgument("参数异常"))
+		return
+	}
+	
+	instance, err := useCase.instanceUseCase.GetInstance(c.Request.Context(), req.OrgUuid, req.GroupUuid, req.InstanceName)
+	if err != nil {
+		c.Set("error", err.Error())
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			writeProtoError(c, v1.ErrorInstanceNotFound("实例不存在: %s", req.InstanceName))
+			return
+		}
+		writeProtoError(c, v1.ErrorInternalError("系统异常"))
+		return
+	}
+	
+	if !instance.SupportInstruct(biz.TunnelInstruct) {
+		writeProtoError(c, v1.ErrorUnsupportedInstructType("目标实例不支持端口转发"))
+		return
+	}
+	
+	// 1. 打开流 (权限校验、审计记录及连接目标地址)，失败时以 HTTP 错误返回
+	operator, role := middleware.GetOperator(c)
+	stream, err := useCase.tunnelUseCase.Open(c.Request.Context(), instance, operator, role, req.Addr)
+	if err != nil {
+		c.Set("error", err.Error())
+		switch {
+		case errors.Is(err, biz.ErrTunnelForbidden), errors.Is(err, biz.ErrTunnelTargetNotAllowed):
+			c.JSON(403, gin.H{"errCode": 403, "errMsg": err.Error()})
+		case errors.Is(err, biz.ErrTunnelNotConnected):
+			writeProtoError(c, v1.ErrorInstanceOffline("%s", err.Error()))
+		case errors.Is(err, biz.ErrTunnelDialFailed):
+			c.JSON(502, gin.H{"errCode": 502, "errMsg": err.Error()})
+		default:
+			useCase.logger.Error("打开端口转发失败", zap.Error(err))
+			writeProtoError(c, v1.ErrorInternalError("系统异常"))
+		}
+		return
+	}
+	
+	// 2. 协议升级，与终端接口相同使用默认的同源检查
+	conn, err := shellUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		c.Set("error", err.Error())
+		stream.Close("操作端协议升级失败")
+		return
+	}
+	defer conn.Close()
+	
+	// 3. 转发操作端数据，窗口耗尽时停止读取，由 TCP 反压到操作端
+	go func() {
+		for {
+			messageType, message, err := conn.ReadMessage()
+			if err != nil {
+				stream.Close("操作端断开")
+				return
+			}
+			
+			if messageType != websocket.BinaryMessage {
+				continue
+			}
+			
+			err = stream.Write(message)
+			if err != nil {
+				return
+			}
+		}
+	}()
+	
+	// 4. 将目标地址返回的数据写回操作端，写出后归还窗口
+	for {
+		select {
+		case data := <-stream.Output():
+			err = conn.WriteMessage(websocket.BinaryMessage, data)
+			if err != nil {
+				stream.Close("操作端断开")
+				return
+			}
+			stream.Ack(len(data))
+		
+		case <-stream.Done():
+			for len(stream.Output()) > 0 {
+				conn.WriteMessage(websocket.BinaryMessage, <-stream.Output())
+			}
+			
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, stream.Reason()),
+				time.Now().Add(time.Second))
+			return
+		}
+	}
+}
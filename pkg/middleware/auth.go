@@ -4,9 +4,15 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"github.com/qx66/camp/pkg/tracing"
 	"github.com/startopsz/rule/pkg/response/errCode"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"net/http"
 	"regexp"
 	
 	"github.com/gin-gonic/gin"
@@ -19,9 +25,39 @@ OpenTelemetry
 
 func OpenTelemetry() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 1. 使用 OpenTelemetry 发送数据
+		// 1. 从请求头中提取上游 trace 信息，并为本次请求创建 span
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
 		
-		// 2. 获取 tranceId 并放在
+		spanName := c.FullPath()
+		if spanName == "" {
+			spanName = c.Request.URL.Path
+		}
+		
+		ctx, span := tracing.Tracer().Start(ctx, c.Request.Method+" "+spanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.method", c.Request.Method),
+				attribute.String("http.route", c.FullPath()),
+				attribute.String("http.client_ip", GetClientIp(c)),
+			),
+		)
+		defer span.End()
+		
+		// 2. 将带有 span 的 ctx 放回请求中，后续 handler 及 Recording 可获取 traceId
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+		
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.status_code", status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		
+		if err, ok := c.Get("error"); ok {
+			if errMsg, ok := err.(string); ok && errMsg != "ok" {
+				span.SetStatus(codes.Error, errMsg)
+			}
+		}
 	}
 }
 
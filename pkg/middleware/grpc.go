@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"github.com/qx66/camp/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// grpc metadata 作为 trace 上下文的载体
+
+type metadataCarrier metadata.MD
+
+func (mc metadataCarrier) Get(key string) string {
+	values := metadata.MD(mc).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (mc metadataCarrier) Set(key string, value string) {
+	metadata.MD(mc).Set(key, value)
+}
+
+func (mc metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(mc))
+	for k := range mc {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+/*
+UnaryServerTracing
+
+为每个 gRPC 请求创建 span 并记录访问日志，与 HTTP 的 OpenTelemetry、Recording 中间件保持一致
+*/
+
+func UnaryServerTracing(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md.Copy()))
+		
+		ctx, span := tracing.Tracer().Start(ctx, info.FullMethod,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("rpc.method", info.FullMethod)),
+		)
+		defer span.End()
+		
+		resp, err := handler(ctx, req)
+		
+		code := status.Code(err)
+		span.SetAttributes(attribute.String("rpc.grpc.status_code", code.String()))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		
+		logger.Info(code.String(),
+			zap.String("traceId", span.SpanContext().TraceID().String()),
+			zap.String("spanId", span.SpanContext().SpanID().String()),
+			zap.Int64("startTimestamp", start.Unix()),
+			zap.String("method", info.FullMethod),
+			zap.Float64("latency", time.Now().Sub(start).Seconds()),
+			zap.Error(err),
+		)
+		
+		return resp, err
+	}
+}
+
+/*
+UnaryServerOperator
+
+从 metadata (x-camp-user 等，与 HTTP 请求头相同) 中校验操作人身份并保存到 context，签名无效时返回 Unauthenticated
+*/
+
+func UnaryServerOperator(identityVerifier *IdentityVerifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		operator, _, err := identityVerifier.Identity(metadataCarrier(md).Get)
+		if err != nil {
+			return nil, status.Error(grpccodes.Unauthenticated, err.Error())
+		}
+		
+		if operator != "" {
+			ctx = NewOperatorContext(ctx, operator)
+		}
+		
+		return handler(ctx, req)
+	}
+}
@@ -1,6 +1,13 @@
 package middleware
 
-import "github.com/gin-gonic/gin"
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"time"
+)
 
 func GetClientIp(c *gin.Context) string {
 	
@@ -22,3 +29,113 @@ func GetClientIp(c *gin.Context) string {
 	
 	return "0.0.0.0"
 }
+
+const (
+	OperatorHeader          = "X-Camp-User"
+	RoleHeader              = "X-Camp-Role"
+	IdentityTimestampHeader = "X-Camp-Timestamp"
+	IdentitySignatureHeader = "X-Camp-Identity-Signature"
+	
+	defaultIdentityMaxSkew = 5 * time.Minute
+	
+	operatorContextKey = "operator"
+	roleContextKey     = "role"
+)
+
+var ErrInvalidIdentity = errors.New("身份签名校验失败")
+
+// 操作人及角色由上游网关认证后通过请求头传递，网关对其签名:
+// X-Camp-Identity-Signature = hex(HMAC-SHA256(secret, user + "\n" + role + "\n" + timestamp))，timestamp 为 X-Camp-Timestamp (unix 秒)
+
+type IdentityVerifier struct {
+	secret  []byte
+	maxSkew time.Duration
+}
+
+// secret 为空时返回 nil，此时操作人仅用于审计，不信任角色
+
+func NewIdentityVerifier(secret string, maxSkew time.Duration) *IdentityVerifier {
+	if secret == "" {
+		return nil
+	}
+	
+	if maxSkew <= 0 {
+		maxSkew = defaultIdentityMaxSkew
+	}
+	
+	return &IdentityVerifier{
+		secret:  []byte(secret),
+		maxSkew: maxSkew,
+	}
+}
+
+// 返回操作人及角色，get 读取请求头 (gRPC 为 metadata)；未携带签名时视为匿名，签名无效时返回 ErrInvalidIdentity
+
+func (identityVerifier *IdentityVerifier) Identity(get func(key string) string) (string, string, error) {
+	if identityVerifier == nil {
+		return get(OperatorHeader), "", nil
+	}
+	
+	signature := get(IdentitySignatureHeader)
+	if signature == "" {
+		return "", "", nil
+	}
+	
+	operator, role, timestamp := get(OperatorHeader), get(RoleHeader), get(IdentityTimestampHeader)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", "", ErrInvalidIdentity
+	}
+	
+	skew := time.Since(time.Unix(unix, 0))
+	if skew < -identityVerifier.maxSkew || skew > identityVerifier.maxSkew {
+		return "", "", ErrInvalidIdentity
+	}
+	
+	expected, err := Sign([]byte(operator+"\n"+role+"\n"+timestamp), identityVerifier.secret)
+	if err != nil || !hmac.Equal([]byte(expected), []byte(signature)) {
+		return "", "", ErrInvalidIdentity
+	}
+	
+	return operator, role, nil
+}
+
+/*
+Identity
+
+校验请求头中的操作人身份，签名无效时返回 401
+*/
+
+func Identity(identityVerifier *IdentityVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		operator, role, err := identityVerifier.Identity(c.GetHeader)
+		if err != nil {
+			c.Set("error", err.Error())
+			c.JSON(401, gin.H{"errCode": 401, "errMsg": err.Error()})
+			c.Abort()
+			return
+		}
+		
+		c.Set(operatorContextKey, operator)
+		c.Set(roleContextKey, role)
+	}
+}
+
+// 返回 Identity 中间件校验后的操作人及角色
+
+func GetOperator(c *gin.Context) (string, string) {
+	return c.GetString(operatorContextKey), c.GetString(roleContextKey)
+}
+
+type operatorKey struct{}
+
+// 将操作人保存到 context，用于 HTTP 及 gRPC 共用的接口实现
+
+func NewOperatorContext(ctx context.Context, operator string) context.Context {
+	return context.WithValue(ctx, operatorKey{}, operator)
+}
+
+func OperatorFromContext(ctx context.Context) string {
+	operator, _ := ctx.Value(operatorKey{}).(string)
+	return operator
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func signedHeader(t *testing.T, secret, operator, role string, now time.Time) http.Header {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature, err := Sign([]byte(operator+"\n"+role+"\n"+timestamp), []byte(secret))
+	require.NoError(t, err)
+	
+	header := http.Header{}
+	header.Set(OperatorHeader, operator)
+	header.Set(RoleHeader, role)
+	header.Set(IdentityTimestampHeader, timestamp)
+	header.Set(IdentitySignatureHeader, signature)
+	return header
+}
+
+// 未配置密钥时不信任角色，配置后仅接受签名有效且未过期的身份
+
+func TestIdentityVerifier(t *testing.T) {
+	header := signedHeader(t, "secret", "alice", "ops", time.Now())
+	
+	var verifier *IdentityVerifier
+	operator, role, err := verifier.Identity(header.Get)
+	require.NoError(t, err)
+	require.Equal(t, "alice", operator)
+	require.Empty(t, role)
+	
+	verifier = NewIdentityVerifier("secret", time.Minute)
+	operator, role, err = verifier.Identity(header.Get)
+	require.NoError(t, err)
+	require.Equal(t, "alice", operator)
+	require.Equal(t, "ops", role)
+	
+	// 未携带签名视为匿名
+	operator, role, err = verifier.Identity(http.Header{OperatorHeader: {"alice"}, RoleHeader: {"ops"}}.Get)
+	require.NoError(t, err)
+	require.Empty(t, operator)
+	require.Empty(t, role)
+	
+	// 篡改角色、密钥不一致及时间戳过期
+	header.Set(RoleHeader, "admin")
+	_, _, err = verifier.Identity(header.Get)
+	require.ErrorIs(t, err, ErrInvalidIdentity)
+	
+	_, _, err = verifier.Identity(signedHeader(t, "other", "alice", "ops", time.Now()).Get)
+	require.ErrorIs(t, err, ErrInvalidIdentity)
+	
+	_, _, err = verifier.Identity(signedHeader(t, "secret", "alice", "ops", time.Now().Add(-2*time.Minute)).Get)
+	require.ErrorIs(t, err, ErrInvalidIdentity)
+}
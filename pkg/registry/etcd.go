@@ -0,0 +1,251 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 通过 etcd v3 HTTP 接口 (grpc-gateway) 登记服务节点，key 为 {prefix}/{name}/{id}，value 与 kratos 注册中心的格式一致，
+// 节点登记绑定租约，按 ttl/3 续约，租约过期 (etcd 不可用期间) 后重新登记
+
+const (
+	DefaultPrefix = "/microservices"
+	DefaultTTL    = 15 * time.Second
+	
+	requestTimeout = 5 * time.Second
+)
+
+type Instance struct {
+	Id        string            `json:"id"`
+	Name      string            `json:"name"`
+	Version   string            `json:"version"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Endpoints []string          `json:"endpoints"`
+}
+
+type EtcdRegistry struct {
+	endpoints []string
+	prefix    string
+	ttl       time.Duration
+	client    *http.Client
+	
+	mu    sync.Mutex
+	lease int64
+	stop  context.CancelFunc
+}
+
+func NewEtcdRegistry(endpoints []string, prefix string, ttl time.Duration) *EtcdRegistry {
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	
+	return &EtcdRegistry{
+		endpoints: endpoints,
+		prefix:    strings.TrimSuffix(prefix, "/"),
+		ttl:       ttl,
+		client:    &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (etcdRegistry *EtcdRegistry) key(instance Instance) string {
+	return fmt.Sprintf("%s/%s/%s", etcdRegistry.prefix, instance.Name, instance.Id)
+}
+
+// 登记节点并在 ctx 结束或 Deregister 前持续续约
+
+func (etcdRegistry *EtcdRegistry) Register(ctx context.Context, instance Instance) error {
+	err := etcdRegistry.put(ctx, instance)
+	if err != nil {
+		return err
+	}
+	
+	ctx, cancel := context.WithCancel(ctx)
+	etcdRegistry.mu.Lock()
+	etcdRegistry.stop = cancel
+	etcdRegistry.mu.Unlock()
+	
+	go etcdRegistry.keepAlive(ctx, instance)
+	return nil
+}
+
+func (etcdRegistry *EtcdRegistry) put(ctx context.Context, instance Instance) error {
+	value, err := json.Marshal(instance)
+	if err != nil {
+		return err
+	}
+	
+	var grant struct {
+		ID string `json:"ID"`
+	}
+	err = etcdRegistry.call(ctx, "/v3/lease/grant", map[string]interface{}{"TTL": int64(etcdRegistry.ttl.Seconds())}, &grant)
+	if err != nil {
+		return err
+	}
+	
+	lease, err := strconv.ParseInt(grant.ID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("etcd 返回的租约 id 异常: %q", grant.ID)
+	}
+	
+	err = etcdRegistry.call(ctx, "/v3/kv/put", map[string]interface{}{
+		"key":   base64.StdEncoding.EncodeToString([]byte(etcdRegistry.key(instance))),
+		"value": base64.StdEncoding.EncodeToString(value),
+		"lease": strconv.FormatInt(lease, 10),
+	}, nil)
+	if err != nil {
+		return err
+	}
+	
+	etcdRegistry.mu.Lock()
+	etcdRegistry.lease = lease
+	etcdRegistry.mu.Unlock()
+	return nil
+}
+
+func (etcdRegistry *EtcdRegistry) keepAlive(ctx context.Context, instance Instance) {
+	ticker := time.NewTicker(etcdRegistry.ttl / 3)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ticker.C:
+			alive, err := etcdRegistry.refresh(ctx)
+			if err == nil && alive {
+				continue
+			}
+			
+			// 续约失败或租约已过期时重新登记，etcd 不可用时下一周期重试
+			etcdRegistry.put(ctx, instance)
+		
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (etcdRegistry *EtcdRegistry) refresh(ctx context.Context) (bool, error) {
+	etcdRegistry.mu.Lock()
+	lease := etcdRegistry.lease
+	etcdRegistry.mu.Unlock()
+	
+	var keepAlive struct {
+		Result struct {
+			TTL string `json:"TTL"`
+		} `json:"result"`
+	}
+	err := etcdRegistry.call(ctx, "/v3/lease/keepalive", map[string]interface{}{"ID": strconv.FormatInt(lease, 10)}, &keepAlive)
+	if err != nil {
+		return false, err
+	}
+	
+	// 租约已过期时 etcd 返回的 TTL 为空
+	ttl, _ := strconv.ParseInt(keepAlive.Result.TTL, 10, 64)
+	return ttl > 0, nil
+}
+
+// 停止续约并撤销租约，节点登记随之删除
+
+func (etcdRegistry *EtcdRegistry) Deregister(ctx context.Context) error {
+	etcdRegistry.mu.Lock()
+	lease := etcdRegistry.lease
+	etcdRegistry.lease = 0
+	if etcdRegistry.stop != nil {
+		etcdRegistry.stop()
+	}
+	etcdRegistry.mu.Unlock()
+	
+	if lease == 0 {
+		return nil
+	}
+	
+	return etcdRegistry.call(ctx, "/v3/lease/revoke", map[string]interface{}{"ID": strconv.FormatInt(lease, 10)}, nil)
+}
+
+// 依次请求各 etcd 地址，返回最后一个错误
+
+func (etcdRegistry *EtcdRegistry) call(ctx context.Context, path string, req interface{}, resp interface{}) error {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	
+	err = errors.New("未配置 etcd 地址")
+	for _, endpoint := range etcdRegistry.endpoints {
+		err = etcdRegistry.post(ctx, strings.TrimSuffix(endpoint, "/")+path, b, resp)
+		if err == nil {
+			return nil
+		}
+	}
+	
+	return err
+}
+
+func (etcdRegistry *EtcdRegistry) post(ctx context.Context, url string, body []byte, resp interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	
+	response, err := etcdRegistry.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("etcd 请求失败: %s %s", url, response.Status)
+	}
+	
+	if resp == nil {
+		return nil
+	}
+	
+	return json.NewDecoder(response.Body).Decode(resp)
+}
+
+// 监听地址转为登记的地址，未指定主机时使用本机第一个非回环 IPv4 地址
+
+func Endpoint(scheme, addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Sprintf("%s://%s", scheme, addr)
+	}
+	
+	ip := net.ParseIP(host)
+	if host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = localIP()
+	}
+	
+	return fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(host, port))
+}
+
+func localIP() string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "127.0.0.1"
+	}
+	
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return ipNet.IP.String()
+		}
+	}
+	
+	return "127.0.0.1"
+}
@@ -0,0 +1,83 @@
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 模拟 etcd HTTP 接口，仅记录租约及 key
+
+type fakeEtcd struct {
+	mu     sync.Mutex
+	kvs    map[string]string
+	leases map[string]string
+}
+
+func (fakeEtcd *fakeEtcd) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req map[string]string
+	json.NewDecoder(r.Body).Decode(&req)
+	
+	fakeEtcd.mu.Lock()
+	defer fakeEtcd.mu.Unlock()
+	
+	switch r.URL.Path {
+	case "/v3/lease/grant":
+		json.NewEncoder(w).Encode(map[string]string{"ID": "7"})
+	case "/v3/kv/put":
+		key, _ := base64.StdEncoding.DecodeString(req["key"])
+		value, _ := base64.StdEncoding.DecodeString(req["value"])
+		fakeEtcd.kvs[string(key)] = string(value)
+		fakeEtcd.leases[string(key)] = req["lease"]
+		w.Write([]byte("{}"))
+	case "/v3/lease/revoke":
+		for key, lease := range fakeEtcd.leases {
+			if lease == req["ID"] {
+				delete(fakeEtcd.kvs, key)
+				delete(fakeEtcd.leases, key)
+			}
+		}
+		w.Write([]byte("{}"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestEtcdRegistry(t *testing.T) {
+	etcd := &fakeEtcd{kvs: make(map[string]string), leases: make(map[string]string)}
+	server := httptest.NewServer(etcd)
+	defer server.Close()
+	
+	// 第一个地址不可用时使用下一个地址
+	etcdRegistry := NewEtcdRegistry([]string{"http://127.0.0.1:1", server.URL + "/"}, "/camp/", time.Minute)
+	err := etcdRegistry.Register(context.Background(), Instance{Id: "node1", Name: "commander", Endpoints: []string{"http://10.0.0.1:8080"}})
+	require.NoError(t, err)
+	
+	etcd.mu.Lock()
+	value := etcd.kvs["/camp/commander/node1"]
+	lease := etcd.leases["/camp/commander/node1"]
+	etcd.mu.Unlock()
+	
+	var instance Instance
+	require.NoError(t, json.Unmarshal([]byte(value), &instance))
+	require.Equal(t, "node1", instance.Id)
+	require.Equal(t, []string{"http://10.0.0.1:8080"}, instance.Endpoints)
+	require.Equal(t, "7", lease)
+	
+	require.NoError(t, etcdRegistry.Deregister(context.Background()))
+	etcd.mu.Lock()
+	require.Empty(t, etcd.kvs)
+	etcd.mu.Unlock()
+}
+
+func TestEndpoint(t *testing.T) {
+	require.Equal(t, "http://10.0.0.1:8080", Endpoint("http", "10.0.0.1:8080"))
+	require.NotEqual(t, "http://:8080", Endpoint("http", ":8080"))
+	require.Equal(t, "http://localhost", Endpoint("http", "localhost"))
+}
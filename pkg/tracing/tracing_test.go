@@ -0,0 +1,33 @@
+package tracing
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+func TestInjectExtract(t *testing.T) {
+	_, err := NewTracerProvider(context.Background(), "", "camp-test", "dev")
+	require.NoError(t, err)
+	
+	traceId, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanId, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceId,
+		SpanID:     spanId,
+		TraceFlags: trace.FlagsSampled,
+	}))
+	
+	carrier := Inject(ctx)
+	require.NotEmpty(t, carrier["traceparent"], "注入trace上下文失败")
+	
+	sc := trace.SpanContextFromContext(Extract(context.Background(), carrier))
+	require.Equal(t, traceId, sc.TraceID())
+	require.Equal(t, spanId, sc.SpanID())
+	require.True(t, sc.IsRemote())
+}
+
+func TestInjectWithoutSpan(t *testing.T) {
+	require.Nil(t, Inject(context.Background()))
+}
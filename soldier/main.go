@@ -5,9 +5,13 @@ import (
 	"flag"
 	"fmt"
 	"github.com/qx66/camp/internal/biz"
+	"github.com/qx66/camp/pkg/tracing"
 	"go.uber.org/zap"
+	"net/url"
 	"os"
 	"os/signal"
+	"strings"
+	"time"
 )
 
 type app struct {
@@ -21,11 +25,11 @@ type app struct {
 }
 
 func newApp(logger *zap.Logger,
-//chromeDpClientUseCase *biz.ChromeDpClientUseCase,
-//dnsClientInspectUseCase *biz.DnsClientInspectUseCase,
-//httpInspectClientUseCase *biz.HttpInspectClientUseCase,
-//icmpClientUseCase *biz.IcmpClientUseCase,
-//socketClientUseCase *biz.SocketClientUseCase,
+	//chromeDpClientUseCase *biz.ChromeDpClientUseCase,
+	//dnsClientInspectUseCase *biz.DnsClientInspectUseCase,
+	//httpInspectClientUseCase *biz.HttpInspectClientUseCase,
+	//icmpClientUseCase *biz.IcmpClientUseCase,
+	//socketClientUseCase *biz.SocketClientUseCase,
 	webSocketUseCase *biz.WebSocketUseCase) *app {
 	return &app{
 		logger: logger,
@@ -39,11 +43,29 @@ func newApp(logger *zap.Logger,
 }
 
 var (
-	webSocketUrl = ""
-	token        = ""
-	orgUuid      = ""
-	groupUuid    = ""
-	instanceName = ""
+	// Version 通过 -ldflags "-X main.Version=x.y.z" 设置
+	Version = "dev"
+	
+	webSocketUrl  = ""
+	traceEndpoint = ""
+	token         = ""
+	orgUuid       = ""
+	groupUuid     = ""
+	instanceName  = ""
+	labels        = ""
+	
+	fileAllowPaths = ""
+	fileMaxSize    int64
+	shellEnable    bool
+	tunnelEnable   bool
+	
+	maxFrameSize   int
+	maxMessageSize int
+	compression    bool
+	
+	spoolDir     = ""
+	spoolMaxSize int64
+	spoolMaxAge  time.Duration
 )
 
 func init() {
@@ -52,6 +74,18 @@ func init() {
 	flag.StringVar(&orgUuid, "orgUuid", "", "your orgUuid (required)")
 	flag.StringVar(&groupUuid, "groupUuid", "", "your groupUuid (required)")
 	flag.StringVar(&instanceName, "instanceName", "", "your instanceName (required)")
+	flag.StringVar(&labels, "labels", "", "instance labels reported on connect, comma separated key=value pairs (optional), e.g: env=prod,role=web")
+	flag.StringVar(&traceEndpoint, "traceEndpoint", "", "OTLP/HTTP trace endpoint (optional), e.g: 127.0.0.1:4318")
+	flag.StringVar(&fileAllowPaths, "fileAllowPaths", "", "directories allowed for file get/put instructs, comma separated (optional, disabled if empty), e.g: /var/log,/tmp")
+	flag.Int64Var(&fileMaxSize, "fileMaxSize", 10*1024*1024, "max file size in bytes for file get/put instructs")
+	flag.BoolVar(&shellEnable, "shell", true, "allow interactive shell sessions (requires bash), disable with -shell=false")
+	flag.BoolVar(&tunnelEnable, "tunnel", true, "allow tcp port forwarding to targets permitted by commander, disable with -tunnel=false")
+	flag.IntVar(&maxFrameSize, "maxFrameSize", biz.DefaultMaxFrameSize, "max websocket message size in bytes accepted from commander, larger messages are sent in fragments")
+	flag.IntVar(&maxMessageSize, "maxMessageSize", biz.DefaultMaxMessageSize, "max reassembled message size in bytes, larger replies are truncated")
+	flag.BoolVar(&compression, "compression", true, "negotiate permessage-deflate compression, disable with -compression=false")
+	flag.StringVar(&spoolDir, "spoolDir", "spool", "directory for instruct replies and telemetry produced while commander is unreachable (disabled if empty)")
+	flag.Int64Var(&spoolMaxSize, "spoolMaxSize", biz.DefaultSpoolMaxSize, "max spool size in bytes, oldest messages are dropped when exceeded")
+	flag.DurationVar(&spoolMaxAge, "spoolMaxAge", biz.DefaultSpoolMaxAge, "max age of spooled messages, older messages are dropped")
 }
 
 func main() {
@@ -68,30 +102,60 @@ func main() {
 		return
 	}
 	
+	_, err = biz.ParseLabels(labels)
+	if err != nil {
+		logger.Error("标签格式异常", zap.Error(err))
+		return
+	}
+	
 	websocketUrl := fmt.Sprintf("%s?orgUuid=%s&groupUuid=%s&instanceName=%s",
 		webSocketUrl, orgUuid, groupUuid, instanceName)
+	if labels != "" {
+		websocketUrl += "&labels=" + url.QueryEscape(labels)
+	}
 	
 	//
 	ctx := context.Background()
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	
+	traceCleanup, err := tracing.NewTracerProvider(ctx, traceEndpoint, "camp-soldier", Version)
+	if err != nil {
+		logger.Error("初始化Trace失败", zap.Error(err))
+		return
+	}
+	defer traceCleanup()
+	
 	//
-	sendMsg := make(chan biz.ClientMessage)
-	receiveMsg := make(chan []byte)
+	// 按逻辑通道划分的发送及接收队列
+	sendChannels := biz.NewClientChannels()
+	receiveQueues := biz.NewServiceQueues()
 	done := make(chan struct{})
 	//actionChannel := make(chan Action)
 	
 	sig := make(chan os.Signal)
 	signal.Notify(sig, os.Interrupt)
 	
-	app := initApp(logger)
+	filePolicy := &biz.FilePolicy{MaxSize: fileMaxSize}
+	for _, path := range strings.Split(fileAllowPaths, ",") {
+		if strings.TrimSpace(path) != "" {
+			filePolicy.AllowPaths = append(filePolicy.AllowPaths, strings.TrimSpace(path))
+		}
+	}
+	
+	app := initApp(logger, filePolicy, &biz.ShellClientPolicy{Enabled: shellEnable}, &biz.TunnelClientPolicy{Enabled: tunnelEnable},
+		&biz.FramePolicy{MaxFrameSize: maxFrameSize, MaxMessageSize: maxMessageSize, Compression: compression},
+		&biz.SpoolPolicy{Dir: spoolDir, MaxSize: spoolMaxSize, MaxAge: spoolMaxAge})
 	
-	go app.webSocketUseCase.NewWebSocket(ctx, websocketUrl, token, sendMsg, receiveMsg, done)
+	go app.webSocketUseCase.NewWebSocket(ctx, websocketUrl, token, Version, sendChannels, receiveQueues, done)
+	
+	for channel, receiveMsg := range receiveQueues {
+		go app.webSocketUseCase.ProcessServiceMessage(ctx, biz.Channel(channel), receiveMsg, sendChannels)
+	}
 	
-	go app.webSocketUseCase.ProcessServiceMessage(ctx, receiveMsg, sendMsg)
+	go app.webSocketUseCase.ReportMetrics(ctx, sendChannels.Queue(biz.TelemetryChannel))
 	
-	go app.webSocketUseCase.HelloEcho(ctx, sendMsg)
+	go app.webSocketUseCase.ReportInventory(ctx, sendChannels.Queue(biz.TelemetryChannel))
 	
 	select {
 	case <-sig:
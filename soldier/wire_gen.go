@@ -13,13 +13,19 @@ import (
 
 // Injectors from wire.go:
 
-func initApp(logger *zap.Logger) *app {
+func initApp(logger *zap.Logger, filePolicy *biz.FilePolicy, shellClientPolicy *biz.ShellClientPolicy, tunnelClientPolicy *biz.TunnelClientPolicy, framePolicy *biz.FramePolicy, spoolPolicy *biz.SpoolPolicy) *app {
 	dnsClientInspectUseCase := biz.NewDnsClientInspectUseCase(logger)
 	httpInspectClientUseCase := biz.NewHttpInspectClientUseCase(logger)
 	icmpClientUseCase := biz.NewIcmpClientUseCase(logger)
 	socketClientUseCase := biz.NewSocketClientUseCase(logger)
 	chromeDpClientUseCase := biz.NewChromeDpClientUseCase(logger)
-	webSocketUseCase := biz.NewWebSocketUseCase(logger, dnsClientInspectUseCase, httpInspectClientUseCase, icmpClientUseCase, socketClientUseCase, chromeDpClientUseCase)
+	inventoryClientUseCase := biz.NewInventoryClientUseCase(logger)
+	metricsClientUseCase := biz.NewMetricsClientUseCase(logger)
+	fileClientUseCase := biz.NewFileClientUseCase(logger, filePolicy)
+	shellClientUseCase := biz.NewShellClientUseCase(logger, shellClientPolicy)
+	tunnelClientUseCase := biz.NewTunnelClientUseCase(logger, tunnelClientPolicy)
+	spool := biz.NewSpool(logger, spoolPolicy)
+	webSocketUseCase := biz.NewWebSocketUseCase(logger, dnsClientInspectUseCase, httpInspectClientUseCase, icmpClientUseCase, socketClientUseCase, chromeDpClientUseCase, inventoryClientUseCase, metricsClientUseCase, fileClientUseCase, shellClientUseCase, tunnelClientUseCase, framePolicy, spool)
 	mainApp := newApp(logger, webSocketUseCase)
 	return mainApp
 }